@@ -0,0 +1,1221 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: api/proto/corebanking.proto
+
+package corebankingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Account struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccountId      string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	DocumentNumber string                 `protobuf:"bytes,2,opt,name=document_number,json=documentNumber,proto3" json:"document_number,omitempty"`
+	HolderName     string                 `protobuf:"bytes,3,opt,name=holder_name,json=holderName,proto3" json:"holder_name,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Account) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Account) GetDocumentNumber() string {
+	if x != nil {
+		return x.DocumentNumber
+	}
+	return ""
+}
+
+func (x *Account) GetHolderName() string {
+	if x != nil {
+		return x.HolderName
+	}
+	return ""
+}
+
+type AccountState struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Balance        int64                  `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	OverdraftLimit int64                  `protobuf:"varint,3,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AccountState) Reset() {
+	*x = AccountState{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountState) ProtoMessage() {}
+
+func (x *AccountState) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountState.ProtoReflect.Descriptor instead.
+func (*AccountState) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AccountState) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AccountState) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *AccountState) GetOverdraftLimit() int64 {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return 0
+}
+
+type CreateAccountRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DocumentNumber string                 `protobuf:"bytes,1,opt,name=document_number,json=documentNumber,proto3" json:"document_number,omitempty"`
+	HolderName     string                 `protobuf:"bytes,2,opt,name=holder_name,json=holderName,proto3" json:"holder_name,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateAccountRequest) Reset() {
+	*x = CreateAccountRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountRequest) ProtoMessage() {}
+
+func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateAccountRequest) GetDocumentNumber() string {
+	if x != nil {
+		return x.DocumentNumber
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetHolderName() string {
+	if x != nil {
+		return x.HolderName
+	}
+	return ""
+}
+
+type GetAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountRequest) Reset() {
+	*x = GetAccountRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountRequest) ProtoMessage() {}
+
+func (x *GetAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type GetBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBalanceRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       int64                  `protobuf:"varint,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Balance) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+type ConfigOverdraftRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigOverdraftRequest) Reset() {
+	*x = ConfigOverdraftRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigOverdraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigOverdraftRequest) ProtoMessage() {}
+
+func (x *ConfigOverdraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigOverdraftRequest.ProtoReflect.Descriptor instead.
+func (*ConfigOverdraftRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ConfigOverdraftRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ConfigOverdraftRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ConfigOverdraftResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigOverdraftResponse) Reset() {
+	*x = ConfigOverdraftResponse{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigOverdraftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigOverdraftResponse) ProtoMessage() {}
+
+func (x *ConfigOverdraftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigOverdraftResponse.ProtoReflect.Descriptor instead.
+func (*ConfigOverdraftResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{7}
+}
+
+type Transaction struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId   int64                  `protobuf:"varint,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	AccountId       string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	OperationTypeId int32                  `protobuf:"varint,3,opt,name=operation_type_id,json=operationTypeId,proto3" json:"operation_type_id,omitempty"`
+	Amount          int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	EventDate       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=event_date,json=eventDate,proto3" json:"event_date,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Transaction) GetTransactionId() int64 {
+	if x != nil {
+		return x.TransactionId
+	}
+	return 0
+}
+
+func (x *Transaction) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Transaction) GetOperationTypeId() int32 {
+	if x != nil {
+		return x.OperationTypeId
+	}
+	return 0
+}
+
+func (x *Transaction) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetEventDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventDate
+	}
+	return nil
+}
+
+type CreateTransactionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccountId       string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	OperationTypeId int32                  `protobuf:"varint,2,opt,name=operation_type_id,json=operationTypeId,proto3" json:"operation_type_id,omitempty"`
+	Amount          int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateTransactionRequest) Reset() {
+	*x = CreateTransactionRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTransactionRequest) ProtoMessage() {}
+
+func (x *CreateTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTransactionRequest.ProtoReflect.Descriptor instead.
+func (*CreateTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateTransactionRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetOperationTypeId() int32 {
+	if x != nil {
+		return x.OperationTypeId
+	}
+	return 0
+}
+
+func (x *CreateTransactionRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type GetTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId int64                  `protobuf:"varint,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTransactionRequest) Reset() {
+	*x = GetTransactionRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionRequest) ProtoMessage() {}
+
+func (x *GetTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionRequest.ProtoReflect.Descriptor instead.
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetTransactionRequest) GetTransactionId() int64 {
+	if x != nil {
+		return x.TransactionId
+	}
+	return 0
+}
+
+type ListTransactionsTodayRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsTodayRequest) Reset() {
+	*x = ListTransactionsTodayRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsTodayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsTodayRequest) ProtoMessage() {}
+
+func (x *ListTransactionsTodayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsTodayRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsTodayRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{11}
+}
+
+type ListTransactionsInRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Begin         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=begin,proto3" json:"begin,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsInRangeRequest) Reset() {
+	*x = ListTransactionsInRangeRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsInRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsInRangeRequest) ProtoMessage() {}
+
+func (x *ListTransactionsInRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsInRangeRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsInRangeRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListTransactionsInRangeRequest) GetBegin() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Begin
+	}
+	return nil
+}
+
+func (x *ListTransactionsInRangeRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type ListTransactionsByTypeRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	OperationTypeId int32                  `protobuf:"varint,1,opt,name=operation_type_id,json=operationTypeId,proto3" json:"operation_type_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListTransactionsByTypeRequest) Reset() {
+	*x = ListTransactionsByTypeRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsByTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsByTypeRequest) ProtoMessage() {}
+
+func (x *ListTransactionsByTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsByTypeRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsByTypeRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListTransactionsByTypeRequest) GetOperationTypeId() int32 {
+	if x != nil {
+		return x.OperationTypeId
+	}
+	return 0
+}
+
+type ListTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transactions  []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+type WatchTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTransactionsRequest) Reset() {
+	*x = WatchTransactionsRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTransactionsRequest) ProtoMessage() {}
+
+func (x *WatchTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchTransactionsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type DepositRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Destination   string                 `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepositRequest) Reset() {
+	*x = DepositRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositRequest) ProtoMessage() {}
+
+func (x *DepositRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositRequest.ProtoReflect.Descriptor instead.
+func (*DepositRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DepositRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *DepositRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type WithdrawRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawRequest) Reset() {
+	*x = WithdrawRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawRequest) ProtoMessage() {}
+
+func (x *WithdrawRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawRequest.ProtoReflect.Descriptor instead.
+func (*WithdrawRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WithdrawRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type TransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination   string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TransferRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type EventResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        *AccountState          `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination   *AccountState          `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventResult) Reset() {
+	*x = EventResult{}
+	mi := &file_api_proto_corebanking_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventResult) ProtoMessage() {}
+
+func (x *EventResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_corebanking_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventResult.ProtoReflect.Descriptor instead.
+func (*EventResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_corebanking_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EventResult) GetOrigin() *AccountState {
+	if x != nil {
+		return x.Origin
+	}
+	return nil
+}
+
+func (x *EventResult) GetDestination() *AccountState {
+	if x != nil {
+		return x.Destination
+	}
+	return nil
+}
+
+var File_api_proto_corebanking_proto protoreflect.FileDescriptor
+
+const file_api_proto_corebanking_proto_rawDesc = "" +
+	"\n" +
+	"\x1bapi/proto/corebanking.proto\x12\x0ecorebanking.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"r\n" +
+	"\aAccount\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12'\n" +
+	"\x0fdocument_number\x18\x02 \x01(\tR\x0edocumentNumber\x12\x1f\n" +
+	"\vholder_name\x18\x03 \x01(\tR\n" +
+	"holderName\"a\n" +
+	"\fAccountState\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x03R\abalance\x12'\n" +
+	"\x0foverdraft_limit\x18\x03 \x01(\x03R\x0eoverdraftLimit\"`\n" +
+	"\x14CreateAccountRequest\x12'\n" +
+	"\x0fdocument_number\x18\x01 \x01(\tR\x0edocumentNumber\x12\x1f\n" +
+	"\vholder_name\x18\x02 \x01(\tR\n" +
+	"holderName\"2\n" +
+	"\x11GetAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"2\n" +
+	"\x11GetBalanceRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"#\n" +
+	"\aBalance\x12\x18\n" +
+	"\abalance\x18\x01 \x01(\x03R\abalance\"M\n" +
+	"\x16ConfigOverdraftRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\"\x19\n" +
+	"\x17ConfigOverdraftResponse\"\xd2\x01\n" +
+	"\vTransaction\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\x03R\rtransactionId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12*\n" +
+	"\x11operation_type_id\x18\x03 \x01(\x05R\x0foperationTypeId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x129\n" +
+	"\n" +
+	"event_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\teventDate\"}\n" +
+	"\x18CreateTransactionRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12*\n" +
+	"\x11operation_type_id\x18\x02 \x01(\x05R\x0foperationTypeId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\">\n" +
+	"\x15GetTransactionRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\x03R\rtransactionId\"\x1e\n" +
+	"\x1cListTransactionsTodayRequest\"\x80\x01\n" +
+	"\x1eListTransactionsInRangeRequest\x120\n" +
+	"\x05begin\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05begin\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"K\n" +
+	"\x1dListTransactionsByTypeRequest\x12*\n" +
+	"\x11operation_type_id\x18\x01 \x01(\x05R\x0foperationTypeId\"[\n" +
+	"\x18ListTransactionsResponse\x12?\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x1b.corebanking.v1.TransactionR\ftransactions\"9\n" +
+	"\x18WatchTransactionsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"J\n" +
+	"\x0eDepositRequest\x12 \n" +
+	"\vdestination\x18\x01 \x01(\tR\vdestination\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"A\n" +
+	"\x0fWithdrawRequest\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"c\n" +
+	"\x0fTransferRequest\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\"\x83\x01\n" +
+	"\vEventResult\x124\n" +
+	"\x06origin\x18\x01 \x01(\v2\x1c.corebanking.v1.AccountStateR\x06origin\x12>\n" +
+	"\vdestination\x18\x02 \x01(\v2\x1c.corebanking.v1.AccountStateR\vdestination2\xd8\x02\n" +
+	"\x0eAccountService\x12N\n" +
+	"\rCreateAccount\x12$.corebanking.v1.CreateAccountRequest\x1a\x17.corebanking.v1.Account\x12H\n" +
+	"\n" +
+	"GetAccount\x12!.corebanking.v1.GetAccountRequest\x1a\x17.corebanking.v1.Account\x12H\n" +
+	"\n" +
+	"GetBalance\x12!.corebanking.v1.GetBalanceRequest\x1a\x17.corebanking.v1.Balance\x12b\n" +
+	"\x0fConfigOverdraft\x12&.corebanking.v1.ConfigOverdraftRequest\x1a'.corebanking.v1.ConfigOverdraftResponse2\xfd\x04\n" +
+	"\x12TransactionService\x12Z\n" +
+	"\x11CreateTransaction\x12(.corebanking.v1.CreateTransactionRequest\x1a\x1b.corebanking.v1.Transaction\x12T\n" +
+	"\x0eGetTransaction\x12%.corebanking.v1.GetTransactionRequest\x1a\x1b.corebanking.v1.Transaction\x12o\n" +
+	"\x15ListTransactionsToday\x12,.corebanking.v1.ListTransactionsTodayRequest\x1a(.corebanking.v1.ListTransactionsResponse\x12s\n" +
+	"\x17ListTransactionsInRange\x12..corebanking.v1.ListTransactionsInRangeRequest\x1a(.corebanking.v1.ListTransactionsResponse\x12q\n" +
+	"\x16ListTransactionsByType\x12-.corebanking.v1.ListTransactionsByTypeRequest\x1a(.corebanking.v1.ListTransactionsResponse\x12\\\n" +
+	"\x11WatchTransactions\x12(.corebanking.v1.WatchTransactionsRequest\x1a\x1b.corebanking.v1.Transaction0\x012\xea\x01\n" +
+	"\fEventService\x12F\n" +
+	"\aDeposit\x12\x1e.corebanking.v1.DepositRequest\x1a\x1b.corebanking.v1.EventResult\x12H\n" +
+	"\bWithdraw\x12\x1f.corebanking.v1.WithdrawRequest\x1a\x1b.corebanking.v1.EventResult\x12H\n" +
+	"\bTransfer\x12\x1f.corebanking.v1.TransferRequest\x1a\x1b.corebanking.v1.EventResultB#Z!corebanking/api/gen/corebankingv1b\x06proto3"
+
+var (
+	file_api_proto_corebanking_proto_rawDescOnce sync.Once
+	file_api_proto_corebanking_proto_rawDescData []byte
+)
+
+func file_api_proto_corebanking_proto_rawDescGZIP() []byte {
+	file_api_proto_corebanking_proto_rawDescOnce.Do(func() {
+		file_api_proto_corebanking_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_corebanking_proto_rawDesc), len(file_api_proto_corebanking_proto_rawDesc)))
+	})
+	return file_api_proto_corebanking_proto_rawDescData
+}
+
+var file_api_proto_corebanking_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_api_proto_corebanking_proto_goTypes = []any{
+	(*Account)(nil),                        // 0: corebanking.v1.Account
+	(*AccountState)(nil),                   // 1: corebanking.v1.AccountState
+	(*CreateAccountRequest)(nil),           // 2: corebanking.v1.CreateAccountRequest
+	(*GetAccountRequest)(nil),              // 3: corebanking.v1.GetAccountRequest
+	(*GetBalanceRequest)(nil),              // 4: corebanking.v1.GetBalanceRequest
+	(*Balance)(nil),                        // 5: corebanking.v1.Balance
+	(*ConfigOverdraftRequest)(nil),         // 6: corebanking.v1.ConfigOverdraftRequest
+	(*ConfigOverdraftResponse)(nil),        // 7: corebanking.v1.ConfigOverdraftResponse
+	(*Transaction)(nil),                    // 8: corebanking.v1.Transaction
+	(*CreateTransactionRequest)(nil),       // 9: corebanking.v1.CreateTransactionRequest
+	(*GetTransactionRequest)(nil),          // 10: corebanking.v1.GetTransactionRequest
+	(*ListTransactionsTodayRequest)(nil),   // 11: corebanking.v1.ListTransactionsTodayRequest
+	(*ListTransactionsInRangeRequest)(nil), // 12: corebanking.v1.ListTransactionsInRangeRequest
+	(*ListTransactionsByTypeRequest)(nil),  // 13: corebanking.v1.ListTransactionsByTypeRequest
+	(*ListTransactionsResponse)(nil),       // 14: corebanking.v1.ListTransactionsResponse
+	(*WatchTransactionsRequest)(nil),       // 15: corebanking.v1.WatchTransactionsRequest
+	(*DepositRequest)(nil),                 // 16: corebanking.v1.DepositRequest
+	(*WithdrawRequest)(nil),                // 17: corebanking.v1.WithdrawRequest
+	(*TransferRequest)(nil),                // 18: corebanking.v1.TransferRequest
+	(*EventResult)(nil),                    // 19: corebanking.v1.EventResult
+	(*timestamppb.Timestamp)(nil),          // 20: google.protobuf.Timestamp
+}
+var file_api_proto_corebanking_proto_depIdxs = []int32{
+	20, // 0: corebanking.v1.Transaction.event_date:type_name -> google.protobuf.Timestamp
+	20, // 1: corebanking.v1.ListTransactionsInRangeRequest.begin:type_name -> google.protobuf.Timestamp
+	20, // 2: corebanking.v1.ListTransactionsInRangeRequest.end:type_name -> google.protobuf.Timestamp
+	8,  // 3: corebanking.v1.ListTransactionsResponse.transactions:type_name -> corebanking.v1.Transaction
+	1,  // 4: corebanking.v1.EventResult.origin:type_name -> corebanking.v1.AccountState
+	1,  // 5: corebanking.v1.EventResult.destination:type_name -> corebanking.v1.AccountState
+	2,  // 6: corebanking.v1.AccountService.CreateAccount:input_type -> corebanking.v1.CreateAccountRequest
+	3,  // 7: corebanking.v1.AccountService.GetAccount:input_type -> corebanking.v1.GetAccountRequest
+	4,  // 8: corebanking.v1.AccountService.GetBalance:input_type -> corebanking.v1.GetBalanceRequest
+	6,  // 9: corebanking.v1.AccountService.ConfigOverdraft:input_type -> corebanking.v1.ConfigOverdraftRequest
+	9,  // 10: corebanking.v1.TransactionService.CreateTransaction:input_type -> corebanking.v1.CreateTransactionRequest
+	10, // 11: corebanking.v1.TransactionService.GetTransaction:input_type -> corebanking.v1.GetTransactionRequest
+	11, // 12: corebanking.v1.TransactionService.ListTransactionsToday:input_type -> corebanking.v1.ListTransactionsTodayRequest
+	12, // 13: corebanking.v1.TransactionService.ListTransactionsInRange:input_type -> corebanking.v1.ListTransactionsInRangeRequest
+	13, // 14: corebanking.v1.TransactionService.ListTransactionsByType:input_type -> corebanking.v1.ListTransactionsByTypeRequest
+	15, // 15: corebanking.v1.TransactionService.WatchTransactions:input_type -> corebanking.v1.WatchTransactionsRequest
+	16, // 16: corebanking.v1.EventService.Deposit:input_type -> corebanking.v1.DepositRequest
+	17, // 17: corebanking.v1.EventService.Withdraw:input_type -> corebanking.v1.WithdrawRequest
+	18, // 18: corebanking.v1.EventService.Transfer:input_type -> corebanking.v1.TransferRequest
+	0,  // 19: corebanking.v1.AccountService.CreateAccount:output_type -> corebanking.v1.Account
+	0,  // 20: corebanking.v1.AccountService.GetAccount:output_type -> corebanking.v1.Account
+	5,  // 21: corebanking.v1.AccountService.GetBalance:output_type -> corebanking.v1.Balance
+	7,  // 22: corebanking.v1.AccountService.ConfigOverdraft:output_type -> corebanking.v1.ConfigOverdraftResponse
+	8,  // 23: corebanking.v1.TransactionService.CreateTransaction:output_type -> corebanking.v1.Transaction
+	8,  // 24: corebanking.v1.TransactionService.GetTransaction:output_type -> corebanking.v1.Transaction
+	14, // 25: corebanking.v1.TransactionService.ListTransactionsToday:output_type -> corebanking.v1.ListTransactionsResponse
+	14, // 26: corebanking.v1.TransactionService.ListTransactionsInRange:output_type -> corebanking.v1.ListTransactionsResponse
+	14, // 27: corebanking.v1.TransactionService.ListTransactionsByType:output_type -> corebanking.v1.ListTransactionsResponse
+	8,  // 28: corebanking.v1.TransactionService.WatchTransactions:output_type -> corebanking.v1.Transaction
+	19, // 29: corebanking.v1.EventService.Deposit:output_type -> corebanking.v1.EventResult
+	19, // 30: corebanking.v1.EventService.Withdraw:output_type -> corebanking.v1.EventResult
+	19, // 31: corebanking.v1.EventService.Transfer:output_type -> corebanking.v1.EventResult
+	19, // [19:32] is the sub-list for method output_type
+	6,  // [6:19] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_corebanking_proto_init() }
+func file_api_proto_corebanking_proto_init() {
+	if File_api_proto_corebanking_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_corebanking_proto_rawDesc), len(file_api_proto_corebanking_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_api_proto_corebanking_proto_goTypes,
+		DependencyIndexes: file_api_proto_corebanking_proto_depIdxs,
+		MessageInfos:      file_api_proto_corebanking_proto_msgTypes,
+	}.Build()
+	File_api_proto_corebanking_proto = out.File
+	file_api_proto_corebanking_proto_goTypes = nil
+	file_api_proto_corebanking_proto_depIdxs = nil
+}
@@ -1,32 +1,612 @@
 package config
 
 import (
-	"os"
+	"corebanking/internal/aml"
+	"corebanking/internal/domain"
+	"corebanking/internal/event"
+	"corebanking/internal/fraud"
+	"corebanking/internal/logging"
+	"corebanking/internal/ratelimit"
+	"corebanking/internal/sanctions"
+	"corebanking/internal/server"
+	"corebanking/internal/service"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Config is loaded in layers: the defaults below, then the config file,
+// then environment variables, then command-line flags. Each field names its
+// file key (also the flag name), its environment variable and its default.
+// Fields tagged secret are redacted by --print-config; fields tagged reload
+// are applied on SIGHUP, the rest need a restart.
 type Config struct {
-	AppName  string
-	Port     string
-	LogLevel string
-	LogPath  string
-	Version  string
+	AppName string `key:"app.name" env:"APP_NAME" default:"coreBanking"`
+
+	Port              string        `key:"server.port" env:"PORT" default:"8080"`
+	ReadTimeout       time.Duration `key:"server.read_timeout" env:"SERVER_READ_TIMEOUT" default:"15s"`
+	ReadHeaderTimeout time.Duration `key:"server.read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT" default:"5s"`
+	WriteTimeout      time.Duration `key:"server.write_timeout" env:"SERVER_WRITE_TIMEOUT" default:"30s"`
+	IdleTimeout       time.Duration `key:"server.idle_timeout" env:"SERVER_IDLE_TIMEOUT" default:"120s"`
+	MaxHeaderBytes    int           `key:"server.max_header_bytes" env:"SERVER_MAX_HEADER_BYTES" default:"65536"`
+	// ShutdownDrainDelay is how long readiness fails before the server
+	// stops accepting connections; ShutdownTimeout bounds the drain.
+	ShutdownDrainDelay time.Duration `key:"server.shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY" default:"5s"`
+	ShutdownTimeout    time.Duration `key:"server.shutdown_timeout" env:"SHUTDOWN_TIMEOUT" default:"30s"`
+	// GRPCPort serves the gRPC API next to HTTP; empty turns it off.
+	GRPCPort string `key:"server.grpc_port" env:"GRPC_PORT" default:"50051"`
+
+	// TLSCertFile and TLSKeyFile switch the server to HTTPS. They and the
+	// client CA file are re-read on SIGHUP and when they change on disk.
+	TLSCertFile     string `key:"tls.cert_file" env:"TLS_CERT_FILE"`
+	TLSKeyFile      string `key:"tls.key_file" env:"TLS_KEY_FILE"`
+	TLSClientCAFile string `key:"tls.client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	// TLSClientAuth is none, optional or require; it needs a client CA.
+	TLSClientAuth string `key:"tls.client_auth" env:"TLS_CLIENT_AUTH" default:"optional"`
+	TLSMinVersion string `key:"tls.min_version" env:"TLS_MIN_VERSION" default:"1.2"`
+	// TLSCipherSuites lists TLS 1.2 suites, comma separated.
+	TLSCipherSuites   string        `key:"tls.cipher_suites" env:"TLS_CIPHER_SUITES"`
+	TLSReloadInterval time.Duration `key:"tls.reload_interval" env:"TLS_RELOAD_INTERVAL" default:"30s"`
+
+	// Version is the API version served to requests that don't pick one.
+	Version  string `key:"api.default_version" env:"VERSION" default:"v1"`
+	V1Sunset string `key:"api.v1_sunset" env:"API_V1_SUNSET" default:"2027-06-30"`
+
+	LogLevel string `key:"log.level" env:"LOG_LEVEL" default:"DEBUG" reload:"true"`
+	LogPath  string `key:"log.path" env:"LOG_PATH" default:"log/transactions.log"`
+	// LogOutputs lists the log sinks, comma separated: stdout, file.
+	LogOutputs string `key:"log.outputs" env:"LOG_OUTPUTS" default:"stdout,file"`
+	// LogOverflow is what the file sink does when its buffer is full: block,
+	// drop-newest, drop-oldest or spill.
+	LogOverflow      string        `key:"log.overflow" env:"LOG_OVERFLOW" default:"spill"`
+	LogBufferSize    int           `key:"log.buffer_size" env:"LOG_BUFFER_SIZE" default:"100"`
+	LogSpillSize     int           `key:"log.spill_size" env:"LOG_SPILL_SIZE" default:"10000"`
+	LogFlushInterval time.Duration `key:"log.flush_interval" env:"LOG_FLUSH_INTERVAL" default:"1s"`
+	LogMaxSizeMB     int           `key:"log.max_size_mb" env:"LOG_MAX_SIZE_MB" default:"100"`
+	LogRotateDaily   bool          `key:"log.rotate_daily" env:"LOG_ROTATE_DAILY" default:"true"`
+	LogCompress      bool          `key:"log.compress" env:"LOG_COMPRESS" default:"true"`
+	LogMaxBackups    int           `key:"log.max_backups" env:"LOG_MAX_BACKUPS" default:"14"`
+	LogMaxAge        time.Duration `key:"log.max_age" env:"LOG_MAX_AGE" default:"720h"`
+
+	AuditLogPath string `key:"storage.audit_log_path" env:"AUDIT_LOG_PATH" default:"log/audit.jsonl"`
+
+	SandboxMode bool   `key:"sandbox.enabled" env:"SANDBOX_MODE" default:"false"`
+	ResetToken  string `key:"sandbox.reset_token" env:"RESET_CONFIRMATION_TOKEN" secret:"true"`
+
+	AuthEnabled      bool   `key:"auth.enabled" env:"AUTH_ENABLED" default:"true"`
+	APIKeysFile      string `key:"auth.api_keys_file" env:"API_KEYS_FILE"`
+	JWTSecret        string `key:"auth.jwt_hs256_secret" env:"JWT_HS256_SECRET" secret:"true"`
+	JWTPublicKeyFile string `key:"auth.jwt_rs256_public_key_file" env:"JWT_RS256_PUBLIC_KEY_FILE"`
+	JWKSFile         string `key:"auth.jwks_file" env:"JWT_JWKS_FILE"`
+	// ClientCertsFile maps TLS client certificates to principals.
+	ClientCertsFile string `key:"auth.client_certs_file" env:"CLIENT_CERTS_FILE"`
+	JWTIssuer       string `key:"auth.jwt_issuer" env:"JWT_ISSUER"`
+	JWTAudience     string `key:"auth.jwt_audience" env:"JWT_AUDIENCE"`
+
+	// Rate limits are "<n>/s", "<n>/m", "<n>/h" or "off". Standard limits
+	// cover every request that does not move money.
+	RateLimitEnabled         bool   `key:"ratelimit.enabled" env:"RATE_LIMIT_ENABLED" default:"true" reload:"true"`
+	RateLimitClientStandard  string `key:"ratelimit.client_standard" env:"RATE_LIMIT_CLIENT_STANDARD" default:"50/s" reload:"true"`
+	RateLimitClientMoney     string `key:"ratelimit.client_money" env:"RATE_LIMIT_CLIENT_MONEY" default:"10/s" reload:"true"`
+	RateLimitIPStandard      string `key:"ratelimit.ip_standard" env:"RATE_LIMIT_IP_STANDARD" default:"100/s" reload:"true"`
+	RateLimitIPMoney         string `key:"ratelimit.ip_money" env:"RATE_LIMIT_IP_MONEY" default:"20/s" reload:"true"`
+	RateLimitAccountStandard string `key:"ratelimit.account_standard" env:"RATE_LIMIT_ACCOUNT_STANDARD" default:"off" reload:"true"`
+	RateLimitAccountMoney    string `key:"ratelimit.account_money" env:"RATE_LIMIT_ACCOUNT_MONEY" default:"60/m" reload:"true"`
+	// RateLimitTiers replaces the client limits per tier, as comma separated
+	// <tier>.<class>=<limit> pairs, e.g. partner.money=50/s.
+	RateLimitTiers string `key:"ratelimit.tiers" env:"RATE_LIMIT_TIERS" reload:"true"`
+
+	// LimitProducts are each product's debit limits, as comma separated
+	// <product>.<limit>=<value> pairs; amounts are in minor units and a
+	// missing or zero limit is no cap. Accounts without a product of their
+	// own use LimitDefaultProduct. Night transfer caps apply between
+	// LimitNightStart and LimitNightEnd, "HH:MM" in LimitTimezone, which also
+	// sets where days and months begin.
+	LimitsEnabled       bool   `key:"limits.enabled" env:"LIMITS_ENABLED" default:"true" reload:"true"`
+	LimitDefaultProduct string `key:"limits.default_product" env:"LIMITS_DEFAULT_PRODUCT" default:"standard" reload:"true"`
+	LimitProducts       string `key:"limits.products" env:"LIMITS_PRODUCTS" default:"standard.max_transaction=500000,standard.daily_debit=1000000,standard.monthly_debit=5000000,standard.daily_withdrawals=10,standard.night_transfer=100000" reload:"true"`
+	LimitNightStart     string `key:"limits.night_start" env:"LIMITS_NIGHT_START" default:"22:00" reload:"true"`
+	LimitNightEnd       string `key:"limits.night_end" env:"LIMITS_NIGHT_END" default:"06:00" reload:"true"`
+	LimitTimezone       string `key:"limits.timezone" env:"LIMITS_TIMEZONE" default:"Local" reload:"true"`
+
+	// FraudWeights are what each fraud rule adds to a debit's score when it
+	// matches, as comma separated <rule>=<weight> pairs; a rule left out is
+	// off. Debits scoring FraudReviewScore wait for review and those scoring
+	// FraudDenyScore are declined.
+	FraudEnabled              bool          `key:"fraud.enabled" env:"FRAUD_ENABLED" default:"true" reload:"true"`
+	FraudWeights              string        `key:"fraud.weights" env:"FRAUD_WEIGHTS" default:"amount_vs_history=40,new_destinations=50,round_amount=20,new_account_drain=60" reload:"true"`
+	FraudReviewScore          int           `key:"fraud.review_score" env:"FRAUD_REVIEW_SCORE" default:"50" reload:"true"`
+	FraudDenyScore            int           `key:"fraud.deny_score" env:"FRAUD_DENY_SCORE" default:"100" reload:"true"`
+	FraudHistoryMultiplier    float64       `key:"fraud.history_multiplier" env:"FRAUD_HISTORY_MULTIPLIER" default:"5" reload:"true"`
+	FraudHistoryMinimum       int           `key:"fraud.history_minimum" env:"FRAUD_HISTORY_MINIMUM" default:"3" reload:"true"`
+	FraudNewDestinationWindow time.Duration `key:"fraud.new_destination_window" env:"FRAUD_NEW_DESTINATION_WINDOW" default:"1h" reload:"true"`
+	FraudNewDestinationCount  int           `key:"fraud.new_destination_count" env:"FRAUD_NEW_DESTINATION_COUNT" default:"3" reload:"true"`
+	// FraudRoundUnit and FraudRoundMinimum are in minor units.
+	FraudRoundUnit     int           `key:"fraud.round_unit" env:"FRAUD_ROUND_UNIT" default:"10000" reload:"true"`
+	FraudRoundMinimum  int           `key:"fraud.round_minimum" env:"FRAUD_ROUND_MINIMUM" default:"100000" reload:"true"`
+	FraudNewAccountAge time.Duration `key:"fraud.new_account_age" env:"FRAUD_NEW_ACCOUNT_AGE" default:"72h" reload:"true"`
+	FraudDrainRatio    float64       `key:"fraud.drain_ratio" env:"FRAUD_DRAIN_RATIO" default:"0.9" reload:"true"`
+
+	// AMLReportingThreshold and AMLRapidMinimum are in minor units. A rule
+	// with a zero window is off.
+	AMLEnabled            bool          `key:"aml.enabled" env:"AML_ENABLED" default:"true" reload:"true"`
+	AMLReportingThreshold int           `key:"aml.reporting_threshold" env:"AML_REPORTING_THRESHOLD" default:"1000000" reload:"true"`
+	AMLStructuringWindow  time.Duration `key:"aml.structuring_window" env:"AML_STRUCTURING_WINDOW" default:"72h" reload:"true"`
+	AMLStructuringCount   int           `key:"aml.structuring_count" env:"AML_STRUCTURING_COUNT" default:"3" reload:"true"`
+	AMLStructuringMargin  float64       `key:"aml.structuring_margin" env:"AML_STRUCTURING_MARGIN" default:"0.1" reload:"true"`
+	AMLRapidWindow        time.Duration `key:"aml.rapid_window" env:"AML_RAPID_WINDOW" default:"24h" reload:"true"`
+	AMLRapidMinimum       int           `key:"aml.rapid_minimum" env:"AML_RAPID_MINIMUM" default:"500000" reload:"true"`
+	AMLRapidRatio         float64       `key:"aml.rapid_ratio" env:"AML_RAPID_RATIO" default:"0.8" reload:"true"`
+	AMLLargeCashWindow    time.Duration `key:"aml.large_cash_window" env:"AML_LARGE_CASH_WINDOW" default:"24h" reload:"true"`
+
+	// SanctionsListPath is the sanctions and PEP list, a .json or .csv file;
+	// without one everybody screens clear. The thresholds are the name
+	// similarity, 0 to 1, at which a name matches a sanctions or PEP entry.
+	SanctionsEnabled       bool    `key:"sanctions.enabled" env:"SANCTIONS_ENABLED" default:"true" reload:"true"`
+	SanctionsListPath      string  `key:"sanctions.list_path" env:"SANCTIONS_LIST_PATH" reload:"true"`
+	SanctionsNameThreshold float64 `key:"sanctions.name_threshold" env:"SANCTIONS_NAME_THRESHOLD" default:"0.9" reload:"true"`
+	SanctionsPEPThreshold  float64 `key:"sanctions.pep_threshold" env:"SANCTIONS_PEP_THRESHOLD" default:"0.95" reload:"true"`
+
+	// SuspenseUnknownDestination is what deposits and transfers to accounts
+	// that don't exist do: reject them, or post them to the suspense
+	// account SuspenseAccountID until claimed or returned. Transfers held
+	// longer than SuspenseReturnAfter go back to their origin, checked every
+	// SuspenseSweepInterval; zero keeps them.
+	SuspenseUnknownDestination string        `key:"suspense.unknown_destination" env:"SUSPENSE_UNKNOWN_DESTINATION" default:"reject" reload:"true"`
+	SuspenseAccountID          string        `key:"suspense.account_id" env:"SUSPENSE_ACCOUNT_ID" default:"suspense"`
+	SuspenseReturnAfter        time.Duration `key:"suspense.return_after" env:"SUSPENSE_RETURN_AFTER" default:"168h" reload:"true"`
+	SuspenseSweepInterval      time.Duration `key:"suspense.sweep_interval" env:"SUSPENSE_SWEEP_INTERVAL" default:"1m"`
+
+	// SchedulePath is the file scheduled transfers are kept in; instances
+	// sharing it never run an occurrence twice. Due schedules are checked
+	// every ScheduleInterval. An occurrence declined for lack of funds is
+	// tried again ScheduleRetryAttempts times, ScheduleRetryInterval apart.
+	// An instance has ScheduleLease to run an occurrence it claimed before
+	// the others record it as interrupted. Executions are kept for
+	// ScheduleExecutionRetention, forever when zero. ScheduleInstance names
+	// this instance, the host name and process ID when empty.
+	SchedulePath               string        `key:"schedule.path" env:"SCHEDULE_PATH" default:"data/schedules.json"`
+	ScheduleInterval           time.Duration `key:"schedule.interval" env:"SCHEDULE_INTERVAL" default:"1m"`
+	ScheduleRetryAttempts      int           `key:"schedule.retry_attempts" env:"SCHEDULE_RETRY_ATTEMPTS" default:"3" reload:"true"`
+	ScheduleRetryInterval      time.Duration `key:"schedule.retry_interval" env:"SCHEDULE_RETRY_INTERVAL" default:"1h" reload:"true"`
+	ScheduleLease              time.Duration `key:"schedule.lease" env:"SCHEDULE_LEASE" default:"5m" reload:"true"`
+	ScheduleExecutionRetention time.Duration `key:"schedule.execution_retention" env:"SCHEDULE_EXECUTION_RETENTION" default:"2160h" reload:"true"`
+	ScheduleInstance           string        `key:"schedule.instance" env:"SCHEDULE_INSTANCE"`
+
+	// BatchMaxItems caps the items of a batch. Batches above
+	// BatchAsyncThreshold items are processed in the background, at most
+	// BatchQueueSize waiting at once; BatchWorkers posts best-effort items
+	// in parallel. Processed batches can be read back for BatchRetention.
+	BatchMaxItems       int           `key:"batch.max_items" env:"BATCH_MAX_ITEMS" default:"10000"`
+	BatchAsyncThreshold int           `key:"batch.async_threshold" env:"BATCH_ASYNC_THRESHOLD" default:"500"`
+	BatchQueueSize      int           `key:"batch.queue_size" env:"BATCH_QUEUE_SIZE" default:"16"`
+	BatchWorkers        int           `key:"batch.workers" env:"BATCH_WORKERS" default:"8"`
+	BatchRetention      time.Duration `key:"batch.retention" env:"BATCH_RETENTION" default:"24h"`
+
+	// TraceExporter is where spans go: none, stdout, file or otlp.
+	TraceExporter string `key:"tracing.exporter" env:"TRACE_EXPORTER" default:"none"`
+	TracePath     string `key:"tracing.path" env:"TRACE_PATH" default:"log/traces.jsonl"`
+	// TraceOTLPEndpoint is the collector's OTLP/HTTP traces URL.
+	TraceOTLPEndpoint string `key:"tracing.otlp_endpoint" env:"TRACE_OTLP_ENDPOINT" default:"http://localhost:4318/v1/traces"`
+	// TraceOTLPHeaders are extra request headers, as key=value pairs
+	// separated by commas. They usually carry credentials.
+	TraceOTLPHeaders   string        `key:"tracing.otlp_headers" env:"TRACE_OTLP_HEADERS" secret:"true"`
+	TraceSampleRatio   float64       `key:"tracing.sample_ratio" env:"TRACE_SAMPLE_RATIO" default:"1"`
+	TraceBatchInterval time.Duration `key:"tracing.batch_interval" env:"TRACE_BATCH_INTERVAL" default:"5s"`
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+		}
+	}
+
+	port, err := strconv.Atoi(c.Port)
+	check(err == nil && port > 0 && port < 65536, "server.port", "must be a port number, got %q", c.Port)
+	if c.GRPCPort != "" {
+		grpcPort, err := strconv.Atoi(c.GRPCPort)
+		check(err == nil && grpcPort > 0 && grpcPort < 65536, "server.grpc_port", "must be a port number, got %q", c.GRPCPort)
+		check(c.GRPCPort != c.Port, "server.grpc_port", "must differ from server.port")
+	}
+	for _, f := range fields {
+		if f.typ == durationType {
+			check(f.get(c).(time.Duration) >= 0, f.key, "must not be negative")
+		}
+	}
+	check(c.MaxHeaderBytes > 0, "server.max_header_bytes", "must be positive")
+
+	check((c.TLSCertFile == "") == (c.TLSKeyFile == ""), "tls.key_file", "must be set together with tls.cert_file")
+	check(c.TLSClientCAFile == "" || c.TLSCertFile != "", "tls.client_ca_file", "needs tls.cert_file")
+	switch c.TLSClientAuth {
+	case server.ClientAuthNone, server.ClientAuthOptional:
+	case server.ClientAuthRequire:
+		check(c.TLSClientCAFile != "", "tls.client_auth", "require needs tls.client_ca_file")
+	default:
+		check(false, "tls.client_auth", "must be none, optional or require, got %q", c.TLSClientAuth)
+	}
+	_, err = server.ParseTLSVersion(c.TLSMinVersion)
+	check(err == nil, "tls.min_version", "must be 1.2 or 1.3, got %q", c.TLSMinVersion)
+	_, err = server.ParseCipherSuites(c.CipherSuites())
+	check(err == nil, "tls.cipher_suites", "%v", err)
+	check(c.TLSReloadInterval > 0, "tls.reload_interval", "must be positive")
+	check(c.ClientCertsFile == "" || c.TLSClientCAFile != "", "auth.client_certs_file", "needs tls.client_ca_file")
+
+	check(c.Version == "v1" || c.Version == "v2", "api.default_version", "must be v1 or v2, got %q", c.Version)
+	_, err = time.Parse("2006-01-02", c.V1Sunset)
+	check(err == nil, "api.v1_sunset", "must be a YYYY-MM-DD date, got %q", c.V1Sunset)
+
+	_, err = logging.ParseLevel(c.LogLevel)
+	check(err == nil, "log.level", "must be DEBUG, INFO, WARN or ERROR, got %q", c.LogLevel)
+	for _, output := range strings.Split(c.LogOutputs, ",") {
+		output = strings.TrimSpace(output)
+		check(output == "stdout" || output == "file", "log.outputs", "unknown sink %q", output)
+	}
+	_, err = event.ParseOverflowPolicy(c.LogOverflow)
+	check(err == nil, "log.overflow", "must be block, drop-newest, drop-oldest or spill, got %q", c.LogOverflow)
+	check(c.LogBufferSize > 0, "log.buffer_size", "must be positive")
+	check(c.LogSpillSize >= 0, "log.spill_size", "must not be negative")
+	check(c.LogMaxSizeMB >= 0, "log.max_size_mb", "must not be negative")
+	check(c.LogMaxBackups >= 0, "log.max_backups", "must not be negative")
+	check(c.AuditLogPath != "", "storage.audit_log_path", "must be set")
+
+	if _, err := c.RateLimitRules(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := c.LimitPolicy(); err != nil {
+		errs = append(errs, err)
+	}
+	_, err = fraud.ParseWeights(c.FraudWeights)
+	check(err == nil, "fraud.weights", "%v", err)
+	check(c.FraudReviewScore > 0, "fraud.review_score", "must be positive")
+	check(c.FraudDenyScore == 0 || c.FraudDenyScore >= c.FraudReviewScore, "fraud.deny_score", "must be 0 (never deny) or at least fraud.review_score")
+	check(c.FraudHistoryMultiplier > 1, "fraud.history_multiplier", "must be greater than 1")
+	check(c.FraudHistoryMinimum > 0, "fraud.history_minimum", "must be positive")
+	check(c.FraudNewDestinationCount > 0, "fraud.new_destination_count", "must be positive")
+	check(c.FraudRoundUnit > 0, "fraud.round_unit", "must be positive")
+	check(c.FraudRoundMinimum >= 0, "fraud.round_minimum", "must not be negative")
+	check(c.FraudDrainRatio > 0 && c.FraudDrainRatio <= 1, "fraud.drain_ratio", "must be above 0 and at most 1")
+	check(c.AMLReportingThreshold > 0, "aml.reporting_threshold", "must be positive")
+	check(c.AMLStructuringWindow >= 0, "aml.structuring_window", "must not be negative")
+	check(c.AMLStructuringCount > 1, "aml.structuring_count", "must be at least 2")
+	check(c.AMLStructuringMargin > 0 && c.AMLStructuringMargin < 1, "aml.structuring_margin", "must be above 0 and below 1")
+	check(c.AMLRapidWindow >= 0, "aml.rapid_window", "must not be negative")
+	check(c.AMLRapidMinimum >= 0, "aml.rapid_minimum", "must not be negative")
+	check(c.AMLRapidRatio > 0 && c.AMLRapidRatio <= 1, "aml.rapid_ratio", "must be above 0 and at most 1")
+	check(c.AMLLargeCashWindow >= 0, "aml.large_cash_window", "must not be negative")
+	check(c.SanctionsNameThreshold > 0 && c.SanctionsNameThreshold <= 1, "sanctions.name_threshold", "must be above 0 and at most 1")
+	check(c.SanctionsPEPThreshold > 0 && c.SanctionsPEPThreshold <= 1, "sanctions.pep_threshold", "must be above 0 and at most 1")
+
+	check(c.SuspenseUnknownDestination == domain.UnknownDestinationReject || c.SuspenseUnknownDestination == domain.UnknownDestinationSuspense,
+		"suspense.unknown_destination", "must be reject or suspense, got %q", c.SuspenseUnknownDestination)
+	check(strings.TrimSpace(c.SuspenseAccountID) != "", "suspense.account_id", "must be set")
+	check(c.SuspenseSweepInterval > 0, "suspense.sweep_interval", "must be positive")
+	check(c.SchedulePath != "", "schedule.path", "must be set")
+	check(c.ScheduleInterval > 0, "schedule.interval", "must be positive")
+	check(c.ScheduleRetryAttempts >= 0, "schedule.retry_attempts", "must not be negative")
+	check(c.ScheduleRetryAttempts == 0 || c.ScheduleRetryInterval > 0, "schedule.retry_interval", "must be positive when retrying")
+	check(c.ScheduleLease > 0, "schedule.lease", "must be positive")
+	check(c.ScheduleExecutionRetention >= 0, "schedule.execution_retention", "must not be negative")
+	check(c.BatchMaxItems > 0, "batch.max_items", "must be positive")
+	check(c.BatchAsyncThreshold >= 0, "batch.async_threshold", "must not be negative")
+	check(c.BatchQueueSize >= 0, "batch.queue_size", "must not be negative")
+	check(c.BatchWorkers > 0, "batch.workers", "must be positive")
+	check(c.BatchRetention > 0, "batch.retention", "must be positive")
+
+	switch c.TraceExporter {
+	case "none", "stdout", "file", "otlp":
+	default:
+		check(false, "tracing.exporter", "must be none, stdout, file or otlp, got %q", c.TraceExporter)
+	}
+	if c.TraceExporter == "otlp" {
+		endpoint, err := url.Parse(c.TraceOTLPEndpoint)
+		check(err == nil && (endpoint.Scheme == "http" || endpoint.Scheme == "https") && endpoint.Host != "",
+			"tracing.otlp_endpoint", "must be an http(s) URL, got %q", c.TraceOTLPEndpoint)
+	}
+	check(c.TraceSampleRatio >= 0 && c.TraceSampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+	check(c.TraceBatchInterval > 0, "tracing.batch_interval", "must be positive")
+
+	return errors.Join(errs...)
+}
+
+// RateLimitRules parses the rate limits; disabled limiting is a zero Rules,
+// which limits nothing.
+func (c *Config) RateLimitRules() (ratelimit.Rules, error) {
+	if !c.RateLimitEnabled {
+		return ratelimit.Rules{}, nil
+	}
+
+	var rules ratelimit.Rules
+	var errs []error
+	for _, limit := range []struct {
+		key    string
+		value  string
+		target *ratelimit.Limit
+	}{
+		{"ratelimit.client_standard", c.RateLimitClientStandard, &rules.Standard.Client},
+		{"ratelimit.client_money", c.RateLimitClientMoney, &rules.Money.Client},
+		{"ratelimit.ip_standard", c.RateLimitIPStandard, &rules.Standard.IP},
+		{"ratelimit.ip_money", c.RateLimitIPMoney, &rules.Money.IP},
+		{"ratelimit.account_standard", c.RateLimitAccountStandard, &rules.Standard.Account},
+		{"ratelimit.account_money", c.RateLimitAccountMoney, &rules.Money.Account},
+	} {
+		parsed, err := ratelimit.ParseLimit(limit.value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", limit.key, err))
+		}
+		*limit.target = parsed
+	}
+	tiers, err := ratelimit.ParseTiers(c.RateLimitTiers)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("ratelimit.tiers: %w", err))
+	}
+	rules.Tiers = tiers
+	return rules, errors.Join(errs...)
+}
+
+// LimitPolicy parses the transaction limits; disabled limits are a zero
+// LimitPolicy, which has no products and so caps nothing.
+func (c *Config) LimitPolicy() (service.LimitPolicy, error) {
+	if !c.LimitsEnabled {
+		return service.LimitPolicy{}, nil
+	}
+
+	var errs []error
+	products, err := domain.ParseProductLimits(c.LimitProducts)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("limits.products: %w", err))
+	} else if _, ok := products[c.LimitDefaultProduct]; !ok {
+		errs = append(errs, fmt.Errorf("limits.default_product: %q is not one of limits.products %v", c.LimitDefaultProduct, domain.ProductNames(products)))
+	}
+	start, err := parseClock(c.LimitNightStart)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("limits.night_start: %w", err))
+	}
+	end, err := parseClock(c.LimitNightEnd)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("limits.night_end: %w", err))
+	}
+	location, err := time.LoadLocation(c.LimitTimezone)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("limits.timezone: unknown time zone %q", c.LimitTimezone))
+	}
+
+	return service.LimitPolicy{
+		Products:       products,
+		DefaultProduct: c.LimitDefaultProduct,
+		NightStart:     start,
+		NightEnd:       end,
+		Location:       location,
+	}, errors.Join(errs...)
+}
+
+// FraudRules builds the fraud rules; disabled screening is a zero
+// fraud.Rules, which scores every debit zero and so allows it.
+func (c *Config) FraudRules() fraud.Rules {
+	if !c.FraudEnabled {
+		return fraud.Rules{}
+	}
+	weights, _ := fraud.ParseWeights(c.FraudWeights)
+	return fraud.Rules{
+		Weights:              weights,
+		ReviewScore:          c.FraudReviewScore,
+		DenyScore:            c.FraudDenyScore,
+		HistoryMultiplier:    c.FraudHistoryMultiplier,
+		HistoryMinimum:       c.FraudHistoryMinimum,
+		NewDestinationWindow: c.FraudNewDestinationWindow,
+		NewDestinationCount:  c.FraudNewDestinationCount,
+		RoundUnit:            int64(c.FraudRoundUnit),
+		RoundMinimum:         int64(c.FraudRoundMinimum),
+		NewAccountAge:        c.FraudNewAccountAge,
+		DrainRatio:           c.FraudDrainRatio,
+	}
+}
+
+// AMLRules builds the AML monitoring rules; disabled monitoring is a zero
+// aml.Rules, whose rules are all off.
+func (c *Config) AMLRules() aml.Rules {
+	if !c.AMLEnabled {
+		return aml.Rules{}
+	}
+	return aml.Rules{
+		ReportingThreshold: int64(c.AMLReportingThreshold),
+		StructuringWindow:  c.AMLStructuringWindow,
+		StructuringCount:   c.AMLStructuringCount,
+		StructuringMargin:  c.AMLStructuringMargin,
+		RapidWindow:        c.AMLRapidWindow,
+		RapidMinimum:       int64(c.AMLRapidMinimum),
+		RapidRatio:         c.AMLRapidRatio,
+		LargeCashWindow:    c.AMLLargeCashWindow,
+	}
+}
+
+// SanctionsPolicy builds the screening policy; the list itself is loaded
+// from SanctionsListPath by the sanctions service.
+func (c *Config) SanctionsPolicy() sanctions.Policy {
+	return sanctions.Policy{
+		Enabled:       c.SanctionsEnabled,
+		NameThreshold: c.SanctionsNameThreshold,
+		PEPThreshold:  c.SanctionsPEPThreshold,
+	}
+}
+
+// SuspensePolicy builds what deposits and transfers to unknown accounts do.
+func (c *Config) SuspensePolicy() service.SuspensePolicy {
+	return service.SuspensePolicy{
+		UnknownDestination: c.SuspenseUnknownDestination,
+		ReturnAfter:        c.SuspenseReturnAfter,
+	}
+}
+
+// SchedulePolicy builds how the scheduler runs due transfers.
+func (c *Config) SchedulePolicy() service.SchedulePolicy {
+	return service.SchedulePolicy{
+		RetryAttempts:      c.ScheduleRetryAttempts,
+		RetryInterval:      c.ScheduleRetryInterval,
+		Lease:              c.ScheduleLease,
+		ExecutionRetention: c.ScheduleExecutionRetention,
+	}
+}
+
+// BatchPolicy builds how batches are taken and processed.
+func (c *Config) BatchPolicy() service.BatchPolicy {
+	return service.BatchPolicy{
+		MaxItems:       c.BatchMaxItems,
+		AsyncThreshold: c.BatchAsyncThreshold,
+		QueueSize:      c.BatchQueueSize,
+		Workers:        c.BatchWorkers,
+		Retention:      c.BatchRetention,
+	}
+}
+
+// parseClock reads an "HH:MM" time of day as an offset from midnight.
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("must be a HH:MM time of day, got %q", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// CipherSuites splits TLSCipherSuites.
+func (c *Config) CipherSuites() []string {
+	if strings.TrimSpace(c.TLSCipherSuites) == "" {
+		return nil
+	}
+	return strings.Split(c.TLSCipherSuites, ",")
+}
+
+// field is one tagged Config field.
+type field struct {
+	key    string
+	env    string
+	def    string
+	secret bool
+	reload bool
+	index  int
+	typ    reflect.Type
 }
 
-func LoadConfig() *Config {
-	cfg := &Config{
-		AppName:  getEnv("APP_NAME", "coreBanking"),
-		Port:     getEnv("PORT", "8080"),
-		LogLevel: getEnv("LOG_LEVEL", "DEBUG"),
-		LogPath:  getEnv("LOG_PATH", "log/transactions.log"),
-		Version:  getEnv("VERSION", "v1"),
+var fields = configFields()
+
+func configFields() []field {
+	t := reflect.TypeOf(Config{})
+	result := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		result = append(result, field{
+			key:    f.Tag.Get("key"),
+			env:    f.Tag.Get("env"),
+			def:    f.Tag.Get("default"),
+			secret: f.Tag.Get("secret") == "true",
+			reload: f.Tag.Get("reload") == "true",
+			index:  i,
+			typ:    f.Type,
+		})
 	}
+	return result
+}
 
-	return cfg
+func fieldByKey(key string) (field, bool) {
+	for _, f := range fields {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// set parses value into the field of c.
+func (f field) set(c *Config, value string) error {
+	target := reflect.ValueOf(c).Elem().Field(f.index)
+	value = strings.TrimSpace(value)
+
+	if f.typ == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q", f.key, value)
+		}
+		target.SetInt(int64(d))
+		return nil
+	}
+	switch f.typ.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %q", f.key, value)
+		}
+		target.SetInt(int64(n))
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", f.key, value)
+		}
+		target.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q", f.key, value)
+		}
+		target.SetBool(b)
+	default:
+		return fmt.Errorf("%s: unsupported type %s", f.key, f.typ)
+	}
+	return nil
+}
+
+func (f field) get(c *Config) any {
+	return reflect.ValueOf(c).Elem().Field(f.index).Interface()
+}
+
+// Redacted returns the settings grouped by section, with secrets that are
+// set replaced by "[REDACTED]".
+func (c *Config) Redacted() map[string]map[string]any {
+	sections := make(map[string]map[string]any)
+	for _, f := range fields {
+		section, name, _ := strings.Cut(f.key, ".")
+		if sections[section] == nil {
+			sections[section] = make(map[string]any)
+		}
+		value := f.get(c)
+		switch v := value.(type) {
+		case time.Duration:
+			value = v.String()
+		case string:
+			if f.secret && v != "" {
+				value = "[REDACTED]"
+			}
+		}
+		sections[section][name] = value
+	}
+	return sections
 }
 
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// Reload takes the reloadable settings from next and keeps the rest of
+// current. It returns the merged config, the reloadable keys that changed,
+// and the changed keys that only a restart applies.
+func Reload(current, next *Config) (merged *Config, applied, ignored []string) {
+	copied := *current
+	merged = &copied
+	for _, f := range fields {
+		if reflect.DeepEqual(f.get(current), f.get(next)) {
+			continue
+		}
+		if !f.reload {
+			ignored = append(ignored, f.key)
+			continue
+		}
+		reflect.ValueOf(merged).Elem().Field(f.index).Set(reflect.ValueOf(next).Elem().Field(f.index))
+		applied = append(applied, f.key)
 	}
-	return defaultValue
+	return merged, applied, ignored
 }
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Loader reads the config layers. Load can be called again to reload: the
+// file and environment are read afresh and the same flags reapplied.
+type Loader struct {
+	args      []string
+	lookupEnv func(string) (string, bool)
+
+	// File is the config file from --config or CONFIG_FILE; empty means
+	// none.
+	File string
+	// PrintConfig is set by --print-config.
+	PrintConfig bool
+}
+
+// NewLoader reads args, usually os.Args[1:], and the process environment.
+func NewLoader(args []string) *Loader {
+	return NewLoaderWithEnv(args, os.LookupEnv)
+}
+
+// NewLoaderWithEnv reads the environment through lookupEnv.
+func NewLoaderWithEnv(args []string, lookupEnv func(string) (string, bool)) *Loader {
+	return &Loader{args: args, lookupEnv: lookupEnv}
+}
+
+// Load applies defaults, the file, the environment and flags, in that
+// order, and validates the result.
+func (l *Loader) Load() (*Config, error) {
+	overrides, err := l.parseFlags()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	for _, f := range fields {
+		if f.def == "" {
+			continue
+		}
+		if err := f.set(cfg, f.def); err != nil {
+			return nil, fmt.Errorf("default %w", err)
+		}
+	}
+
+	if l.File != "" {
+		values, err := readFile(l.File)
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: %w", l.File, err)
+		}
+		if err := apply(cfg, values); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", l.File, err)
+		}
+	}
+
+	for _, f := range fields {
+		if value, ok := l.lookupEnv(f.env); ok {
+			if err := f.set(cfg, value); err != nil {
+				return nil, fmt.Errorf("env %s: %w", f.env, err)
+			}
+		}
+	}
+
+	if err := apply(cfg, overrides); err != nil {
+		return nil, fmt.Errorf("flag %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// parseFlags returns the flags given for config keys and records --config
+// and --print-config.
+func (l *Loader) parseFlags() (map[string]string, error) {
+	fs := flag.NewFlagSet("corebanking", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	file, _ := l.lookupEnv("CONFIG_FILE")
+	fs.StringVar(&file, "config", file, "config file, JSON or YAML")
+	printConfig := fs.Bool("print-config", false, "print the effective config with secrets redacted and exit")
+
+	overrides := make(map[string]string)
+	for _, f := range fields {
+		key := f.key
+		fs.Func(key, "overrides "+f.env, func(value string) error {
+			overrides[key] = value
+			return nil
+		})
+	}
+
+	if err := fs.Parse(l.args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	l.File = file
+	l.PrintConfig = *printConfig
+	return overrides, nil
+}
+
+func apply(cfg *Config, values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		f, ok := fieldByKey(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown setting %q", key))
+			continue
+		}
+		if err := f.set(cfg, values[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readFile flattens a JSON or YAML file into dotted keys such as
+// server.port. YAML is read when the extension is .yaml or .yml.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseYAML(data)
+	default:
+		return parseJSON(data)
+	}
+}
+
+func parseJSON(data []byte) (map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree map[string]any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	return values, flatten("", tree, values)
+}
+
+func flatten(prefix string, tree map[string]any, values map[string]string) error {
+	for name, value := range tree {
+		key := prefix + name
+		switch v := value.(type) {
+		case map[string]any:
+			if err := flatten(key+".", v, values); err != nil {
+				return err
+			}
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// parseYAML reads the subset of YAML a config needs: nested mappings by
+// indentation, scalar values, quoted strings, flow lists like [a, b] and
+// comments. Anchors, block lists and multi-line strings are rejected.
+func parseYAML(data []byte) (map[string]string, error) {
+	type level struct {
+		indent int
+		prefix string
+	}
+	values := make(map[string]string)
+	stack := []level{{indent: 0}}
+	// pending is a key whose value is a nested mapping still to come.
+	pending := ""
+	pendingIndent := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := stripComment(scanner.Text())
+		if strings.TrimSpace(line) == "" || strings.TrimSpace(line) == "---" {
+			continue
+		}
+		indentation := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if strings.Contains(indentation, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNo)
+		}
+
+		indent := len(indentation)
+		content := strings.TrimSpace(line)
+		if strings.HasPrefix(content, "- ") || content == "-" {
+			return nil, fmt.Errorf("line %d: block lists are not supported, use [a, b]", lineNo)
+		}
+
+		if pending != "" {
+			if indent > pendingIndent {
+				stack = append(stack, level{indent: indent, prefix: pending + "."})
+			} else {
+				values[pending] = ""
+			}
+			pending = ""
+		}
+		for len(stack) > 1 && indent < stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		if indent != stack[len(stack)-1].indent {
+			return nil, fmt.Errorf("line %d: inconsistent indentation", lineNo)
+		}
+
+		name, value, ok := strings.Cut(content, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", lineNo)
+		}
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		key := stack[len(stack)-1].prefix + name
+		if name == "" {
+			return nil, fmt.Errorf("line %d: empty key", lineNo)
+		}
+
+		if value == "" {
+			pending = key
+			pendingIndent = indent
+			continue
+		}
+		scalar, err := yamlScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		values[key] = scalar
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if pending != "" {
+		values[pending] = ""
+	}
+	return values, nil
+}
+
+func yamlScalar(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("bad quoted string %s", value)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("bad quoted string %s", value)
+		}
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	case strings.HasPrefix(value, "["):
+		if !strings.HasSuffix(value, "]") {
+			return "", fmt.Errorf("bad list %s", value)
+		}
+		var items []string
+		for _, item := range strings.Split(value[1:len(value)-1], ",") {
+			item, err := yamlScalar(strings.TrimSpace(item))
+			if err != nil {
+				return "", err
+			}
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		return strings.Join(items, ","), nil
+	case strings.HasPrefix(value, "&"), strings.HasPrefix(value, "*"), value == "|", value == ">":
+		return "", fmt.Errorf("unsupported YAML %s", value)
+	case value == "~" || value == "null":
+		return "", nil
+	default:
+		return value, nil
+	}
+}
+
+// stripComment drops a # comment that is outside quotes.
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' '):
+			return line[:i]
+		}
+	}
+	return line
+}
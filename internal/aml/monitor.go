@@ -0,0 +1,190 @@
+// Package aml looks for money laundering patterns in an account's recent
+// movements: structuring, rapid movement in and out, and large cash
+// deposits.
+package aml
+
+import (
+	"corebanking/internal/domain"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Rule names, used in alerts.
+const (
+	RuleStructuring = "structuring"
+	RuleRapidInOut  = "rapid_in_out"
+	RuleLargeCash   = "large_cash"
+)
+
+// Rules are the monitoring thresholds. Amounts are in minor units; a rule
+// with a zero window is off.
+type Rules struct {
+	// ReportingThreshold is the cash amount that has to be reported.
+	ReportingThreshold int64
+
+	// structuring matches StructuringCount or more cash deposits within
+	// StructuringWindow that each fall short of the threshold by at most
+	// StructuringMargin of it.
+	StructuringWindow time.Duration
+	StructuringCount  int
+	StructuringMargin float64
+
+	// rapid_in_out matches RapidMinimum or more credited within RapidWindow
+	// of which RapidRatio or more is debited again after the first credit.
+	RapidWindow  time.Duration
+	RapidMinimum int64
+	RapidRatio   float64
+
+	// large_cash matches cash deposits adding up to the threshold within
+	// LargeCashWindow, a single large deposit included.
+	LargeCashWindow time.Duration
+}
+
+// Retention is the longest window, how far back history must go.
+func (r Rules) Retention() time.Duration {
+	return max(r.StructuringWindow, r.RapidWindow, r.LargeCashWindow)
+}
+
+// Finding is a rule matched by the movements between From and To, the
+// first and last that matched.
+type Finding struct {
+	Rule    string
+	Amount  int64
+	Count   int
+	From    time.Time
+	To      time.Time
+	Details string
+}
+
+// Monitor evaluates histories against rules that can be replaced while
+// serving.
+type Monitor struct {
+	rules atomic.Pointer[Rules]
+}
+
+func NewMonitor(rules Rules) *Monitor {
+	m := &Monitor{}
+	m.SetRules(rules)
+	return m
+}
+
+func (m *Monitor) SetRules(rules Rules) {
+	m.rules.Store(&rules)
+}
+
+func (m *Monitor) Rules() Rules {
+	return *m.rules.Load()
+}
+
+// Evaluate returns the rules the history matches at now, each looking at
+// its own window ending at now.
+func (m *Monitor) Evaluate(history domain.AMLHistory, now time.Time) []Finding {
+	rules := m.rules.Load()
+	var findings []Finding
+	for _, match := range []func(*Rules, []domain.AMLMovement, time.Time) (Finding, bool){
+		matchStructuring, matchRapidInOut, matchLargeCash,
+	} {
+		if finding, ok := match(rules, history.Movements, now); ok {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// within returns the movements in the window ending at now.
+func within(movements []domain.AMLMovement, window time.Duration, now time.Time) []domain.AMLMovement {
+	since := now.Add(-window)
+	for i, movement := range movements {
+		if !movement.At.Before(since) {
+			return movements[i:]
+		}
+	}
+	return nil
+}
+
+func matchStructuring(r *Rules, movements []domain.AMLMovement, now time.Time) (Finding, bool) {
+	if r.StructuringWindow <= 0 || r.ReportingThreshold <= 0 || r.StructuringCount <= 0 {
+		return Finding{}, false
+	}
+	floor := r.ReportingThreshold - int64(r.StructuringMargin*float64(r.ReportingThreshold))
+
+	finding := Finding{Rule: RuleStructuring}
+	for _, movement := range within(movements, r.StructuringWindow, now) {
+		if movement.Kind != domain.MovementCashDeposit || movement.Amount < floor || movement.Amount >= r.ReportingThreshold {
+			continue
+		}
+		if finding.Count == 0 {
+			finding.From = movement.At
+		}
+		finding.To = movement.At
+		finding.Count++
+		finding.Amount += movement.Amount
+	}
+	if finding.Count < r.StructuringCount {
+		return Finding{}, false
+	}
+	finding.Details = fmt.Sprintf("%d cash deposits between %s and %s within %s, %s in total",
+		finding.Count, formatAmount(floor), formatAmount(r.ReportingThreshold), r.StructuringWindow, formatAmount(finding.Amount))
+	return finding, true
+}
+
+func matchRapidInOut(r *Rules, movements []domain.AMLMovement, now time.Time) (Finding, bool) {
+	if r.RapidWindow <= 0 || r.RapidRatio <= 0 {
+		return Finding{}, false
+	}
+
+	finding := Finding{Rule: RuleRapidInOut}
+	var in, out int64
+	for _, movement := range within(movements, r.RapidWindow, now) {
+		switch {
+		case movement.IsCredit():
+			if in == 0 {
+				finding.From = movement.At
+			}
+			in += movement.Amount
+		case in > 0:
+			out += movement.Amount
+		default:
+			continue
+		}
+		finding.To = movement.At
+		finding.Count++
+	}
+	if in == 0 || in < r.RapidMinimum || float64(out) < r.RapidRatio*float64(in) {
+		return Finding{}, false
+	}
+	finding.Amount = out
+	finding.Details = fmt.Sprintf("%s in and %s out within %s", formatAmount(in), formatAmount(out), r.RapidWindow)
+	return finding, true
+}
+
+func matchLargeCash(r *Rules, movements []domain.AMLMovement, now time.Time) (Finding, bool) {
+	if r.LargeCashWindow <= 0 || r.ReportingThreshold <= 0 {
+		return Finding{}, false
+	}
+
+	finding := Finding{Rule: RuleLargeCash}
+	for _, movement := range within(movements, r.LargeCashWindow, now) {
+		if movement.Kind != domain.MovementCashDeposit {
+			continue
+		}
+		if finding.Count == 0 {
+			finding.From = movement.At
+		}
+		finding.To = movement.At
+		finding.Count++
+		finding.Amount += movement.Amount
+	}
+	if finding.Amount < r.ReportingThreshold {
+		return Finding{}, false
+	}
+	finding.Details = fmt.Sprintf("%s in %d cash deposits within %s, reporting threshold %s",
+		formatAmount(finding.Amount), finding.Count, r.LargeCashWindow, formatAmount(r.ReportingThreshold))
+	return finding, true
+}
+
+// formatAmount writes minor units as a decimal amount.
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
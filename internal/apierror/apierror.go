@@ -0,0 +1,77 @@
+// Package apierror maps service errors to the error codes the HTTP and
+// gRPC APIs answer with.
+package apierror
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/service"
+	"errors"
+	"net/http"
+)
+
+// Status maps a service error to the HTTP status and error code the API
+// answers with. The gRPC server derives its status codes from the same
+// error codes.
+func Status(err error) (int, string) {
+	switch {
+	case errors.Is(err, auth.ErrMissingCredentials),
+		errors.Is(err, auth.ErrInvalidCredentials):
+		return http.StatusUnauthorized, "unauthorized"
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden, "forbidden"
+	case errors.Is(err, service.ErrAccountNotFound),
+		errors.Is(err, service.ErrOriginNotFound),
+		errors.Is(err, service.ErrDestinationNotFound),
+		errors.Is(err, service.ErrTransactionNotFound),
+		errors.Is(err, service.ErrAPIKeyNotFound),
+		errors.Is(err, service.ErrFraudCaseNotFound),
+		errors.Is(err, service.ErrAMLAlertNotFound),
+		errors.Is(err, service.ErrSanctionsCaseNotFound),
+		errors.Is(err, service.ErrSuspenseItemNotFound),
+		errors.Is(err, service.ErrScheduleNotFound),
+		errors.Is(err, service.ErrBatchNotFound):
+		return http.StatusNotFound, "not_found"
+	case errors.Is(err, service.ErrResetDisabled):
+		return http.StatusForbidden, "reset_disabled"
+	case errors.Is(err, service.ErrResetNotConfirmed):
+		return http.StatusPreconditionRequired, "confirmation_required"
+	case errors.Is(err, service.ErrDocumentAlreadyExists),
+		errors.Is(err, service.ErrFraudCaseDecided),
+		errors.Is(err, service.ErrAMLAlertClosed),
+		errors.Is(err, service.ErrSanctionsCaseDecided),
+		errors.Is(err, service.ErrSuspenseItemResolved),
+		errors.Is(err, service.ErrScheduleFinished):
+		return http.StatusConflict, "conflict"
+	case errors.Is(err, service.ErrInsufficientFunds),
+		errors.Is(err, service.ErrInsufficientOverdraft):
+		return http.StatusUnprocessableEntity, "insufficient_funds"
+	case errors.Is(err, service.ErrLimitExceeded):
+		return http.StatusUnprocessableEntity, "limit_exceeded"
+	case errors.Is(err, service.ErrFraudReview):
+		// The posting is not refused: it waits in the case queue.
+		return http.StatusAccepted, "under_review"
+	case errors.Is(err, service.ErrSanctionsReview):
+		// Like fraud holds, the request waits in a case queue.
+		return http.StatusAccepted, "sanctions_review"
+	case errors.Is(err, service.ErrHeldInSuspense):
+		// The money left the origin and waits in the suspense account.
+		return http.StatusAccepted, "held_in_suspense"
+	case errors.Is(err, service.ErrFraudDenied):
+		return http.StatusUnprocessableEntity, "fraud_declined"
+	case errors.Is(err, service.ErrUnknownProduct),
+		errors.Is(err, service.ErrInvalidLimits),
+		errors.Is(err, service.ErrInvalidDisposition),
+		errors.Is(err, service.ErrInvalidSchedule),
+		errors.Is(err, service.ErrInvalidBatch):
+		return http.StatusBadRequest, "invalid_request"
+	case errors.Is(err, service.ErrInvalidEventType),
+		errors.Is(err, service.ErrInvalidOperationType),
+		errors.Is(err, service.ErrInvalidAmount):
+		return http.StatusBadRequest, "invalid_request"
+	case errors.Is(err, service.ErrBatchQueueFull),
+		errors.Is(err, service.ErrWatchLagged):
+		return http.StatusServiceUnavailable, "busy"
+	default:
+		return http.StatusInternalServerError, "internal_error"
+	}
+}
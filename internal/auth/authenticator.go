@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type APIKeyVerifier interface {
+	VerifyAPIKey(raw string) (*Principal, error)
+}
+
+// Authenticator resolves the caller from an X-API-Key header, an
+// Authorization: Bearer token or, when neither is sent, a verified TLS
+// client certificate.
+type Authenticator struct {
+	keys  APIKeyVerifier
+	jwt   *JWTVerifier
+	certs *ClientCertMapper
+}
+
+func NewAuthenticator(keys APIKeyVerifier, jwt *JWTVerifier) *Authenticator {
+	return &Authenticator{keys: keys, jwt: jwt}
+}
+
+// SetClientCertMapper enables client certificate authentication.
+func (a *Authenticator) SetClientCertMapper(certs *ClientCertMapper) {
+	a.certs = certs
+}
+
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	return a.AuthenticateCredentials(r.Header.Get(APIKeyHeader), r.Header.Get("Authorization"), r.TLS)
+}
+
+// AuthenticateCredentials is Authenticate for other transports, such as
+// gRPC: apiKey and authorization are what the X-API-Key and Authorization
+// headers would hold, and state is the connection's TLS state, nil without
+// TLS.
+func (a *Authenticator) AuthenticateCredentials(apiKey, authorization string, state *tls.ConnectionState) (*Principal, error) {
+	if apiKey != "" {
+		if a.keys == nil {
+			return nil, ErrInvalidCredentials
+		}
+		return a.keys.VerifyAPIKey(apiKey)
+	}
+
+	if authorization == "" && a.certs != nil && state != nil && len(state.VerifiedChains) > 0 {
+		return a.certs.Map(state.VerifiedChains[0][0])
+	}
+
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, ErrMissingCredentials
+	}
+	if a.jwt == nil || !a.jwt.Enabled() {
+		return nil, ErrInvalidCredentials
+	}
+	return a.jwt.Verify(strings.TrimSpace(token))
+}
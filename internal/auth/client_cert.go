@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const MethodClientCert = "client_cert"
+
+// ClientCertificate maps a client certificate to a principal. Fingerprint
+// is the hex SHA-256 of the DER certificate and pins one certificate;
+// CommonName matches any certificate the client CA issued with that
+// subject CN. Fingerprints win when both match.
+type ClientCertificate struct {
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	CommonName  string   `json:"commonName,omitempty"`
+	Subject     string   `json:"subject"`
+	Roles       []string `json:"roles"`
+	Tier        string   `json:"tier,omitempty"`
+}
+
+// ClientCertMapper resolves principals for certificates the TLS handshake
+// has already verified against the client CA.
+type ClientCertMapper struct {
+	byFingerprint map[string]ClientCertificate
+	byCommonName  map[string]ClientCertificate
+}
+
+func NewClientCertMapper(certs []ClientCertificate) (*ClientCertMapper, error) {
+	m := &ClientCertMapper{
+		byFingerprint: make(map[string]ClientCertificate),
+		byCommonName:  make(map[string]ClientCertificate),
+	}
+	for _, cert := range certs {
+		if cert.Subject == "" {
+			return nil, fmt.Errorf("client certificate mapping without subject")
+		}
+		switch {
+		case cert.Fingerprint != "":
+			m.byFingerprint[normalizeFingerprint(cert.Fingerprint)] = cert
+		case cert.CommonName != "":
+			m.byCommonName[cert.CommonName] = cert
+		default:
+			return nil, fmt.Errorf("client certificate mapping for %s needs a fingerprint or commonName", cert.Subject)
+		}
+	}
+	return m, nil
+}
+
+// LoadClientCertMapperFile reads a JSON array of ClientCertificate.
+func LoadClientCertMapperFile(path string) (*ClientCertMapper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []ClientCertificate
+	if err := json.Unmarshal(data, &certs); err != nil {
+		return nil, err
+	}
+	return NewClientCertMapper(certs)
+}
+
+// Map returns the principal for cert, or ErrInvalidCredentials when no
+// mapping names it.
+func (m *ClientCertMapper) Map(cert *x509.Certificate) (*Principal, error) {
+	fingerprint := CertificateFingerprint(cert)
+	mapping, ok := m.byFingerprint[fingerprint]
+	if !ok {
+		mapping, ok = m.byCommonName[cert.Subject.CommonName]
+	}
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{
+		ID:      "cert:" + fingerprint[:16],
+		Subject: mapping.Subject,
+		Roles:   mapping.Roles,
+		Method:  MethodClientCert,
+		Tier:    mapping.Tier,
+	}, nil
+}
+
+// CertificateFingerprint is the lowercase hex SHA-256 of the DER encoding.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeFingerprint accepts the colon separated, uppercase form openssl
+// prints.
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+}
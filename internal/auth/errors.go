@@ -0,0 +1,11 @@
+package auth
+
+import "errors"
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrTokenExpired       = errors.New("token expired")
+	ErrUnsupportedAlg     = errors.New("unsupported token algorithm")
+	ErrForbidden          = errors.New("operation not allowed for principal")
+)
@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+type verificationKey struct {
+	id     string
+	secret []byte
+	rsa    *rsa.PublicKey
+}
+
+// JWTVerifier validates HS256 and RS256 bearer tokens against locally
+// configured keys. Tokens must carry sub and exp; iss and aud are checked
+// when configured.
+type JWTVerifier struct {
+	keys     []verificationKey
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+func NewJWTVerifier(issuer, audience string) *JWTVerifier {
+	return &JWTVerifier{issuer: issuer, audience: audience, now: time.Now}
+}
+
+func (v *JWTVerifier) AddHS256Secret(id string, secret []byte) {
+	v.keys = append(v.keys, verificationKey{id: id, secret: secret})
+}
+
+func (v *JWTVerifier) AddRS256Key(id string, key *rsa.PublicKey) {
+	v.keys = append(v.keys, verificationKey{id: id, rsa: key})
+}
+
+// LoadRS256PublicKeyFile adds a PEM encoded RSA public key.
+func (v *JWTVerifier) LoadRS256PublicKeyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("no PEM block in %s", path)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("public key in %s is not RSA", path)
+	}
+
+	v.AddRS256Key("", key)
+	return nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// LoadJWKSFile adds the RSA and symmetric (oct) keys of a JWKS document.
+func (v *JWTVerifier) LoadJWKSFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return err
+	}
+
+	for _, key := range set.Keys {
+		switch key.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return fmt.Errorf("jwk %s: %w", key.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil {
+				return fmt.Errorf("jwk %s: %w", key.Kid, err)
+			}
+			v.AddRS256Key(key.Kid, &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			})
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return fmt.Errorf("jwk %s: %w", key.Kid, err)
+			}
+			v.AddHS256Secret(key.Kid, secret)
+		default:
+			return fmt.Errorf("jwk %s: unsupported key type %q", key.Kid, key.Kty)
+		}
+	}
+	return nil
+}
+
+func (v *JWTVerifier) Enabled() bool {
+	return len(v.keys) > 0
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt int64           `json:"exp"`
+	NotBefore int64           `json:"nbf"`
+	ID        string          `json:"jti"`
+	Roles     []string        `json:"roles"`
+	Role      string          `json:"role"`
+	Tier      string          `json:"tier"`
+}
+
+// Verify checks the signature and claims of a compact JWT and returns the
+// principal it describes.
+func (v *JWTVerifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidCredentials
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := v.verifySignature(header, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	roles := claims.Roles
+	if len(roles) == 0 && claims.Role != "" {
+		roles = []string{claims.Role}
+	}
+	return &Principal{
+		ID:      claims.Subject,
+		Subject: claims.Subject,
+		Roles:   roles,
+		Method:  MethodJWT,
+		Tier:    claims.Tier,
+	}, nil
+}
+
+// verifySignature only tries keys of the family named by alg, so an RSA
+// public key can never be used as an HMAC secret.
+func (v *JWTVerifier) verifySignature(header jwtHeader, signed string, signature []byte) error {
+	if header.Alg != "HS256" && header.Alg != "RS256" {
+		return ErrUnsupportedAlg
+	}
+	digest := sha256.Sum256([]byte(signed))
+
+	for _, key := range v.keys {
+		if header.Kid != "" && key.id != "" && key.id != header.Kid {
+			continue
+		}
+
+		switch header.Alg {
+		case "HS256":
+			if key.secret == nil {
+				continue
+			}
+			mac := hmac.New(sha256.New, key.secret)
+			mac.Write([]byte(signed))
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return nil
+			}
+		case "RS256":
+			if key.rsa == nil {
+				continue
+			}
+			if rsa.VerifyPKCS1v15(key.rsa, crypto.SHA256, digest[:], signature) == nil {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidCredentials
+}
+
+func (v *JWTVerifier) validateClaims(claims jwtClaims) error {
+	now := v.now().Unix()
+	if claims.Subject == "" || claims.ExpiresAt == 0 {
+		return ErrInvalidCredentials
+	}
+	if now >= claims.ExpiresAt {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return ErrInvalidCredentials
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return ErrInvalidCredentials
+	}
+	if v.audience != "" && !audienceContains(claims.Audience, v.audience) {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// audienceContains accepts aud as a single string or an array of strings.
+func audienceContains(raw json.RawMessage, audience string) bool {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single == audience
+	}
+	var many []string
+	if json.Unmarshal(raw, &many) == nil {
+		for _, value := range many {
+			if value == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
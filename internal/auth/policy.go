@@ -0,0 +1,146 @@
+package auth
+
+import "context"
+
+const (
+	RoleCustomer      = "customer"
+	RoleTeller        = "teller"
+	RoleAdmin         = "admin"
+	RoleCreditOfficer = "credit-officer"
+)
+
+type Action string
+
+const (
+	ActionCreateAccount     Action = "account:create"
+	ActionReadAccount       Action = "account:read"
+	ActionVerifyAccount     Action = "account:verify"
+	ActionSetOverdraft      Action = "account:overdraft"
+	ActionSetLimits         Action = "account:limits"
+	ActionReset             Action = "system:reset"
+	ActionCreateTransaction Action = "transaction:create"
+	ActionReadTransaction   Action = "transaction:read"
+	ActionListTransactions  Action = "transaction:list"
+	ActionDeposit           Action = "event:deposit"
+	ActionWithdraw          Action = "event:withdraw"
+	ActionTransfer          Action = "event:transfer"
+	ActionManageAPIKeys     Action = "apikey:manage"
+	ActionReadAudit         Action = "audit:read"
+	ActionReviewFraud       Action = "fraud:review"
+	ActionReviewAML         Action = "aml:review"
+	ActionReviewSanctions   Action = "sanctions:review"
+	ActionManageSuspense    Action = "suspense:manage"
+	ActionManageSchedules   Action = "schedule:manage"
+)
+
+// Resource identifies what an action touches. Customers are matched against
+// it: DocumentNumber must be their subject and AccountID one of their
+// accounts.
+type Resource struct {
+	AccountID      string
+	DocumentNumber string
+}
+
+// EventAuthorization maps an event to the action it performs and the
+// account it moves money from (or into, for deposits).
+func EventAuthorization(eventType, origin, destination string) (Action, Resource) {
+	switch eventType {
+	case "deposit":
+		return ActionDeposit, Resource{AccountID: destination}
+	case "withdraw":
+		return ActionWithdraw, Resource{AccountID: origin}
+	default:
+		return ActionTransfer, Resource{AccountID: origin}
+	}
+}
+
+// TransactionAuthorization maps a transaction to the action it performs:
+// a credit (operation type 4) is a deposit, anything else a debit of the
+// account.
+func TransactionAuthorization(operationTypeID int, accountID string) (Action, Resource) {
+	if operationTypeID == 4 {
+		return ActionDeposit, Resource{AccountID: accountID}
+	}
+	return ActionCreateTransaction, Resource{AccountID: accountID}
+}
+
+type OwnershipChecker interface {
+	IsOwner(accountID, subject string) bool
+}
+
+type rule struct {
+	roles []string
+	// owner lets customers perform the action on their own resources.
+	owner bool
+}
+
+// Deposits and credit transactions bring in money from outside the bank,
+// which only staff can vouch for, so ActionDeposit has no owner rule.
+var rules = map[Action]rule{
+	ActionCreateAccount:     {roles: []string{RoleTeller, RoleAdmin}, owner: true},
+	ActionReadAccount:       {roles: []string{RoleTeller, RoleAdmin, RoleCreditOfficer}, owner: true},
+	ActionVerifyAccount:     {roles: []string{RoleCustomer, RoleTeller, RoleAdmin, RoleCreditOfficer}},
+	ActionSetOverdraft:      {roles: []string{RoleAdmin, RoleCreditOfficer}},
+	ActionSetLimits:         {roles: []string{RoleAdmin}},
+	ActionReset:             {roles: []string{RoleAdmin}},
+	ActionCreateTransaction: {roles: []string{RoleTeller, RoleAdmin}, owner: true},
+	ActionReadTransaction:   {roles: []string{RoleTeller, RoleAdmin, RoleCreditOfficer}, owner: true},
+	ActionListTransactions:  {roles: []string{RoleTeller, RoleAdmin, RoleCreditOfficer}},
+	ActionDeposit:           {roles: []string{RoleTeller, RoleAdmin}},
+	ActionWithdraw:          {roles: []string{RoleTeller, RoleAdmin}, owner: true},
+	ActionTransfer:          {roles: []string{RoleTeller, RoleAdmin}, owner: true},
+	ActionManageAPIKeys:     {roles: []string{RoleAdmin}},
+	ActionReadAudit:         {roles: []string{RoleAdmin}},
+	ActionReviewFraud:       {roles: []string{RoleAdmin}},
+	ActionReviewAML:         {roles: []string{RoleAdmin}},
+	ActionReviewSanctions:   {roles: []string{RoleAdmin}},
+	ActionManageSuspense:    {roles: []string{RoleAdmin}},
+	ActionManageSchedules:   {roles: []string{RoleTeller, RoleAdmin}, owner: true},
+}
+
+// Policy decides whether the principal in a request context may perform an
+// action. Unknown actions are denied.
+type Policy struct {
+	owners OwnershipChecker
+}
+
+func NewPolicy(owners OwnershipChecker) *Policy {
+	return &Policy{owners: owners}
+}
+
+func (p *Policy) Authorize(ctx context.Context, action Action, resource Resource) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return ErrMissingCredentials
+	}
+
+	rule, exists := rules[action]
+	if !exists {
+		return ErrForbidden
+	}
+	if principal.HasRole(rule.roles...) {
+		return nil
+	}
+	if rule.owner && principal.HasRole(RoleCustomer) && p.owns(principal, resource) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+func (p *Policy) owns(principal *Principal, resource Resource) bool {
+	if resource.AccountID == "" && resource.DocumentNumber == "" {
+		return false
+	}
+	if resource.DocumentNumber != "" && resource.DocumentNumber != principal.Subject {
+		return false
+	}
+	if resource.AccountID != "" && (p.owners == nil || !p.owners.IsOwner(resource.AccountID, principal.Subject)) {
+		return false
+	}
+	return true
+}
+
+// Roles lists every role the policy knows about.
+func Roles() []string {
+	return []string{RoleCustomer, RoleTeller, RoleAdmin, RoleCreditOfficer}
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+// Principal is the authenticated caller attached to the request context.
+type Principal struct {
+	ID      string   `json:"id"`
+	Subject string   `json:"subject"`
+	Roles   []string `json:"roles"`
+	Method  string   `json:"method"`
+	// Tier picks the caller's rate limits; empty means the default tier.
+	Tier string `json:"tier,omitempty"`
+}
+
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if slices.Contains(p.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
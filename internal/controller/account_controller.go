@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"corebanking/internal/auth"
 	"corebanking/internal/dto"
 	"corebanking/internal/service"
 	"corebanking/internal/utils"
@@ -11,33 +12,43 @@ import (
 
 type AccountController struct {
 	Service      *service.AccountService
+	Resetter     *service.ResetService
+	Policy       *auth.Policy
 	ErrorHandler utils.ErrorHandler
 }
 
-func NewAccountController(service *service.AccountService, errHandler utils.ErrorHandler) *AccountController {
+func NewAccountController(service *service.AccountService, resetter *service.ResetService, policy *auth.Policy, errHandler utils.ErrorHandler) *AccountController {
 	return &AccountController{
 		Service:      service,
+		Resetter:     resetter,
+		Policy:       policy,
 		ErrorHandler: errHandler,
 	}
 }
 
+func (c *AccountController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/accounts", Handler: c.CreateAccount},
+		{Method: http.MethodGet, Pattern: "/accounts/", Handler: c.RouteAccount},
+		{Method: http.MethodGet, Pattern: "/accounts/balance", Handler: c.GetBalance},
+		{Method: http.MethodPost, Pattern: "/accounts/overdraft", Handler: c.SetOverdraft},
+		{Method: http.MethodPost, Pattern: "/accounts/reset", Handler: c.Reset},
+	}
+}
+
 func (c *AccountController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
-	mux.HandleFunc(apiPrefix+"/accounts", c.CreateAccount)
-	mux.HandleFunc(apiPrefix+"/accounts/", c.RouteAccount)
-	mux.HandleFunc(apiPrefix+"/accounts/balance", c.GetBalance)
-	mux.HandleFunc(apiPrefix+"/accounts/overdraft", c.SetOverdraft)
-	mux.HandleFunc(apiPrefix+"/accounts/reset", c.Reset)
+	registerRoutes(mux, apiPrefix, c.Routes())
 }
 
 func (c *AccountController) RouteAccount(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		utils.HandleHTTPError(w, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
 		return
 	}
 
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(parts) != 4 { // /api/v1/accounts/{id}
-		utils.HandleHTTPError(w, nil, "Failed to split path.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to split path.", c.ErrorHandler)
 		return
 	}
 
@@ -46,9 +57,14 @@ func (c *AccountController) RouteAccount(w http.ResponseWriter, r *http.Request)
 }
 
 func (c *AccountController) GetAccount(w http.ResponseWriter, r *http.Request, accountID string) {
-	account, err := c.Service.GetAccount(accountID)
+	r = withAccount(r, accountID)
+	if !authorize(w, r, c.Policy, auth.ActionReadAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	account, err := c.Service.GetAccount(r.Context(), accountID)
 	if err != nil {
-		utils.HandleHTTPError(w, err, "Failed to get account.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, err, "Failed to get account.", c.ErrorHandler)
 		return
 	}
 	respondJSON(w, http.StatusOK, account)
@@ -56,14 +72,19 @@ func (c *AccountController) GetAccount(w http.ResponseWriter, r *http.Request, a
 
 func (c *AccountController) GetBalance(w http.ResponseWriter, r *http.Request) {
 	accountID := r.URL.Query().Get("account_id")
+	r = withAccount(r, accountID)
 	if accountID == "" {
-		utils.HandleHTTPError(w, nil, "Failed to recovery account_id.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to recovery account_id.", c.ErrorHandler)
 		return
 	}
 
-	balance, err := c.Service.GetBalance(accountID)
+	if !authorize(w, r, c.Policy, auth.ActionReadAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	balance, err := c.Service.GetBalance(r.Context(), accountID)
 	if err != nil {
-		utils.HandleHTTPError(w, err, "Failed to get balance.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, err, "Failed to get balance.", c.ErrorHandler)
 		return
 	}
 
@@ -72,19 +93,26 @@ func (c *AccountController) GetBalance(w http.ResponseWriter, r *http.Request) {
 
 func (c *AccountController) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		utils.HandleHTTPError(w, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
 		return
 	}
 
 	var req dto.AccountRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.HandleHTTPError(w, err, "Failed to decode request.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	if !authorize(w, r, c.Policy, auth.ActionCreateAccount, auth.Resource{DocumentNumber: req.DocumentNumber}, c.ErrorHandler) {
 		return
 	}
 
-	account, err := c.Service.CreateAccount(req.DocumentNumber)
+	account, err := c.Service.CreateAccount(r.Context(), req.DocumentNumber, req.HolderName)
+	if respondHeld(w, err) {
+		return
+	}
 	if err != nil {
-		utils.HandleHTTPError(w, err, "Failed to create account.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, err, "Failed to create account.", c.ErrorHandler)
 		return
 	}
 
@@ -93,42 +121,50 @@ func (c *AccountController) CreateAccount(w http.ResponseWriter, r *http.Request
 
 func (c *AccountController) SetOverdraft(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		utils.HandleHTTPError(w, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
 		return
 	}
 
 	var req dto.OverdraftRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.HandleHTTPError(w, err, "Failed to decode request.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	r = withAccount(r, req.AccountID)
+	if !authorize(w, r, c.Policy, auth.ActionSetOverdraft, auth.Resource{AccountID: req.AccountID}, c.ErrorHandler) {
 		return
 	}
 
-	if err := c.Service.ConfigOverdraft(req.AccountID, req.Limit); err != nil {
-		utils.HandleHTTPError(w, err, "Failed in set new overdraft limit.", c.ErrorHandler)
+	if err := c.Service.ConfigOverdraft(r.Context(), req.AccountID, req.Limit); err != nil {
+		utils.HandleHTTPError(w, r, err, "Failed in set new overdraft limit.", c.ErrorHandler)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 	if http.StatusOK == 200 {
-		respondJSON(w, http.StatusOK, map[string]string{
-			"message": "Overdraft limit was applied.",
-		})
+		respondJSON(w, http.StatusOK, dto.NewMessageResponse("Overdraft limit was applied."))
 	}
 }
 
 func (c *AccountController) Reset(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		utils.HandleHTTPError(w, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
 		return
 	}
 
-	c.Service.Reset()
+	if !authorize(w, r, c.Policy, auth.ActionReset, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	if err := c.Resetter.ResetAll(r.Context(), r.Header.Get(ResetConfirmationHeader)); err != nil {
+		utils.HandleHTTPError(w, r, err, "Failed to reset system.", c.ErrorHandler)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	respondJSON(w, http.StatusOK, map[string]string{
-		"message": "System has been reseted.",
-	})
+	respondJSON(w, http.StatusOK, dto.NewMessageResponse("System has been reseted."))
 }
 
 func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
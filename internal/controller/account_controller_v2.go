@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"net/http"
+)
+
+type AccountControllerV2 struct {
+	Service      *service.AccountService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewAccountControllerV2(service *service.AccountService, policy *auth.Policy, errHandler utils.ErrorHandler) *AccountControllerV2 {
+	return &AccountControllerV2{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *AccountControllerV2) Routes() []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/accounts", Handler: c.CreateAccount},
+		{Method: http.MethodGet, Pattern: "/accounts/{accountId}", Handler: c.GetAccount},
+		{Method: http.MethodGet, Pattern: "/accounts/{accountId}/balance", Handler: c.GetBalance},
+		{Method: http.MethodGet, Pattern: "/accounts/{accountId}/verify", Handler: c.VerifyAccount},
+		{Method: http.MethodPut, Pattern: "/accounts/{accountId}/overdraft", Handler: c.SetOverdraft},
+	}
+}
+
+func (c *AccountControllerV2) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+func (c *AccountControllerV2) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	var req dto.AccountRequest
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	if !authorizeV2(w, r, c.Policy, auth.ActionCreateAccount, auth.Resource{DocumentNumber: req.DocumentNumber}, c.ErrorHandler) {
+		return
+	}
+
+	account, err := c.Service.CreateAccount(r.Context(), req.DocumentNumber, req.HolderName)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to create account.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusCreated, dto.NewDataEnvelope(v2, dto.AccountV2Response(*account)))
+}
+
+func (c *AccountControllerV2) GetAccount(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionReadAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	account, err := c.Service.GetAccount(r.Context(), accountID)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get account.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.AccountV2Response(*account)))
+}
+
+// VerifyAccount lets a payer check a destination before sending money to
+// it. Any principal may verify any account; the holder name is masked.
+func (c *AccountControllerV2) VerifyAccount(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionVerifyAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	verification, err := c.Service.Verify(r.Context(), accountID)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to verify account.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.AccountVerificationV2Response(*verification)))
+}
+
+func (c *AccountControllerV2) GetBalance(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionReadAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	balance, err := c.Service.GetBalance(r.Context(), accountID)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get balance.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.BalanceV2Response{
+		AccountID: accountID,
+		Balance:   dto.Money(balance.Balance),
+	}))
+}
+
+func (c *AccountControllerV2) SetOverdraft(w http.ResponseWriter, r *http.Request) {
+	var req dto.OverdraftV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionSetOverdraft, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	if err := c.Service.ConfigOverdraft(r.Context(), accountID, int64(req.Limit)); err != nil {
+		respondV2Error(w, r, err, "Failed in set new overdraft limit.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.OverdraftV2Response{
+		AccountID: accountID,
+		Limit:     req.Limit,
+	}))
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type AMLController struct {
+	Service      *service.AMLService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewAMLController(service *service.AMLService, policy *auth.Policy, errHandler utils.ErrorHandler) *AMLController {
+	return &AMLController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *AMLController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/aml/alerts", Handler: c.ListAlerts},
+		{Method: http.MethodGet, Pattern: "/aml/alerts/{alertId}", Handler: c.GetAlert},
+		{Method: http.MethodPost, Pattern: "/aml/alerts/{alertId}/disposition", Handler: c.DispositionAlert},
+		{Method: http.MethodGet, Pattern: "/aml/reports", Handler: c.ExportReport},
+	}
+}
+
+func (c *AMLController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+// ListAlerts lists the alerts with the status query parameter, open by
+// default; status=all lists every alert. accountId narrows it to one
+// account.
+func (c *AMLController) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewAML, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	query := r.URL.Query()
+	status := query.Get("status")
+	switch status {
+	case "":
+		status = domain.AlertOpen
+	case "all":
+		status = ""
+	case domain.AlertOpen, domain.AlertFalsePositive, domain.AlertNoAction, domain.AlertSuspicious:
+	default:
+		respondV2BadRequest(w, r, errors.New("status must be open, false_positive, no_action, suspicious or all"), "Failed to parse status.", c.ErrorHandler)
+		return
+	}
+
+	alerts := c.Service.Alerts(r.Context(), status, query.Get("accountId"))
+	response := make([]dto.AMLAlertV2Response, 0, len(alerts))
+	for _, alert := range alerts {
+		response = append(response, dto.NewAMLAlertV2Response(alert))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, response))
+}
+
+func (c *AMLController) GetAlert(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewAML, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	alert, err := c.Service.Alert(r.Context(), r.PathValue("alertId"))
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get aml alert.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewAMLAlertV2Response(alert)))
+}
+
+func (c *AMLController) DispositionAlert(w http.ResponseWriter, r *http.Request) {
+	var req dto.AMLDispositionV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewAML, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	alert, err := c.Service.Disposition(r.Context(), r.PathValue("alertId"), req.Disposition, req.Note)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to disposition aml alert.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewAMLAlertV2Response(alert)))
+}
+
+// ExportReport downloads the suspicious activity report for alerts
+// dispositioned in an optional from/to window in RFC 3339. The report is
+// a file for the regulator, so it is served as is, without the envelope.
+func (c *AMLController) ExportReport(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewAML, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	query := r.URL.Query()
+
+	var from, to time.Time
+	var err error
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			respondV2BadRequest(w, r, err, "Failed in parse from date time format data.", c.ErrorHandler)
+			return
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			respondV2BadRequest(w, r, err, "Failed in parse to date time format data.", c.ErrorHandler)
+			return
+		}
+	}
+
+	report, err := c.Service.Report(r.Context(), from, to)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to build suspicious activity report.", c.ErrorHandler)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="sar-%s.json"`, report.GeneratedAt.UTC().Format("20060102T150405Z")))
+	respondJSON(w, http.StatusOK, report)
+}
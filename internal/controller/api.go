@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/openapi"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"time"
+)
+
+// Services are what the API controllers serve.
+type Services struct {
+	Accounts     *service.AccountService
+	Transactions *service.TransactionService
+	APIKeys      *service.APIKeyService
+	Limits       *service.LimitService
+	Fraud        *service.FraudService
+	AML          *service.AMLService
+	Sanctions    *service.SanctionsService
+	Suspense     *service.SuspenseService
+	Schedules    *service.ScheduleService
+	Batches      *service.BatchService
+	Audit        *service.AuditService
+	Reset        *service.ResetService
+}
+
+// APIVersions builds every version of the API with the controllers it
+// serves: v1, deprecated until v1Sunset, and v2. Each serves its OpenAPI
+// spec titled appName.
+func APIVersions(appName string, v1Sunset time.Time, services Services, policy *auth.Policy, errHandler utils.ErrorHandler) []APIVersion {
+	v1 := APIVersion{
+		Name:       "v1",
+		Deprecated: true,
+		Sunset:     v1Sunset,
+		Successor:  "v2",
+		Registrars: []Registrar{
+			NewAccountController(services.Accounts, services.Reset, policy, errHandler),
+			NewTransactionController(services.Transactions, policy, errHandler),
+			NewDocsController(openapi.Build(appName, "v1"), errHandler),
+		},
+	}
+	v2 := APIVersion{
+		Name: "v2",
+		Registrars: []Registrar{
+			NewAccountControllerV2(services.Accounts, policy, errHandler),
+			NewTransactionControllerV2(services.Transactions, policy, errHandler),
+			NewAuthController(services.APIKeys, policy, errHandler),
+			NewLimitsController(services.Limits, policy, errHandler),
+			NewFraudController(services.Fraud, policy, errHandler),
+			NewAMLController(services.AML, policy, errHandler),
+			NewSanctionsController(services.Sanctions, policy, errHandler),
+			NewSuspenseController(services.Suspense, policy, errHandler),
+			NewScheduleController(services.Schedules, policy, errHandler),
+			NewBatchController(services.Batches, policy, errHandler),
+			NewAuditController(services.Audit, policy, errHandler),
+			NewSystemController(services.Reset, policy, errHandler),
+			NewDocsController(openapi.Build(appName, "v2"), errHandler),
+		},
+	}
+	return []APIVersion{v1, v2}
+}
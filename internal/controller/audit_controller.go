@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"net/http"
+	"time"
+)
+
+type AuditController struct {
+	Service      *service.AuditService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewAuditController(service *service.AuditService, policy *auth.Policy, errHandler utils.ErrorHandler) *AuditController {
+	return &AuditController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *AuditController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/audit", Handler: c.QueryAudit},
+		{Method: http.MethodGet, Pattern: "/audit/verify", Handler: c.VerifyAudit},
+	}
+}
+
+func (c *AuditController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+// QueryAudit filters by actor, entity type, entity id and an optional
+// from/to window in RFC 3339.
+func (c *AuditController) QueryAudit(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReadAudit, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	query := r.URL.Query()
+
+	var from, to time.Time
+	var err error
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			respondV2BadRequest(w, r, err, "Failed in parse from date time format data.", c.ErrorHandler)
+			return
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			respondV2BadRequest(w, r, err, "Failed in parse to date time format data.", c.ErrorHandler)
+			return
+		}
+	}
+
+	entries := c.Service.Query(r.Context(), query.Get("actor"), query.Get("entity"), query.Get("entityId"), from, to)
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, entries))
+}
+
+func (c *AuditController) VerifyAudit(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReadAudit, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	response := dto.AuditVerifyResponse{Valid: true, Entries: len(c.Service.Query(r.Context(), "", "", "", time.Time{}, time.Time{}))}
+	if err := c.Service.Verify(); err != nil {
+		response.Valid = false
+		response.Error = err.Error()
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, response))
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"net/http"
+	"time"
+)
+
+type AuthController struct {
+	Service      *service.APIKeyService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewAuthController(service *service.APIKeyService, policy *auth.Policy, errHandler utils.ErrorHandler) *AuthController {
+	return &AuthController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *AuthController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/auth/me", Handler: c.GetPrincipal},
+		{Method: http.MethodGet, Pattern: "/auth/keys", Handler: c.ListKeys},
+		{Method: http.MethodPost, Pattern: "/auth/keys", Handler: c.IssueKey},
+		{Method: http.MethodPost, Pattern: "/auth/keys/{keyId}/rotate", Handler: c.RotateKey},
+		{Method: http.MethodDelete, Pattern: "/auth/keys/{keyId}", Handler: c.RevokeKey},
+	}
+}
+
+func (c *AuthController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+func (c *AuthController) GetPrincipal(w http.ResponseWriter, r *http.Request) {
+	principal, ok := auth.PrincipalFrom(r.Context())
+	if !ok {
+		respondV2Error(w, r, auth.ErrMissingCredentials, "Failed to recovery principal.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, principal))
+}
+
+func (c *AuthController) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	return authorizeV2(w, r, c.Policy, auth.ActionManageAPIKeys, auth.Resource{}, c.ErrorHandler)
+}
+
+func (c *AuthController) ListKeys(w http.ResponseWriter, r *http.Request) {
+	if !c.requireAdmin(w, r) {
+		return
+	}
+
+	keys := c.Service.ListKeys(r.Context())
+	result := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, dto.NewAPIKeyResponse(key, ""))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, result))
+}
+
+func (c *AuthController) IssueKey(w http.ResponseWriter, r *http.Request) {
+	if !c.requireAdmin(w, r) {
+		return
+	}
+
+	var req dto.APIKeyRequest
+	if err := decodeV2(r, &req); err != nil || req.Subject == "" {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	plaintext, key, err := c.Service.Issue(r.Context(), req.Subject, req.Roles, req.Tier)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to issue api key.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusCreated, dto.NewDataEnvelope(v2, dto.NewAPIKeyResponse(key, plaintext)))
+}
+
+func (c *AuthController) RotateKey(w http.ResponseWriter, r *http.Request) {
+	if !c.requireAdmin(w, r) {
+		return
+	}
+
+	var req dto.APIKeyRotateRequest
+	if err := decodeV2(r, &req); err != nil || req.GraceSeconds < 0 {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	plaintext, key, err := c.Service.Rotate(r.Context(), r.PathValue("keyId"), time.Duration(req.GraceSeconds)*time.Second)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to rotate api key.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewAPIKeyResponse(key, plaintext)))
+}
+
+func (c *AuthController) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	if !c.requireAdmin(w, r) {
+		return
+	}
+
+	if err := c.Service.Revoke(r.Context(), r.PathValue("keyId")); err != nil {
+		respondV2Error(w, r, err, "Failed to revoke api key.", c.ErrorHandler)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/utils"
+	"net/http"
+)
+
+// authorize checks the policy and, when it denies, answers 403 with the v1
+// error body. It returns whether the handler may continue.
+func authorize(w http.ResponseWriter, r *http.Request, policy *auth.Policy, action auth.Action, resource auth.Resource, logger utils.ErrorHandler) bool {
+	err := policy.Authorize(r.Context(), action, resource)
+	if err == nil {
+		return true
+	}
+
+	message := "Operation not allowed."
+	if logger != nil {
+		logger.Handle(r.Context(), err, message)
+	}
+	respondJSON(w, http.StatusForbidden, dto.NewErrorResponse(message, err))
+	return false
+}
+
+// authorizeV2 is authorize for v2 handlers, answering with an envelope.
+func authorizeV2(w http.ResponseWriter, r *http.Request, policy *auth.Policy, action auth.Action, resource auth.Resource, logger utils.ErrorHandler) bool {
+	err := policy.Authorize(r.Context(), action, resource)
+	if err == nil {
+		return true
+	}
+
+	respondV2Error(w, r, err, "Operation not allowed.", logger)
+	return false
+}
+
+// canReadTransaction checks that the caller may read transaction, logging
+// a denial. Callers answer a denied read exactly as an unknown ID, so
+// transaction IDs can't be probed for other accounts' postings.
+func canReadTransaction(r *http.Request, policy *auth.Policy, transaction *dto.TransactionResponse, logger utils.ErrorHandler) bool {
+	err := policy.Authorize(r.Context(), auth.ActionReadTransaction, auth.Resource{AccountID: transaction.AccountID})
+	if err == nil {
+		return true
+	}
+	if logger != nil {
+		logger.Handle(r.Context(), err, "Operation not allowed.")
+	}
+	return false
+}
+
+// batchItemAuthorization is auth.EventAuthorization for a batch item,
+// which may also be a transaction.
+func batchItemAuthorization(item domain.BatchItem) (auth.Action, auth.Resource) {
+	if item.Type == domain.BatchItemTransaction {
+		return auth.TransactionAuthorization(item.OperationTypeID, item.AccountID)
+	}
+	return auth.EventAuthorization(item.Type, item.Origin, item.Destination)
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"corebanking/internal/apierror"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"fmt"
+	"net/http"
+)
+
+type BatchController struct {
+	Service      *service.BatchService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewBatchController(service *service.BatchService, policy *auth.Policy, errHandler utils.ErrorHandler) *BatchController {
+	return &BatchController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *BatchController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/transactions/batch", Handler: c.PostBatch},
+		{Method: http.MethodGet, Pattern: "/transactions/batch/{batchId}", Handler: c.GetBatch},
+	}
+}
+
+func (c *BatchController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+// PostBatch posts a batch of transactions and events. Every item is
+// authorized as its own request would be before any is posted. Batches
+// processed at once answer 201, background ones 202 while pending; both
+// point to the batch with Location.
+func (c *BatchController) PostBatch(w http.ResponseWriter, r *http.Request) {
+	var req dto.BatchV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Invalid request body.", c.ErrorHandler)
+		return
+	}
+
+	items := req.Domain()
+	for i, item := range items {
+		action, resource := batchItemAuthorization(item)
+		if err := c.Policy.Authorize(r.Context(), action, resource); err != nil {
+			respondV2Error(w, r, fmt.Errorf("item %d: %w", i, err), "Operation not allowed.", c.ErrorHandler)
+			return
+		}
+	}
+
+	batch, err := c.Service.Submit(r.Context(), req.Mode, items, req.Async)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to post batch.", c.ErrorHandler)
+		return
+	}
+
+	status := http.StatusCreated
+	if batch.Status == domain.BatchPending {
+		status = http.StatusAccepted
+	}
+	w.Header().Set("Location", "/api/"+v2+"/transactions/batch/"+batch.ID)
+	respondEnvelope(w, status, dto.NewDataEnvelope(v2, batchV2Response(batch)))
+}
+
+// GetBatch returns a batch's status and item results. Staff see every
+// batch, others only the ones they submitted.
+func (c *BatchController) GetBatch(w http.ResponseWriter, r *http.Request) {
+	batch, err := c.Service.Batch(r.Context(), r.PathValue("batchId"))
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get batch.", c.ErrorHandler)
+		return
+	}
+
+	principal, _ := auth.PrincipalFrom(r.Context())
+	if principal == nil || principal.Subject != batch.CreatedBy {
+		if !authorizeV2(w, r, c.Policy, auth.ActionListTransactions, auth.Resource{}, c.ErrorHandler) {
+			return
+		}
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, batchV2Response(batch)))
+}
+
+func batchV2Response(batch *domain.Batch) dto.BatchV2Response {
+	return dto.NewBatchV2Response(batch, func(err error) string {
+		_, code := apierror.Status(err)
+		return code
+	})
+}
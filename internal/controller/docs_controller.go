@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"corebanking/internal/openapi"
+	"corebanking/internal/utils"
+	"net/http"
+)
+
+type DocsController struct {
+	Spec         *openapi.Document
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewDocsController(spec *openapi.Document, errHandler utils.ErrorHandler) *DocsController {
+	return &DocsController{Spec: spec, ErrorHandler: errHandler}
+}
+
+func (c *DocsController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/openapi.json", Handler: c.GetSpec},
+		{Method: http.MethodGet, Pattern: "/docs", Handler: c.GetUI},
+	}
+}
+
+func (c *DocsController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerRoutes(mux, apiPrefix, c.Routes())
+}
+
+func (c *DocsController) GetSpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, c.Spec)
+}
+
+func (c *DocsController) GetUI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", openapi.UIContentSecurityPolicy)
+	w.WriteHeader(http.StatusOK)
+	w.Write(openapi.UI)
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"errors"
+	"net/http"
+)
+
+type FraudController struct {
+	Service      *service.FraudService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewFraudController(service *service.FraudService, policy *auth.Policy, errHandler utils.ErrorHandler) *FraudController {
+	return &FraudController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *FraudController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/fraud/cases", Handler: c.ListCases},
+		{Method: http.MethodGet, Pattern: "/fraud/cases/{caseId}", Handler: c.GetCase},
+		{Method: http.MethodPost, Pattern: "/fraud/cases/{caseId}/approve", Handler: c.ApproveCase},
+		{Method: http.MethodPost, Pattern: "/fraud/cases/{caseId}/reject", Handler: c.RejectCase},
+	}
+}
+
+func (c *FraudController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+// ListCases lists the cases with the status query parameter, pending by
+// default; status=all lists every case.
+func (c *FraudController) ListCases(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewFraud, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = domain.FraudCasePending
+	case "all":
+		status = ""
+	case domain.FraudCasePending, domain.FraudCaseApproved, domain.FraudCaseRejected:
+	default:
+		respondV2BadRequest(w, r, errors.New("status must be pending, approved, rejected or all"), "Failed to parse status.", c.ErrorHandler)
+		return
+	}
+
+	cases := c.Service.Cases(r.Context(), status)
+	response := make([]dto.FraudCaseV2Response, 0, len(cases))
+	for _, fraudCase := range cases {
+		response = append(response, dto.NewFraudCaseV2Response(fraudCase))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, response))
+}
+
+func (c *FraudController) GetCase(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewFraud, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	fraudCase, err := c.Service.Case(r.Context(), r.PathValue("caseId"))
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get fraud case.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewFraudCaseV2Response(fraudCase)))
+}
+
+// ApproveCase posts the held debit and closes the case.
+func (c *FraudController) ApproveCase(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, c.Service.Approve, "Failed to approve fraud case.")
+}
+
+// RejectCase closes the case without posting the debit.
+func (c *FraudController) RejectCase(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, c.Service.Reject, "Failed to reject fraud case.")
+}
+
+func (c *FraudController) decide(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, id, note string) (*domain.FraudCase, error), message string) {
+	var req dto.FraudDecisionV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewFraud, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	fraudCase, err := decide(r.Context(), r.PathValue("caseId"), req.Note)
+	if err != nil {
+		respondV2Error(w, r, err, message, c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewFraudCaseV2Response(fraudCase)))
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"corebanking/internal/dto"
+	"corebanking/internal/health"
+	"net/http"
+)
+
+// HealthController serves the probes. They live outside /api and its
+// authentication so load balancers and orchestrators can reach them.
+type HealthController struct {
+	Checker *health.Checker
+	Version dto.VersionResponse
+}
+
+func NewHealthController(checker *health.Checker, version dto.VersionResponse) *HealthController {
+	return &HealthController{Checker: checker, Version: version}
+}
+
+func (c *HealthController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/healthz", Handler: c.Live},
+		{Method: http.MethodGet, Pattern: "/readyz", Handler: c.Ready},
+		{Method: http.MethodGet, Pattern: "/version", Handler: c.GetVersion},
+	}
+}
+
+func (c *HealthController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+// Live answers as long as the process can serve HTTP; it checks nothing
+// else so a slow dependency never gets the process restarted.
+func (c *HealthController) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, dto.HealthResponse{
+		Status:        "ok",
+		UptimeSeconds: c.Checker.Uptime().Seconds(),
+	})
+}
+
+// Ready answers 503 while any check fails or the server is draining.
+func (c *HealthController) Ready(w http.ResponseWriter, r *http.Request) {
+	ready, results := c.Checker.Ready(r.Context())
+
+	response := dto.ReadinessResponse{Status: "ready", Checks: make([]dto.HealthCheck, 0, len(results))}
+	for _, result := range results {
+		check := dto.HealthCheck{
+			Name:      result.Name,
+			Status:    "ok",
+			Error:     result.Error,
+			LatencyMs: float64(result.Latency.Microseconds()) / 1000,
+		}
+		if result.Error != "" {
+			check.Status = "failing"
+		}
+		response.Checks = append(response.Checks, check)
+	}
+
+	status := http.StatusOK
+	if !ready {
+		response.Status = "not_ready"
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, status, response)
+}
+
+func (c *HealthController) GetVersion(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, c.Version)
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"net/http"
+)
+
+type LimitsController struct {
+	Service      *service.LimitService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewLimitsController(service *service.LimitService, policy *auth.Policy, errHandler utils.ErrorHandler) *LimitsController {
+	return &LimitsController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *LimitsController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/accounts/{accountId}/limits", Handler: c.GetLimits},
+		{Method: http.MethodPut, Pattern: "/accounts/{accountId}/limits", Handler: c.SetLimits},
+	}
+}
+
+func (c *LimitsController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+func (c *LimitsController) GetLimits(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionReadAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	limits, err := c.Service.GetLimits(r.Context(), accountID)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get limits.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewLimitsV2Response(limits)))
+}
+
+// SetLimits replaces the account's product and own limits.
+func (c *LimitsController) SetLimits(w http.ResponseWriter, r *http.Request) {
+	var req dto.LimitsV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionSetLimits, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	var override *domain.Limits
+	if req.Limits != nil {
+		limits := req.Limits.Domain()
+		override = &limits
+	}
+	limits, err := c.Service.SetLimits(r.Context(), accountID, req.Product, override)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to set limits.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewLimitsV2Response(limits)))
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"corebanking/internal/logging"
+	"corebanking/internal/utils"
+	"net/http"
+)
+
+type Route struct {
+	Method  string
+	Pattern string
+	Handler http.HandlerFunc
+}
+
+func registerRoutes(mux *http.ServeMux, apiPrefix string, routes []Route) {
+	for _, route := range routes {
+		mux.HandleFunc(apiPrefix+route.Pattern, recordPattern(route.Handler))
+	}
+}
+
+// registerMethodRoutes registers routes with method-qualified patterns, so
+// path wildcards like {accountId} are available through r.PathValue.
+func registerMethodRoutes(mux *http.ServeMux, apiPrefix string, routes []Route) {
+	for _, route := range routes {
+		mux.HandleFunc(route.Method+" "+apiPrefix+route.Pattern, recordPattern(route.Handler))
+	}
+}
+
+// withAccount and withTransaction tag the request context so error logs
+// carry account_id and transaction_id.
+func withAccount(r *http.Request, accountID string) *http.Request {
+	return r.WithContext(logging.WithAccountID(r.Context(), accountID))
+}
+
+func withTransaction(r *http.Request, transactionID int64) *http.Request {
+	return r.WithContext(logging.WithTransactionID(r.Context(), transactionID))
+}
+
+// recordPattern reports the matched pattern to the access log. The mux sets
+// r.Pattern on the request it passes down, which outer middleware never
+// sees.
+func recordPattern(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if info := utils.RequestInfoFrom(r.Context()); info != nil {
+			info.Pattern = r.Pattern
+		}
+		handler(w, r)
+	}
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/sanctions"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"errors"
+	"net/http"
+)
+
+type SanctionsController struct {
+	Service      *service.SanctionsService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewSanctionsController(service *service.SanctionsService, policy *auth.Policy, errHandler utils.ErrorHandler) *SanctionsController {
+	return &SanctionsController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *SanctionsController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/sanctions/list", Handler: c.GetList},
+		{Method: http.MethodPost, Pattern: "/sanctions/list/reload", Handler: c.ReloadList},
+		{Method: http.MethodGet, Pattern: "/sanctions/screenings", Handler: c.ListScreenings},
+		{Method: http.MethodGet, Pattern: "/sanctions/cases", Handler: c.ListCases},
+		{Method: http.MethodGet, Pattern: "/sanctions/cases/{caseId}", Handler: c.GetCase},
+		{Method: http.MethodPost, Pattern: "/sanctions/cases/{caseId}/approve", Handler: c.ApproveCase},
+		{Method: http.MethodPost, Pattern: "/sanctions/cases/{caseId}/reject", Handler: c.RejectCase},
+	}
+}
+
+func (c *SanctionsController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+func (c *SanctionsController) GetList(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewSanctions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, listResponse(c.Service.List())))
+}
+
+// ReloadList loads the list again from its file, for when it was updated
+// in place. A list that fails to load leaves the current one in use.
+func (c *SanctionsController) ReloadList(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewSanctions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	list, err := c.Service.ReloadList(r.Context())
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to reload sanctions list.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, listResponse(list)))
+}
+
+func listResponse(list *sanctions.List) dto.SanctionsListV2Response {
+	return dto.SanctionsListV2Response{
+		Version:  list.Version,
+		Digest:   list.Digest,
+		Source:   list.Source,
+		LoadedAt: list.LoadedAt,
+		Entries:  len(list.Entries),
+	}
+}
+
+// ListScreenings lists screenings, narrowed by the accountId and
+// documentNumber query parameters.
+func (c *SanctionsController) ListScreenings(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewSanctions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	query := r.URL.Query()
+	screenings := c.Service.Screenings(r.Context(), query.Get("accountId"), query.Get("documentNumber"))
+	response := make([]dto.SanctionsScreeningV2Response, 0, len(screenings))
+	for _, screening := range screenings {
+		response = append(response, dto.NewSanctionsScreeningV2Response(screening))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, response))
+}
+
+// ListCases lists the cases with the status query parameter, pending by
+// default; status=all lists every case.
+func (c *SanctionsController) ListCases(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewSanctions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = domain.SanctionsCasePending
+	case "all":
+		status = ""
+	case domain.SanctionsCasePending, domain.SanctionsCaseApproved, domain.SanctionsCaseRejected:
+	default:
+		respondV2BadRequest(w, r, errors.New("status must be pending, approved, rejected or all"), "Failed to parse status.", c.ErrorHandler)
+		return
+	}
+
+	cases := c.Service.Cases(r.Context(), status)
+	response := make([]dto.SanctionsCaseV2Response, 0, len(cases))
+	for _, sanctionsCase := range cases {
+		response = append(response, dto.NewSanctionsCaseV2Response(sanctionsCase))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, response))
+}
+
+func (c *SanctionsController) GetCase(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewSanctions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	sanctionsCase, err := c.Service.Case(r.Context(), r.PathValue("caseId"))
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get sanctions case.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewSanctionsCaseV2Response(sanctionsCase)))
+}
+
+// ApproveCase clears the hits and opens the account or posts the
+// transfer.
+func (c *SanctionsController) ApproveCase(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, c.Service.Approve, "Failed to approve sanctions case.")
+}
+
+// RejectCase closes the case without opening the account or posting the
+// transfer.
+func (c *SanctionsController) RejectCase(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, c.Service.Reject, "Failed to reject sanctions case.")
+}
+
+func (c *SanctionsController) decide(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, id, note string) (*domain.SanctionsCase, error), message string) {
+	var req dto.SanctionsDecisionV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	if !authorizeV2(w, r, c.Policy, auth.ActionReviewSanctions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	sanctionsCase, err := decide(r.Context(), r.PathValue("caseId"), req.Note)
+	if err != nil {
+		respondV2Error(w, r, err, message, c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewSanctionsCaseV2Response(sanctionsCase)))
+}
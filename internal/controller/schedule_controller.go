@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"net/http"
+)
+
+type ScheduleController struct {
+	Service      *service.ScheduleService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewScheduleController(service *service.ScheduleService, policy *auth.Policy, errHandler utils.ErrorHandler) *ScheduleController {
+	return &ScheduleController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *ScheduleController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/accounts/{accountId}/schedules", Handler: c.CreateSchedule},
+		{Method: http.MethodGet, Pattern: "/accounts/{accountId}/schedules", Handler: c.ListSchedules},
+		{Method: http.MethodGet, Pattern: "/accounts/{accountId}/schedules/{scheduleId}", Handler: c.GetSchedule},
+		{Method: http.MethodGet, Pattern: "/accounts/{accountId}/schedules/{scheduleId}/executions", Handler: c.ListExecutions},
+		{Method: http.MethodPost, Pattern: "/accounts/{accountId}/schedules/{scheduleId}/pause", Handler: c.PauseSchedule},
+		{Method: http.MethodPost, Pattern: "/accounts/{accountId}/schedules/{scheduleId}/resume", Handler: c.ResumeSchedule},
+		{Method: http.MethodPost, Pattern: "/accounts/{accountId}/schedules/{scheduleId}/cancel", Handler: c.CancelSchedule},
+	}
+}
+
+func (c *ScheduleController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+// CreateSchedule schedules a one-off or recurring transfer from the
+// account.
+func (c *ScheduleController) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req dto.ScheduleV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionManageSchedules, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	schedule, err := c.Service.Create(r.Context(), req.Domain(accountID))
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to create schedule.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusCreated, dto.NewDataEnvelope(v2, dto.NewScheduleV2Response(schedule)))
+}
+
+func (c *ScheduleController) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionReadAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	schedules, err := c.Service.Schedules(r.Context(), accountID)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to list schedules.", c.ErrorHandler)
+		return
+	}
+
+	response := make([]dto.ScheduleV2Response, 0, len(schedules))
+	for _, schedule := range schedules {
+		response = append(response, dto.NewScheduleV2Response(schedule))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, response))
+}
+
+func (c *ScheduleController) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionReadAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	schedule, err := c.Service.Schedule(r.Context(), accountID, r.PathValue("scheduleId"))
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get schedule.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewScheduleV2Response(schedule)))
+}
+
+// ListExecutions lists every run of the schedule, oldest first, retries
+// included.
+func (c *ScheduleController) ListExecutions(w http.ResponseWriter, r *http.Request) {
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionReadAccount, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	executions, err := c.Service.Executions(r.Context(), accountID, r.PathValue("scheduleId"))
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to list schedule executions.", c.ErrorHandler)
+		return
+	}
+
+	response := make([]dto.ScheduleExecutionV2Response, 0, len(executions))
+	for _, execution := range executions {
+		response = append(response, dto.NewScheduleExecutionV2Response(execution))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, response))
+}
+
+func (c *ScheduleController) PauseSchedule(w http.ResponseWriter, r *http.Request) {
+	c.change(w, r, c.Service.Pause, "Failed to pause schedule.")
+}
+
+func (c *ScheduleController) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	c.change(w, r, c.Service.Resume, "Failed to resume schedule.")
+}
+
+func (c *ScheduleController) CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	c.change(w, r, c.Service.Cancel, "Failed to cancel schedule.")
+}
+
+// change authorizes and applies a status change to the schedule in the
+// path.
+func (c *ScheduleController) change(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, origin, id string) (*domain.Schedule, error), message string) {
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if !authorizeV2(w, r, c.Policy, auth.ActionManageSchedules, auth.Resource{AccountID: accountID}, c.ErrorHandler) {
+		return
+	}
+
+	schedule, err := apply(r.Context(), accountID, r.PathValue("scheduleId"))
+	if err != nil {
+		respondV2Error(w, r, err, message, c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewScheduleV2Response(schedule)))
+}
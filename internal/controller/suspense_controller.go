@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"errors"
+	"net/http"
+)
+
+type SuspenseController struct {
+	Service      *service.SuspenseService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewSuspenseController(service *service.SuspenseService, policy *auth.Policy, errHandler utils.ErrorHandler) *SuspenseController {
+	return &SuspenseController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *SuspenseController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/suspense/items", Handler: c.ListItems},
+		{Method: http.MethodGet, Pattern: "/suspense/items/{itemId}", Handler: c.GetItem},
+		{Method: http.MethodPost, Pattern: "/suspense/items/{itemId}/claim", Handler: c.ClaimItem},
+		{Method: http.MethodPost, Pattern: "/suspense/items/{itemId}/return", Handler: c.ReturnItem},
+	}
+}
+
+func (c *SuspenseController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+// ListItems lists the items with the status query parameter, held by
+// default; status=all lists every item. accountId narrows them to those
+// naming an account.
+func (c *SuspenseController) ListItems(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionManageSuspense, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	query := r.URL.Query()
+	status := query.Get("status")
+	switch status {
+	case "":
+		status = domain.SuspenseHeld
+	case "all":
+		status = ""
+	case domain.SuspenseHeld, domain.SuspenseClaimed, domain.SuspenseReturned:
+	default:
+		respondV2BadRequest(w, r, errors.New("status must be held, claimed, returned or all"), "Failed to parse status.", c.ErrorHandler)
+		return
+	}
+
+	items := c.Service.Items(r.Context(), status, query.Get("accountId"))
+	response := make([]dto.SuspenseItemV2Response, 0, len(items))
+	for _, item := range items {
+		response = append(response, dto.NewSuspenseItemV2Response(item))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, response))
+}
+
+func (c *SuspenseController) GetItem(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionManageSuspense, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	item, err := c.Service.Item(r.Context(), r.PathValue("itemId"))
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to get suspense item.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewSuspenseItemV2Response(item)))
+}
+
+// ClaimItem moves a held item to the account it was meant for.
+func (c *SuspenseController) ClaimItem(w http.ResponseWriter, r *http.Request) {
+	var req dto.SuspenseClaimV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+	if req.AccountID == "" {
+		respondV2BadRequest(w, r, errors.New("accountId is required"), "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	if !authorizeV2(w, r, c.Policy, auth.ActionManageSuspense, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	item, err := c.Service.Claim(r.Context(), r.PathValue("itemId"), req.AccountID, req.Note)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to claim suspense item.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewSuspenseItemV2Response(item)))
+}
+
+// ReturnItem sends a held transfer back to its origin, or takes a held
+// deposit out of suspense when its cash is handed back.
+func (c *SuspenseController) ReturnItem(w http.ResponseWriter, r *http.Request) {
+	var req dto.SuspenseReturnV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Failed to decode request.", c.ErrorHandler)
+		return
+	}
+
+	if !authorizeV2(w, r, c.Policy, auth.ActionManageSuspense, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	item, err := c.Service.Return(r.Context(), r.PathValue("itemId"), req.Note)
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to return suspense item.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewSuspenseItemV2Response(item)))
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"net/http"
+)
+
+// ResetConfirmationHeader carries the token that confirms a reset.
+const ResetConfirmationHeader = "X-Reset-Confirmation"
+
+type SystemController struct {
+	Resetter     *service.ResetService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewSystemController(resetter *service.ResetService, policy *auth.Policy, errHandler utils.ErrorHandler) *SystemController {
+	return &SystemController{Resetter: resetter, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *SystemController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/system/reset", Handler: c.Reset},
+		{Method: http.MethodPost, Pattern: "/accounts/{accountId}/reset", Handler: c.ResetAccount},
+	}
+}
+
+func (c *SystemController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+func (c *SystemController) Reset(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReset, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	if err := c.Resetter.ResetAll(r.Context(), r.Header.Get(ResetConfirmationHeader)); err != nil {
+		respondV2Error(w, r, err, "Failed to reset system.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewMessageResponse("System has been reseted.")))
+}
+
+func (c *SystemController) ResetAccount(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionReset, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	accountID := r.PathValue("accountId")
+	r = withAccount(r, accountID)
+	if err := c.Resetter.ResetAccount(r.Context(), accountID, r.Header.Get(ResetConfirmationHeader)); err != nil {
+		respondV2Error(w, r, err, "Failed to reset account.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewMessageResponse("Account "+accountID+" has been reseted.")))
+}
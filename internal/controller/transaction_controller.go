@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"corebanking/internal/apierror"
+	"corebanking/internal/auth"
 	"corebanking/internal/dto"
 	"corebanking/internal/service"
 	"corebanking/internal/utils"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,38 +16,45 @@ import (
 
 type TransactionController struct {
 	Service      *service.TransactionService
+	Policy       *auth.Policy
 	ErrorHandler utils.ErrorHandler
 }
 
-func NewTransactionController(service *service.TransactionService, errHandler utils.ErrorHandler) *TransactionController {
-	return &TransactionController{Service: service, ErrorHandler: errHandler}
+func NewTransactionController(service *service.TransactionService, policy *auth.Policy, errHandler utils.ErrorHandler) *TransactionController {
+	return &TransactionController{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *TransactionController) Routes() []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/transactions", Handler: c.CreateTransaction},
+		{Method: http.MethodPost, Pattern: "/transactions/event", Handler: c.HandleTransactionEvent},
+		{Method: http.MethodGet, Pattern: "/transactions/today", Handler: c.GetTransactionsToday},
+		{Method: http.MethodGet, Pattern: "/transactions/range", Handler: c.GetTransactionsInRange},
+		{Method: http.MethodGet, Pattern: "/transactions/type/", Handler: c.GetTransactionsByType},
+		{Method: http.MethodGet, Pattern: "/transactions/", Handler: c.RouteTransaction},
+		{Method: http.MethodGet, Pattern: "/transactions/all", Handler: c.GetAllTransactions},
+	}
 }
 
 func (c *TransactionController) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
-	mux.HandleFunc(apiPrefix+"/transactions", c.CreateTransaction)
-	mux.HandleFunc(apiPrefix+"/transactions/event", c.HandleTransactionEvent)
-	mux.HandleFunc(apiPrefix+"/transactions/today", c.GetTransactionsToday)
-	mux.HandleFunc(apiPrefix+"/transactions/range", c.GetTransactionsInRange)
-	mux.HandleFunc(apiPrefix+"/transactions/type/", c.GetTransactionsByType)
-	mux.HandleFunc(apiPrefix+"/transactions/", c.RouteTransaction)
-	mux.HandleFunc(apiPrefix+"/transactions/all", c.GetAllTransactions)
+	registerRoutes(mux, apiPrefix, c.Routes())
 }
 
 func (c *TransactionController) RouteTransaction(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		utils.HandleHTTPError(w, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
 		return
 	}
 
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(parts) != 4 { // ["api", "v1", "transactions", "{transactionId}"]
-		utils.HandleHTTPError(w, nil, "Failed to split path.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to split path.", c.ErrorHandler)
 		return
 	}
 
 	id, err := strconv.ParseInt(parts[3], 10, 64)
 	if err != nil {
-		utils.HandleHTTPError(w, nil, "Failed to parse string to int.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to parse string to int.", c.ErrorHandler)
 		return
 	}
 
@@ -53,19 +63,28 @@ func (c *TransactionController) RouteTransaction(w http.ResponseWriter, r *http.
 
 func (c *TransactionController) CreateTransaction(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		utils.HandleHTTPError(w, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
 		return
 	}
 
 	var req dto.TransactionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.HandleHTTPError(w, nil, "Invalid request body.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Invalid request body.", c.ErrorHandler)
 		return
 	}
 
-	transaction, err := c.Service.CreateTransaction(&req)
+	r = withAccount(r, req.AccountID)
+	action, resource := auth.TransactionAuthorization(req.OperationTypeID, req.AccountID)
+	if !authorize(w, r, c.Policy, action, resource, c.ErrorHandler) {
+		return
+	}
+
+	transaction, err := c.Service.CreateTransaction(r.Context(), &req)
+	if respondHeld(w, err) {
+		return
+	}
 	if err != nil {
-		utils.HandleHTTPError(w, nil, "Failed to create request body.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to create request body.", c.ErrorHandler)
 		return
 	}
 
@@ -74,19 +93,27 @@ func (c *TransactionController) CreateTransaction(w http.ResponseWriter, r *http
 
 func (c *TransactionController) HandleTransactionEvent(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		utils.HandleHTTPError(w, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
 		return
 	}
 
 	var req dto.EventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.HandleHTTPError(w, nil, "Invalid request body.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Invalid request body.", c.ErrorHandler)
+		return
+	}
+
+	action, resource := auth.EventAuthorization(req.Type, req.Origin, req.Destination)
+	if !authorize(w, r, c.Policy, action, resource, c.ErrorHandler) {
 		return
 	}
 
-	result, err := c.Service.HandleTransaction(&req)
+	result, err := c.Service.HandleTransaction(r.Context(), &req)
+	if respondHeld(w, err) {
+		return
+	}
 	if err != nil {
-		utils.HandleHTTPError(w, nil, "Invalid request body.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Invalid request body.", c.ErrorHandler)
 		return
 	}
 
@@ -94,9 +121,13 @@ func (c *TransactionController) HandleTransactionEvent(w http.ResponseWriter, r
 }
 
 func (c *TransactionController) GetTransactionByID(w http.ResponseWriter, r *http.Request, id int64) {
-	transaction, err := c.Service.GetTransactionByID(id)
+	r = withTransaction(r, id)
+	transaction, err := c.Service.GetTransactionByID(r.Context(), id)
+	if err == nil && !canReadTransaction(r, c.Policy, transaction, c.ErrorHandler) {
+		err = service.ErrTransactionNotFound
+	}
 	if err != nil {
-		utils.HandleHTTPError(w, nil, "Failed to recovery transactionByID.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to recovery transactionByID.", c.ErrorHandler)
 		return
 	}
 
@@ -104,62 +135,91 @@ func (c *TransactionController) GetTransactionByID(w http.ResponseWriter, r *htt
 }
 
 func (c *TransactionController) GetTransactionsToday(w http.ResponseWriter, r *http.Request) {
-	transactions := c.Service.GetTransactionsToday()
+	if !authorize(w, r, c.Policy, auth.ActionListTransactions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	transactions := c.Service.GetTransactionsToday(r.Context())
 	respondJSON(w, http.StatusOK, transactions)
 }
 
 func (c *TransactionController) GetTransactionsInRange(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, c.Policy, auth.ActionListTransactions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
 	beginStr := r.URL.Query().Get("begin")
 	endStr := r.URL.Query().Get("end")
 
 	if beginStr == "" || endStr == "" {
-		utils.HandleHTTPError(w, nil, "Failed to recovery paremeters begin or end date.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to recovery paremeters begin or end date.", c.ErrorHandler)
 		return
 	}
 
 	begin, err := time.Parse(time.RFC3339, beginStr)
 	if err != nil {
-		utils.HandleHTTPError(w, nil, "Failed in parse begin date time format data.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed in parse begin date time format data.", c.ErrorHandler)
 		return
 	}
 
 	end, err := time.Parse(time.RFC3339, endStr)
 	if err != nil {
-		utils.HandleHTTPError(w, nil, "Failed in parse end date time format data.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed in parse end date time format data.", c.ErrorHandler)
 		return
 	}
 
-	transactions := c.Service.GetTransactionsInRange(begin, end)
+	transactions := c.Service.GetTransactionsInRange(r.Context(), begin, end)
 	respondJSON(w, http.StatusOK, transactions)
 }
 
 func (c *TransactionController) GetTransactionsByType(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, c.Policy, auth.ActionListTransactions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(parts) != 5 { // ["api","transactions","type","{operationTypeId}"]
-		utils.HandleHTTPError(w, nil, "Failed to split path.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to split path.", c.ErrorHandler)
 		return
 	}
 
 	typeID, err := strconv.Atoi(parts[4])
 	if err != nil {
-		utils.HandleHTTPError(w, nil, "Failed parse data in transactionByType.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed parse data in transactionByType.", c.ErrorHandler)
 		return
 	}
 
-	transactions := c.Service.GetTransactionsByType(typeID)
+	transactions := c.Service.GetTransactionsByType(r.Context(), typeID)
 	respondJSON(w, http.StatusOK, transactions)
 }
 
 func (c *TransactionController) GetAllTransactions(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		utils.HandleHTTPError(w, nil, "Failed to instance method RESTful.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "Failed to instance method RESTful.", c.ErrorHandler)
 		return
 	}
 
-	transactions := c.Service.GetAllTransactions()
+	if !authorize(w, r, c.Policy, auth.ActionListTransactions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	transactions := c.Service.GetAllTransactions(r.Context())
 	if len(transactions) == 0 {
-		utils.HandleHTTPError(w, nil, "No transactions found.", c.ErrorHandler)
+		utils.HandleHTTPError(w, r, nil, "No transactions found.", c.ErrorHandler)
 		return
 	}
 	respondJSON(w, http.StatusOK, transactions)
 }
+
+// respondHeld answers a held posting with 202 and the case or suspense item
+// holding it, and reports whether err was a hold. A hold is not a failure:
+// the posting waits on a decision or already sits in suspense.
+func respondHeld(w http.ResponseWriter, err error) bool {
+	var hold *service.HoldError
+	if !errors.As(err, &hold) {
+		return false
+	}
+	_, code := apierror.Status(err)
+	respondJSON(w, http.StatusAccepted, dto.HoldResponse{Status: code, HoldID: hold.ID, Message: hold.Error()})
+	return true
+}
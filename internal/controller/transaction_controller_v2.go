@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type TransactionControllerV2 struct {
+	Service      *service.TransactionService
+	Policy       *auth.Policy
+	ErrorHandler utils.ErrorHandler
+}
+
+func NewTransactionControllerV2(service *service.TransactionService, policy *auth.Policy, errHandler utils.ErrorHandler) *TransactionControllerV2 {
+	return &TransactionControllerV2{Service: service, Policy: policy, ErrorHandler: errHandler}
+}
+
+func (c *TransactionControllerV2) Routes() []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/transactions", Handler: c.CreateTransaction},
+		{Method: http.MethodGet, Pattern: "/transactions", Handler: c.ListTransactions},
+		{Method: http.MethodGet, Pattern: "/transactions/{transactionId}", Handler: c.GetTransaction},
+		{Method: http.MethodPost, Pattern: "/events", Handler: c.HandleEvent},
+	}
+}
+
+func (c *TransactionControllerV2) RegisterRoutes(mux *http.ServeMux, apiPrefix string) {
+	registerMethodRoutes(mux, apiPrefix, c.Routes())
+}
+
+func (c *TransactionControllerV2) CreateTransaction(w http.ResponseWriter, r *http.Request) {
+	var req dto.TransactionV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Invalid request body.", c.ErrorHandler)
+		return
+	}
+
+	r = withAccount(r, req.AccountID)
+	action, resource := auth.TransactionAuthorization(req.OperationTypeID, req.AccountID)
+	if !authorizeV2(w, r, c.Policy, action, resource, c.ErrorHandler) {
+		return
+	}
+
+	transaction, err := c.Service.CreateTransaction(r.Context(), &dto.TransactionRequest{
+		AccountID:       req.AccountID,
+		OperationTypeID: req.OperationTypeID,
+		Amount:          int64(req.Amount),
+	})
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to create transaction.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusCreated, dto.NewDataEnvelope(v2, dto.NewTransactionV2Response(transaction)))
+}
+
+func (c *TransactionControllerV2) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("transactionId"), 10, 64)
+	if err != nil {
+		respondV2BadRequest(w, r, err, "Failed to parse transactionId.", c.ErrorHandler)
+		return
+	}
+	r = withTransaction(r, id)
+
+	transaction, err := c.Service.GetTransactionByID(r.Context(), id)
+	if err == nil && !canReadTransaction(r, c.Policy, transaction, c.ErrorHandler) {
+		err = service.ErrTransactionNotFound
+	}
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to recovery transactionByID.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusOK, dto.NewDataEnvelope(v2, dto.NewTransactionV2Response(transaction)))
+}
+
+// ListTransactions replaces the v1 today/range/type/all endpoints with query
+// filters: date=today, begin and end (RFC 3339), or operationTypeId.
+func (c *TransactionControllerV2) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	if !authorizeV2(w, r, c.Policy, auth.ActionListTransactions, auth.Resource{}, c.ErrorHandler) {
+		return
+	}
+
+	query := r.URL.Query()
+
+	var transactions []*dto.TransactionResponse
+	switch {
+	case query.Get("date") != "":
+		if query.Get("date") != "today" {
+			respondV2BadRequest(w, r, nil, "Unsupported date filter, use date=today.", c.ErrorHandler)
+			return
+		}
+		transactions = c.Service.GetTransactionsToday(r.Context())
+	case query.Get("begin") != "" || query.Get("end") != "":
+		begin, err := time.Parse(time.RFC3339, query.Get("begin"))
+		if err != nil {
+			respondV2BadRequest(w, r, err, "Failed in parse begin date time format data.", c.ErrorHandler)
+			return
+		}
+		end, err := time.Parse(time.RFC3339, query.Get("end"))
+		if err != nil {
+			respondV2BadRequest(w, r, err, "Failed in parse end date time format data.", c.ErrorHandler)
+			return
+		}
+		transactions = c.Service.GetTransactionsInRange(r.Context(), begin, end)
+	case query.Get("operationTypeId") != "":
+		typeID, err := strconv.Atoi(query.Get("operationTypeId"))
+		if err != nil {
+			respondV2BadRequest(w, r, err, "Failed parse data in operationTypeId.", c.ErrorHandler)
+			return
+		}
+		if typeID < 1 || typeID > 4 {
+			respondV2Error(w, r, service.ErrInvalidOperationType, "Failed parse data in operationTypeId.", c.ErrorHandler)
+			return
+		}
+		transactions = c.Service.GetTransactionsByType(r.Context(), typeID)
+	default:
+		for _, t := range c.Service.GetAllTransactions(r.Context()) {
+			transactions = append(transactions, &dto.TransactionResponse{
+				TransactionID:   t.TransactionID,
+				AccountID:       t.AccountID,
+				OperationTypeID: t.OperationTypeID,
+				Amount:          t.Amount,
+				EventDate:       t.EventDate,
+			})
+		}
+	}
+
+	result := make([]dto.TransactionV2Response, 0, len(transactions))
+	for _, t := range transactions {
+		result = append(result, dto.NewTransactionV2Response(t))
+	}
+	respondEnvelope(w, http.StatusOK, dto.NewListEnvelope(v2, result))
+}
+
+func (c *TransactionControllerV2) HandleEvent(w http.ResponseWriter, r *http.Request) {
+	var req dto.EventV2Request
+	if err := decodeV2(r, &req); err != nil {
+		respondV2BadRequest(w, r, err, "Invalid request body.", c.ErrorHandler)
+		return
+	}
+
+	action, resource := auth.EventAuthorization(req.Type, req.Origin, req.Destination)
+	if !authorizeV2(w, r, c.Policy, action, resource, c.ErrorHandler) {
+		return
+	}
+
+	result, err := c.Service.HandleTransaction(r.Context(), &dto.EventRequest{
+		Type:        req.Type,
+		Origin:      req.Origin,
+		Destination: req.Destination,
+		Amount:      int64(req.Amount),
+	})
+	if err != nil {
+		respondV2Error(w, r, err, "Failed to handle event.", c.ErrorHandler)
+		return
+	}
+
+	accounts, ok := result.(map[string]*domain.Account)
+	if !ok {
+		respondV2Error(w, r, fmt.Errorf("unexpected event result %T", result), "Failed to handle event.", c.ErrorHandler)
+		return
+	}
+
+	respondEnvelope(w, http.StatusCreated, dto.NewDataEnvelope(v2, dto.EventV2Response{
+		Origin:      accountStateV2(accounts["origin"]),
+		Destination: accountStateV2(accounts["destination"]),
+	}))
+}
+
+func accountStateV2(account *domain.Account) *dto.AccountStateV2 {
+	if account == nil {
+		return nil
+	}
+	return &dto.AccountStateV2{
+		AccountID:      account.ID,
+		Balance:        dto.Money(account.Balance),
+		OverdraftLimit: dto.Money(account.OverdraftLimit),
+	}
+}
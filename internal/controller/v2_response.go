@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"corebanking/internal/apierror"
+	"corebanking/internal/dto"
+	"corebanking/internal/utils"
+	"encoding/json"
+	"net/http"
+)
+
+const v2 = "v2"
+
+func respondEnvelope(w http.ResponseWriter, status int, envelope dto.Envelope) {
+	respondJSON(w, status, envelope)
+}
+
+// respondV2Error logs the failure through the error handler and writes it
+// as an error envelope with a status derived from the service error.
+func respondV2Error(w http.ResponseWriter, r *http.Request, err error, message string, logger utils.ErrorHandler) {
+	if logger != nil {
+		logger.Handle(r.Context(), err, message)
+	}
+
+	status, code := apierror.Status(err)
+	respondEnvelope(w, status, dto.NewErrorEnvelope(v2, code, message, err))
+}
+
+func respondV2BadRequest(w http.ResponseWriter, r *http.Request, err error, message string, logger utils.ErrorHandler) {
+	if logger != nil {
+		logger.Handle(r.Context(), err, message)
+	}
+
+	respondEnvelope(w, http.StatusBadRequest, dto.NewErrorEnvelope(v2, "invalid_request", message, err))
+}
+
+func decodeV2(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
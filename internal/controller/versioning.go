@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"corebanking/internal/dto"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const vendorMediaPrefix = "application/vnd.corebanking."
+
+var versionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+type Registrar interface {
+	Routes() []Route
+	RegisterRoutes(mux *http.ServeMux, apiPrefix string)
+}
+
+// APIVersion groups the controllers served under /api/{Name}. Deprecated
+// versions answer with Deprecation, Sunset and successor Link headers.
+type APIVersion struct {
+	Name       string
+	Deprecated bool
+	Sunset     time.Time
+	Successor  string
+	Registrars []Registrar
+}
+
+// NewVersionedRouter serves every version under its own path prefix and
+// negotiates unversioned /api/... requests through the Accept header,
+// falling back to defaultVersion.
+func NewVersionedRouter(defaultVersion string, versions ...APIVersion) http.Handler {
+	mux := http.NewServeMux()
+	known := make(map[string]bool, len(versions))
+
+	for _, version := range versions {
+		apiPrefix := "/api/" + version.Name
+		versionMux := http.NewServeMux()
+		for _, registrar := range version.Registrars {
+			registrar.RegisterRoutes(versionMux, apiPrefix)
+		}
+
+		var handler http.Handler = versionMux
+		if version.Deprecated {
+			handler = deprecationHeaders(handler, version)
+		}
+		mux.Handle(apiPrefix+"/", handler)
+		known[version.Name] = true
+	}
+
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, "/api")
+		segment, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+		if versionSegment.MatchString(segment) {
+			respondJSON(w, http.StatusNotFound, dto.NewErrorResponse("Unsupported API version.", nil))
+			return
+		}
+
+		version := negotiateVersion(r.Header.Get("Accept"), known)
+		if version == "" {
+			version = defaultVersion
+		}
+
+		w.Header().Add("Vary", "Accept")
+		routed := new(http.Request)
+		*routed = *r
+		routedURL := *r.URL
+		routedURL.Path = "/api/" + version + rest
+		routedURL.RawPath = ""
+		routed.URL = &routedURL
+		mux.ServeHTTP(w, routed)
+	})
+
+	return mux
+}
+
+// negotiateVersion reads application/vnd.corebanking.v2+json or a
+// version=2 media type parameter from the Accept header.
+func negotiateVersion(accept string, known map[string]bool) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		candidate := ""
+		if rest, found := strings.CutPrefix(mediaType, vendorMediaPrefix); found {
+			candidate, _, _ = strings.Cut(rest, "+")
+		} else if value, exists := params["version"]; exists {
+			candidate = "v" + strings.TrimPrefix(value, "v")
+		}
+
+		if known[candidate] {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func deprecationHeaders(next http.Handler, version APIVersion) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		if !version.Sunset.IsZero() {
+			w.Header().Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if version.Successor != "" {
+			w.Header().Set("Link", `</api/`+version.Successor+`>; rel="successor-version"`)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -4,6 +4,15 @@ type Account struct {
 	ID             string `json:"id"`
 	Balance        int64  `json:"balance"`
 	OverdraftLimit int64  `json:"overdraft_limit"`
+	// Product picks the account's default limits; empty is the default
+	// product. Limits, when set, replaces the product's limits.
+	Product string  `json:"product,omitempty"`
+	Limits  *Limits `json:"limits,omitempty"`
+	// HolderName is the name given at onboarding, if any.
+	HolderName string `json:"holderName,omitempty"`
+	// Internal accounts, such as the suspense account, belong to the bank:
+	// deposits and transfers can't name them.
+	Internal bool `json:"internal,omitempty"`
 }
 
 func NewAccount(id string, balance int64) *Account {
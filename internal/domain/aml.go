@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// Kinds of money movement AML monitoring sees. Cash deposits are the
+// deposit events; credits are every other credit, debits every debit.
+const (
+	MovementCashDeposit = "cash_deposit"
+	MovementCredit      = "credit"
+	MovementDebit       = "debit"
+)
+
+// AMLMovement is one posting to an account.
+type AMLMovement struct {
+	At     time.Time `json:"at"`
+	Kind   string    `json:"kind"`
+	Amount int64     `json:"amount"`
+}
+
+// IsCredit reports whether the movement brought money into the account.
+func (m AMLMovement) IsCredit() bool {
+	return m.Kind != MovementDebit
+}
+
+// AMLHistory is an account's recent movements, oldest first.
+type AMLHistory struct {
+	Movements []AMLMovement `json:"movements"`
+}
+
+// Add appends a movement and drops those older than keep.
+func (h *AMLHistory) Add(movement AMLMovement, keep time.Duration) {
+	h.Movements = append(h.Movements, movement)
+
+	cutoff := movement.At.Add(-keep)
+	drop := 0
+	for drop < len(h.Movements) && h.Movements[drop].At.Before(cutoff) {
+		drop++
+	}
+	h.Movements = h.Movements[drop:]
+}
+
+// AML alert statuses. Open alerts wait for an analyst, who dispositions
+// them as one of the others; suspicious ones go into activity reports.
+const (
+	AlertOpen          = "open"
+	AlertFalsePositive = "false_positive"
+	AlertNoAction      = "no_action"
+	AlertSuspicious    = "suspicious"
+)
+
+// AMLAlert is raised when an account's movements match a monitoring rule.
+// While it is open, further matches of the same rule update it.
+type AMLAlert struct {
+	ID        string `json:"id"`
+	AccountID string `json:"accountId"`
+	Rule      string `json:"rule"`
+	Status    string `json:"status"`
+	// Amount and Count are the movements that matched, between WindowStart
+	// and WindowEnd.
+	Amount      int64     `json:"amount"`
+	Count       int       `json:"count"`
+	WindowStart time.Time `json:"windowStart"`
+	WindowEnd   time.Time `json:"windowEnd"`
+	Details     string    `json:"details"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	DispositionedAt *time.Time `json:"dispositionedAt,omitempty"`
+	DispositionedBy string     `json:"dispositionedBy,omitempty"`
+	Note            string     `json:"note,omitempty"`
+}
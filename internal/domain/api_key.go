@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// APIKey stores only SHA-256 hashes of the secret. After a rotation the
+// previous hash stays valid until PreviousExpiresAt so clients can roll over.
+type APIKey struct {
+	ID                string     `json:"id"`
+	Hash              string     `json:"hash"`
+	Subject           string     `json:"subject"`
+	Roles             []string   `json:"roles"`
+	Tier              string     `json:"tier,omitempty"`
+	PreviousHash      string     `json:"previousHash,omitempty"`
+	PreviousExpiresAt time.Time  `json:"previousExpiresAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	RotatedAt         time.Time  `json:"rotatedAt,omitempty"`
+	RevokedAt         *time.Time `json:"revokedAt,omitempty"`
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
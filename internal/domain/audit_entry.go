@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records one state change. Hash covers every other field plus
+// the previous entry's hash, chaining the log so edits are detectable.
+type AuditEntry struct {
+	Sequence   int64           `json:"sequence"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Actor      string          `json:"actor"`
+	ActorRoles []string        `json:"actorRoles,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+	PrevHash   string          `json:"prevHash"`
+	Hash       string          `json:"hash"`
+}
@@ -0,0 +1,101 @@
+package domain
+
+import "time"
+
+// Batch modes. An all-or-nothing batch posts every item or, when one
+// fails, undoes the ones it posted; a best-effort batch posts what it can.
+const (
+	BatchAllOrNothing = "all_or_nothing"
+	BatchBestEffort   = "best_effort"
+)
+
+// Batch statuses. A batch is completed once every item was tried, even
+// when some failed in best-effort mode; failed means an all-or-nothing
+// batch was rolled back.
+const (
+	BatchPending    = "pending"
+	BatchProcessing = "processing"
+	BatchCompleted  = "completed"
+	BatchFailed     = "failed"
+)
+
+// Batch item statuses. Held items wait for a fraud or sanctions review or
+// in suspense, and only happen in best-effort mode: all-or-nothing batches
+// count them as failures. Skipped items were not tried because an earlier
+// one failed.
+const (
+	BatchItemPending    = "pending"
+	BatchItemPosted     = "posted"
+	BatchItemHeld       = "held"
+	BatchItemFailed     = "failed"
+	BatchItemRolledBack = "rolled_back"
+	BatchItemSkipped    = "skipped"
+)
+
+// Batch item types: a transaction by operation type, or an event.
+const (
+	BatchItemTransaction = "transaction"
+	BatchItemDeposit     = "deposit"
+	BatchItemWithdraw    = "withdraw"
+	BatchItemTransfer    = "transfer"
+)
+
+// BatchItem is one posting of a batch. Transactions use AccountID and
+// OperationTypeID, events Origin and Destination. Reference is the
+// client's own, echoed back in the result.
+type BatchItem struct {
+	Type            string `json:"type"`
+	AccountID       string `json:"accountId,omitempty"`
+	OperationTypeID int    `json:"operationTypeId,omitempty"`
+	Origin          string `json:"origin,omitempty"`
+	Destination     string `json:"destination,omitempty"`
+	Amount          int64  `json:"amount"`
+	Reference       string `json:"reference,omitempty"`
+}
+
+// Accounts returns the accounts the item moves money from or to.
+func (i BatchItem) Accounts() []string {
+	switch i.Type {
+	case BatchItemTransaction:
+		return []string{i.AccountID}
+	case BatchItemDeposit:
+		return []string{i.Destination}
+	case BatchItemWithdraw:
+		return []string{i.Origin}
+	default:
+		return []string{i.Origin, i.Destination}
+	}
+}
+
+// BatchItemResult is the outcome of one item. TransactionID is set for
+// posted transactions; Err is the error behind a held or failed item.
+type BatchItemResult struct {
+	Status        string `json:"status"`
+	TransactionID int64  `json:"transactionId,omitempty"`
+	Err           error  `json:"-"`
+}
+
+type Batch struct {
+	ID      string            `json:"id"`
+	Mode    string            `json:"mode"`
+	Status  string            `json:"status"`
+	Items   []BatchItem       `json:"items"`
+	Results []BatchItemResult `json:"results"`
+	// Error says why undoing an all-or-nothing batch did not complete;
+	// the items it could not undo stay posted.
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CreatedBy   string     `json:"createdBy,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// Count returns how many items have the status.
+func (b *Batch) Count(status string) int {
+	count := 0
+	for _, result := range b.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
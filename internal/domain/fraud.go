@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// Kinds of postings kept in an account's fraud history.
+const (
+	ActivityPurchase = "purchase"
+	ActivityWithdraw = "withdraw"
+	ActivityTransfer = "transfer"
+	ActivityCredit   = "credit"
+)
+
+// FraudActivity is one posting to an account.
+type FraudActivity struct {
+	At          time.Time `json:"at"`
+	Kind        string    `json:"kind"`
+	Amount      int64     `json:"amount"`
+	Destination string    `json:"destination,omitempty"`
+}
+
+// IsDebit reports whether the posting took money out of the account.
+func (a FraudActivity) IsDebit() bool {
+	return a.Kind != ActivityCredit
+}
+
+// FraudHistory is what fraud rules know about an account: when it was first
+// posted to and its recent postings, oldest first.
+type FraudHistory struct {
+	FirstSeen time.Time       `json:"firstSeen"`
+	Activity  []FraudActivity `json:"activity"`
+}
+
+// Add appends a posting and drops those older than keep.
+func (h *FraudHistory) Add(activity FraudActivity, keep time.Duration) {
+	if h.FirstSeen.IsZero() {
+		h.FirstSeen = activity.At
+	}
+	h.Activity = append(h.Activity, activity)
+
+	cutoff := activity.At.Add(-keep)
+	drop := 0
+	for drop < len(h.Activity) && h.Activity[drop].At.Before(cutoff) {
+		drop++
+	}
+	h.Activity = h.Activity[drop:]
+}
+
+// Fraud case statuses. Pending cases hold a posting until it is approved,
+// which posts it, or rejected, which drops it.
+const (
+	FraudCasePending  = "pending"
+	FraudCaseApproved = "approved"
+	FraudCaseRejected = "rejected"
+)
+
+// FraudCase is a posting held for review, with what is needed to post it
+// once approved.
+type FraudCase struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	AccountID string `json:"accountId"`
+	Kind      string `json:"kind"`
+	// OperationTypeID is set for transactions, zero for events.
+	OperationTypeID int        `json:"operationTypeId,omitempty"`
+	Destination     string     `json:"destination,omitempty"`
+	Amount          int64      `json:"amount"`
+	Score           int        `json:"score"`
+	Reasons         []string   `json:"reasons"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	DecidedAt       *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy       string     `json:"decidedBy,omitempty"`
+	Note            string     `json:"note,omitempty"`
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Limits cap an account's debits. Amounts are in minor units; a zero field
+// is no cap.
+type Limits struct {
+	MaxTransactionAmount int64 `json:"maxTransactionAmount,omitempty"`
+	DailyDebitLimit      int64 `json:"dailyDebitLimit,omitempty"`
+	MonthlyDebitLimit    int64 `json:"monthlyDebitLimit,omitempty"`
+	DailyWithdrawalCount int   `json:"dailyWithdrawalCount,omitempty"`
+	// NightTransferLimit caps the total transferred out during one night
+	// window.
+	NightTransferLimit int64 `json:"nightTransferLimit,omitempty"`
+}
+
+// Validate rejects negative caps.
+func (l Limits) Validate() error {
+	if l.MaxTransactionAmount < 0 || l.DailyDebitLimit < 0 || l.MonthlyDebitLimit < 0 ||
+		l.DailyWithdrawalCount < 0 || l.NightTransferLimit < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	return nil
+}
+
+// LimitUsage is what an account has debited in the current day, month and
+// night window, each identified by its key so a new period starts at zero.
+type LimitUsage struct {
+	Day              string `json:"day"`
+	DailyDebits      int64  `json:"dailyDebits"`
+	DailyWithdrawals int    `json:"dailyWithdrawals"`
+	Month            string `json:"month"`
+	MonthlyDebits    int64  `json:"monthlyDebits"`
+	Night            string `json:"night,omitempty"`
+	NightTransfers   int64  `json:"nightTransfers"`
+}
+
+// Roll starts new periods: counters of a period other than day, month or
+// night are zeroed. An empty night means the current time is outside the
+// night window.
+func (u *LimitUsage) Roll(day, month, night string) {
+	if u.Day != day {
+		u.Day, u.DailyDebits, u.DailyWithdrawals = day, 0, 0
+	}
+	if u.Month != month {
+		u.Month, u.MonthlyDebits = month, 0
+	}
+	if u.Night != night {
+		u.Night, u.NightTransfers = night, 0
+	}
+}
+
+var productLimitFields = map[string]func(*Limits, int64){
+	"max_transaction":   func(l *Limits, v int64) { l.MaxTransactionAmount = v },
+	"daily_debit":       func(l *Limits, v int64) { l.DailyDebitLimit = v },
+	"monthly_debit":     func(l *Limits, v int64) { l.MonthlyDebitLimit = v },
+	"daily_withdrawals": func(l *Limits, v int64) { l.DailyWithdrawalCount = int(v) },
+	"night_transfer":    func(l *Limits, v int64) { l.NightTransferLimit = v },
+}
+
+// ParseProductLimits reads comma separated <product>.<limit>=<value> pairs,
+// e.g. "standard.daily_debit=1000000,premium.daily_debit=0". Limits are
+// max_transaction, daily_debit, monthly_debit and night_transfer in minor
+// units, and daily_withdrawals as a count.
+func ParseProductLimits(value string) (map[string]Limits, error) {
+	products := make(map[string]Limits)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		product, limit, dotted := strings.Cut(strings.TrimSpace(name), ".")
+		if !ok || !dotted || product == "" {
+			return nil, fmt.Errorf("invalid product limit %q, want <product>.<limit>=<value>", pair)
+		}
+		set, known := productLimitFields[limit]
+		if !known {
+			return nil, fmt.Errorf("product %s: unknown limit %q", product, limit)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("product %s: %s must be a non-negative integer, got %q", product, limit, raw)
+		}
+
+		limits := products[product]
+		set(&limits, n)
+		products[product] = limits
+	}
+	return products, nil
+}
+
+// ProductNames lists products in order.
+func ProductNames(products map[string]Limits) []string {
+	names := make([]string, 0, len(products))
+	for name := range products {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
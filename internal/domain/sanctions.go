@@ -0,0 +1,96 @@
+package domain
+
+import "time"
+
+// Watchlists an entry can be on: sanctions, which prohibit business, and
+// politically exposed persons, who need enhanced due diligence.
+const (
+	ListSanctions = "sanctions"
+	ListPEP       = "pep"
+)
+
+// SanctionsEntry is a listed person or organisation.
+type SanctionsEntry struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Documents []string `json:"documents,omitempty"`
+	List      string   `json:"list"`
+	Program   string   `json:"program,omitempty"`
+}
+
+// What is screened: a customer being onboarded or the holder of a transfer
+// destination.
+const (
+	ScreeningOnboarding = "onboarding"
+	ScreeningTransfer   = "transfer"
+)
+
+// How a hit matched.
+const (
+	MatchDocument = "document"
+	MatchName     = "name"
+)
+
+// SanctionsHit is an entry a subject matched. Cleared hits were already
+// reviewed and found not to be the subject; they don't hold anything.
+type SanctionsHit struct {
+	EntryID string `json:"entryId"`
+	// Name is the listed name or alias that matched.
+	Name    string  `json:"name"`
+	List    string  `json:"list"`
+	Program string  `json:"program,omitempty"`
+	Match   string  `json:"match"`
+	Score   float64 `json:"score"`
+	Cleared bool    `json:"cleared,omitempty"`
+}
+
+// SanctionsScreening records one screening and the list version it ran
+// against, hits or not.
+type SanctionsScreening struct {
+	ID             string `json:"id"`
+	Kind           string `json:"kind"`
+	DocumentNumber string `json:"documentNumber"`
+	HolderName     string `json:"holderName,omitempty"`
+	// AccountID is the transfer destination, or the account opened by a
+	// clear onboarding.
+	AccountID   string         `json:"accountId,omitempty"`
+	Origin      string         `json:"origin,omitempty"`
+	Amount      int64          `json:"amount,omitempty"`
+	ListVersion string         `json:"listVersion"`
+	ListDigest  string         `json:"listDigest"`
+	Hits        []SanctionsHit `json:"hits"`
+	CaseID      string         `json:"caseId,omitempty"`
+	At          time.Time      `json:"at"`
+}
+
+// Sanctions case statuses. Pending cases hold an onboarding or transfer
+// until it is approved, which clears the hits and carries it out, or
+// rejected, which drops it.
+const (
+	SanctionsCasePending  = "pending"
+	SanctionsCaseApproved = "approved"
+	SanctionsCaseRejected = "rejected"
+)
+
+// SanctionsCase is a screening with uncleared hits, held for review with
+// what is needed to carry it out once approved.
+type SanctionsCase struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	Kind           string `json:"kind"`
+	ScreeningID    string `json:"screeningId"`
+	DocumentNumber string `json:"documentNumber"`
+	HolderName     string `json:"holderName,omitempty"`
+	// AccountID is the transfer destination, or the account opened when an
+	// onboarding is approved.
+	AccountID   string         `json:"accountId,omitempty"`
+	Origin      string         `json:"origin,omitempty"`
+	Amount      int64          `json:"amount,omitempty"`
+	ListVersion string         `json:"listVersion"`
+	Hits        []SanctionsHit `json:"hits"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	DecidedAt   *time.Time     `json:"decidedAt,omitempty"`
+	DecidedBy   string         `json:"decidedBy,omitempty"`
+	Note        string         `json:"note,omitempty"`
+}
@@ -0,0 +1,144 @@
+package domain
+
+import "time"
+
+// Schedule frequencies. A once schedule is a single future-dated transfer;
+// the others are standing orders repeating from StartAt.
+const (
+	ScheduleOnce    = "once"
+	ScheduleDaily   = "daily"
+	ScheduleWeekly  = "weekly"
+	ScheduleMonthly = "monthly"
+)
+
+// Schedule statuses. Active schedules run when due and paused ones skip the
+// occurrences that fall due meanwhile. Cancelled and completed schedules
+// never run again.
+const (
+	ScheduleActive    = "active"
+	SchedulePaused    = "paused"
+	ScheduleCancelled = "cancelled"
+	ScheduleCompleted = "completed"
+)
+
+// Execution statuses. Posted executions moved the money and held ones wait
+// for fraud or sanctions review or in suspense; neither is retried.
+// Retrying executions failed for lack of funds and run again later. Failed
+// ones are given up on, and interrupted ones were started by an instance
+// that stopped before recording the outcome, so whether they posted is
+// unknown and they are not run again.
+const (
+	ExecutionPosted      = "posted"
+	ExecutionHeld        = "held"
+	ExecutionRetrying    = "retrying"
+	ExecutionFailed      = "failed"
+	ExecutionInterrupted = "interrupted"
+)
+
+// Schedule is a transfer from Origin to Destination posted at StartAt and,
+// unless it runs once, repeated at Frequency until EndAt or Count
+// transfers, whichever comes first.
+type Schedule struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Origin      string     `json:"origin"`
+	Destination string     `json:"destination"`
+	Amount      int64      `json:"amount"`
+	Frequency   string     `json:"frequency"`
+	StartAt     time.Time  `json:"startAt"`
+	EndAt       *time.Time `json:"endAt,omitempty"`
+	// Count caps the occurrences run; zero runs until EndAt, or forever.
+	Count       int       `json:"count,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+
+	// Index is the occurrence due next, counting from 0 at StartAt, and
+	// Executed how many occurrences ran, whatever their outcome; the two
+	// differ by the occurrences skipped while paused. Attempts counts the
+	// failed attempts at occurrence Index. NextRunAt is when it runs, later
+	// than its due time while retrying, and nil once the schedule is
+	// finished.
+	Index     int        `json:"index"`
+	Executed  int        `json:"executed"`
+	Attempts  int        `json:"attempts,omitempty"`
+	NextRunAt *time.Time `json:"nextRunAt,omitempty"`
+
+	// ClaimedBy is the instance running the due occurrence, until
+	// ClaimedUntil.
+	ClaimedBy    string     `json:"claimedBy,omitempty"`
+	ClaimedUntil *time.Time `json:"claimedUntil,omitempty"`
+}
+
+// ValidFrequency reports whether frequency is one of the schedule
+// frequencies.
+func ValidFrequency(frequency string) bool {
+	switch frequency {
+	case ScheduleOnce, ScheduleDaily, ScheduleWeekly, ScheduleMonthly:
+		return true
+	}
+	return false
+}
+
+// Occurrence returns when occurrence n is due. Monthly occurrences keep
+// StartAt's day, or the month's last day in shorter months.
+func (s *Schedule) Occurrence(n int) time.Time {
+	switch s.Frequency {
+	case ScheduleDaily:
+		return s.StartAt.AddDate(0, 0, n)
+	case ScheduleWeekly:
+		return s.StartAt.AddDate(0, 0, 7*n)
+	case ScheduleMonthly:
+		year, month, day := s.StartAt.Date()
+		first := time.Date(year, month+time.Month(n), 1, 0, 0, 0, 0, s.StartAt.Location())
+		if last := first.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		hour, minute, second := s.StartAt.Clock()
+		return time.Date(first.Year(), first.Month(), day, hour, minute, second, s.StartAt.Nanosecond(), s.StartAt.Location())
+	default:
+		return s.StartAt
+	}
+}
+
+// DueAt is when the occurrence due next was due.
+func (s *Schedule) DueAt() time.Time {
+	return s.Occurrence(s.Index)
+}
+
+// Finished reports whether the schedule will never run again.
+func (s *Schedule) Finished() bool {
+	return s.Status == ScheduleCancelled || s.Status == ScheduleCompleted
+}
+
+// Advance moves past the occurrence due next, counting it as executed when
+// executed is true, and completes the schedule when no occurrence is left.
+func (s *Schedule) Advance(executed bool) {
+	s.Index++
+	if executed {
+		s.Executed++
+	}
+	s.Attempts = 0
+	next := s.DueAt()
+	if s.Frequency == ScheduleOnce || (s.Count > 0 && s.Executed >= s.Count) || (s.EndAt != nil && next.After(*s.EndAt)) {
+		s.Status = ScheduleCompleted
+		s.NextRunAt = nil
+		return
+	}
+	s.NextRunAt = &next
+}
+
+// ScheduleExecution records one attempt at running a schedule's
+// occurrence.
+type ScheduleExecution struct {
+	ID         string    `json:"id"`
+	ScheduleID string    `json:"scheduleId"`
+	Occurrence int       `json:"occurrence"`
+	DueAt      time.Time `json:"dueAt"`
+	Attempt    int       `json:"attempt"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	ExecutedAt time.Time `json:"executedAt"`
+	// Instance is the server instance that ran it.
+	Instance string `json:"instance"`
+}
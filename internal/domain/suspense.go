@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Unknown destination policies: what a deposit or transfer to an account
+// that does not exist does. Reject declines it; suspense posts it to the
+// suspense account until it is claimed or returned.
+const (
+	UnknownDestinationReject   = "reject"
+	UnknownDestinationSuspense = "suspense"
+)
+
+// Suspense item statuses. Held items wait in the suspense account; claimed
+// ones were moved to the account they were meant for, returned ones back
+// to the origin, or paid out for deposits.
+const (
+	SuspenseHeld     = "held"
+	SuspenseClaimed  = "claimed"
+	SuspenseReturned = "returned"
+)
+
+// SuspenseItem is a deposit or transfer posted to the suspense account
+// because its destination does not exist.
+type SuspenseItem struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// Kind is deposit or transfer; deposits have no origin.
+	Kind        string    `json:"kind"`
+	Origin      string    `json:"origin,omitempty"`
+	Destination string    `json:"destination"`
+	Amount      int64     `json:"amount"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// AccountID is the account credited when the item was claimed or
+	// returned; returned deposits have none.
+	AccountID  string     `json:"accountId,omitempty"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	ResolvedBy string     `json:"resolvedBy,omitempty"`
+	Note       string     `json:"note,omitempty"`
+}
@@ -2,4 +2,6 @@ package dto
 
 type AccountRequest struct {
 	DocumentNumber string `json:"documentNumber"`
+	// HolderName is screened against sanctions lists when set.
+	HolderName string `json:"holderName,omitempty"`
 }
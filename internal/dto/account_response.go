@@ -3,6 +3,14 @@ package dto
 type AccountResponse struct {
 	AccountID      string `json:"accountId"`
 	DocumentNumber string `json:"documentNumber"`
+	HolderName     string `json:"holderName,omitempty"`
+}
+
+// AccountVerificationResponse confirms a destination account exists.
+// HolderName is masked, and empty for accounts opened without one.
+type AccountVerificationResponse struct {
+	AccountID  string `json:"accountId"`
+	HolderName string `json:"holderName,omitempty"`
 }
 
 func NewAccountResponse(accountID, documentNumber string) AccountResponse {
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"corebanking/internal/domain"
+	"time"
+)
+
+// AMLDispositionV2Request closes an open alert as false_positive, no_action
+// or suspicious.
+type AMLDispositionV2Request struct {
+	Disposition string `json:"disposition"`
+	Note        string `json:"note,omitempty"`
+}
+
+type AMLAlertV2Response struct {
+	ID              string     `json:"id"`
+	AccountID       string     `json:"accountId"`
+	Rule            string     `json:"rule"`
+	Status          string     `json:"status"`
+	Amount          Money      `json:"amount"`
+	Count           int        `json:"count"`
+	WindowStart     time.Time  `json:"windowStart"`
+	WindowEnd       time.Time  `json:"windowEnd"`
+	Details         string     `json:"details"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	DispositionedAt *time.Time `json:"dispositionedAt,omitempty"`
+	DispositionedBy string     `json:"dispositionedBy,omitempty"`
+	Note            string     `json:"note,omitempty"`
+}
+
+func NewAMLAlertV2Response(a *domain.AMLAlert) AMLAlertV2Response {
+	return AMLAlertV2Response{
+		ID:              a.ID,
+		AccountID:       a.AccountID,
+		Rule:            a.Rule,
+		Status:          a.Status,
+		Amount:          Money(a.Amount),
+		Count:           a.Count,
+		WindowStart:     a.WindowStart,
+		WindowEnd:       a.WindowEnd,
+		Details:         a.Details,
+		CreatedAt:       a.CreatedAt,
+		UpdatedAt:       a.UpdatedAt,
+		DispositionedAt: a.DispositionedAt,
+		DispositionedBy: a.DispositionedBy,
+		Note:            a.Note,
+	}
+}
+
+// SARFormat names the layout of SuspiciousActivityReport, so consumers can
+// tell versions apart.
+const SARFormat = "corebanking-sar/1"
+
+// SuspiciousActivityReport lists the alerts dispositioned as suspicious in
+// a period, grouped by account.
+type SuspiciousActivityReport struct {
+	Format      string       `json:"format"`
+	ReportID    string       `json:"reportId"`
+	GeneratedAt time.Time    `json:"generatedAt"`
+	From        *time.Time   `json:"from,omitempty"`
+	To          *time.Time   `json:"to,omitempty"`
+	Subjects    []SARSubject `json:"subjects"`
+}
+
+// SARSubject is one account in a report, with the movements still on
+// record in its alerts' windows.
+type SARSubject struct {
+	AccountID      string        `json:"accountId"`
+	DocumentNumber string        `json:"documentNumber,omitempty"`
+	Alerts         []SARAlert    `json:"alerts"`
+	Activity       []SARMovement `json:"activity"`
+}
+
+type SARAlert struct {
+	AlertID         string    `json:"alertId"`
+	Rule            string    `json:"rule"`
+	Amount          Money     `json:"amount"`
+	Count           int       `json:"count"`
+	WindowStart     time.Time `json:"windowStart"`
+	WindowEnd       time.Time `json:"windowEnd"`
+	Details         string    `json:"details"`
+	DispositionedAt time.Time `json:"dispositionedAt"`
+	DispositionedBy string    `json:"dispositionedBy"`
+	Note            string    `json:"note,omitempty"`
+}
+
+type SARMovement struct {
+	At     time.Time `json:"at"`
+	Kind   string    `json:"kind"`
+	Amount Money     `json:"amount"`
+}
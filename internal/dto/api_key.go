@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"corebanking/internal/domain"
+	"time"
+)
+
+type APIKeyRequest struct {
+	Subject string   `json:"subject"`
+	Roles   []string `json:"roles"`
+	Tier    string   `json:"tier,omitempty"`
+}
+
+type APIKeyRotateRequest struct {
+	GraceSeconds int64 `json:"graceSeconds"`
+}
+
+// APIKeyResponse describes a key. Key holds the plaintext secret and is only
+// present right after the key is issued or rotated.
+type APIKeyResponse struct {
+	ID        string    `json:"id"`
+	Subject   string    `json:"subject"`
+	Roles     []string  `json:"roles"`
+	Tier      string    `json:"tier,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	RotatedAt time.Time `json:"rotatedAt,omitempty"`
+	Revoked   bool      `json:"revoked"`
+	Key       string    `json:"key,omitempty"`
+}
+
+func NewAPIKeyResponse(key *domain.APIKey, plaintext string) APIKeyResponse {
+	return APIKeyResponse{
+		ID:        key.ID,
+		Subject:   key.Subject,
+		Roles:     key.Roles,
+		Tier:      key.Tier,
+		CreatedAt: key.CreatedAt,
+		RotatedAt: key.RotatedAt,
+		Revoked:   key.IsRevoked(),
+		Key:       plaintext,
+	}
+}
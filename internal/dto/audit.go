@@ -0,0 +1,9 @@
+package dto
+
+// AuditVerifyResponse reports whether the audit hash chain is intact. Error
+// names the first broken entry when it is not.
+type AuditVerifyResponse struct {
+	Valid   bool   `json:"valid"`
+	Entries int    `json:"entries"`
+	Error   string `json:"error,omitempty"`
+}
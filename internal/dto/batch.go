@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"corebanking/internal/domain"
+	"time"
+)
+
+// BatchV2Request posts many transactions and events at once. Mode is
+// all_or_nothing, the default, or best_effort. Async processes the batch
+// in the background whatever its size; larger batches always are.
+type BatchV2Request struct {
+	Mode  string               `json:"mode,omitempty"`
+	Async bool                 `json:"async,omitempty"`
+	Items []BatchItemV2Request `json:"items"`
+}
+
+// BatchItemV2Request is a transaction, of type transaction with accountId
+// and operationTypeId, or a deposit, withdraw or transfer event with
+// origin and destination. Reference is echoed back in the item's result.
+type BatchItemV2Request struct {
+	Type            string `json:"type"`
+	AccountID       string `json:"accountId,omitempty"`
+	OperationTypeID int    `json:"operationTypeId,omitempty"`
+	Origin          string `json:"origin,omitempty"`
+	Destination     string `json:"destination,omitempty"`
+	Amount          Money  `json:"amount"`
+	Reference       string `json:"reference,omitempty"`
+}
+
+// Domain returns the batch items the request describes.
+func (r BatchV2Request) Domain() []domain.BatchItem {
+	items := make([]domain.BatchItem, 0, len(r.Items))
+	for _, item := range r.Items {
+		items = append(items, domain.BatchItem{
+			Type:            item.Type,
+			AccountID:       item.AccountID,
+			OperationTypeID: item.OperationTypeID,
+			Origin:          item.Origin,
+			Destination:     item.Destination,
+			Amount:          int64(item.Amount),
+			Reference:       item.Reference,
+		})
+	}
+	return items
+}
+
+// BatchV2Response is a batch and its items' results, in request order.
+// Status is pending or processing until every item was tried, then
+// completed, or failed for an all-or-nothing batch that was rolled back.
+type BatchV2Response struct {
+	ID     string `json:"id"`
+	Mode   string `json:"mode"`
+	Status string `json:"status"`
+	Total  int    `json:"total"`
+	Posted int    `json:"posted"`
+	Held   int    `json:"held"`
+	Failed int    `json:"failed"`
+	// Error says why a failed batch could not be fully rolled back.
+	Error       string                `json:"error,omitempty"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	CreatedBy   string                `json:"createdBy,omitempty"`
+	CompletedAt *time.Time            `json:"completedAt,omitempty"`
+	Items       []BatchItemV2Response `json:"items"`
+}
+
+// BatchItemV2Response is one item's result. Status is pending, posted,
+// held, failed, rolled_back or skipped; held and failed items carry the
+// error code and message a single request would have answered with.
+type BatchItemV2Response struct {
+	Index         int    `json:"index"`
+	Reference     string `json:"reference,omitempty"`
+	Status        string `json:"status"`
+	TransactionID int64  `json:"transactionId,omitempty"`
+	Code          string `json:"code,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// NewBatchV2Response builds the response for batch, naming item errors
+// with code.
+func NewBatchV2Response(batch *domain.Batch, code func(err error) string) BatchV2Response {
+	response := BatchV2Response{
+		ID:          batch.ID,
+		Mode:        batch.Mode,
+		Status:      batch.Status,
+		Total:       len(batch.Items),
+		Posted:      batch.Count(domain.BatchItemPosted),
+		Held:        batch.Count(domain.BatchItemHeld),
+		Failed:      batch.Count(domain.BatchItemFailed),
+		Error:       batch.Error,
+		CreatedAt:   batch.CreatedAt,
+		CreatedBy:   batch.CreatedBy,
+		CompletedAt: batch.CompletedAt,
+		Items:       make([]BatchItemV2Response, 0, len(batch.Items)),
+	}
+	for i, result := range batch.Results {
+		item := BatchItemV2Response{
+			Index:         i,
+			Reference:     batch.Items[i].Reference,
+			Status:        result.Status,
+			TransactionID: result.TransactionID,
+		}
+		if result.Err != nil {
+			item.Code = code(result.Err)
+			item.Error = result.Err.Error()
+		}
+		response.Items = append(response.Items, item)
+	}
+	return response
+}
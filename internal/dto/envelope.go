@@ -0,0 +1,37 @@
+package dto
+
+// Envelope wraps every v2 response body: exactly one of Data and Error is
+// set, and Meta always carries the API version.
+type Envelope struct {
+	Data  any            `json:"data"`
+	Error *EnvelopeError `json:"error"`
+	Meta  EnvelopeMeta   `json:"meta"`
+}
+
+type EnvelopeError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+}
+
+type EnvelopeMeta struct {
+	APIVersion string `json:"apiVersion"`
+	Count      *int   `json:"count,omitempty"`
+}
+
+func NewDataEnvelope(version string, data any) Envelope {
+	return Envelope{Data: data, Meta: EnvelopeMeta{APIVersion: version}}
+}
+
+func NewListEnvelope[T any](version string, items []T) Envelope {
+	count := len(items)
+	return Envelope{Data: items, Meta: EnvelopeMeta{APIVersion: version, Count: &count}}
+}
+
+func NewErrorEnvelope(version, code, message string, err error) Envelope {
+	envErr := &EnvelopeError{Code: code, Message: message}
+	if err != nil {
+		envErr.Details = err.Error()
+	}
+	return Envelope{Error: envErr, Meta: EnvelopeMeta{APIVersion: version}}
+}
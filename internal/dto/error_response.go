@@ -0,0 +1,14 @@
+package dto
+
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Details string `json:"details"`
+}
+
+func NewErrorResponse(message string, err error) ErrorResponse {
+	resp := ErrorResponse{Error: message}
+	if err != nil {
+		resp.Details = err.Error()
+	}
+	return resp
+}
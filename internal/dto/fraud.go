@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"corebanking/internal/domain"
+	"time"
+)
+
+// FraudDecisionV2Request approves or rejects a fraud case, with an optional
+// note for the record.
+type FraudDecisionV2Request struct {
+	Note string `json:"note,omitempty"`
+}
+
+type FraudCaseV2Response struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	AccountID string `json:"accountId"`
+	// Type is purchase, withdraw or transfer; OperationTypeID is set for
+	// transactions.
+	Type            string     `json:"type"`
+	OperationTypeID int        `json:"operationTypeId,omitempty"`
+	Destination     string     `json:"destination,omitempty"`
+	Amount          Money      `json:"amount"`
+	Score           int        `json:"score"`
+	Reasons         []string   `json:"reasons"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	DecidedAt       *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy       string     `json:"decidedBy,omitempty"`
+	Note            string     `json:"note,omitempty"`
+}
+
+func NewFraudCaseV2Response(c *domain.FraudCase) FraudCaseV2Response {
+	return FraudCaseV2Response{
+		ID:              c.ID,
+		Status:          c.Status,
+		AccountID:       c.AccountID,
+		Type:            c.Kind,
+		OperationTypeID: c.OperationTypeID,
+		Destination:     c.Destination,
+		Amount:          Money(c.Amount),
+		Score:           c.Score,
+		Reasons:         c.Reasons,
+		CreatedAt:       c.CreatedAt,
+		DecidedAt:       c.DecidedAt,
+		DecidedBy:       c.DecidedBy,
+		Note:            c.Note,
+	}
+}
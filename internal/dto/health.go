@@ -0,0 +1,31 @@
+package dto
+
+// HealthResponse answers /healthz while the process is alive.
+type HealthResponse struct {
+	Status        string  `json:"status"`
+	UptimeSeconds float64 `json:"uptimeSeconds"`
+}
+
+// ReadinessResponse lists every readiness check; Status is "ready" only
+// when all of them passed.
+type ReadinessResponse struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+type HealthCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+type VersionResponse struct {
+	App         string   `json:"app"`
+	APIVersion  string   `json:"apiVersion"`
+	APIVersions []string `json:"apiVersions"`
+	Commit      string   `json:"commit,omitempty"`
+	BuildTime   string   `json:"buildTime,omitempty"`
+	Modified    bool     `json:"modified,omitempty"`
+	GoVersion   string   `json:"goVersion"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// HoldResponse answers a v1 posting that was held rather than made, in a
+// review case or in suspense. Status is the code v2 answers the hold with
+// and HoldID the case or suspense item holding it. The posting is not
+// refused, so it must not be retried.
+type HoldResponse struct {
+	Status  string `json:"status"`
+	HoldID  string `json:"holdId"`
+	Message string `json:"message"`
+}
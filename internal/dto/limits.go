@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"corebanking/internal/domain"
+	"time"
+)
+
+// LimitsResponse is an account's limits and its usage in the current day,
+// month and night window.
+type LimitsResponse struct {
+	AccountID  string
+	Product    string
+	Overridden bool
+	Limits     domain.Limits
+	Usage      domain.LimitUsage
+	// Night reports whether AsOf falls in the night window.
+	Night bool
+	AsOf  time.Time
+}
+
+// LimitsV2 are limits in the v2 format; null is no cap.
+type LimitsV2 struct {
+	MaxTransactionAmount *Money `json:"maxTransactionAmount"`
+	DailyDebitLimit      *Money `json:"dailyDebitLimit"`
+	MonthlyDebitLimit    *Money `json:"monthlyDebitLimit"`
+	DailyWithdrawalCount *int   `json:"dailyWithdrawalCount"`
+	NightTransferLimit   *Money `json:"nightTransferLimit"`
+}
+
+// Domain converts to domain.Limits, where no cap is zero.
+func (l LimitsV2) Domain() domain.Limits {
+	limits := domain.Limits{
+		MaxTransactionAmount: moneyValue(l.MaxTransactionAmount),
+		DailyDebitLimit:      moneyValue(l.DailyDebitLimit),
+		MonthlyDebitLimit:    moneyValue(l.MonthlyDebitLimit),
+		NightTransferLimit:   moneyValue(l.NightTransferLimit),
+	}
+	if l.DailyWithdrawalCount != nil {
+		limits.DailyWithdrawalCount = *l.DailyWithdrawalCount
+	}
+	return limits
+}
+
+// LimitsV2Request moves an account to a product, empty meaning the default
+// product, and sets its own limits. Without limits the product's apply.
+type LimitsV2Request struct {
+	Product string    `json:"product,omitempty"`
+	Limits  *LimitsV2 `json:"limits,omitempty"`
+}
+
+// RemainingV2 is what is left of each cap; null where there is no cap.
+type RemainingV2 struct {
+	DailyDebit       *Money `json:"dailyDebit"`
+	MonthlyDebit     *Money `json:"monthlyDebit"`
+	DailyWithdrawals *int   `json:"dailyWithdrawals"`
+	NightTransfer    *Money `json:"nightTransfer"`
+}
+
+type LimitsV2Response struct {
+	AccountID  string      `json:"accountId"`
+	Product    string      `json:"product"`
+	Overridden bool        `json:"overridden"`
+	Limits     LimitsV2    `json:"limits"`
+	Remaining  RemainingV2 `json:"remaining"`
+	// NightWindow reports whether night transfer caps apply right now.
+	NightWindow bool      `json:"nightWindow"`
+	AsOf        time.Time `json:"asOf"`
+}
+
+func NewLimitsV2Response(r *LimitsResponse) LimitsV2Response {
+	limits, usage := r.Limits, r.Usage
+	response := LimitsV2Response{
+		AccountID:  r.AccountID,
+		Product:    r.Product,
+		Overridden: r.Overridden,
+		Limits: LimitsV2{
+			MaxTransactionAmount: moneyCap(limits.MaxTransactionAmount),
+			DailyDebitLimit:      moneyCap(limits.DailyDebitLimit),
+			MonthlyDebitLimit:    moneyCap(limits.MonthlyDebitLimit),
+			DailyWithdrawalCount: countCap(limits.DailyWithdrawalCount),
+			NightTransferLimit:   moneyCap(limits.NightTransferLimit),
+		},
+		Remaining: RemainingV2{
+			DailyDebit:       remainingMoney(limits.DailyDebitLimit, usage.DailyDebits),
+			MonthlyDebit:     remainingMoney(limits.MonthlyDebitLimit, usage.MonthlyDebits),
+			DailyWithdrawals: countCap(limits.DailyWithdrawalCount - usage.DailyWithdrawals),
+			NightTransfer:    remainingMoney(limits.NightTransferLimit, usage.NightTransfers),
+		},
+		NightWindow: r.Night,
+		AsOf:        r.AsOf,
+	}
+	if limits.DailyWithdrawalCount > 0 && response.Remaining.DailyWithdrawals == nil {
+		zero := 0
+		response.Remaining.DailyWithdrawals = &zero
+	}
+	return response
+}
+
+func moneyCap(limit int64) *Money {
+	if limit <= 0 {
+		return nil
+	}
+	m := Money(limit)
+	return &m
+}
+
+func countCap(limit int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	return &limit
+}
+
+func remainingMoney(limit, used int64) *Money {
+	if limit <= 0 {
+		return nil
+	}
+	m := Money(max(limit-used, 0))
+	return &m
+}
+
+func moneyValue(m *Money) int64 {
+	if m == nil {
+		return 0
+	}
+	return int64(*m)
+}
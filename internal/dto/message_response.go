@@ -0,0 +1,9 @@
+package dto
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+func NewMessageResponse(message string) MessageResponse {
+	return MessageResponse{Message: message}
+}
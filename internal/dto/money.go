@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in minor units (cents) that the v2 API encodes as a
+// decimal string, e.g. 1050 <-> "10.50", so clients never round floats.
+type Money int64
+
+func (m Money) String() string {
+	value := int64(m)
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("money must be a decimal string")
+	}
+	parsed, err := ParseMoney(raw)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// ParseMoney parses a decimal string with at most two fractional digits:
+// digits, optionally after a single '-', then optionally a '.' and one or
+// two more digits. Amounts that don't fit in cents as an int64 are refused.
+func ParseMoney(raw string) (Money, error) {
+	value := strings.TrimSpace(raw)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	units, cents, hasCents := strings.Cut(value, ".")
+	if !digits(units) || (hasCents && (!digits(cents) || len(cents) > 2)) {
+		return 0, fmt.Errorf("invalid money amount %q", raw)
+	}
+	for len(cents) < 2 {
+		cents += "0"
+	}
+
+	whole, err := strconv.ParseInt(units, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("money amount %q out of range", raw)
+	}
+	fraction, err := strconv.ParseInt(cents, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid money amount %q", raw)
+	}
+	if whole > (math.MaxInt64-fraction)/100 {
+		return 0, fmt.Errorf("money amount %q out of range", raw)
+	}
+
+	amount := whole*100 + fraction
+	if negative {
+		amount = -amount
+	}
+	return Money(amount), nil
+}
+
+// digits reports whether s is one or more ASCII digits, with no sign.
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
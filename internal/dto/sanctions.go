@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"corebanking/internal/domain"
+	"time"
+)
+
+// SanctionsDecisionV2Request approves or rejects a sanctions case, with an
+// optional note for the record.
+type SanctionsDecisionV2Request struct {
+	Note string `json:"note,omitempty"`
+}
+
+// SanctionsListV2Response describes the list in use. Digest is the
+// SHA-256 of the file it was loaded from.
+type SanctionsListV2Response struct {
+	Version  string    `json:"version"`
+	Digest   string    `json:"digest,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	LoadedAt time.Time `json:"loadedAt"`
+	Entries  int       `json:"entries"`
+}
+
+type SanctionsScreeningV2Response struct {
+	ID             string                `json:"id"`
+	Type           string                `json:"type"`
+	DocumentNumber string                `json:"documentNumber"`
+	HolderName     string                `json:"holderName,omitempty"`
+	AccountID      string                `json:"accountId,omitempty"`
+	Origin         string                `json:"origin,omitempty"`
+	Amount         *Money                `json:"amount,omitempty"`
+	ListVersion    string                `json:"listVersion"`
+	ListDigest     string                `json:"listDigest"`
+	Hits           []domain.SanctionsHit `json:"hits"`
+	CaseID         string                `json:"caseId,omitempty"`
+	At             time.Time             `json:"at"`
+}
+
+func NewSanctionsScreeningV2Response(s *domain.SanctionsScreening) SanctionsScreeningV2Response {
+	return SanctionsScreeningV2Response{
+		ID:             s.ID,
+		Type:           s.Kind,
+		DocumentNumber: s.DocumentNumber,
+		HolderName:     s.HolderName,
+		AccountID:      s.AccountID,
+		Origin:         s.Origin,
+		Amount:         transferAmount(s.Kind, s.Amount),
+		ListVersion:    s.ListVersion,
+		ListDigest:     s.ListDigest,
+		Hits:           s.Hits,
+		CaseID:         s.CaseID,
+		At:             s.At,
+	}
+}
+
+type SanctionsCaseV2Response struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// Type is onboarding or transfer. AccountID is the transfer's
+	// destination, or the account opened by an approved onboarding.
+	Type           string                `json:"type"`
+	ScreeningID    string                `json:"screeningId"`
+	DocumentNumber string                `json:"documentNumber"`
+	HolderName     string                `json:"holderName,omitempty"`
+	AccountID      string                `json:"accountId,omitempty"`
+	Origin         string                `json:"origin,omitempty"`
+	Amount         *Money                `json:"amount,omitempty"`
+	ListVersion    string                `json:"listVersion"`
+	Hits           []domain.SanctionsHit `json:"hits"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	DecidedAt      *time.Time            `json:"decidedAt,omitempty"`
+	DecidedBy      string                `json:"decidedBy,omitempty"`
+	Note           string                `json:"note,omitempty"`
+}
+
+func NewSanctionsCaseV2Response(c *domain.SanctionsCase) SanctionsCaseV2Response {
+	return SanctionsCaseV2Response{
+		ID:             c.ID,
+		Status:         c.Status,
+		Type:           c.Kind,
+		ScreeningID:    c.ScreeningID,
+		DocumentNumber: c.DocumentNumber,
+		HolderName:     c.HolderName,
+		AccountID:      c.AccountID,
+		Origin:         c.Origin,
+		Amount:         transferAmount(c.Kind, c.Amount),
+		ListVersion:    c.ListVersion,
+		Hits:           c.Hits,
+		CreatedAt:      c.CreatedAt,
+		DecidedAt:      c.DecidedAt,
+		DecidedBy:      c.DecidedBy,
+		Note:           c.Note,
+	}
+}
+
+// transferAmount is the amount of transfer screenings; onboardings have
+// none.
+func transferAmount(kind string, amount int64) *Money {
+	if kind != domain.ScreeningTransfer {
+		return nil
+	}
+	m := Money(amount)
+	return &m
+}
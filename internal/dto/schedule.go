@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"corebanking/internal/domain"
+	"time"
+)
+
+// ScheduleV2Request schedules a transfer from the account in the path.
+// Frequency is once, daily, weekly or monthly; a missing StartAt is now.
+// Standing orders repeat until EndAt or Count transfers, whichever comes
+// first, or until cancelled when both are missing.
+type ScheduleV2Request struct {
+	Destination string     `json:"destination"`
+	Amount      Money      `json:"amount"`
+	Frequency   string     `json:"frequency"`
+	StartAt     *time.Time `json:"startAt,omitempty"`
+	EndAt       *time.Time `json:"endAt,omitempty"`
+	Count       int        `json:"count,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+// Domain returns the schedule the request describes, from origin.
+func (r ScheduleV2Request) Domain(origin string) *domain.Schedule {
+	schedule := &domain.Schedule{
+		Origin:      origin,
+		Destination: r.Destination,
+		Amount:      int64(r.Amount),
+		Frequency:   r.Frequency,
+		EndAt:       r.EndAt,
+		Count:       r.Count,
+		Description: r.Description,
+	}
+	if r.StartAt != nil {
+		schedule.StartAt = *r.StartAt
+	}
+	return schedule
+}
+
+type ScheduleV2Response struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Origin      string     `json:"origin"`
+	Destination string     `json:"destination"`
+	Amount      Money      `json:"amount"`
+	Frequency   string     `json:"frequency"`
+	StartAt     time.Time  `json:"startAt"`
+	EndAt       *time.Time `json:"endAt,omitempty"`
+	Count       int        `json:"count,omitempty"`
+	Description string     `json:"description,omitempty"`
+	// Executed counts the occurrences run, whatever their outcome.
+	// NextRunAt is missing once the schedule is finished.
+	Executed  int        `json:"executed"`
+	NextRunAt *time.Time `json:"nextRunAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+}
+
+func NewScheduleV2Response(schedule *domain.Schedule) ScheduleV2Response {
+	return ScheduleV2Response{
+		ID:          schedule.ID,
+		Status:      schedule.Status,
+		Origin:      schedule.Origin,
+		Destination: schedule.Destination,
+		Amount:      Money(schedule.Amount),
+		Frequency:   schedule.Frequency,
+		StartAt:     schedule.StartAt,
+		EndAt:       schedule.EndAt,
+		Count:       schedule.Count,
+		Description: schedule.Description,
+		Executed:    schedule.Executed,
+		NextRunAt:   schedule.NextRunAt,
+		CreatedAt:   schedule.CreatedAt,
+		CreatedBy:   schedule.CreatedBy,
+	}
+}
+
+// ScheduleExecutionV2Response is one attempt at a schedule's occurrence,
+// numbered from 0. Status is posted, held, retrying, failed or
+// interrupted.
+type ScheduleExecutionV2Response struct {
+	ID         string    `json:"id"`
+	Occurrence int       `json:"occurrence"`
+	DueAt      time.Time `json:"dueAt"`
+	Attempt    int       `json:"attempt"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	ExecutedAt time.Time `json:"executedAt"`
+	Instance   string    `json:"instance"`
+}
+
+func NewScheduleExecutionV2Response(execution *domain.ScheduleExecution) ScheduleExecutionV2Response {
+	return ScheduleExecutionV2Response{
+		ID:         execution.ID,
+		Occurrence: execution.Occurrence,
+		DueAt:      execution.DueAt,
+		Attempt:    execution.Attempt,
+		Status:     execution.Status,
+		Error:      execution.Error,
+		ExecutedAt: execution.ExecutedAt,
+		Instance:   execution.Instance,
+	}
+}
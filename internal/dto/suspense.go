@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"corebanking/internal/domain"
+	"time"
+)
+
+// SuspenseClaimV2Request moves a held item to AccountID, the account it
+// was meant for.
+type SuspenseClaimV2Request struct {
+	AccountID string `json:"accountId"`
+	Note      string `json:"note,omitempty"`
+}
+
+// SuspenseReturnV2Request returns a held item, with an optional note for
+// the record.
+type SuspenseReturnV2Request struct {
+	Note string `json:"note,omitempty"`
+}
+
+type SuspenseItemV2Response struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// Type is deposit or transfer. Destination is the account the posting
+	// named, which did not exist; AccountID the one credited when it was
+	// claimed or returned.
+	Type        string     `json:"type"`
+	Origin      string     `json:"origin,omitempty"`
+	Destination string     `json:"destination"`
+	Amount      Money      `json:"amount"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	AccountID   string     `json:"accountId,omitempty"`
+	ResolvedAt  *time.Time `json:"resolvedAt,omitempty"`
+	ResolvedBy  string     `json:"resolvedBy,omitempty"`
+	Note        string     `json:"note,omitempty"`
+}
+
+func NewSuspenseItemV2Response(item *domain.SuspenseItem) SuspenseItemV2Response {
+	return SuspenseItemV2Response{
+		ID:          item.ID,
+		Status:      item.Status,
+		Type:        item.Kind,
+		Origin:      item.Origin,
+		Destination: item.Destination,
+		Amount:      Money(item.Amount),
+		CreatedAt:   item.CreatedAt,
+		AccountID:   item.AccountID,
+		ResolvedAt:  item.ResolvedAt,
+		ResolvedBy:  item.ResolvedBy,
+		Note:        item.Note,
+	}
+}
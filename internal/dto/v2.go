@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// Request and response bodies of the v2 API. Amounts are Money.
+
+type AccountV2Response struct {
+	AccountID      string `json:"accountId"`
+	DocumentNumber string `json:"documentNumber"`
+	HolderName     string `json:"holderName,omitempty"`
+}
+
+type AccountVerificationV2Response struct {
+	AccountID  string `json:"accountId"`
+	HolderName string `json:"holderName,omitempty"`
+}
+
+type BalanceV2Response struct {
+	AccountID string `json:"accountId"`
+	Balance   Money  `json:"balance"`
+}
+
+type OverdraftV2Request struct {
+	Limit Money `json:"limit"`
+}
+
+type OverdraftV2Response struct {
+	AccountID string `json:"accountId"`
+	Limit     Money  `json:"limit"`
+}
+
+type TransactionV2Request struct {
+	AccountID       string `json:"accountId"`
+	OperationTypeID int    `json:"operationTypeId"`
+	Amount          Money  `json:"amount"`
+}
+
+type TransactionV2Response struct {
+	TransactionID   int64     `json:"transactionId"`
+	AccountID       string    `json:"accountId"`
+	OperationTypeID int       `json:"operationTypeId"`
+	Amount          Money     `json:"amount"`
+	EventDate       time.Time `json:"eventDate"`
+}
+
+type EventV2Request struct {
+	Type        string `json:"type"`
+	Origin      string `json:"origin,omitempty"`
+	Destination string `json:"destination,omitempty"`
+	Amount      Money  `json:"amount"`
+}
+
+type AccountStateV2 struct {
+	AccountID      string `json:"accountId"`
+	Balance        Money  `json:"balance"`
+	OverdraftLimit Money  `json:"overdraftLimit"`
+}
+
+type EventV2Response struct {
+	Origin      *AccountStateV2 `json:"origin,omitempty"`
+	Destination *AccountStateV2 `json:"destination,omitempty"`
+}
+
+func NewTransactionV2Response(t *TransactionResponse) TransactionV2Response {
+	return TransactionV2Response{
+		TransactionID:   t.TransactionID,
+		AccountID:       t.AccountID,
+		OperationTypeID: t.OperationTypeID,
+		Amount:          Money(t.Amount),
+		EventDate:       t.EventDate,
+	}
+}
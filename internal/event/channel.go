@@ -1,29 +1,121 @@
 package event
 
 import (
-	"os"
+	"bufio"
+	"errors"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+var (
+	ErrLogChannelClosed = errors.New("log channel is closed")
+	ErrLogDropped       = errors.New("log message dropped, buffer is full")
+)
+
+// OverflowPolicy decides what Send does when the buffer is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock waits for room, as the channel always did.
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropNewest discards the message being sent.
+	OverflowDropNewest OverflowPolicy = "drop-newest"
+	// OverflowDropOldest discards the oldest queued message to make room.
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowSpill moves messages to a secondary buffer of SpillSize and
+	// drops the newest once that is full too.
+	OverflowSpill OverflowPolicy = "spill"
+)
+
+func ParseOverflowPolicy(value string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(value); policy {
+	case OverflowBlock, OverflowDropNewest, OverflowDropOldest, OverflowSpill:
+		return policy, nil
+	default:
+		return "", errors.New("unknown log overflow policy " + value)
+	}
+}
+
+type Options struct {
+	BufferSize int
+	Overflow   OverflowPolicy
+	SpillSize  int
+	// FlushInterval is how often buffered lines are flushed and the file
+	// fsynced. Zero flushes after every message.
+	FlushInterval time.Duration
+	// OnError receives write, flush, sync and rotation failures. It runs on
+	// the worker or a compression goroutine and must not log through this
+	// channel.
+	OnError func(error)
+	// Rotation rotates, compresses and prunes the file.
+	Rotation RotateOptions
+}
+
+// Stats counts messages since the channel was opened.
+type Stats struct {
+	Written     uint64
+	Dropped     uint64
+	Spilled     uint64
+	WriteErrors uint64
+}
+
 type LogEvent struct {
 	Message string
 }
 
 type LogChannel struct {
 	channel chan LogEvent
-	file    *os.File
+	file    *rotatingFile
+	writer  *bufio.Writer
+	options Options
+	reopen  chan chan error
 	wg      sync.WaitGroup
+
+	// mu guards closed; Send holds it shared so Close cannot close the
+	// channel under a sender.
+	mu     sync.RWMutex
+	closed bool
+
+	spillMu     sync.Mutex
+	spill       []LogEvent
+	spillNotify chan struct{}
+
+	written     atomic.Uint64
+	dropped     atomic.Uint64
+	spilled     atomic.Uint64
+	writeErrors atomic.Uint64
+	// lastErr is the latest write, flush or rotation error, cleared by the
+	// next successful flush.
+	lastErr atomic.Pointer[error]
 }
 
+// NewLogChannel opens a channel that blocks when full and flushes after
+// every message.
 func NewLogChannel(filePath string, bufferSize int) (*LogChannel, error) {
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	return NewLogChannelWithOptions(filePath, Options{BufferSize: bufferSize, Overflow: OverflowBlock})
+}
+
+// NewLogChannelWithOptions opens filePath for appending, creating its
+// directory when missing.
+func NewLogChannelWithOptions(filePath string, options Options) (*LogChannel, error) {
+	f, err := openRotatingFile(filePath, options.Rotation)
 	if err != nil {
 		return nil, err
 	}
+	f.onError = options.OnError
+	if options.Overflow == "" {
+		options.Overflow = OverflowBlock
+	}
 
 	lc := &LogChannel{
-		channel: make(chan LogEvent, bufferSize),
-		file:    f,
+		channel:     make(chan LogEvent, options.BufferSize),
+		file:        f,
+		writer:      bufio.NewWriter(f),
+		options:     options,
+		reopen:      make(chan chan error),
+		spillNotify: make(chan struct{}, 1),
 	}
 
 	lc.wg.Add(1)
@@ -34,17 +126,238 @@ func NewLogChannel(filePath string, bufferSize int) (*LogChannel, error) {
 
 func (lc *LogChannel) StartWorker() {
 	defer lc.wg.Done()
-	for logEvent := range lc.channel {
-		lc.file.WriteString(logEvent.Message + "\n")
+
+	var tick <-chan time.Time
+	if lc.options.FlushInterval > 0 {
+		ticker := time.NewTicker(lc.options.FlushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case logEvent, ok := <-lc.channel:
+			if !ok {
+				lc.drainSpill()
+				lc.flush()
+				return
+			}
+			lc.write(logEvent)
+			if len(lc.channel) == 0 {
+				lc.drainSpill()
+			}
+			if tick == nil {
+				lc.flush()
+			}
+		case <-lc.spillNotify:
+			if len(lc.channel) == 0 {
+				lc.drainSpill()
+			}
+			if tick == nil {
+				lc.flush()
+			}
+		case <-tick:
+			lc.flush()
+		case done := <-lc.reopen:
+			lc.flush()
+			done <- lc.file.reopen()
+		}
+	}
+}
+
+// Reopen flushes and reopens the log file, so external tools such as
+// logrotate can move it away; call it on SIGHUP.
+func (lc *LogChannel) Reopen() error {
+	lc.mu.RLock()
+	defer lc.mu.RUnlock()
+	if lc.closed {
+		return ErrLogChannelClosed
+	}
+
+	done := make(chan error)
+	lc.reopen <- done
+	return <-done
+}
+
+// Send queues a message according to the overflow policy. It never panics:
+// after Close it returns ErrLogChannelClosed.
+func (lc *LogChannel) Send(message string) error {
+	lc.mu.RLock()
+	defer lc.mu.RUnlock()
+
+	if lc.closed {
+		lc.dropped.Add(1)
+		return ErrLogChannelClosed
+	}
+
+	logEvent := LogEvent{Message: message}
+	if lc.options.Overflow == OverflowSpill && lc.spillPending() {
+		// Keep order: once messages spilled, new ones queue behind them.
+		return lc.spillEvent(logEvent)
+	}
+
+	select {
+	case lc.channel <- logEvent:
+		return nil
+	default:
+	}
+
+	switch lc.options.Overflow {
+	case OverflowDropNewest:
+		lc.dropped.Add(1)
+		return ErrLogDropped
+	case OverflowDropOldest:
+		for {
+			select {
+			case lc.channel <- logEvent:
+				return nil
+			default:
+			}
+			select {
+			case <-lc.channel:
+				lc.dropped.Add(1)
+			default:
+			}
+		}
+	case OverflowSpill:
+		return lc.spillEvent(logEvent)
+	default:
+		lc.channel <- logEvent
+		return nil
 	}
 }
 
-func (lc *LogChannel) Send(message string) {
-	lc.channel <- LogEvent{Message: message}
+// Write queues p as one line, so the channel can back a slog handler: each
+// record arrives in a single Write and is written by the worker goroutine.
+func (lc *LogChannel) Write(p []byte) (int, error) {
+	if err := lc.Send(strings.TrimSuffix(string(p), "\n")); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (lc *LogChannel) Stats() Stats {
+	return Stats{
+		Written:     lc.written.Load(),
+		Dropped:     lc.dropped.Load(),
+		Spilled:     lc.spilled.Load(),
+		WriteErrors: lc.writeErrors.Load(),
+	}
+}
+
+// Err reports why the channel cannot write: it is closed, or its latest
+// write failed and nothing has been flushed since.
+func (lc *LogChannel) Err() error {
+	lc.mu.RLock()
+	closed := lc.closed
+	lc.mu.RUnlock()
+	if closed {
+		return ErrLogChannelClosed
+	}
+	if err := lc.lastErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// QueueDepth is the number of messages waiting to be written, counting the
+// spill buffer.
+func (lc *LogChannel) QueueDepth() int {
+	lc.spillMu.Lock()
+	defer lc.spillMu.Unlock()
+	return len(lc.channel) + len(lc.spill)
 }
 
+// Close stops accepting messages, writes everything queued, and fsyncs and
+// closes the file. Calling it again is a no-op.
 func (lc *LogChannel) Close() error {
+	lc.mu.Lock()
+	if lc.closed {
+		lc.mu.Unlock()
+		return nil
+	}
+	lc.closed = true
 	close(lc.channel)
+	lc.mu.Unlock()
+
 	lc.wg.Wait()
 	return lc.file.Close()
 }
+
+func (lc *LogChannel) spillPending() bool {
+	lc.spillMu.Lock()
+	defer lc.spillMu.Unlock()
+	return len(lc.spill) > 0
+}
+
+func (lc *LogChannel) spillEvent(logEvent LogEvent) error {
+	lc.spillMu.Lock()
+	if len(lc.spill) >= lc.options.SpillSize {
+		lc.spillMu.Unlock()
+		lc.dropped.Add(1)
+		return ErrLogDropped
+	}
+	lc.spill = append(lc.spill, logEvent)
+	lc.spillMu.Unlock()
+
+	lc.spilled.Add(1)
+	select {
+	case lc.spillNotify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (lc *LogChannel) drainSpill() {
+	lc.spillMu.Lock()
+	pending := lc.spill
+	lc.spill = nil
+	lc.spillMu.Unlock()
+
+	for _, logEvent := range pending {
+		lc.write(logEvent)
+	}
+}
+
+func (lc *LogChannel) write(logEvent LogEvent) {
+	if lc.file.needsRotation(lc.writer.Buffered() + len(logEvent.Message) + 1) {
+		lc.flush()
+		if err := lc.file.rotate(); err != nil {
+			lc.reportError(err)
+		}
+	}
+	if _, err := lc.writer.WriteString(logEvent.Message + "\n"); err != nil {
+		lc.reportError(err)
+		lc.writer.Reset(lc.file)
+		return
+	}
+	lc.written.Add(1)
+}
+
+func (lc *LogChannel) flush() {
+	if lc.writer.Buffered() == 0 {
+		return
+	}
+	if err := lc.writer.Flush(); err != nil {
+		// A failed bufio.Writer rejects every later write; start over so
+		// one bad flush does not silence the log for good.
+		lc.reportError(err)
+		lc.writer.Reset(lc.file)
+		return
+	}
+	if lc.options.FlushInterval > 0 {
+		if err := lc.file.Sync(); err != nil {
+			lc.reportError(err)
+			return
+		}
+	}
+	lc.lastErr.Store(nil)
+}
+
+func (lc *LogChannel) reportError(err error) {
+	lc.writeErrors.Add(1)
+	lc.lastErr.Store(&err)
+	if lc.options.OnError != nil {
+		lc.options.OnError(err)
+	}
+}
@@ -0,0 +1,313 @@
+package event
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	backupDayFormat  = "2006-01-02"
+)
+
+// RotateOptions configures log file rotation. The zero value never rotates.
+type RotateOptions struct {
+	// MaxSize rotates before a write would grow the file past it, in bytes.
+	MaxSize int64
+	// Daily rotates on the first write of a new local day.
+	Daily bool
+	// Compress gzips rotated files in the background.
+	Compress bool
+	// MaxBackups keeps at most this many rotated files; zero keeps all.
+	MaxBackups int
+	// MaxAge removes rotated files older than this; zero keeps all.
+	MaxAge time.Duration
+	// Now is the clock used for daily rotation and backup names. It
+	// defaults to time.Now.
+	Now func() time.Time
+}
+
+// rotatingFile is the file behind a LogChannel. Rotated files are renamed
+// to <name>-<timestamp><ext>, or <name>-<day><ext> when a new day starts,
+// plus .gz when compressed.
+type rotatingFile struct {
+	path    string
+	options RotateOptions
+
+	file   *os.File
+	size   int64
+	opened time.Time
+	// onError receives compression failures from the background goroutine.
+	onError func(error)
+
+	// background tracks compression and cleanup so Close can wait for it;
+	// maintenance runs one of them at a time so pruning never races a
+	// compression.
+	background  sync.WaitGroup
+	maintenance sync.Mutex
+}
+
+// openRotatingFile creates the log directory if needed and opens path for
+// appending.
+func openRotatingFile(path string, options RotateOptions) (*rotatingFile, error) {
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	rf := &rotatingFile{path: path, options: options}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	rf.file = f
+	rf.size = info.Size()
+	rf.opened = rf.options.Now()
+	if info.Size() > 0 {
+		// An existing file belongs to the day it was last written.
+		rf.opened = info.ModTime()
+	}
+	return nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *rotatingFile) Sync() error {
+	return rf.file.Sync()
+}
+
+// needsRotation reports whether the file should rotate before pending more
+// bytes are written to it.
+func (rf *rotatingFile) needsRotation(pending int) bool {
+	if rf.size == 0 {
+		return false
+	}
+	if rf.options.MaxSize > 0 && rf.size+int64(pending) > rf.options.MaxSize {
+		return true
+	}
+	return rf.dayEnded()
+}
+
+// dayEnded reports whether daily rotation is on and the file was opened on
+// an earlier day.
+func (rf *rotatingFile) dayEnded() bool {
+	if !rf.options.Daily {
+		return false
+	}
+	y1, m1, d1 := rf.opened.Date()
+	y2, m2, d2 := rf.options.Now().Date()
+	return y1 != y2 || m1 != m2 || d1 != d2
+}
+
+// rotate renames the current file to a backup, opens a fresh one, then
+// compresses and prunes backups in the background. Whatever fails, it
+// leaves an open file behind when it can, so logging carries on.
+func (rf *rotatingFile) rotate() error {
+	backup := rf.backupName()
+	// The handle is released even when Close fails, so every path below
+	// opens the file again.
+	closeErr := rf.file.Close()
+	if err := os.Rename(rf.path, backup); err != nil {
+		// Keep logging to the current file rather than losing lines.
+		return errors.Join(err, closeErr, rf.open())
+	}
+	if err := rf.open(); err != nil {
+		// Put the file back and keep logging to it.
+		return errors.Join(err, closeErr, os.Rename(backup, rf.path), rf.open())
+	}
+
+	rf.background.Add(1)
+	go func() {
+		defer rf.background.Done()
+		rf.maintenance.Lock()
+		defer rf.maintenance.Unlock()
+		if rf.options.Compress {
+			if err := compressFile(backup); err != nil && rf.onError != nil {
+				rf.onError(err)
+			}
+		}
+		rf.prune()
+	}()
+	return closeErr
+}
+
+// reopen opens the path again, for tools that moved the file away. The old
+// file is closed only once the new one is open, so a failure keeps logging
+// to the old one.
+func (rf *rotatingFile) reopen() error {
+	old := rf.file
+	if err := rf.open(); err != nil {
+		return err
+	}
+	return old.Close()
+}
+
+func (rf *rotatingFile) Close() error {
+	err := rf.file.Close()
+	rf.background.Wait()
+	return err
+}
+
+// backupName names the backup for the current file: after the day it holds
+// when a new day started, otherwise after the time of rotation.
+func (rf *rotatingFile) backupName() string {
+	ext := filepath.Ext(rf.path)
+	base := strings.TrimSuffix(rf.path, ext)
+	if rf.dayEnded() {
+		name := base + "-" + rf.opened.Format(backupDayFormat) + ext
+		if !backupExists(name) {
+			return name
+		}
+	}
+	// Bump the timestamp on collisions so names keep sorting by age.
+	at := rf.options.Now()
+	for {
+		name := base + "-" + at.Format(backupTimeFormat) + ext
+		if !backupExists(name) {
+			return name
+		}
+		at = at.Add(time.Millisecond)
+	}
+}
+
+// Backups lists the rotated files of path, oldest first. Only names rotate
+// gives are listed: <name>-<timestamp><ext> or <name>-<day><ext>, with an
+// optional .gz. A day's backup sorts as rotated at the end of that day.
+func Backups(path string) ([]string, error) {
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	prefix := filepath.Base(strings.TrimSuffix(path, ext)) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type backup struct {
+		path string
+		at   time.Time
+	}
+	var found []backup
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok {
+			continue
+		}
+		stamp, ok = strings.CutSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		if !ok {
+			continue
+		}
+		if at, ok := backupTime(stamp); ok {
+			found = append(found, backup{path: filepath.Join(dir, entry.Name()), at: at})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].at.Before(found[j].at) })
+
+	backups := make([]string, len(found))
+	for i, b := range found {
+		backups[i] = b.path
+	}
+	return backups, nil
+}
+
+// backupTime parses the time in a backup name: when it was rotated, or the
+// end of the day a daily backup holds.
+func backupTime(stamp string) (time.Time, bool) {
+	if at, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local); err == nil {
+		return at, true
+	}
+	if day, err := time.ParseInLocation(backupDayFormat, stamp, time.Local); err == nil {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), true
+	}
+	return time.Time{}, false
+}
+
+func (rf *rotatingFile) prune() {
+	if rf.options.MaxBackups <= 0 && rf.options.MaxAge <= 0 {
+		return
+	}
+	backups, err := Backups(rf.path)
+	if err != nil {
+		return
+	}
+
+	cutoff := rf.options.Now().Add(-rf.options.MaxAge)
+	for i, backup := range backups {
+		remove := rf.options.MaxBackups > 0 && i < len(backups)-rf.options.MaxBackups
+		if !remove && rf.options.MaxAge > 0 {
+			if info, err := os.Stat(backup); err == nil && info.ModTime().Before(cutoff) {
+				remove = true
+			}
+		}
+		if remove {
+			os.Remove(backup)
+		}
+	}
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+func backupExists(name string) bool {
+	return fileExists(name) || fileExists(name+".gz")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
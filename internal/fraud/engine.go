@@ -0,0 +1,89 @@
+// Package fraud scores postings against rules built from an account's
+// history and decides whether they go through, wait for review or are
+// declined.
+package fraud
+
+import (
+	"corebanking/internal/domain"
+	"sync/atomic"
+	"time"
+)
+
+// Decision is the outcome of screening a posting.
+type Decision string
+
+const (
+	Allow  Decision = "allow"
+	Review Decision = "review"
+	Deny   Decision = "deny"
+)
+
+// Input is the posting being screened. Balance is the account's balance
+// before it.
+type Input struct {
+	AccountID   string
+	Kind        string
+	Amount      int64
+	Destination string
+	Balance     int64
+	At          time.Time
+}
+
+// Verdict is the decision with the total score of the rules that matched
+// and a reason for each.
+type Verdict struct {
+	Decision Decision
+	Score    int
+	Reasons  []string
+}
+
+// Engine evaluates postings against rules that can be replaced while
+// serving.
+type Engine struct {
+	rules atomic.Pointer[Rules]
+}
+
+func NewEngine(rules Rules) *Engine {
+	e := &Engine{}
+	e.SetRules(rules)
+	return e
+}
+
+func (e *Engine) SetRules(rules Rules) {
+	e.rules.Store(&rules)
+}
+
+func (e *Engine) Rules() Rules {
+	return *e.rules.Load()
+}
+
+// Evaluate scores in against the account's history. Matching rules add
+// their weight; the total decides between allow, review and deny. A score of
+// zero is always allowed, so zero Rules screen nothing.
+func (e *Engine) Evaluate(in Input, history domain.FraudHistory) Verdict {
+	rules := e.rules.Load()
+
+	var verdict Verdict
+	for _, rule := range ruleSet {
+		weight := rules.Weights[rule.name]
+		if weight <= 0 {
+			continue
+		}
+		if reason, matched := rule.match(rules, in, history); matched {
+			verdict.Score += weight
+			verdict.Reasons = append(verdict.Reasons, rule.name+": "+reason)
+		}
+	}
+
+	switch {
+	case verdict.Score == 0:
+		verdict.Decision = Allow
+	case rules.DenyScore > 0 && verdict.Score >= rules.DenyScore:
+		verdict.Decision = Deny
+	case verdict.Score >= rules.ReviewScore:
+		verdict.Decision = Review
+	default:
+		verdict.Decision = Allow
+	}
+	return verdict
+}
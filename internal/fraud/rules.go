@@ -0,0 +1,168 @@
+package fraud
+
+import (
+	"corebanking/internal/domain"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule names, used in weights and reasons.
+const (
+	RuleAmountHistory   = "amount_vs_history"
+	RuleNewDestinations = "new_destinations"
+	RuleRoundAmount     = "round_amount"
+	RuleNewAccountDrain = "new_account_drain"
+)
+
+// Rules are the thresholds of each rule and the weight it adds to the score
+// when it matches. A rule without a positive weight is off.
+type Rules struct {
+	Weights map[string]int
+	// ReviewScore holds postings for review; DenyScore, when positive,
+	// declines them.
+	ReviewScore int
+	DenyScore   int
+
+	// amount_vs_history matches debits above HistoryMultiplier times the
+	// account's average debit, once it has HistoryMinimum debits.
+	HistoryMultiplier float64
+	HistoryMinimum    int
+	// new_destinations matches the NewDestinationCount-th transfer to a
+	// destination the account never sent to before within
+	// NewDestinationWindow.
+	NewDestinationWindow time.Duration
+	NewDestinationCount  int
+	// round_amount matches debits of at least RoundMinimum that are a
+	// multiple of RoundUnit.
+	RoundUnit    int64
+	RoundMinimum int64
+	// new_account_drain matches debits taking DrainRatio of the balance or
+	// more within NewAccountAge of the account's first posting.
+	NewAccountAge time.Duration
+	DrainRatio    float64
+}
+
+type rule struct {
+	name  string
+	match func(*Rules, Input, domain.FraudHistory) (string, bool)
+}
+
+var ruleSet = []rule{
+	{RuleAmountHistory, matchAmountHistory},
+	{RuleNewDestinations, matchNewDestinations},
+	{RuleRoundAmount, matchRoundAmount},
+	{RuleNewAccountDrain, matchNewAccountDrain},
+}
+
+func matchAmountHistory(r *Rules, in Input, history domain.FraudHistory) (string, bool) {
+	var total int64
+	var debits int
+	for _, activity := range history.Activity {
+		if activity.IsDebit() {
+			total += activity.Amount
+			debits++
+		}
+	}
+	if debits == 0 || debits < r.HistoryMinimum || r.HistoryMultiplier <= 0 {
+		return "", false
+	}
+	average := float64(total) / float64(debits)
+	if float64(in.Amount) <= r.HistoryMultiplier*average {
+		return "", false
+	}
+	return fmt.Sprintf("%s is %.1fx the average debit of %s", formatAmount(in.Amount), float64(in.Amount)/average, formatAmount(int64(average))), true
+}
+
+func matchNewDestinations(r *Rules, in Input, history domain.FraudHistory) (string, bool) {
+	if in.Kind != domain.ActivityTransfer || r.NewDestinationCount <= 0 {
+		return "", false
+	}
+
+	seen := make(map[string]bool)
+	recent := 0
+	since := in.At.Add(-r.NewDestinationWindow)
+	for _, activity := range history.Activity {
+		if activity.Kind != domain.ActivityTransfer {
+			continue
+		}
+		if !seen[activity.Destination] && !activity.At.Before(since) {
+			recent++
+		}
+		seen[activity.Destination] = true
+	}
+	if seen[in.Destination] || recent+1 < r.NewDestinationCount {
+		return "", false
+	}
+	return fmt.Sprintf("%d transfers to new destinations within %s", recent+1, r.NewDestinationWindow), true
+}
+
+func matchRoundAmount(r *Rules, in Input, _ domain.FraudHistory) (string, bool) {
+	if in.Kind == domain.ActivityCredit || r.RoundUnit <= 0 || in.Amount < r.RoundMinimum || in.Amount%r.RoundUnit != 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%s is a multiple of %s", formatAmount(in.Amount), formatAmount(r.RoundUnit)), true
+}
+
+func matchNewAccountDrain(r *Rules, in Input, history domain.FraudHistory) (string, bool) {
+	if history.FirstSeen.IsZero() || in.At.Sub(history.FirstSeen) >= r.NewAccountAge || in.Balance <= 0 || r.DrainRatio <= 0 {
+		return "", false
+	}
+	if float64(in.Amount) < r.DrainRatio*float64(in.Balance) {
+		return "", false
+	}
+	return fmt.Sprintf("takes %.0f%% of the balance %s after the first posting", 100*float64(in.Amount)/float64(in.Balance),
+		in.At.Sub(history.FirstSeen).Round(time.Minute)), true
+}
+
+// formatAmount writes minor units as a decimal amount.
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
+
+// ParseWeights reads comma separated <rule>=<weight> pairs, e.g.
+// "round_amount=20,new_account_drain=60".
+func ParseWeights(value string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid rule weight %q, want <rule>=<weight>", pair)
+		}
+		if !knownRule(name) {
+			return nil, fmt.Errorf("unknown rule %q, want one of %s", name, strings.Join(RuleNames(), ", "))
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("rule %s: weight must be a non-negative integer, got %q", name, raw)
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
+
+// RuleNames lists the rules in order.
+func RuleNames() []string {
+	names := make([]string, 0, len(ruleSet))
+	for _, rule := range ruleSet {
+		names = append(names, rule.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func knownRule(name string) bool {
+	for _, rule := range ruleSet {
+		if rule.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time, for example:
+//
+//	go build -ldflags "-X corebanking/internal/health.Commit=$(git rev-parse HEAD)"
+//
+// When empty they fall back to the VCS stamp the go command embeds.
+var (
+	Commit    string
+	BuildTime string
+)
+
+// Build describes the running binary.
+type Build struct {
+	Commit    string
+	BuildTime string
+	Modified  bool
+	GoVersion string
+}
+
+func ReadBuild() Build {
+	build := Build{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if build.Commit == "" {
+				build.Commit = setting.Value
+			}
+		case "vcs.time":
+			if build.BuildTime == "" {
+				build.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"context"
+	"corebanking/internal/tracing"
+	"corebanking/internal/utils"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Field names shared by every log line.
+const (
+	FieldRequestID     = "request_id"
+	FieldAccountID     = "account_id"
+	FieldTransactionID = "transaction_id"
+	FieldTraceID       = "trace_id"
+	FieldSpanID        = "span_id"
+)
+
+// New returns a JSON logger writing each record to every sink. Records below
+// level are dropped; pass a *slog.LevelVar to change it at runtime.
+func New(level slog.Leveler, sinks ...io.Writer) *slog.Logger {
+	handlers := make([]slog.Handler, 0, len(sinks))
+	for _, sink := range sinks {
+		handlers = append(handlers, slog.NewJSONHandler(sink, &slog.HandlerOptions{Level: level}))
+	}
+	return slog.New(&contextHandler{next: &fanoutHandler{handlers: handlers}})
+}
+
+// ParseLevel reads DEBUG, INFO, WARN or ERROR, in any case.
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+		return slog.LevelInfo, errors.New("unknown log level " + value)
+	}
+	return level, nil
+}
+
+type contextKey int
+
+const (
+	accountIDKey contextKey = iota
+	transactionIDKey
+)
+
+func WithAccountID(ctx context.Context, accountID string) context.Context {
+	return context.WithValue(ctx, accountIDKey, accountID)
+}
+
+func WithTransactionID(ctx context.Context, transactionID int64) context.Context {
+	return context.WithValue(ctx, transactionIDKey, transactionID)
+}
+
+// contextHandler adds the request, trace, account and transaction IDs
+// carried by the context to each record.
+type contextHandler struct {
+	next slog.Handler
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := utils.RequestIDFrom(ctx); requestID != "" {
+		record.AddAttrs(slog.String(FieldRequestID, requestID))
+	}
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		record.AddAttrs(slog.String(FieldTraceID, sc.TraceID().String()), slog.String(FieldSpanID, sc.SpanID().String()))
+	}
+	if accountID, ok := ctx.Value(accountIDKey).(string); ok {
+		record.AddAttrs(slog.String(FieldAccountID, accountID))
+	}
+	if transactionID, ok := ctx.Value(transactionIDKey).(int64); ok {
+		record.AddAttrs(slog.Int64(FieldTransactionID, transactionID))
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{next: h.next.WithGroup(name)}
+}
+
+// fanoutHandler sends each record to every handler that accepts its level.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, record.Level) {
+			errs = append(errs, handler.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
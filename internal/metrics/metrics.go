@@ -0,0 +1,110 @@
+// Package metrics exposes application metrics in the Prometheus text format
+// without depending on a Prometheus client.
+package metrics
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default is the registry served on /metrics.
+var Default = NewRegistry()
+
+var (
+	HTTPRequests = Default.NewCounterVec("corebanking_http_requests_total",
+		"HTTP requests served, by method, route pattern and status.",
+		"method", "route", "status")
+	HTTPDuration = Default.NewHistogramVec("corebanking_http_request_duration_seconds",
+		"HTTP request latency in seconds, by method, route pattern and status.",
+		DefBuckets, "method", "route", "status")
+	RateLimited = Default.NewCounterVec("corebanking_rate_limited_total",
+		"Requests refused with 429, by the scope whose limit was hit and request class.",
+		"scope", "class")
+
+	TransactionsPosted = Default.NewCounterVec("corebanking_transactions_posted_total",
+		"Transactions and events posted, by operation type.",
+		"operation")
+	TransactionsDeclined = Default.NewCounterVec("corebanking_transactions_declined_total",
+		"Transactions and events declined, by operation type and reason.",
+		"operation", "reason")
+	TransferVolume = Default.NewCounterVec("corebanking_transfer_volume_total",
+		"Amount moved by transfers, in minor units.")
+	FraudDecisions = Default.NewCounterVec("corebanking_fraud_decisions_total",
+		"Postings screened for fraud, by decision.",
+		"decision")
+	AMLAlerts = Default.NewCounterVec("corebanking_aml_alerts_total",
+		"AML alerts raised, by rule.",
+		"rule")
+	SanctionsScreenings = Default.NewCounterVec("corebanking_sanctions_screenings_total",
+		"Sanctions screenings, by kind and result.",
+		"kind", "result")
+	SuspenseItems = Default.NewCounterVec("corebanking_suspense_items_total",
+		"Deposits and transfers to unknown accounts held in suspense, claimed and returned, by kind and status.",
+		"kind", "status")
+	ScheduleExecutions = Default.NewCounterVec("corebanking_schedule_executions_total",
+		"Scheduled transfer runs, by outcome.",
+		"status")
+	BatchItems = Default.NewCounterVec("corebanking_batch_items_total",
+		"Batch items processed, by batch mode and item outcome.",
+		"mode", "status")
+)
+
+// Operation labels for the transaction operation types.
+var operationNames = map[int]string{
+	1: "normal_purchase",
+	2: "installment_purchase",
+	3: "withdrawal",
+	4: "credit_voucher",
+}
+
+// OperationLabel names an operation type ID for use as a label value.
+func OperationLabel(operationTypeID int) string {
+	if name, ok := operationNames[operationTypeID]; ok {
+		return name
+	}
+	return strconv.Itoa(operationTypeID)
+}
+
+func init() {
+	registerRuntime(Default)
+}
+
+// registerRuntime adds Go runtime gauges. Memory stats are read at most once
+// per scrape window since ReadMemStats stops the world.
+func registerRuntime(r *Registry) {
+	var (
+		mu       sync.Mutex
+		stats    runtime.MemStats
+		readAt   time.Time
+		memStats = func() *runtime.MemStats {
+			mu.Lock()
+			defer mu.Unlock()
+			if time.Since(readAt) > time.Second {
+				runtime.ReadMemStats(&stats)
+				readAt = time.Now()
+			}
+			return &stats
+		}
+	)
+
+	r.GaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+	r.GaugeFunc("go_memstats_alloc_bytes", "Bytes of allocated heap objects.", func() float64 {
+		return float64(memStats().HeapAlloc)
+	})
+	r.GaugeFunc("go_memstats_sys_bytes", "Bytes of memory obtained from the OS.", func() float64 {
+		return float64(memStats().Sys)
+	})
+	r.GaugeFunc("go_memstats_heap_objects", "Number of allocated heap objects.", func() float64 {
+		return float64(memStats().HeapObjects)
+	})
+	r.CounterFunc("go_gc_cycles_total", "Completed GC cycles.", func() float64 {
+		return float64(memStats().NumGC)
+	})
+	r.CounterFunc("go_gc_pause_seconds_total", "Total GC stop-the-world pause time in seconds.", func() float64 {
+		return float64(memStats().PauseTotalNs) / 1e9
+	})
+}
@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the default latency buckets, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector writes one metric family in the Prometheus text format.
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry holds metric families and renders them for /metrics.
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// register adds c, replacing a previous family of the same name so callers
+// can rebind gauge functions.
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors[c.name()] = c
+}
+
+// WriteText renders every family, sorted by name.
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	collectors := r.collectors
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		r.mu.RLock()
+		c := collectors[name]
+		r.mu.RUnlock()
+		c.write(w)
+	}
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// series is one labelled value of a vector.
+type series struct {
+	labels []string
+	value  float64
+	// Histograms only.
+	buckets []uint64
+	count   uint64
+}
+
+type vec struct {
+	metricName string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func (v *vec) name() string {
+	return v.metricName
+}
+
+func (v *vec) get(labelValues []string, init func(*series)) *series {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.metricName, len(v.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, exists := v.series[key]
+	if !exists {
+		s = &series{labels: append([]string(nil), labelValues...)}
+		if init != nil {
+			init(s)
+		}
+		v.series[key] = s
+	}
+	return s
+}
+
+func (v *vec) sortedSeries() []*series {
+	result := make([]*series, 0, len(v.series))
+	for _, s := range v.series {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.Join(result[i].labels, "\xff") < strings.Join(result[j].labels, "\xff")
+	})
+	return result
+}
+
+// CounterVec is a monotonically increasing value per label set.
+type CounterVec struct {
+	vec
+}
+
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{vec{metricName: name, help: help, labelNames: labelNames, series: map[string]*series{}}}
+	r.register(c)
+	return c
+}
+
+// Add increases the series by delta; negative deltas are ignored.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.get(labelValues, nil).value += delta
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Value returns the current value of a series, zero when it was never set.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(labelValues, nil).value
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writeHeader(w, c.metricName, c.help, "counter")
+	for _, s := range c.sortedSeries() {
+		writeSample(w, c.metricName, c.labelNames, s.labels, "", "", s.value)
+	}
+}
+
+// HistogramVec counts observations into cumulative buckets per label set.
+type HistogramVec struct {
+	vec
+	upperBounds []float64
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := &HistogramVec{
+		vec:         vec{metricName: name, help: help, labelNames: labelNames, series: map[string]*series{}},
+		upperBounds: append([]float64(nil), buckets...),
+	}
+	sort.Float64s(h.upperBounds)
+	r.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.get(labelValues, func(s *series) { s.buckets = make([]uint64, len(h.upperBounds)) })
+	for i, bound := range h.upperBounds {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	writeHeader(w, h.metricName, h.help, "histogram")
+	for _, s := range h.sortedSeries() {
+		for i, bound := range h.upperBounds {
+			writeSample(w, h.metricName+"_bucket", h.labelNames, s.labels, "le", formatFloat(bound), float64(s.buckets[i]))
+		}
+		writeSample(w, h.metricName+"_bucket", h.labelNames, s.labels, "le", "+Inf", float64(s.count))
+		writeSample(w, h.metricName+"_sum", h.labelNames, s.labels, "", "", s.value)
+		writeSample(w, h.metricName+"_count", h.labelNames, s.labels, "", "", float64(s.count))
+	}
+}
+
+// funcMetric reads its value when scraped.
+type funcMetric struct {
+	metricName string
+	help       string
+	kind       string
+	fn         func() float64
+}
+
+func (f *funcMetric) name() string {
+	return f.metricName
+}
+
+func (f *funcMetric) write(w io.Writer) {
+	writeHeader(w, f.metricName, f.help, f.kind)
+	writeSample(w, f.metricName, nil, nil, "", "", f.fn())
+}
+
+// GaugeFunc exposes fn as a gauge. Registering a name again replaces it.
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{metricName: name, help: help, kind: "gauge", fn: fn})
+}
+
+// CounterFunc exposes fn, which must never decrease, as a counter.
+func (r *Registry) CounterFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{metricName: name, help: help, kind: "counter", fn: fn})
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, kind)
+}
+
+func writeSample(w io.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+
+	pairs := make([]string, 0, len(labelNames)+1)
+	for i, labelName := range labelNames {
+		pairs = append(pairs, labelName+`="`+escapeLabel(labelValues[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	if len(pairs) > 0 {
+		b.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	b.WriteString(" " + formatFloat(value) + "\n")
+	io.WriteString(w, b.String())
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(value string) string {
+	return helpEscaper.Replace(value)
+}
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"corebanking/internal/utils"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// AccessLog writes one line per request with its method, matched route
+// pattern, status, latency, response size and principal. It must wrap the
+// authentication middleware and router so they can report into the request
+// info it attaches.
+func AccessLog(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx, info := utils.WithRequestInfo(r.Context())
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			route := info.Pattern
+			if route == "" {
+				route = "unmatched"
+			}
+			logger.LogAttrs(ctx, slog.LevelInfo, "request",
+				slog.String("method", r.Method),
+				slog.String("route", route),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", recorder.status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.Int64("bytes", recorder.bytes),
+				slog.String("principal", info.Principal),
+			)
+		})
+	}
+}
+
+// statusRecorder remembers the status and counts the body bytes written
+// through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/utils"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Authenticate rejects requests without valid credentials with a 401
+// problem body and attaches the principal of the others to their context.
+// Paths for which public returns true are served without credentials.
+func Authenticate(authenticator *auth.Authenticator, logger utils.ErrorHandler, public func(*http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if public != nil && public(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r)
+			if err != nil {
+				if logger != nil {
+					logger.Handle(r.Context(), err, "Failed to authenticate request.")
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer realm="corebanking", ApiKey header="`+auth.APIKeyHeader+`"`)
+				utils.WriteProblem(w, r, http.StatusUnauthorized, err.Error())
+				return
+			}
+
+			recordPrincipal(r, principal)
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+func recordPrincipal(r *http.Request, principal *auth.Principal) {
+	if info := utils.RequestInfoFrom(r.Context()); info != nil {
+		info.Principal = principal.Subject
+	}
+}
+
+// PublicPaths treats requests for any of the documents of an API version
+// as public: "openapi.json" is /api/{version}/openapi.json for every
+// version and /api/openapi.json for the default one, and no other path
+// ending with it.
+func PublicPaths(documents ...string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		rest, found := strings.CutPrefix(r.URL.Path, "/api/")
+		if !found {
+			return false
+		}
+		version, document, found := strings.Cut(rest, "/")
+		if !found {
+			return slices.Contains(documents, rest)
+		}
+		return version != "" && slices.Contains(documents, document)
+	}
+}
+
+// Anonymous attaches a fixed principal to every request. It replaces
+// Authenticate when authentication is disabled so the policy still has a
+// caller to evaluate.
+func Anonymous(principal *auth.Principal) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recordPrincipal(r, principal)
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
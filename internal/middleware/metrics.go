@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"corebanking/internal/metrics"
+	"corebanking/internal/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Metrics counts requests and observes their latency by method, matched
+// route pattern and status. Like AccessLog it must wrap the router; unmatched
+// requests share one route label so paths never become labels.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx, info := utils.WithRequestInfo(r.Context())
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		route := info.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(recorder.status)
+		metrics.HTTPRequests.Inc(r.Method, route, status)
+		metrics.HTTPDuration.Observe(time.Since(start).Seconds(), r.Method, route, status)
+	})
+}
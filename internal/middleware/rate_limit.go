@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"bytes"
+	"corebanking/internal/auth"
+	"corebanking/internal/metrics"
+	"corebanking/internal/ratelimit"
+	"corebanking/internal/utils"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeekBody bounds how much of a money-moving body is read to find the
+// account it debits, and maxPeekBatch how much of a batch is read to count
+// its items.
+const (
+	maxPeekBody  = 64 << 10
+	maxPeekBatch = 4 << 20
+)
+
+// RateLimitIP answers 429 once the caller's IP address runs out of tokens.
+// It runs in front of Authenticate, so requests with missing or wrong
+// credentials are limited too, and every request draws from the IP bucket
+// whether it is refused later or not.
+func RateLimitIP(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return rateLimit(limiter, func(r *http.Request, class ratelimit.Class, body []byte) []ratelimit.Key {
+		return limiter.Rules().Keys(class, "", "", clientIP(r), "")
+	})
+}
+
+// RateLimit answers 429 once the caller or the account the request touches
+// runs out of tokens. It must run inside Authenticate so the principal is
+// known, and inside RateLimitIP, which limits the IP address.
+func RateLimit(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return rateLimit(limiter, func(r *http.Request, class ratelimit.Class, body []byte) []ratelimit.Key {
+		var clientID, tier string
+		if principal, ok := auth.PrincipalFrom(r.Context()); ok {
+			clientID, tier = principal.ID, principal.Tier
+		}
+		return limiter.Rules().Keys(class, clientID, tier, "", requestAccount(r, body))
+	})
+}
+
+// rateLimit draws from the buckets keys returns. A batch takes a token per
+// item. Allowed responses carry RateLimit-* headers for the most
+// constrained bucket.
+func rateLimit(limiter *ratelimit.Limiter, keys func(r *http.Request, class ratelimit.Class, body []byte) []ratelimit.Key) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			class := RequestClass(r)
+			body := peekBody(r, class)
+			decision := limiter.AllowN(requestCost(r, body), keys(r, class, body)...)
+			if !decision.Limit.Unlimited() {
+				setRateLimitHeaders(w.Header(), decision)
+			}
+			if !decision.Allowed {
+				metrics.RateLimited.Inc(decision.Scope, string(class))
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+				utils.WriteProblem(w, r, http.StatusTooManyRequests, "rate limit exceeded for "+decision.Scope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestClass treats posting transactions, events and batches of them as
+// money-moving.
+func RequestClass(r *http.Request) ratelimit.Class {
+	if r.Method != http.MethodPost {
+		return ratelimit.ClassStandard
+	}
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	for _, suffix := range []string{"/transactions", "/transactions/event", "/transactions/batch", "/events"} {
+		if strings.HasSuffix(path, suffix) {
+			return ratelimit.ClassMoney
+		}
+	}
+	return ratelimit.ClassStandard
+}
+
+// setRateLimitHeaders describes decision's bucket, unless an outer
+// middleware has already described one with fewer tokens left.
+func setRateLimitHeaders(header http.Header, decision ratelimit.Decision) {
+	if remaining, err := strconv.Atoi(header.Get("RateLimit-Remaining")); err == nil && decision.Allowed && remaining <= decision.Remaining {
+		return
+	}
+	header.Set("RateLimit-Limit", strconv.Itoa(decision.Limit.Burst))
+	header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+	header.Set("RateLimit-Policy", strconv.Itoa(decision.Limit.Burst)+";w="+strconv.Itoa(ceilSeconds(decision.Limit.Window())))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// isBatch reports whether r posts a batch.
+func isBatch(r *http.Request) bool {
+	return strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/transactions/batch")
+}
+
+// peekBody reads the start of a money-moving request's body and puts it
+// back for the handler. Other requests' bodies are not read.
+func peekBody(r *http.Request, class ratelimit.Class) []byte {
+	if class != ratelimit.ClassMoney || r.Body == nil {
+		return nil
+	}
+	limit := int64(maxPeekBody)
+	if isBatch(r) {
+		limit = maxPeekBatch
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil {
+		return nil
+	}
+	return body
+}
+
+// requestCost is how many tokens a request takes: one per item of a
+// batch, one for anything else. A batch too large to read whole is
+// charged as if it had no end, which takes full buckets.
+func requestCost(r *http.Request, body []byte) int {
+	if !isBatch(r) {
+		return 1
+	}
+	var batch struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if json.Unmarshal(body, &batch) != nil {
+		return math.MaxInt
+	}
+	return max(len(batch.Items), 1)
+}
+
+// requestAccount finds the account a request reads or debits: a path
+// segment after /accounts/, an account_id or accountId query parameter, or
+// for money-moving requests the accountId, origin or destination field of
+// the peeked body. Batches touch many accounts and name none.
+func requestAccount(r *http.Request, body []byte) string {
+	if _, rest, ok := strings.Cut(r.URL.Path, "/accounts/"); ok {
+		segment, _, _ := strings.Cut(rest, "/")
+		switch segment {
+		case "", "balance", "overdraft", "reset":
+		default:
+			return segment
+		}
+	}
+	query := r.URL.Query()
+	for _, name := range []string{"account_id", "accountId"} {
+		if id := query.Get(name); id != "" {
+			return id
+		}
+	}
+	if body == nil || isBatch(r) {
+		return ""
+	}
+
+	var fields struct {
+		AccountID   string `json:"accountId"`
+		Origin      string `json:"origin"`
+		Destination string `json:"destination"`
+	}
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	switch {
+	case fields.AccountID != "":
+		return fields.AccountID
+	case fields.Origin != "":
+		return fields.Origin
+	default:
+		return fields.Destination
+	}
+}
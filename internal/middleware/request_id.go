@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"corebanking/internal/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID propagates the caller's X-Request-ID, or assigns a new one,
+// echoing it on the response and storing it in the request context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(utils.RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(utils.RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(utils.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"corebanking/internal/tracing"
+	"corebanking/internal/utils"
+	"net/http"
+	"strings"
+)
+
+// Trace starts a server span per request, continuing the caller's trace when
+// the request carries a W3C traceparent header. The span is named after the
+// matched route once the router has run, so it must wrap the router.
+func Trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tracing.Extract(r.Context(), r.Header)
+		ctx, info := utils.WithRequestInfo(ctx)
+		ctx, span := tracing.StartKind(ctx, r.Method, tracing.KindServer,
+			tracing.String("http.request.method", r.Method),
+			tracing.String("url.path", r.URL.Path),
+			tracing.String("request.id", utils.RequestIDFrom(ctx)),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		if route := info.Pattern; route != "" {
+			// v2 patterns already start with the method.
+			if !strings.HasPrefix(route, r.Method+" ") {
+				route = r.Method + " " + route
+			}
+			span.SetName(route)
+			span.SetAttributes(tracing.String("http.route", info.Pattern))
+		}
+		span.SetAttributes(tracing.Int("http.response.status_code", recorder.status))
+		if info.Principal != "" {
+			span.SetAttributes(tracing.String("enduser.id", info.Principal))
+		}
+		if recorder.status >= http.StatusInternalServerError {
+			span.RecordError(errStatus(recorder.status))
+		}
+	})
+}
+
+type errStatus int
+
+func (e errStatus) Error() string {
+	return http.StatusText(int(e))
+}
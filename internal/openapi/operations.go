@@ -0,0 +1,472 @@
+package openapi
+
+import (
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type operation struct {
+	method   string
+	path     string
+	summary  string
+	tag      string
+	params   []Parameter
+	request  any
+	status   int
+	response any
+	// raw responses are served as is, without the envelope, such as files
+	// to download.
+	raw bool
+}
+
+func pathParam(name string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Required: true, Schema: schema}
+}
+
+func queryParam(name string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Required: true, Schema: schema}
+}
+
+func headerParam(name string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "header", Required: true, Schema: schema}
+}
+
+func optionalQueryParam(name string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Schema: schema}
+}
+
+// resetConfirmationHeader mirrors controller.ResetConfirmationHeader; the
+// controller package imports this one, so it cannot be referenced here.
+const resetConfirmationHeader = "X-Reset-Confirmation"
+
+var (
+	stringSchema   = &Schema{Type: "string"}
+	int32Schema    = &Schema{Type: "integer", Format: "int32"}
+	int64Schema    = &Schema{Type: "integer", Format: "int64"}
+	dateTimeSchema = &Schema{Type: "string", Format: "date-time"}
+)
+
+var v1Operations = []operation{
+	{
+		method: http.MethodPost, path: "/accounts", summary: "Create account", tag: "accounts",
+		request: dto.AccountRequest{}, status: http.StatusCreated, response: dto.AccountResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/{accountId}", summary: "Search account", tag: "accounts",
+		params: []Parameter{pathParam("accountId", stringSchema)},
+		status: http.StatusOK, response: dto.AccountResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/balance", summary: "Return balance", tag: "accounts",
+		params: []Parameter{queryParam("account_id", stringSchema)},
+		status: http.StatusOK, response: dto.BalanceResponse{},
+	},
+	{
+		method: http.MethodPost, path: "/accounts/overdraft", summary: "Set overdraft", tag: "accounts",
+		request: dto.OverdraftRequest{}, status: http.StatusOK, response: dto.MessageResponse{},
+	},
+	{
+		method: http.MethodPost, path: "/accounts/reset", summary: "Reset data (sandbox only)", tag: "accounts",
+		params: []Parameter{headerParam(resetConfirmationHeader, stringSchema)},
+		status: http.StatusOK, response: dto.MessageResponse{},
+	},
+	{
+		method: http.MethodPost, path: "/transactions", summary: "Create transaction", tag: "transactions",
+		request: dto.TransactionRequest{}, status: http.StatusCreated, response: dto.TransactionResponse{},
+	},
+	{
+		method: http.MethodPost, path: "/transactions/event", summary: "Handle event to operate", tag: "transactions",
+		request: dto.EventRequest{}, status: http.StatusCreated, response: map[string]*domain.Account{},
+	},
+	{
+		method: http.MethodGet, path: "/transactions/today", summary: "List transactions of the day", tag: "transactions",
+		status: http.StatusOK, response: []*dto.TransactionResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/transactions/range", summary: "List transactions in a date range", tag: "transactions",
+		params: []Parameter{queryParam("begin", dateTimeSchema), queryParam("end", dateTimeSchema)},
+		status: http.StatusOK, response: []*dto.TransactionResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/transactions/type/{operationTypeId}", summary: "List transactions by type", tag: "transactions",
+		params: []Parameter{pathParam("operationTypeId", int32Schema)},
+		status: http.StatusOK, response: []*dto.TransactionResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/transactions/{transactionId}", summary: "Search transaction", tag: "transactions",
+		params: []Parameter{pathParam("transactionId", int64Schema)},
+		status: http.StatusOK, response: dto.TransactionResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/transactions/all", summary: "List all transactions", tag: "transactions",
+		status: http.StatusOK, response: []*domain.Transaction{},
+	},
+}
+
+var v2Operations = []operation{
+	{
+		method: http.MethodPost, path: "/accounts", summary: "Create account", tag: "accounts",
+		request: dto.AccountRequest{}, status: http.StatusCreated, response: dto.AccountV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/{accountId}", summary: "Search account", tag: "accounts",
+		params: []Parameter{pathParam("accountId", stringSchema)},
+		status: http.StatusOK, response: dto.AccountV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/{accountId}/balance", summary: "Return balance", tag: "accounts",
+		params: []Parameter{pathParam("accountId", stringSchema)},
+		status: http.StatusOK, response: dto.BalanceV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/{accountId}/verify", summary: "Confirm a destination account exists, with its holder name masked", tag: "accounts",
+		params: []Parameter{pathParam("accountId", stringSchema)},
+		status: http.StatusOK, response: dto.AccountVerificationV2Response{},
+	},
+	{
+		method: http.MethodPut, path: "/accounts/{accountId}/overdraft", summary: "Set overdraft", tag: "accounts",
+		params:  []Parameter{pathParam("accountId", stringSchema)},
+		request: dto.OverdraftV2Request{}, status: http.StatusOK, response: dto.OverdraftV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/{accountId}/limits", summary: "Return limits and what is left of them", tag: "accounts",
+		params: []Parameter{pathParam("accountId", stringSchema)},
+		status: http.StatusOK, response: dto.LimitsV2Response{},
+	},
+	{
+		method: http.MethodPut, path: "/accounts/{accountId}/limits", summary: "Set product and limits", tag: "accounts",
+		params:  []Parameter{pathParam("accountId", stringSchema)},
+		request: dto.LimitsV2Request{}, status: http.StatusOK, response: dto.LimitsV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/accounts/{accountId}/schedules", summary: "Schedule a one-off or recurring transfer", tag: "schedules",
+		params:  []Parameter{pathParam("accountId", stringSchema)},
+		request: dto.ScheduleV2Request{}, status: http.StatusCreated, response: dto.ScheduleV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/{accountId}/schedules", summary: "List scheduled transfers", tag: "schedules",
+		params: []Parameter{pathParam("accountId", stringSchema)},
+		status: http.StatusOK, response: []dto.ScheduleV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/{accountId}/schedules/{scheduleId}", summary: "Get scheduled transfer", tag: "schedules",
+		params: []Parameter{pathParam("accountId", stringSchema), pathParam("scheduleId", stringSchema)},
+		status: http.StatusOK, response: dto.ScheduleV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/accounts/{accountId}/schedules/{scheduleId}/executions", summary: "List the runs of a scheduled transfer", tag: "schedules",
+		params: []Parameter{pathParam("accountId", stringSchema), pathParam("scheduleId", stringSchema)},
+		status: http.StatusOK, response: []dto.ScheduleExecutionV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/accounts/{accountId}/schedules/{scheduleId}/pause", summary: "Pause a scheduled transfer", tag: "schedules",
+		params: []Parameter{pathParam("accountId", stringSchema), pathParam("scheduleId", stringSchema)},
+		status: http.StatusOK, response: dto.ScheduleV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/accounts/{accountId}/schedules/{scheduleId}/resume", summary: "Resume a paused scheduled transfer", tag: "schedules",
+		params: []Parameter{pathParam("accountId", stringSchema), pathParam("scheduleId", stringSchema)},
+		status: http.StatusOK, response: dto.ScheduleV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/accounts/{accountId}/schedules/{scheduleId}/cancel", summary: "Cancel a scheduled transfer", tag: "schedules",
+		params: []Parameter{pathParam("accountId", stringSchema), pathParam("scheduleId", stringSchema)},
+		status: http.StatusOK, response: dto.ScheduleV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/transactions", summary: "Create transaction", tag: "transactions",
+		request: dto.TransactionV2Request{}, status: http.StatusCreated, response: dto.TransactionV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/transactions", summary: "List transactions", tag: "transactions",
+		params: []Parameter{
+			optionalQueryParam("date", stringSchema),
+			optionalQueryParam("begin", dateTimeSchema),
+			optionalQueryParam("end", dateTimeSchema),
+			optionalQueryParam("operationTypeId", int32Schema),
+		},
+		status: http.StatusOK, response: []dto.TransactionV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/transactions/{transactionId}", summary: "Search transaction", tag: "transactions",
+		params: []Parameter{pathParam("transactionId", int64Schema)},
+		status: http.StatusOK, response: dto.TransactionV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/transactions/batch", summary: "Post a batch of transactions and events", tag: "transactions",
+		request: dto.BatchV2Request{}, status: http.StatusCreated, response: dto.BatchV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/transactions/batch/{batchId}", summary: "Get batch status and item results", tag: "transactions",
+		params: []Parameter{pathParam("batchId", stringSchema)},
+		status: http.StatusOK, response: dto.BatchV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/events", summary: "Handle event to operate", tag: "transactions",
+		request: dto.EventV2Request{}, status: http.StatusCreated, response: dto.EventV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/auth/me", summary: "Return authenticated principal", tag: "auth",
+		status: http.StatusOK, response: auth.Principal{},
+	},
+	{
+		method: http.MethodGet, path: "/auth/keys", summary: "List api keys", tag: "auth",
+		status: http.StatusOK, response: []dto.APIKeyResponse{},
+	},
+	{
+		method: http.MethodPost, path: "/auth/keys", summary: "Issue api key", tag: "auth",
+		request: dto.APIKeyRequest{}, status: http.StatusCreated, response: dto.APIKeyResponse{},
+	},
+	{
+		method: http.MethodPost, path: "/auth/keys/{keyId}/rotate", summary: "Rotate api key", tag: "auth",
+		params:  []Parameter{pathParam("keyId", stringSchema)},
+		request: dto.APIKeyRotateRequest{}, status: http.StatusOK, response: dto.APIKeyResponse{},
+	},
+	{
+		method: http.MethodDelete, path: "/auth/keys/{keyId}", summary: "Revoke api key", tag: "auth",
+		params: []Parameter{pathParam("keyId", stringSchema)},
+		status: http.StatusNoContent,
+	},
+	{
+		method: http.MethodPost, path: "/system/reset", summary: "Reset accounts and transactions (sandbox only)", tag: "system",
+		params: []Parameter{headerParam(resetConfirmationHeader, stringSchema)},
+		status: http.StatusOK, response: dto.MessageResponse{},
+	},
+	{
+		method: http.MethodPost, path: "/accounts/{accountId}/reset", summary: "Reset one account fixture (sandbox only)", tag: "system",
+		params: []Parameter{pathParam("accountId", stringSchema), headerParam(resetConfirmationHeader, stringSchema)},
+		status: http.StatusOK, response: dto.MessageResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/audit", summary: "Query audit trail", tag: "audit",
+		params: []Parameter{
+			optionalQueryParam("actor", stringSchema),
+			optionalQueryParam("entity", stringSchema),
+			optionalQueryParam("entityId", stringSchema),
+			optionalQueryParam("from", dateTimeSchema),
+			optionalQueryParam("to", dateTimeSchema),
+		},
+		status: http.StatusOK, response: []domain.AuditEntry{},
+	},
+	{
+		method: http.MethodGet, path: "/audit/verify", summary: "Verify audit hash chain", tag: "audit",
+		status: http.StatusOK, response: dto.AuditVerifyResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/fraud/cases", summary: "List fraud cases, pending by default", tag: "fraud",
+		params: []Parameter{optionalQueryParam("status", stringSchema)},
+		status: http.StatusOK, response: []dto.FraudCaseV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/fraud/cases/{caseId}", summary: "Get fraud case", tag: "fraud",
+		params: []Parameter{pathParam("caseId", stringSchema)},
+		status: http.StatusOK, response: dto.FraudCaseV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/fraud/cases/{caseId}/approve", summary: "Approve and post a held debit", tag: "fraud",
+		params:  []Parameter{pathParam("caseId", stringSchema)},
+		request: dto.FraudDecisionV2Request{}, status: http.StatusOK, response: dto.FraudCaseV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/fraud/cases/{caseId}/reject", summary: "Reject a held debit", tag: "fraud",
+		params:  []Parameter{pathParam("caseId", stringSchema)},
+		request: dto.FraudDecisionV2Request{}, status: http.StatusOK, response: dto.FraudCaseV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/aml/alerts", summary: "List AML alerts, open by default", tag: "aml",
+		params: []Parameter{
+			optionalQueryParam("status", stringSchema),
+			optionalQueryParam("accountId", stringSchema),
+		},
+		status: http.StatusOK, response: []dto.AMLAlertV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/aml/alerts/{alertId}", summary: "Get AML alert", tag: "aml",
+		params: []Parameter{pathParam("alertId", stringSchema)},
+		status: http.StatusOK, response: dto.AMLAlertV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/aml/alerts/{alertId}/disposition", summary: "Disposition an open AML alert", tag: "aml",
+		params:  []Parameter{pathParam("alertId", stringSchema)},
+		request: dto.AMLDispositionV2Request{}, status: http.StatusOK, response: dto.AMLAlertV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/aml/reports", summary: "Download a suspicious activity report", tag: "aml",
+		params: []Parameter{
+			optionalQueryParam("from", dateTimeSchema),
+			optionalQueryParam("to", dateTimeSchema),
+		},
+		status: http.StatusOK, response: dto.SuspiciousActivityReport{}, raw: true,
+	},
+	{
+		method: http.MethodGet, path: "/sanctions/list", summary: "Describe the sanctions list in use", tag: "sanctions",
+		status: http.StatusOK, response: dto.SanctionsListV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/sanctions/list/reload", summary: "Reload the sanctions list from its file", tag: "sanctions",
+		status: http.StatusOK, response: dto.SanctionsListV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/sanctions/screenings", summary: "List sanctions screenings", tag: "sanctions",
+		params: []Parameter{
+			optionalQueryParam("accountId", stringSchema),
+			optionalQueryParam("documentNumber", stringSchema),
+		},
+		status: http.StatusOK, response: []dto.SanctionsScreeningV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/sanctions/cases", summary: "List sanctions cases, pending by default", tag: "sanctions",
+		params: []Parameter{optionalQueryParam("status", stringSchema)},
+		status: http.StatusOK, response: []dto.SanctionsCaseV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/sanctions/cases/{caseId}", summary: "Get sanctions case", tag: "sanctions",
+		params: []Parameter{pathParam("caseId", stringSchema)},
+		status: http.StatusOK, response: dto.SanctionsCaseV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/sanctions/cases/{caseId}/approve", summary: "Clear the hits and carry out a held onboarding or transfer", tag: "sanctions",
+		params:  []Parameter{pathParam("caseId", stringSchema)},
+		request: dto.SanctionsDecisionV2Request{}, status: http.StatusOK, response: dto.SanctionsCaseV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/sanctions/cases/{caseId}/reject", summary: "Reject a held onboarding or transfer", tag: "sanctions",
+		params:  []Parameter{pathParam("caseId", stringSchema)},
+		request: dto.SanctionsDecisionV2Request{}, status: http.StatusOK, response: dto.SanctionsCaseV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/suspense/items", summary: "List postings held in suspense, held by default", tag: "suspense",
+		params: []Parameter{
+			optionalQueryParam("status", stringSchema),
+			optionalQueryParam("accountId", stringSchema),
+		},
+		status: http.StatusOK, response: []dto.SuspenseItemV2Response{},
+	},
+	{
+		method: http.MethodGet, path: "/suspense/items/{itemId}", summary: "Get suspense item", tag: "suspense",
+		params: []Parameter{pathParam("itemId", stringSchema)},
+		status: http.StatusOK, response: dto.SuspenseItemV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/suspense/items/{itemId}/claim", summary: "Move a held posting to the account it was meant for", tag: "suspense",
+		params:  []Parameter{pathParam("itemId", stringSchema)},
+		request: dto.SuspenseClaimV2Request{}, status: http.StatusOK, response: dto.SuspenseItemV2Response{},
+	},
+	{
+		method: http.MethodPost, path: "/suspense/items/{itemId}/return", summary: "Return a held posting to its origin", tag: "suspense",
+		params:  []Parameter{pathParam("itemId", stringSchema)},
+		request: dto.SuspenseReturnV2Request{}, status: http.StatusOK, response: dto.SuspenseItemV2Response{},
+	},
+}
+
+// apiSurface describes one API version: its operations and whether bodies
+// are wrapped in dto.Envelope.
+type apiSurface struct {
+	operations []operation
+	envelope   bool
+}
+
+var surfaces = map[string]apiSurface{
+	"v1": {operations: v1Operations},
+	"v2": {operations: v2Operations, envelope: true},
+}
+
+// Build assembles the OpenAPI document for the routes served under
+// /api/{version}.
+func Build(appName, version string) *Document {
+	doc := &Document{
+		OpenAPI:  "3.0.3",
+		Info:     Info{Title: appName, Version: version},
+		Servers:  []Server{{URL: "/api/" + version}},
+		Security: []map[string][]string{{"apiKey": {}}, {"bearer": {}}},
+		Paths:    map[string]PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"apiKey": {Type: "apiKey", Name: auth.APIKeyHeader, In: "header"},
+				"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	surface := surfaces[version]
+	errStatus := strconv.Itoa(http.StatusBadRequest)
+	errSchema := doc.schemaFor(reflect.TypeOf(dto.ErrorResponse{}))
+	problemSchema := doc.schemaFor(reflect.TypeOf(dto.Problem{}))
+	if surface.envelope {
+		errStatus = "default"
+		errSchema = doc.envelopeSchema(nil)
+	}
+
+	for _, op := range surface.operations {
+		item, exists := doc.Paths[op.path]
+		if !exists {
+			item = PathItem{}
+			doc.Paths[op.path] = item
+		}
+
+		success := &Response{Description: http.StatusText(op.status)}
+		if op.response != nil {
+			response := doc.schemaFor(reflect.TypeOf(op.response))
+			if surface.envelope && !op.raw {
+				response = doc.envelopeSchema(response)
+			}
+			success.Content = jsonContent(response)
+		}
+
+		operation := &Operation{
+			Summary:    op.summary,
+			Tags:       []string{op.tag},
+			Parameters: op.params,
+			Responses: map[string]*Response{
+				strconv.Itoa(op.status): success,
+				strconv.Itoa(http.StatusUnauthorized): {
+					Description: http.StatusText(http.StatusUnauthorized),
+					Content:     map[string]MediaType{"application/problem+json": {Schema: problemSchema}},
+				},
+				strconv.Itoa(http.StatusTooManyRequests): {
+					Description: http.StatusText(http.StatusTooManyRequests),
+					Content:     map[string]MediaType{"application/problem+json": {Schema: problemSchema}},
+				},
+				errStatus: {
+					Description: "Error",
+					Content:     jsonContent(errSchema),
+				},
+			},
+		}
+		if op.request != nil {
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(doc.schemaFor(reflect.TypeOf(op.request))),
+			}
+		}
+		item[strings.ToLower(op.method)] = operation
+	}
+
+	return doc
+}
+
+// envelopeSchema describes dto.Envelope with its data member narrowed to
+// the operation's payload.
+func (d *Document) envelopeSchema(data *Schema) *Schema {
+	if data == nil {
+		data = &Schema{}
+	}
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"data":  data,
+			"error": d.schemaFor(reflect.TypeOf(dto.EnvelopeError{})),
+			"meta":  d.schemaFor(reflect.TypeOf(dto.EnvelopeMeta{})),
+		},
+	}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
@@ -0,0 +1,26 @@
+package openapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/base64"
+)
+
+// UI is the documentation page. It is self-contained: its script and
+// styles are inline and it loads nothing but the spec, so no third-party
+// code ever runs on the API's origin.
+//
+//go:embed ui.html
+var UI []byte
+
+// UIContentSecurityPolicy is served with UI. It allows only the page's own
+// inline script, by hash, and requests back to the API.
+var UIContentSecurityPolicy = "default-src 'none'; connect-src 'self'; style-src 'unsafe-inline'; script-src 'sha256-" + inlineScriptHash(UI) + "'"
+
+func inlineScriptHash(page []byte) string {
+	_, script, _ := bytes.Cut(page, []byte("<script>"))
+	script, _, _ = bytes.Cut(script, []byte("</script>"))
+	sum := sha256.Sum256(script)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
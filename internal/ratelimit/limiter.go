@@ -0,0 +1,232 @@
+// Package ratelimit keeps token buckets for API clients, IP addresses and
+// accounts.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limit is a token bucket that holds Burst tokens and refills at Rate
+// tokens per second. The zero Limit is unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Window is how long an empty bucket takes to refill.
+func (l Limit) Window() time.Duration {
+	if l.Unlimited() {
+		return 0
+	}
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Window())
+}
+
+// ParseLimit reads "<n>/<unit>" with unit s, m or h, e.g. "600/m": bursts
+// of 600 requests, refilled over a minute. "off" or "0" is unlimited.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "off" || value == "0" || value == "" {
+		return Limit{}, nil
+	}
+
+	count, unit, ok := strings.Cut(value, "/")
+	n, err := strconv.Atoi(count)
+	if !ok || err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q, want <n>/s, <n>/m or <n>/h", value)
+	}
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid limit %q, want <n>/s, <n>/m or <n>/h", value)
+	}
+	return Limit{Rate: float64(n) / per.Seconds(), Burst: n}, nil
+}
+
+// Key is one bucket a request draws from.
+type Key struct {
+	// Scope says what the bucket is keyed by, e.g. client, ip or account.
+	Scope string
+	ID    string
+	Limit Limit
+}
+
+// Decision is the outcome for the most constrained bucket.
+type Decision struct {
+	Allowed bool
+	// Scope names the bucket that denied the request or, when allowed,
+	// the one with the fewest tokens left.
+	Scope     string
+	Limit     Limit
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until a denied request would be allowed.
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// idleSweep is how often buckets that have refilled are dropped.
+const idleSweep = time.Minute
+
+// Limiter holds the buckets and the rules that pick them. Unlimited keys
+// are skipped.
+type Limiter struct {
+	rules atomic.Pointer[Rules]
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewLimiter(rules Rules) *Limiter {
+	return NewLimiterWithClock(rules, time.Now)
+}
+
+// NewLimiterWithClock lets tests control time.
+func NewLimiterWithClock(rules Rules, now func() time.Time) *Limiter {
+	l := &Limiter{buckets: make(map[string]*bucket), now: now, lastSweep: now()}
+	l.SetRules(rules)
+	return l
+}
+
+// SetRules replaces the rules while serving. Buckets keep their tokens,
+// capped at the new burst.
+func (l *Limiter) SetRules(rules Rules) {
+	l.rules.Store(&rules)
+}
+
+func (l *Limiter) Rules() Rules {
+	return *l.rules.Load()
+}
+
+// Allow takes one token from every key, or from none when any bucket is
+// empty, so a request denied by one bucket does not drain the others.
+func (l *Limiter) Allow(keys ...Key) Decision {
+	return l.AllowN(1, keys...)
+}
+
+// AllowN is Allow for a request costing n tokens, such as a batch of n
+// postings. A bucket never holds more than its burst, so a request costing
+// more takes a full bucket rather than never being allowed.
+func (l *Limiter) AllowN(n int, keys ...Key) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	type draw struct {
+		bucket *bucket
+		cost   float64
+	}
+	decision := Decision{Allowed: true, Remaining: math.MaxInt}
+	draws := make([]draw, 0, len(keys))
+	for _, key := range keys {
+		if key.Limit.Unlimited() {
+			continue
+		}
+		b := l.refill(key, now)
+		cost := float64(min(max(n, 1), key.Limit.Burst))
+		draws = append(draws, draw{b, cost})
+
+		if b.tokens < cost {
+			retryAfter := time.Duration((cost - b.tokens) / key.Limit.Rate * float64(time.Second))
+			if !decision.Allowed && retryAfter <= decision.RetryAfter {
+				continue
+			}
+			decision = Decision{Scope: key.Scope, Limit: key.Limit, RetryAfter: retryAfter, Reset: resetAfter(b, key.Limit)}
+			continue
+		}
+		if decision.Allowed && int(b.tokens-cost) < decision.Remaining {
+			decision.Scope = key.Scope
+			decision.Limit = key.Limit
+			decision.Remaining = int(b.tokens - cost)
+			decision.Reset = resetAfter(&bucket{tokens: b.tokens - cost}, key.Limit)
+		}
+	}
+	if !decision.Allowed {
+		return decision
+	}
+	for _, d := range draws {
+		d.bucket.tokens -= d.cost
+	}
+	if decision.Remaining == math.MaxInt {
+		decision.Remaining = 0
+	}
+	return decision
+}
+
+func (l *Limiter) refill(key Key, now time.Time) *bucket {
+	id := key.Scope + ":" + key.ID
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{tokens: float64(key.Limit.Burst), last: now}
+		l.buckets[id] = b
+		return b
+	}
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(key.Limit.Burst), b.tokens+elapsed*key.Limit.Rate)
+		b.last = now
+	}
+	// A lowered limit takes effect at once.
+	b.tokens = math.Min(b.tokens, float64(key.Limit.Burst))
+	return b
+}
+
+func resetAfter(b *bucket, limit Limit) time.Duration {
+	missing := float64(limit.Burst) - b.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / limit.Rate * float64(time.Second))
+}
+
+// sweep drops buckets untouched for over an hour, the longest window
+// ParseLimit allows. They have refilled, and a new bucket starts full.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleSweep {
+		return
+	}
+	l.lastSweep = now
+	for id, b := range l.buckets {
+		if now.Sub(b.last) > time.Hour {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+// Len is the number of buckets held.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Class separates money-moving requests from the rest.
+type Class string
+
+const (
+	ClassStandard Class = "standard"
+	ClassMoney    Class = "money"
+)
+
+// Scopes a request is limited by.
+const (
+	ScopeClient  = "client"
+	ScopeIP      = "ip"
+	ScopeAccount = "account"
+)
+
+// Limits are the buckets one class of request draws from.
+type Limits struct {
+	Client  Limit
+	IP      Limit
+	Account Limit
+}
+
+// TierLimits replace the client limits for callers of a tier.
+type TierLimits struct {
+	Standard Limit
+	Money    Limit
+}
+
+// Rules holds the limits for each class, with client limits overridable
+// per tier.
+type Rules struct {
+	Standard Limits
+	Money    Limits
+	Tiers    map[string]TierLimits
+}
+
+// Keys returns the buckets a request draws from. Empty IDs are skipped, so
+// requests that name no account only draw from the client and IP buckets.
+func (r Rules) Keys(class Class, clientID, tier, ip, accountID string) []Key {
+	limits := r.Standard
+	if class == ClassMoney {
+		limits = r.Money
+	}
+	if tierLimits, ok := r.Tiers[tier]; ok {
+		limits.Client = tierLimits.Standard
+		if class == ClassMoney {
+			limits.Client = tierLimits.Money
+		}
+	}
+
+	prefix := string(class) + ":"
+	var keys []Key
+	if clientID != "" {
+		keys = append(keys, Key{Scope: ScopeClient, ID: prefix + clientID, Limit: limits.Client})
+	}
+	if ip != "" {
+		keys = append(keys, Key{Scope: ScopeIP, ID: prefix + ip, Limit: limits.IP})
+	}
+	if accountID != "" {
+		keys = append(keys, Key{Scope: ScopeAccount, ID: prefix + accountID, Limit: limits.Account})
+	}
+	return keys
+}
+
+// ParseTiers reads comma separated <tier>.<class>=<limit> pairs, e.g.
+// "partner.standard=200/s,partner.money=20/s". A class left out of a tier
+// is unlimited for it.
+func ParseTiers(value string) (map[string]TierLimits, error) {
+	tiers := make(map[string]TierLimits)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, limitValue, ok := strings.Cut(pair, "=")
+		tier, class, dotted := strings.Cut(strings.TrimSpace(name), ".")
+		if !ok || !dotted || tier == "" {
+			return nil, fmt.Errorf("invalid tier limit %q, want <tier>.<class>=<limit>", pair)
+		}
+		limit, err := ParseLimit(limitValue)
+		if err != nil {
+			return nil, fmt.Errorf("tier %s: %w", tier, err)
+		}
+
+		tierLimits := tiers[tier]
+		switch Class(class) {
+		case ClassStandard:
+			tierLimits.Standard = limit
+		case ClassMoney:
+			tierLimits.Money = limit
+		default:
+			return nil, fmt.Errorf("tier %s: unknown class %q, want standard or money", tier, class)
+		}
+		tiers[tier] = tierLimits
+	}
+	return tiers, nil
+}
+
+// TierNames lists the configured tiers in order.
+func (r Rules) TierNames() []string {
+	names := make([]string, 0, len(r.Tiers))
+	for name := range r.Tiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
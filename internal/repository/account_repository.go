@@ -1,37 +1,128 @@
 package repository
 
 import (
+	"context"
 	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
 	"sync"
 )
 
+// AccountRepository stores copies of accounts: what FindById returns is the
+// caller's to change, and only Save changes the stored account.
 type AccountRepository struct {
 	accounts map[string]*domain.Account
 	mu       sync.RWMutex
+	// locks are what every service takes to change an account. They
+	// outlive resets: a caller may still hold one.
+	locks   map[string]*accountLock
+	locksMu sync.Mutex
 }
 
 func NewAccountRepository() *AccountRepository {
 	return &AccountRepository{
 		accounts: make(map[string]*domain.Account),
+		locks:    make(map[string]*accountLock),
 	}
 }
 
-func (r *AccountRepository) FindById(id string) (*domain.Account, bool) {
+func (r *AccountRepository) FindById(ctx context.Context, id string) (*domain.Account, bool) {
+	_, span := tracing.Start(ctx, "AccountRepository.FindById", tracing.String("account.id", id))
+	defer span.End()
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	account, exists := r.accounts[id]
-	return account, exists
+	if !exists {
+		return nil, false
+	}
+	copied := *account
+	return &copied, true
 }
 
-func (r *AccountRepository) Save(account *domain.Account) *domain.Account {
+func (r *AccountRepository) Save(ctx context.Context, account *domain.Account) *domain.Account {
+	_, span := tracing.Start(ctx, "AccountRepository.Save", tracing.String("account.id", account.ID))
+	defer span.End()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.accounts[account.ID] = account
+	copied := *account
+	r.accounts[account.ID] = &copied
 	return account
 }
 
+// IDs returns the IDs of every account.
+func (r *AccountRepository) IDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.accounts))
+	for id := range r.accounts {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// accountLock is the lock of one account, with the callers holding or
+// waiting for it.
+type accountLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// Lock locks account id for changing, the same lock for every caller
+// whether or not the account exists, and returns the func unlocking it.
+// The lock is dropped once nobody holds or waits for it, so IDs that name
+// no account don't pile up.
+func (r *AccountRepository) Lock(id string) (unlock func()) {
+	r.locksMu.Lock()
+	lock, exists := r.locks[id]
+	if !exists {
+		lock = &accountLock{}
+		r.locks[id] = lock
+	}
+	lock.refs++
+	r.locksMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		r.locksMu.Lock()
+		defer r.locksMu.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(r.locks, id)
+		}
+	}
+}
+
+// Locked counts the accounts whose lock is held or waited for.
+func (r *AccountRepository) Locked() int {
+	r.locksMu.Lock()
+	defer r.locksMu.Unlock()
+
+	return len(r.locks)
+}
+
+func (r *AccountRepository) Delete(ctx context.Context, id string) {
+	_, span := tracing.Start(ctx, "AccountRepository.Delete", tracing.String("account.id", id))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.accounts, id)
+}
+
+func (r *AccountRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.accounts)
+}
+
 func (r *AccountRepository) Reset() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
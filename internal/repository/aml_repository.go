@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AMLHistoryRepository keeps each account's recent movements for AML
+// monitoring.
+type AMLHistoryRepository struct {
+	mu      sync.Mutex
+	history map[string]domain.AMLHistory
+}
+
+func NewAMLHistoryRepository() *AMLHistoryRepository {
+	return &AMLHistoryRepository{history: make(map[string]domain.AMLHistory)}
+}
+
+// Add records a movement, dropping movements older than keep, and returns
+// the account's history.
+func (r *AMLHistoryRepository) Add(ctx context.Context, accountID string, movement domain.AMLMovement, keep time.Duration) domain.AMLHistory {
+	_, span := tracing.Start(ctx, "AMLHistoryRepository.Add", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history := r.history[accountID]
+	history.Movements = slices.Clone(history.Movements)
+	history.Add(movement, keep)
+	r.history[accountID] = history
+	return history
+}
+
+func (r *AMLHistoryRepository) Find(ctx context.Context, accountID string) domain.AMLHistory {
+	_, span := tracing.Start(ctx, "AMLHistoryRepository.Find", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.history[accountID]
+}
+
+func (r *AMLHistoryRepository) Delete(ctx context.Context, accountID string) {
+	_, span := tracing.Start(ctx, "AMLHistoryRepository.Delete", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.history, accountID)
+}
+
+func (r *AMLHistoryRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = make(map[string]domain.AMLHistory)
+}
+
+// AMLAlertRepository keeps AML alerts.
+type AMLAlertRepository struct {
+	mu     sync.RWMutex
+	alerts map[string]*domain.AMLAlert
+}
+
+func NewAMLAlertRepository() *AMLAlertRepository {
+	return &AMLAlertRepository{alerts: make(map[string]*domain.AMLAlert)}
+}
+
+func (r *AMLAlertRepository) Save(ctx context.Context, alert *domain.AMLAlert) {
+	_, span := tracing.Start(ctx, "AMLAlertRepository.Save", tracing.String("aml_alert.id", alert.ID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *alert
+	r.alerts[alert.ID] = &copied
+}
+
+func (r *AMLAlertRepository) FindByID(ctx context.Context, id string) (*domain.AMLAlert, bool) {
+	_, span := tracing.Start(ctx, "AMLAlertRepository.FindByID", tracing.String("aml_alert.id", id))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	alert, exists := r.alerts[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *alert
+	return &copied, true
+}
+
+// Find returns the alerts matching status, account and rule, empty values
+// matching any, oldest first.
+func (r *AMLAlertRepository) Find(ctx context.Context, status, accountID, rule string) []*domain.AMLAlert {
+	_, span := tracing.Start(ctx, "AMLAlertRepository.Find", tracing.String("aml_alert.status", status))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.AMLAlert, 0)
+	for _, alert := range r.alerts {
+		if (status == "" || alert.Status == status) && (accountID == "" || alert.AccountID == accountID) && (rule == "" || alert.Rule == rule) {
+			copied := *alert
+			result = append(result, &copied)
+		}
+	}
+	slices.SortFunc(result, func(a, b *domain.AMLAlert) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return result
+}
+
+// DeleteByAccount drops an account's alerts and returns how many there were.
+func (r *AMLAlertRepository) DeleteByAccount(ctx context.Context, accountID string) int {
+	_, span := tracing.Start(ctx, "AMLAlertRepository.DeleteByAccount", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := 0
+	for id, alert := range r.alerts {
+		if alert.AccountID == accountID {
+			delete(r.alerts, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (r *AMLAlertRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = make(map[string]*domain.AMLAlert)
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// APIKeyRepository keeps API keys by ID. With a file, keys are read from
+// it on start and every change is written back to it, so issued, rotated
+// and revoked keys survive restarts. Changes are made under an exclusive
+// flock on the file's ".lock" companion, like the schedules file, and
+// lookups re-read the file once it changes, so instances sharing it see
+// each other's revocations and rotations.
+type APIKeyRepository struct {
+	keys map[string]*domain.APIKey
+	mu   sync.RWMutex
+	path string
+	// read is the file the keys were last read from or written to, nil
+	// when there was none.
+	read os.FileInfo
+}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		keys: make(map[string]*domain.APIKey),
+	}
+}
+
+// NewFileAPIKeyRepository keeps keys in path, a JSON array of
+// domain.APIKey, so operators provision keys by hash and never write
+// secrets to disk. A missing file starts with no keys and is created, with
+// its directory, on the first change.
+func NewFileAPIKeyRepository(path string) (*APIKeyRepository, error) {
+	info, err := statAPIKeys(path)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := readAPIKeys(path)
+	if err != nil {
+		return nil, err
+	}
+	return &APIKeyRepository{keys: keys, path: path, read: info}, nil
+}
+
+// statAPIKeys returns nil for a missing file. It is called before reading
+// the file, so a change made in between is read again on the next lookup.
+func statAPIKeys(path string) (os.FileInfo, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return info, err
+}
+
+// sameAPIKeys reports whether a and b are the same version of the file.
+// Changes replace it through a rename, and the size and modification time
+// catch files edited in place.
+func sameAPIKeys(a, b os.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return os.SameFile(a, b) && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
+
+func readAPIKeys(path string) (map[string]*domain.APIKey, error) {
+	keys := make(map[string]*domain.APIKey)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return keys, nil
+	case err != nil:
+		return nil, err
+	}
+	var list []*domain.APIKey
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, key := range list {
+		keys[key.ID] = key
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) FindByID(id string) (*domain.APIKey, bool) {
+	r.refresh()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *key
+	return &copied, true
+}
+
+// Save stores a copy of the key and, with a file, writes every key back to
+// it. A key that could not be written is not stored.
+func (r *APIKeyRepository) Save(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	err := r.update(ctx, func(keys map[string]*domain.APIKey) error {
+		copied := *key
+		keys[key.ID] = &copied
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Update calls fn with a copy of key id, nil when there is none, and
+// stores the key fn leaves unless it fails. No other change to the keys
+// happens in between, so concurrent changes to a key apply one after the
+// other.
+func (r *APIKeyRepository) Update(ctx context.Context, id string, fn func(key *domain.APIKey) error) (*domain.APIKey, error) {
+	var updated *domain.APIKey
+	err := r.update(ctx, func(keys map[string]*domain.APIKey) error {
+		key, exists := keys[id]
+		if !exists {
+			return fn(nil)
+		}
+		copied := *key
+		if err := fn(&copied); err != nil {
+			return err
+		}
+		stored := copied
+		keys[id] = &stored
+		updated = &copied
+		return nil
+	})
+	return updated, err
+}
+
+// update calls fn with the keys and stores what it leaves, unless it
+// fails or they can't be written. With a file, the file is locked and the
+// keys re-read from it first, so instances sharing it keep each other's
+// changes.
+func (r *APIKeyRepository) update(ctx context.Context, fn func(keys map[string]*domain.APIKey) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := maps.Clone(r.keys)
+	if r.path != "" {
+		if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+			return err
+		}
+		unlock, err := lockFile(ctx, r.path+".lock")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		if keys, err = readAPIKeys(r.path); err != nil {
+			return err
+		}
+	}
+	if err := fn(keys); err != nil {
+		return err
+	}
+	if err := writeAPIKeys(r.path, keys); err != nil {
+		return err
+	}
+	r.keys = keys
+	if r.path != "" {
+		r.read, _ = statAPIKeys(r.path)
+	}
+	return nil
+}
+
+// refresh re-reads the file when it is no longer the one the keys came
+// from. A file that can't be read leaves the keys as they were, to be
+// read again on the next lookup.
+func (r *APIKeyRepository) refresh() {
+	if r.path == "" {
+		return
+	}
+	info, err := statAPIKeys(r.path)
+	if err != nil {
+		return
+	}
+	r.mu.RLock()
+	unchanged := sameAPIKeys(r.read, info)
+	r.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sameAPIKeys(r.read, info) {
+		return
+	}
+	keys, err := readAPIKeys(r.path)
+	if err != nil {
+		return
+	}
+	r.keys, r.read = keys, info
+}
+
+// writeAPIKeys replaces the file through a temporary one, so a crash
+// leaves either the old keys or the new ones. Keys are sorted by ID.
+func writeAPIKeys(path string, keys map[string]*domain.APIKey) error {
+	if path == "" {
+		return nil
+	}
+	list := slices.SortedFunc(maps.Values(keys), func(a, b *domain.APIKey) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (r *APIKeyRepository) FindAll() []*domain.APIKey {
+	r.refresh()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		copied := *key
+		result = append(result, &copied)
+	}
+	return result
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"bufio"
+	"corebanking/internal/domain"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditRepository is append-only: entries can be added and read, never
+// changed or removed. With a file, every entry is also appended to it as a
+// JSON line and the file is replayed on start.
+type AuditRepository struct {
+	mu      sync.RWMutex
+	entries []*domain.AuditEntry
+	file    *os.File
+	// size is how much of file holds whole entries.
+	size int64
+	// torn is the size of the incomplete last line dropped on replay.
+	torn int64
+	// err is the latest failed write to file, cleared by the next success.
+	err    error
+	closed bool
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{
+		entries: make([]*domain.AuditEntry, 0),
+	}
+}
+
+// NewFileAuditRepository replays path, creating its directory when missing,
+// and appends new entries to it. An incomplete last line, left by a crash
+// or a full disk in the middle of a write, is cut off the file; Torn
+// reports its size.
+func NewFileAuditRepository(path string) (*AuditRepository, error) {
+	r := NewAuditRepository()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	existing, err := os.Open(path)
+	if err == nil {
+		err = r.replay(existing)
+		existing.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	r.file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if r.torn > 0 {
+		if err := r.file.Truncate(r.size); err != nil {
+			r.file.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// replay reads the entries of f. Every line but an incomplete last one
+// must hold an entry.
+func (r *AuditRepository) replay(f *os.File) error {
+	reader := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			r.torn = int64(len(line))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var entry domain.AuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		r.entries = append(r.entries, &entry)
+		r.size += int64(len(line))
+	}
+}
+
+// Torn returns the size of the incomplete last line dropped on replay, 0
+// when the file ended with a whole entry.
+func (r *AuditRepository) Torn() int64 {
+	return r.torn
+}
+
+// Append assigns the entry's sequence and previous hash, lets seal compute
+// its hash, and stores it. The lock makes the chain linear under
+// concurrent writers.
+func (r *AuditRepository) Append(entry *domain.AuditEntry, seal func(*domain.AuditEntry) string) (*domain.AuditEntry, error) {
+	if err := r.AppendAll([]*domain.AuditEntry{entry}, seal); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// AppendAll chains and stores entries like Append, all or none: they are
+// written to the file in one write and kept only when it succeeds. A write
+// that fails part way is cut off the file, so the next one starts on a
+// line of its own.
+func (r *AuditRepository) AppendAll(entries []*domain.AuditEntry, seal func(*domain.AuditEntry) string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prevHash := ""
+	if len(r.entries) > 0 {
+		prevHash = r.entries[len(r.entries)-1].Hash
+	}
+	var lines []byte
+	for i, entry := range entries {
+		entry.Sequence = int64(len(r.entries)+i) + 1
+		entry.PrevHash = prevHash
+		entry.Hash = seal(entry)
+		prevHash = entry.Hash
+
+		if r.file != nil {
+			line, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			lines = append(append(lines, line...), '\n')
+		}
+	}
+
+	if r.file != nil {
+		if n, err := r.file.Write(lines); err != nil {
+			if n > 0 {
+				if truncateErr := r.file.Truncate(r.size); truncateErr != nil {
+					err = errors.Join(err, truncateErr)
+				}
+			}
+			r.err = err
+			return err
+		}
+		r.size += int64(len(lines))
+		r.err = nil
+	}
+
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *AuditRepository) FindAll() []*domain.AuditEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.AuditEntry, len(r.entries))
+	copy(result, r.entries)
+	return result
+}
+
+func (r *AuditRepository) FindFiltered(actor, entityType, entityID string, begin, end time.Time) []*domain.AuditEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.AuditEntry, 0)
+	for _, e := range r.entries {
+		if actor != "" && e.Actor != actor {
+			continue
+		}
+		if entityType != "" && e.EntityType != entityType {
+			continue
+		}
+		if entityID != "" && e.EntityID != entityID {
+			continue
+		}
+		if !begin.IsZero() && e.Timestamp.Before(begin) {
+			continue
+		}
+		if !end.IsZero() && e.Timestamp.After(end) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// Err reports whether entries can be stored: the repository is open and
+// its latest write to the file succeeded.
+func (r *AuditRepository) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return os.ErrClosed
+	}
+	return r.err
+}
+
+func (r *AuditRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	if r.file == nil {
+		return nil
+	}
+	syncErr := r.file.Sync()
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	return syncErr
+}
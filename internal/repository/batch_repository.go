@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
+	"slices"
+	"sync"
+	"time"
+)
+
+// BatchRepository keeps batches and their results while they are
+// processed and for a while after.
+type BatchRepository struct {
+	mu      sync.RWMutex
+	batches map[string]*domain.Batch
+}
+
+func NewBatchRepository() *BatchRepository {
+	return &BatchRepository{batches: make(map[string]*domain.Batch)}
+}
+
+// Save stores a copy of the batch. Items are never changed once a batch
+// is created, so the copy shares them; results are copied.
+func (r *BatchRepository) Save(ctx context.Context, batch *domain.Batch) {
+	_, span := tracing.Start(ctx, "BatchRepository.Save", tracing.String("batch.id", batch.ID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *batch
+	copied.Results = slices.Clone(batch.Results)
+	r.batches[batch.ID] = &copied
+}
+
+// SaveResult stores the result of item i of a stored batch, so a batch
+// being processed can be read with the items posted so far.
+func (r *BatchRepository) SaveResult(ctx context.Context, id string, i int, result domain.BatchItemResult) {
+	_, span := tracing.Start(ctx, "BatchRepository.SaveResult", tracing.String("batch.id", id))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if batch, exists := r.batches[id]; exists {
+		batch.Results[i] = result
+	}
+}
+
+func (r *BatchRepository) FindByID(ctx context.Context, id string) (*domain.Batch, bool) {
+	_, span := tracing.Start(ctx, "BatchRepository.FindByID", tracing.String("batch.id", id))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	batch, exists := r.batches[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *batch
+	copied.Results = slices.Clone(batch.Results)
+	return &copied, true
+}
+
+// Delete drops a batch.
+func (r *BatchRepository) Delete(ctx context.Context, id string) {
+	_, span := tracing.Start(ctx, "BatchRepository.Delete", tracing.String("batch.id", id))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.batches, id)
+}
+
+// DeleteCompletedBefore drops the batches processed before cutoff and
+// returns how many there were.
+func (r *BatchRepository) DeleteCompletedBefore(ctx context.Context, cutoff time.Time) int {
+	_, span := tracing.Start(ctx, "BatchRepository.DeleteCompletedBefore")
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := 0
+	for id, batch := range r.batches {
+		if batch.CompletedAt != nil && batch.CompletedAt.Before(cutoff) {
+			delete(r.batches, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (r *BatchRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = make(map[string]*domain.Batch)
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// lockFile would lock the file at path; the schedules and API keys files
+// are changed under flock, so only in-memory schedules and keys work here.
+func lockFile(ctx context.Context, path string) (unlock func(), err error) {
+	return nil, errors.New("schedules and API keys files need flock, which this platform lacks")
+}
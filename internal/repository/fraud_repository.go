@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FraudHistoryRepository keeps each account's recent postings for the
+// fraud rules.
+type FraudHistoryRepository struct {
+	mu      sync.Mutex
+	history map[string]domain.FraudHistory
+}
+
+func NewFraudHistoryRepository() *FraudHistoryRepository {
+	return &FraudHistoryRepository{history: make(map[string]domain.FraudHistory)}
+}
+
+// Add records a posting, dropping postings older than keep.
+func (r *FraudHistoryRepository) Add(ctx context.Context, accountID string, activity domain.FraudActivity, keep time.Duration) {
+	_, span := tracing.Start(ctx, "FraudHistoryRepository.Add", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history := r.history[accountID]
+	history.Activity = slices.Clone(history.Activity)
+	history.Add(activity, keep)
+	r.history[accountID] = history
+}
+
+func (r *FraudHistoryRepository) Find(ctx context.Context, accountID string) domain.FraudHistory {
+	_, span := tracing.Start(ctx, "FraudHistoryRepository.Find", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.history[accountID]
+}
+
+func (r *FraudHistoryRepository) Delete(ctx context.Context, accountID string) {
+	_, span := tracing.Start(ctx, "FraudHistoryRepository.Delete", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.history, accountID)
+}
+
+func (r *FraudHistoryRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = make(map[string]domain.FraudHistory)
+}
+
+// FraudCaseRepository keeps the postings held for review.
+type FraudCaseRepository struct {
+	mu    sync.Mutex
+	cases map[string]*domain.FraudCase
+}
+
+func NewFraudCaseRepository() *FraudCaseRepository {
+	return &FraudCaseRepository{cases: make(map[string]*domain.FraudCase)}
+}
+
+func (r *FraudCaseRepository) Save(ctx context.Context, fraudCase *domain.FraudCase) {
+	_, span := tracing.Start(ctx, "FraudCaseRepository.Save", tracing.String("fraud_case.id", fraudCase.ID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *fraudCase
+	r.cases[fraudCase.ID] = &copied
+}
+
+func (r *FraudCaseRepository) FindByID(ctx context.Context, id string) (*domain.FraudCase, bool) {
+	_, span := tracing.Start(ctx, "FraudCaseRepository.FindByID", tracing.String("fraud_case.id", id))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fraudCase, exists := r.cases[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *fraudCase
+	return &copied, true
+}
+
+// FindByStatus returns the cases with status, all of them when it is
+// empty, oldest first.
+func (r *FraudCaseRepository) FindByStatus(ctx context.Context, status string) []*domain.FraudCase {
+	_, span := tracing.Start(ctx, "FraudCaseRepository.FindByStatus", tracing.String("fraud_case.status", status))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]*domain.FraudCase, 0)
+	for _, fraudCase := range r.cases {
+		if status == "" || fraudCase.Status == status {
+			copied := *fraudCase
+			result = append(result, &copied)
+		}
+	}
+	slices.SortFunc(result, func(a, b *domain.FraudCase) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return result
+}
+
+// Update calls fn with a copy of the case under the repository lock and
+// stores it unless fn fails, so deciding a case happens at most once. It
+// reports false for an unknown case.
+func (r *FraudCaseRepository) Update(ctx context.Context, id string, fn func(*domain.FraudCase) error) (*domain.FraudCase, bool, error) {
+	_, span := tracing.Start(ctx, "FraudCaseRepository.Update", tracing.String("fraud_case.id", id))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, exists := r.cases[id]
+	if !exists {
+		return nil, false, nil
+	}
+	updated := *stored
+	if err := fn(&updated); err != nil {
+		return nil, true, err
+	}
+	r.cases[id] = &updated
+	copied := updated
+	return &copied, true, nil
+}
+
+// DeleteByAccount drops an account's cases and returns how many there were.
+func (r *FraudCaseRepository) DeleteByAccount(ctx context.Context, accountID string) int {
+	_, span := tracing.Start(ctx, "FraudCaseRepository.DeleteByAccount", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := 0
+	for id, fraudCase := range r.cases {
+		if fraudCase.AccountID == accountID {
+			delete(r.cases, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (r *FraudCaseRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cases = make(map[string]*domain.FraudCase)
+}
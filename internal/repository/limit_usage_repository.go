@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
+	"sync"
+)
+
+// LimitUsageRepository keeps each account's debits toward its limits.
+type LimitUsageRepository struct {
+	mu    sync.Mutex
+	usage map[string]domain.LimitUsage
+}
+
+func NewLimitUsageRepository() *LimitUsageRepository {
+	return &LimitUsageRepository{usage: make(map[string]domain.LimitUsage)}
+}
+
+// Update calls fn with the account's usage under the repository lock and
+// stores the result unless fn fails, so a check and the debit it allows
+// are one step.
+func (r *LimitUsageRepository) Update(ctx context.Context, accountID string, fn func(*domain.LimitUsage) error) error {
+	_, span := tracing.Start(ctx, "LimitUsageRepository.Update", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	usage := r.usage[accountID]
+	if err := fn(&usage); err != nil {
+		return err
+	}
+	r.usage[accountID] = usage
+	return nil
+}
+
+func (r *LimitUsageRepository) Find(ctx context.Context, accountID string) domain.LimitUsage {
+	_, span := tracing.Start(ctx, "LimitUsageRepository.Find", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.usage[accountID]
+}
+
+func (r *LimitUsageRepository) Delete(ctx context.Context, accountID string) {
+	_, span := tracing.Start(ctx, "LimitUsageRepository.Delete", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.usage, accountID)
+}
+
+func (r *LimitUsageRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usage = make(map[string]domain.LimitUsage)
+}
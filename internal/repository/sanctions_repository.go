@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SanctionsScreeningRepository keeps every sanctions screening with the
+// list version it ran against, in the order they ran. Screenings are never
+// changed once saved.
+type SanctionsScreeningRepository struct {
+	mu         sync.RWMutex
+	screenings []domain.SanctionsScreening
+}
+
+func NewSanctionsScreeningRepository() *SanctionsScreeningRepository {
+	return &SanctionsScreeningRepository{}
+}
+
+func (r *SanctionsScreeningRepository) Save(ctx context.Context, screening *domain.SanctionsScreening) {
+	_, span := tracing.Start(ctx, "SanctionsScreeningRepository.Save", tracing.String("sanctions_screening.id", screening.ID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.screenings = append(r.screenings, *screening)
+}
+
+// Find returns the screenings of an account, as destination, origin or
+// opened account, and of a document number; empty values match any.
+func (r *SanctionsScreeningRepository) Find(ctx context.Context, accountID, documentNumber string) []*domain.SanctionsScreening {
+	_, span := tracing.Start(ctx, "SanctionsScreeningRepository.Find", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.SanctionsScreening, 0)
+	for _, screening := range r.screenings {
+		if (accountID == "" || screening.AccountID == accountID || screening.Origin == accountID) &&
+			(documentNumber == "" || screening.DocumentNumber == documentNumber) {
+			copied := screening
+			result = append(result, &copied)
+		}
+	}
+	return result
+}
+
+// DeleteByAccount drops the screenings involving an account and returns
+// how many there were.
+func (r *SanctionsScreeningRepository) DeleteByAccount(ctx context.Context, accountID string) int {
+	_, span := tracing.Start(ctx, "SanctionsScreeningRepository.DeleteByAccount", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	before := len(r.screenings)
+	r.screenings = slices.DeleteFunc(r.screenings, func(screening domain.SanctionsScreening) bool {
+		return screening.AccountID == accountID || screening.Origin == accountID
+	})
+	return before - len(r.screenings)
+}
+
+func (r *SanctionsScreeningRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.screenings = nil
+}
+
+// SanctionsCaseRepository keeps the onboardings and transfers held for
+// sanctions review.
+type SanctionsCaseRepository struct {
+	mu    sync.RWMutex
+	cases map[string]*domain.SanctionsCase
+}
+
+func NewSanctionsCaseRepository() *SanctionsCaseRepository {
+	return &SanctionsCaseRepository{cases: make(map[string]*domain.SanctionsCase)}
+}
+
+func (r *SanctionsCaseRepository) Save(ctx context.Context, sanctionsCase *domain.SanctionsCase) {
+	_, span := tracing.Start(ctx, "SanctionsCaseRepository.Save", tracing.String("sanctions_case.id", sanctionsCase.ID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *sanctionsCase
+	r.cases[sanctionsCase.ID] = &copied
+}
+
+func (r *SanctionsCaseRepository) FindByID(ctx context.Context, id string) (*domain.SanctionsCase, bool) {
+	_, span := tracing.Start(ctx, "SanctionsCaseRepository.FindByID", tracing.String("sanctions_case.id", id))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sanctionsCase, exists := r.cases[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *sanctionsCase
+	return &copied, true
+}
+
+// FindByStatus returns the cases with status, all of them when it is
+// empty, oldest first.
+func (r *SanctionsCaseRepository) FindByStatus(ctx context.Context, status string) []*domain.SanctionsCase {
+	_, span := tracing.Start(ctx, "SanctionsCaseRepository.FindByStatus", tracing.String("sanctions_case.status", status))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.SanctionsCase, 0)
+	for _, sanctionsCase := range r.cases {
+		if status == "" || sanctionsCase.Status == status {
+			copied := *sanctionsCase
+			result = append(result, &copied)
+		}
+	}
+	slices.SortFunc(result, func(a, b *domain.SanctionsCase) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return result
+}
+
+// DeleteByAccount drops the cases involving an account and returns how
+// many there were.
+func (r *SanctionsCaseRepository) DeleteByAccount(ctx context.Context, accountID string) int {
+	_, span := tracing.Start(ctx, "SanctionsCaseRepository.DeleteByAccount", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := 0
+	for id, sanctionsCase := range r.cases {
+		if sanctionsCase.AccountID == accountID || sanctionsCase.Origin == accountID {
+			delete(r.cases, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (r *SanctionsCaseRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cases = make(map[string]*domain.SanctionsCase)
+}
+
+// SanctionsClearanceRepository remembers the entries a document number
+// was reviewed against and found not to be, with the case that cleared
+// it.
+type SanctionsClearanceRepository struct {
+	mu         sync.RWMutex
+	clearances map[string]map[string]string
+}
+
+func NewSanctionsClearanceRepository() *SanctionsClearanceRepository {
+	return &SanctionsClearanceRepository{clearances: make(map[string]map[string]string)}
+}
+
+func (r *SanctionsClearanceRepository) Add(ctx context.Context, documentNumber, entryID, caseID string) {
+	_, span := tracing.Start(ctx, "SanctionsClearanceRepository.Add", tracing.String("sanctions_entry.id", entryID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.clearances[documentNumber] == nil {
+		r.clearances[documentNumber] = make(map[string]string)
+	}
+	r.clearances[documentNumber][entryID] = caseID
+}
+
+// Cleared reports whether the document number was cleared of the entry.
+func (r *SanctionsClearanceRepository) Cleared(ctx context.Context, documentNumber, entryID string) bool {
+	_, span := tracing.Start(ctx, "SanctionsClearanceRepository.Cleared", tracing.String("sanctions_entry.id", entryID))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, cleared := r.clearances[documentNumber][entryID]
+	return cleared
+}
+
+func (r *SanctionsClearanceRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clearances = make(map[string]map[string]string)
+}
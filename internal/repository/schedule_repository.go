@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scheduleState is what the schedules file holds.
+type scheduleState struct {
+	Schedules  map[string]*domain.Schedule `json:"schedules"`
+	Executions []*domain.ScheduleExecution `json:"executions"`
+}
+
+func newScheduleState() *scheduleState {
+	return &scheduleState{Schedules: make(map[string]*domain.Schedule), Executions: make([]*domain.ScheduleExecution, 0)}
+}
+
+// ScheduleRepository keeps scheduled transfers and their executions. With a
+// file, every operation reads the file and every change rewrites it, under
+// a lock on a file next to it, so schedules survive restarts and instances
+// sharing the file see one another's changes and never interleave them.
+type ScheduleRepository struct {
+	mu    sync.Mutex
+	state *scheduleState
+	path  string
+}
+
+func NewScheduleRepository() *ScheduleRepository {
+	return &ScheduleRepository{state: newScheduleState()}
+}
+
+// NewFileScheduleRepository keeps schedules in path, creating its directory
+// when missing, and checks that an existing file can be read.
+func NewFileScheduleRepository(path string) (*ScheduleRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	r := &ScheduleRepository{path: path}
+	if err := r.view(context.Background(), func(*scheduleState) {}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// view calls fn with the current state, which it must not change.
+func (r *ScheduleRepository) view(ctx context.Context, fn func(state *scheduleState)) error {
+	return r.update(ctx, func(state *scheduleState) (bool, error) {
+		fn(state)
+		return false, nil
+	})
+}
+
+// update calls fn with the current state and, when it reports a change,
+// stores the state it left. It gives up when ctx is done while waiting for
+// the lock.
+func (r *ScheduleRepository) update(ctx context.Context, fn func(state *scheduleState) (changed bool, err error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.path == "" {
+		_, err := fn(r.state)
+		return err
+	}
+
+	unlock, err := lockFile(ctx, r.path+".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	state, err := readScheduleState(r.path)
+	if err != nil {
+		return err
+	}
+	changed, err := fn(state)
+	if err != nil || !changed {
+		return err
+	}
+	return writeScheduleState(r.path, state)
+}
+
+func readScheduleState(path string) (*scheduleState, error) {
+	state := newScheduleState()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if state.Schedules == nil {
+		state.Schedules = make(map[string]*domain.Schedule)
+	}
+	return state, nil
+}
+
+// writeScheduleState replaces path through a temporary file, so a crash
+// leaves either the old state or the new one.
+func writeScheduleState(path string, state *scheduleState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (r *ScheduleRepository) Save(ctx context.Context, schedule *domain.Schedule) error {
+	ctx, span := tracing.Start(ctx, "ScheduleRepository.Save", tracing.String("schedule.id", schedule.ID))
+	defer span.End()
+
+	copied := *schedule
+	return r.update(ctx, func(state *scheduleState) (bool, error) {
+		state.Schedules[schedule.ID] = &copied
+		return true, nil
+	})
+}
+
+func (r *ScheduleRepository) FindByID(ctx context.Context, id string) (*domain.Schedule, bool, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleRepository.FindByID", tracing.String("schedule.id", id))
+	defer span.End()
+
+	var found *domain.Schedule
+	err := r.view(ctx, func(state *scheduleState) {
+		if schedule, exists := state.Schedules[id]; exists {
+			copied := *schedule
+			found = &copied
+		}
+	})
+	return found, found != nil, err
+}
+
+// Find returns the schedules from origin, every schedule when it is empty,
+// oldest first.
+func (r *ScheduleRepository) Find(ctx context.Context, origin string) ([]*domain.Schedule, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleRepository.Find", tracing.String("account.id", origin))
+	defer span.End()
+
+	result := make([]*domain.Schedule, 0)
+	err := r.view(ctx, func(state *scheduleState) {
+		for _, schedule := range state.Schedules {
+			if origin == "" || schedule.Origin == origin {
+				copied := *schedule
+				result = append(result, &copied)
+			}
+		}
+	})
+	slices.SortFunc(result, func(a, b *domain.Schedule) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return result, err
+}
+
+// Update applies fn to a schedule and stores the result along with
+// executions, all at once; nothing is stored when fn fails, and nothing is
+// returned for an unknown schedule. Instances sharing the file run their
+// updates one at a time, so fn sees the latest schedule and can refuse a
+// change another instance made first.
+func (r *ScheduleRepository) Update(ctx context.Context, id string, fn func(schedule *domain.Schedule) error, executions ...*domain.ScheduleExecution) (*domain.Schedule, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleRepository.Update", tracing.String("schedule.id", id))
+	defer span.End()
+
+	var updated *domain.Schedule
+	err := r.update(ctx, func(state *scheduleState) (bool, error) {
+		stored, exists := state.Schedules[id]
+		if !exists {
+			return false, nil
+		}
+		schedule := *stored
+		if err := fn(&schedule); err != nil {
+			return false, err
+		}
+		state.Schedules[id] = &schedule
+		for _, execution := range executions {
+			copied := *execution
+			state.Executions = append(state.Executions, &copied)
+		}
+		copied := schedule
+		updated = &copied
+		return true, nil
+	})
+	return updated, err
+}
+
+// Executions returns a schedule's executions, oldest first.
+func (r *ScheduleRepository) Executions(ctx context.Context, scheduleID string) ([]*domain.ScheduleExecution, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleRepository.Executions", tracing.String("schedule.id", scheduleID))
+	defer span.End()
+
+	result := make([]*domain.ScheduleExecution, 0)
+	err := r.view(ctx, func(state *scheduleState) {
+		for _, execution := range state.Executions {
+			if execution.ScheduleID == scheduleID {
+				copied := *execution
+				result = append(result, &copied)
+			}
+		}
+	})
+	return result, err
+}
+
+// DeleteByAccount drops the schedules from or to an account, and their
+// executions, and returns how many schedules there were.
+func (r *ScheduleRepository) DeleteByAccount(ctx context.Context, accountID string) (int, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleRepository.DeleteByAccount", tracing.String("account.id", accountID))
+	defer span.End()
+
+	deleted := 0
+	err := r.update(ctx, func(state *scheduleState) (bool, error) {
+		for id, schedule := range state.Schedules {
+			if schedule.Origin == accountID || schedule.Destination == accountID {
+				delete(state.Schedules, id)
+				deleted++
+			}
+		}
+		state.Executions = slices.DeleteFunc(state.Executions, func(execution *domain.ScheduleExecution) bool {
+			_, exists := state.Schedules[execution.ScheduleID]
+			return !exists
+		})
+		return deleted > 0, nil
+	})
+	return deleted, err
+}
+
+// DeleteExecutionsBefore drops the executions recorded before cutoff and
+// returns how many there were. The file is only rewritten when there were
+// some.
+func (r *ScheduleRepository) DeleteExecutionsBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleRepository.DeleteExecutionsBefore")
+	defer span.End()
+
+	deleted := 0
+	err := r.update(ctx, func(state *scheduleState) (bool, error) {
+		before := len(state.Executions)
+		state.Executions = slices.DeleteFunc(state.Executions, func(execution *domain.ScheduleExecution) bool {
+			return execution.ExecutedAt.Before(cutoff)
+		})
+		deleted = before - len(state.Executions)
+		return deleted > 0, nil
+	})
+	return deleted, err
+}
+
+func (r *ScheduleRepository) Reset() error {
+	return r.update(context.Background(), func(state *scheduleState) (bool, error) {
+		*state = *newScheduleState()
+		return true, nil
+	})
+}
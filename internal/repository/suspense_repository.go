@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SuspenseRepository keeps the deposits and transfers posted to the
+// suspense account.
+type SuspenseRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.SuspenseItem
+}
+
+func NewSuspenseRepository() *SuspenseRepository {
+	return &SuspenseRepository{items: make(map[string]*domain.SuspenseItem)}
+}
+
+func (r *SuspenseRepository) Save(ctx context.Context, item *domain.SuspenseItem) {
+	_, span := tracing.Start(ctx, "SuspenseRepository.Save", tracing.String("suspense_item.id", item.ID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *item
+	r.items[item.ID] = &copied
+}
+
+func (r *SuspenseRepository) FindByID(ctx context.Context, id string) (*domain.SuspenseItem, bool) {
+	_, span := tracing.Start(ctx, "SuspenseRepository.FindByID", tracing.String("suspense_item.id", id))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	item, exists := r.items[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *item
+	return &copied, true
+}
+
+// Find returns the items with status and involving accountID, as origin,
+// destination or the account credited, empty values matching any, oldest
+// first.
+func (r *SuspenseRepository) Find(ctx context.Context, status, accountID string) []*domain.SuspenseItem {
+	_, span := tracing.Start(ctx, "SuspenseRepository.Find", tracing.String("suspense_item.status", status))
+	defer span.End()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*domain.SuspenseItem, 0)
+	for _, item := range r.items {
+		if (status == "" || item.Status == status) && (accountID == "" || suspenseInvolves(item, accountID)) {
+			copied := *item
+			result = append(result, &copied)
+		}
+	}
+	slices.SortFunc(result, func(a, b *domain.SuspenseItem) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return result
+}
+
+// DeleteByAccount drops the items involving an account and returns how
+// many there were.
+func (r *SuspenseRepository) DeleteByAccount(ctx context.Context, accountID string) int {
+	_, span := tracing.Start(ctx, "SuspenseRepository.DeleteByAccount", tracing.String("account.id", accountID))
+	defer span.End()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := 0
+	for id, item := range r.items {
+		if suspenseInvolves(item, accountID) {
+			delete(r.items, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func (r *SuspenseRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = make(map[string]*domain.SuspenseItem)
+}
+
+func suspenseInvolves(item *domain.SuspenseItem, accountID string) bool {
+	return item.Origin == accountID || item.Destination == accountID || item.AccountID == accountID
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
 	"corebanking/internal/domain"
+	"corebanking/internal/tracing"
+	"slices"
 	"sync"
 	"time"
 )
@@ -17,14 +20,18 @@ func NewTransactionRepository() *TransactionRepository {
 	}
 }
 
-func (r *TransactionRepository) Save(transaction *domain.Transaction) *domain.Transaction {
+func (r *TransactionRepository) Save(ctx context.Context, transaction *domain.Transaction) *domain.Transaction {
+	_, span := tracing.Start(ctx, "TransactionRepository.Save", tracing.Int64("transaction.id", transaction.TransactionID))
+	defer span.End()
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.transactions = append(r.transactions, transaction)
 	return transaction
 }
 
-func (r *TransactionRepository) FindByID(transactionID int64) *domain.Transaction {
+func (r *TransactionRepository) FindByID(ctx context.Context, transactionID int64) *domain.Transaction {
+	_, span := tracing.Start(ctx, "TransactionRepository.FindByID", tracing.Int64("transaction.id", transactionID))
+	defer span.End()
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	for _, t := range r.transactions {
@@ -35,7 +42,9 @@ func (r *TransactionRepository) FindByID(transactionID int64) *domain.Transactio
 	return nil
 }
 
-func (r *TransactionRepository) FindAllOperationTypeByID(operationTypeID int) []*domain.Transaction {
+func (r *TransactionRepository) FindAllOperationTypeByID(ctx context.Context, operationTypeID int) []*domain.Transaction {
+	_, span := tracing.Start(ctx, "TransactionRepository.FindAllOperationTypeByID", tracing.Int("transaction.operation_type", operationTypeID))
+	defer span.End()
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	result := make([]*domain.Transaction, 0)
@@ -47,7 +56,9 @@ func (r *TransactionRepository) FindAllOperationTypeByID(operationTypeID int) []
 	return result
 }
 
-func (r *TransactionRepository) FindAllTransactionsBetweenDate(begin, end time.Time) []*domain.Transaction {
+func (r *TransactionRepository) FindAllTransactionsBetweenDate(ctx context.Context, begin, end time.Time) []*domain.Transaction {
+	_, span := tracing.Start(ctx, "TransactionRepository.FindAllTransactionsBetweenDate")
+	defer span.End()
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	result := make([]*domain.Transaction, 0)
@@ -60,7 +71,9 @@ func (r *TransactionRepository) FindAllTransactionsBetweenDate(begin, end time.T
 	return result
 }
 
-func (r *TransactionRepository) FindAllTransactionOnDate(date time.Time) []*domain.Transaction {
+func (r *TransactionRepository) FindAllTransactionOnDate(ctx context.Context, date time.Time) []*domain.Transaction {
+	_, span := tracing.Start(ctx, "TransactionRepository.FindAllTransactionOnDate")
+	defer span.End()
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	result := make([]*domain.Transaction, 0)
@@ -74,14 +87,58 @@ func (r *TransactionRepository) FindAllTransactionOnDate(date time.Time) []*doma
 	return result
 }
 
-func (r *TransactionRepository) FindAll() []*domain.Transaction {
+func (r *TransactionRepository) FindAll(ctx context.Context) []*domain.Transaction {
+	_, span := tracing.Start(ctx, "TransactionRepository.FindAll")
+	defer span.End()
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	return r.transactions
 }
 
-func (r *TransactionRepository) Reset() {
+func (r *TransactionRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.transactions)
+}
+
+// Delete removes a transaction and reports whether it was there.
+func (r *TransactionRepository) Delete(ctx context.Context, transactionID int64) bool {
+	_, span := tracing.Start(ctx, "TransactionRepository.Delete", tracing.Int64("transaction.id", transactionID))
+	defer span.End()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range r.transactions {
+		if t.TransactionID == transactionID {
+			// A new slice, since FindAll hands out the current one.
+			r.transactions = slices.Concat(r.transactions[:i], r.transactions[i+1:])
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteByAccount removes the account's transactions and returns how many
+// were removed.
+func (r *TransactionRepository) DeleteByAccount(ctx context.Context, accountID string) int {
+	_, span := tracing.Start(ctx, "TransactionRepository.DeleteByAccount", tracing.String("account.id", accountID))
+	defer span.End()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := make([]*domain.Transaction, 0, len(r.transactions))
+	for _, t := range r.transactions {
+		if t.AccountID != accountID {
+			kept = append(kept, t)
+		}
+	}
+	removed := len(r.transactions) - len(kept)
+	r.transactions = kept
+	return removed
+}
+
+func (r *TransactionRepository) Reset() int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	count := len(r.transactions)
 	r.transactions = make([]*domain.Transaction, 0)
+	return count
 }
@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"context"
+	corebankingv1 "corebanking/api/gen/corebankingv1"
+	"corebanking/internal/auth"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+)
+
+// accountServer is the AccountService, authorized as the v2 account routes
+// are.
+type accountServer struct {
+	corebankingv1.UnimplementedAccountServiceServer
+	service    *service.AccountService
+	policy     *auth.Policy
+	errHandler utils.ErrorHandler
+}
+
+func (s *accountServer) CreateAccount(ctx context.Context, req *corebankingv1.CreateAccountRequest) (*corebankingv1.Account, error) {
+	if err := authorize(ctx, s.policy, s.errHandler, auth.ActionCreateAccount, auth.Resource{DocumentNumber: req.GetDocumentNumber()}); err != nil {
+		return nil, err
+	}
+
+	account, err := s.service.CreateAccount(ctx, req.GetDocumentNumber(), req.GetHolderName())
+	if err != nil {
+		return nil, fail(ctx, s.errHandler, err, "Failed to create account.")
+	}
+	return accountMessage(account), nil
+}
+
+func (s *accountServer) GetAccount(ctx context.Context, req *corebankingv1.GetAccountRequest) (*corebankingv1.Account, error) {
+	if err := authorize(ctx, s.policy, s.errHandler, auth.ActionReadAccount, auth.Resource{AccountID: req.GetAccountId()}); err != nil {
+		return nil, err
+	}
+
+	account, err := s.service.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, fail(ctx, s.errHandler, err, "Failed to get account.")
+	}
+	return accountMessage(account), nil
+}
+
+func (s *accountServer) GetBalance(ctx context.Context, req *corebankingv1.GetBalanceRequest) (*corebankingv1.Balance, error) {
+	if err := authorize(ctx, s.policy, s.errHandler, auth.ActionReadAccount, auth.Resource{AccountID: req.GetAccountId()}); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.service.GetBalance(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, fail(ctx, s.errHandler, err, "Failed to get balance.")
+	}
+	return &corebankingv1.Balance{Balance: balance.Balance}, nil
+}
+
+func (s *accountServer) ConfigOverdraft(ctx context.Context, req *corebankingv1.ConfigOverdraftRequest) (*corebankingv1.ConfigOverdraftResponse, error) {
+	if err := authorize(ctx, s.policy, s.errHandler, auth.ActionSetOverdraft, auth.Resource{AccountID: req.GetAccountId()}); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.ConfigOverdraft(ctx, req.GetAccountId(), req.GetLimit()); err != nil {
+		return nil, fail(ctx, s.errHandler, err, "Failed in set new overdraft limit.")
+	}
+	return &corebankingv1.ConfigOverdraftResponse{}, nil
+}
+
+func accountMessage(account *dto.AccountResponse) *corebankingv1.Account {
+	return &corebankingv1.Account{
+		AccountId:      account.AccountID,
+		DocumentNumber: account.DocumentNumber,
+		HolderName:     account.HolderName,
+	}
+}
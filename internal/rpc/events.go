@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"context"
+	corebankingv1 "corebanking/api/gen/corebankingv1"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+)
+
+// eventServer is the EventService, authorized as the v2 event route is.
+type eventServer struct {
+	corebankingv1.UnimplementedEventServiceServer
+	service    *service.TransactionService
+	policy     *auth.Policy
+	errHandler utils.ErrorHandler
+}
+
+func (s *eventServer) Deposit(ctx context.Context, req *corebankingv1.DepositRequest) (*corebankingv1.EventResult, error) {
+	return s.handle(ctx, dto.NewEventRequest("deposit", "", req.GetDestination(), req.GetAmount()))
+}
+
+func (s *eventServer) Withdraw(ctx context.Context, req *corebankingv1.WithdrawRequest) (*corebankingv1.EventResult, error) {
+	return s.handle(ctx, dto.NewEventRequest("withdraw", req.GetOrigin(), "", req.GetAmount()))
+}
+
+func (s *eventServer) Transfer(ctx context.Context, req *corebankingv1.TransferRequest) (*corebankingv1.EventResult, error) {
+	return s.handle(ctx, dto.NewEventRequest("transfer", req.GetOrigin(), req.GetDestination(), req.GetAmount()))
+}
+
+func (s *eventServer) handle(ctx context.Context, req dto.EventRequest) (*corebankingv1.EventResult, error) {
+	action, resource := auth.EventAuthorization(req.Type, req.Origin, req.Destination)
+	if err := authorize(ctx, s.policy, s.errHandler, action, resource); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.HandleTransaction(ctx, &req)
+	if err != nil {
+		return nil, fail(ctx, s.errHandler, err, "Failed to handle event.")
+	}
+	accounts, _ := result.(map[string]*domain.Account)
+	return &corebankingv1.EventResult{
+		Origin:      accountState(accounts["origin"]),
+		Destination: accountState(accounts["destination"]),
+	}, nil
+}
+
+// accountState is nil for an account the event didn't touch.
+func accountState(account *domain.Account) *corebankingv1.AccountState {
+	if account == nil {
+		return nil
+	}
+	return &corebankingv1.AccountState{
+		Id:             account.ID,
+		Balance:        account.Balance,
+		OverdraftLimit: account.OverdraftLimit,
+	}
+}
@@ -0,0 +1,269 @@
+// Package rpc serves the account, transaction and event services over
+// gRPC, next to the HTTP API and through the same service layer, policy,
+// rate limits and error codes.
+package rpc
+
+import (
+	"context"
+	corebankingv1 "corebanking/api/gen/corebankingv1"
+	"corebanking/internal/auth"
+	"corebanking/internal/metrics"
+	"corebanking/internal/ratelimit"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Authenticate resolves the caller of a call from its context.
+type Authenticate func(ctx context.Context) (*auth.Principal, error)
+
+// Authenticator authenticates calls as the HTTP API does requests: with
+// x-api-key or authorization metadata or, when neither is sent, the TLS
+// client certificate.
+func Authenticator(authenticator *auth.Authenticator) Authenticate {
+	return func(ctx context.Context) (*auth.Principal, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var state *tls.ConnectionState
+		if p, ok := peer.FromContext(ctx); ok {
+			if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				state = &info.State
+			}
+		}
+		return authenticator.AuthenticateCredentials(first(md, "x-api-key"), first(md, "authorization"), state)
+	}
+}
+
+// Anonymous treats every call as made by principal, for when
+// authentication is disabled.
+func Anonymous(principal *auth.Principal) Authenticate {
+	return func(context.Context) (*auth.Principal, error) {
+		return principal, nil
+	}
+}
+
+// Server is the gRPC server. Watches are ended when it shuts down rather
+// than holding the shutdown up.
+type Server struct {
+	grpc     *grpc.Server
+	stopping chan struct{}
+}
+
+// NewServer registers the services on a gRPC server built with options,
+// such as its TLS credentials. Every call is authenticated, rate limited
+// by limiter and authorized by policy before it reaches the services.
+func NewServer(accounts *service.AccountService, transactions *service.TransactionService, policy *auth.Policy, authenticate Authenticate, limiter *ratelimit.Limiter, errHandler utils.ErrorHandler, options ...grpc.ServerOption) *Server {
+	s := &Server{stopping: make(chan struct{})}
+	guard := &guard{authenticate: authenticate, limiter: limiter, errHandler: errHandler}
+	options = append(options, grpc.UnaryInterceptor(guard.unary), grpc.StreamInterceptor(guard.stream))
+	s.grpc = grpc.NewServer(options...)
+	corebankingv1.RegisterAccountServiceServer(s.grpc, &accountServer{service: accounts, policy: policy, errHandler: errHandler})
+	corebankingv1.RegisterTransactionServiceServer(s.grpc, &transactionServer{service: transactions, policy: policy, errHandler: errHandler, stopping: s.stopping})
+	corebankingv1.RegisterEventServiceServer(s.grpc, &eventServer{service: transactions, policy: policy, errHandler: errHandler})
+	return s
+}
+
+// Serve accepts calls on listener until ctx is done, then ends the watches,
+// lets calls in flight finish for up to timeout and stops.
+func (s *Server) Serve(ctx context.Context, listener net.Listener, timeout time.Duration, logger *slog.Logger) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- s.grpc.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	close(s.stopping)
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		s.grpc.Stop()
+	}
+	logger.Info("gRPC server stopped")
+	if err := <-served; !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// ListenAndServe listens on addr and calls Serve.
+func (s *Server) ListenAndServe(ctx context.Context, addr string, timeout time.Duration, logger *slog.Logger) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener, timeout, logger)
+}
+
+// guard authenticates calls, and gives them a request ID, before they
+// reach a handler.
+type guard struct {
+	authenticate Authenticate
+	limiter      *ratelimit.Limiter
+	errHandler   utils.ErrorHandler
+}
+
+func (g *guard) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := g.admit(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (g *guard) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := g.admit(stream.Context(), info.FullMethod, nil)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+}
+
+// admit returns ctx with the caller's principal and request ID, or the
+// status refusing the call. The IP bucket is drawn before authenticating,
+// so calls with wrong credentials are limited too; the client and account
+// buckets after. Streams are admitted before their request is read, so
+// they draw from no account bucket.
+func (g *guard) admit(ctx context.Context, method string, req any) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := first(md, "x-request-id")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	ctx = utils.WithRequestID(ctx, requestID)
+
+	class := methodClass(method)
+	if err := g.limit(class, "", "", peerIP(ctx), ""); err != nil {
+		return nil, err
+	}
+	principal, err := g.authenticate(ctx)
+	if err != nil {
+		if g.errHandler != nil {
+			g.errHandler.Handle(ctx, err, "Failed to authenticate call.")
+		}
+		return nil, errorStatus(err)
+	}
+	ctx = auth.WithPrincipal(ctx, principal)
+
+	if err := g.limit(class, principal.ID, principal.Tier, "", requestAccount(req)); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// limit draws a token from the buckets of the non-empty IDs.
+func (g *guard) limit(class ratelimit.Class, clientID, tier, ip, accountID string) error {
+	if g.limiter == nil {
+		return nil
+	}
+	decision := g.limiter.Allow(g.limiter.Rules().Keys(class, clientID, tier, ip, accountID)...)
+	if !decision.Allowed {
+		metrics.RateLimited.Inc(decision.Scope, string(class))
+		return rateLimited(decision)
+	}
+	return nil
+}
+
+// peerIP is the caller's address without its port.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+	return ip
+}
+
+// methodClass treats posting transactions and events as money-moving, as
+// the HTTP API does.
+func methodClass(method string) ratelimit.Class {
+	switch method {
+	case corebankingv1.TransactionService_CreateTransaction_FullMethodName,
+		corebankingv1.EventService_Deposit_FullMethodName,
+		corebankingv1.EventService_Withdraw_FullMethodName,
+		corebankingv1.EventService_Transfer_FullMethodName:
+		return ratelimit.ClassMoney
+	default:
+		return ratelimit.ClassStandard
+	}
+}
+
+// requestAccount finds the account a call reads or debits: its account_id,
+// origin or destination.
+func requestAccount(req any) string {
+	if r, ok := req.(interface{ GetAccountId() string }); ok && r.GetAccountId() != "" {
+		return r.GetAccountId()
+	}
+	if r, ok := req.(interface{ GetOrigin() string }); ok && r.GetOrigin() != "" {
+		return r.GetOrigin()
+	}
+	if r, ok := req.(interface{ GetDestination() string }); ok {
+		return r.GetDestination()
+	}
+	return ""
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream is a stream whose context carries what the guard added.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// fail logs a handler's failure through the error handler and returns it
+// as a status.
+func fail(ctx context.Context, errHandler utils.ErrorHandler, err error, message string) error {
+	if errHandler != nil {
+		errHandler.Handle(ctx, err, message)
+	}
+	return errorStatus(err)
+}
+
+// authorize checks the policy and returns the status refusing the call
+// when it denies.
+func authorize(ctx context.Context, policy *auth.Policy, errHandler utils.ErrorHandler, action auth.Action, resource auth.Resource) error {
+	if err := policy.Authorize(ctx, action, resource); err != nil {
+		return fail(ctx, errHandler, err, "Operation not allowed.")
+	}
+	return nil
+}
+
+// badRequest is fail for a request the service can't be asked, answered
+// with INVALID_ARGUMENT whatever the error.
+func badRequest(ctx context.Context, errHandler utils.ErrorHandler, err error, message string) error {
+	if errHandler != nil {
+		errHandler.Handle(ctx, err, message)
+	}
+	return codeStatus("invalid_request", err, nil)
+}
@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"corebanking/internal/apierror"
+	"corebanking/internal/ratelimit"
+	"corebanking/internal/service"
+	"errors"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain names this API in ErrorInfo details.
+const errorDomain = "corebanking"
+
+// grpcCodes maps the HTTP API's error codes to gRPC codes.
+var grpcCodes = map[string]codes.Code{
+	"unauthorized":          codes.Unauthenticated,
+	"forbidden":             codes.PermissionDenied,
+	"reset_disabled":        codes.PermissionDenied,
+	"not_found":             codes.NotFound,
+	"conflict":              codes.AlreadyExists,
+	"invalid_request":       codes.InvalidArgument,
+	"insufficient_funds":    codes.FailedPrecondition,
+	"limit_exceeded":        codes.FailedPrecondition,
+	"fraud_declined":        codes.FailedPrecondition,
+	"confirmation_required": codes.FailedPrecondition,
+	// Held postings wait for a decision; retrying would post them twice.
+	"under_review":     codes.FailedPrecondition,
+	"sanctions_review": codes.FailedPrecondition,
+	"held_in_suspense": codes.FailedPrecondition,
+	"rate_limited":     codes.ResourceExhausted,
+	"busy":             codes.Unavailable,
+	"internal_error":   codes.Internal,
+}
+
+// errorStatus is the status for a service error: the gRPC code for the
+// error code the HTTP API answers it with, and that error code as the
+// reason of an ErrorInfo detail.
+func errorStatus(err error) error {
+	_, code := apierror.Status(err)
+	var metadata map[string]string
+	var hold *service.HoldError
+	if errors.As(err, &hold) {
+		metadata = map[string]string{"hold_id": hold.ID}
+	}
+	return codeStatus(code, err, metadata)
+}
+
+// codeStatus is the status for err with the HTTP API's error code.
+func codeStatus(code string, err error, metadata map[string]string) error {
+	grpcCode, known := grpcCodes[code]
+	if !known {
+		grpcCode = codes.Unknown
+	}
+	st, detailErr := status.New(grpcCode, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   code,
+		Domain:   errorDomain,
+		Metadata: metadata,
+	})
+	if detailErr != nil {
+		return status.Error(grpcCode, err.Error())
+	}
+	return st.Err()
+}
+
+// rateLimited is the status for a call the limiter refused, with when to
+// try again.
+func rateLimited(decision ratelimit.Decision) error {
+	message := "rate limit exceeded for " + decision.Scope
+	st, err := status.New(codes.ResourceExhausted, message).WithDetails(
+		&errdetails.ErrorInfo{
+			Reason:   "rate_limited",
+			Domain:   errorDomain,
+			Metadata: map[string]string{"scope": decision.Scope, "limit": strconv.Itoa(decision.Limit.Burst)},
+		},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(decision.RetryAfter)},
+	)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, message)
+	}
+	return st.Err()
+}
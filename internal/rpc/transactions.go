@@ -0,0 +1,164 @@
+package rpc
+
+import (
+	"context"
+	corebankingv1 "corebanking/api/gen/corebankingv1"
+	"corebanking/internal/auth"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"corebanking/internal/utils"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// transactionServer is the TransactionService, authorized as the v2
+// transaction routes are. Its watches end when stopping is closed.
+type transactionServer struct {
+	corebankingv1.UnimplementedTransactionServiceServer
+	service    *service.TransactionService
+	policy     *auth.Policy
+	errHandler utils.ErrorHandler
+	stopping   <-chan struct{}
+}
+
+func (s *transactionServer) CreateTransaction(ctx context.Context, req *corebankingv1.CreateTransactionRequest) (*corebankingv1.Transaction, error) {
+	action, resource := auth.TransactionAuthorization(int(req.GetOperationTypeId()), req.GetAccountId())
+	if err := authorize(ctx, s.policy, s.errHandler, action, resource); err != nil {
+		return nil, err
+	}
+
+	request := dto.NewTransactionRequest(req.GetAccountId(), int(req.GetOperationTypeId()), req.GetAmount())
+	transaction, err := s.service.CreateTransaction(ctx, &request)
+	if err != nil {
+		return nil, fail(ctx, s.errHandler, err, "Failed to create transaction.")
+	}
+	return transactionMessage(transaction), nil
+}
+
+// GetTransaction answers a caller who may not read the transaction as if
+// it did not exist, so transaction IDs can't be probed for other accounts'
+// postings.
+func (s *transactionServer) GetTransaction(ctx context.Context, req *corebankingv1.GetTransactionRequest) (*corebankingv1.Transaction, error) {
+	transaction, err := s.service.GetTransactionByID(ctx, req.GetTransactionId())
+	if err == nil {
+		err = s.policy.Authorize(ctx, auth.ActionReadTransaction, auth.Resource{AccountID: transaction.AccountID})
+	}
+	if errors.Is(err, auth.ErrForbidden) {
+		if s.errHandler != nil {
+			s.errHandler.Handle(ctx, err, "Operation not allowed.")
+		}
+		return nil, errorStatus(service.ErrTransactionNotFound)
+	}
+	if err != nil {
+		return nil, fail(ctx, s.errHandler, err, "Failed to recovery transactionByID.")
+	}
+	return transactionMessage(transaction), nil
+}
+
+func (s *transactionServer) ListTransactionsToday(ctx context.Context, _ *corebankingv1.ListTransactionsTodayRequest) (*corebankingv1.ListTransactionsResponse, error) {
+	if err := authorize(ctx, s.policy, s.errHandler, auth.ActionListTransactions, auth.Resource{}); err != nil {
+		return nil, err
+	}
+	return transactionList(s.service.GetTransactionsToday(ctx)), nil
+}
+
+func (s *transactionServer) ListTransactionsInRange(ctx context.Context, req *corebankingv1.ListTransactionsInRangeRequest) (*corebankingv1.ListTransactionsResponse, error) {
+	if err := authorize(ctx, s.policy, s.errHandler, auth.ActionListTransactions, auth.Resource{}); err != nil {
+		return nil, err
+	}
+
+	if req.GetBegin() == nil || req.GetEnd() == nil {
+		return nil, badRequest(ctx, s.errHandler, errors.New("begin and end are required"), "Failed in parse date time range.")
+	}
+	return transactionList(s.service.GetTransactionsInRange(ctx, req.GetBegin().AsTime(), req.GetEnd().AsTime())), nil
+}
+
+func (s *transactionServer) ListTransactionsByType(ctx context.Context, req *corebankingv1.ListTransactionsByTypeRequest) (*corebankingv1.ListTransactionsResponse, error) {
+	if err := authorize(ctx, s.policy, s.errHandler, auth.ActionListTransactions, auth.Resource{}); err != nil {
+		return nil, err
+	}
+
+	typeID := int(req.GetOperationTypeId())
+	if typeID < 1 || typeID > 4 {
+		return nil, fail(ctx, s.errHandler, service.ErrInvalidOperationType, "Failed parse data in operationTypeId.")
+	}
+	return transactionList(s.service.GetTransactionsByType(ctx, typeID)), nil
+}
+
+// WatchTransactions streams the account's postings until the client goes
+// away, falls behind or the server shuts down. Postings made through
+// events have no transaction ID or operation type. The response headers
+// are sent once the watch is live.
+func (s *transactionServer) WatchTransactions(req *corebankingv1.WatchTransactionsRequest, stream grpc.ServerStreamingServer[corebankingv1.Transaction]) error {
+	ctx := stream.Context()
+	if err := authorize(ctx, s.policy, s.errHandler, auth.ActionReadTransaction, auth.Resource{AccountID: req.GetAccountId()}); err != nil {
+		return err
+	}
+
+	watch, err := s.service.WatchTransactions(ctx, req.GetAccountId())
+	if err != nil {
+		return fail(ctx, s.errHandler, err, "Failed to watch transactions.")
+	}
+	defer watch.Close()
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for {
+		transaction, err := watch.Next(ctx)
+		switch {
+		case err == nil:
+		case ctx.Err() == nil:
+			return fail(ctx, s.errHandler, err, "Failed to watch transactions.")
+		case isClosed(s.stopping):
+			return status.Error(codes.Unavailable, "server shutting down, watch again")
+		default:
+			// The client went away.
+			return nil
+		}
+		if err := stream.Send(transactionMessage(transaction)); err != nil {
+			return err
+		}
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func transactionMessage(transaction *dto.TransactionResponse) *corebankingv1.Transaction {
+	return &corebankingv1.Transaction{
+		TransactionId:   transaction.TransactionID,
+		AccountId:       transaction.AccountID,
+		OperationTypeId: int32(transaction.OperationTypeID),
+		Amount:          transaction.Amount,
+		EventDate:       timestamppb.New(transaction.EventDate),
+	}
+}
+
+func transactionList(transactions []*dto.TransactionResponse) *corebankingv1.ListTransactionsResponse {
+	result := make([]*corebankingv1.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		result = append(result, transactionMessage(transaction))
+	}
+	return &corebankingv1.ListTransactionsResponse{Transactions: result}
+}
@@ -0,0 +1,153 @@
+package sanctions
+
+import (
+	"bytes"
+	"corebanking/internal/domain"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// List is a loaded watchlist. Digest is the SHA-256 of the file, so a
+// screening's list can be matched to the exact file later; Version is the
+// one the file declares, or the digest's first 12 characters when it
+// doesn't.
+type List struct {
+	Version  string
+	Digest   string
+	Source   string
+	LoadedAt time.Time
+	Entries  []domain.SanctionsEntry
+}
+
+// EmptyList screens everything clear; it is used until a list is loaded.
+func EmptyList() *List {
+	return &List{Version: "none"}
+}
+
+// Load reads a list from a .json or .csv file.
+//
+// JSON files hold {"version": "...", "entries": [...]} with entries shaped
+// like domain.SanctionsEntry, or just the array of entries. CSV files have
+// the header id,name,aliases,documents,list,program, aliases and
+// documents separated by semicolons.
+func Load(path string, now time.Time) (*List, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var version string
+	var entries []domain.SanctionsEntry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		version, entries, err = parseJSON(data)
+	case ".csv":
+		entries, err = parseCSV(data)
+	default:
+		return nil, fmt.Errorf("sanctions list %s: must be a .json or .csv file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sanctions list %s: %w", path, err)
+	}
+	if err := validate(entries); err != nil {
+		return nil, fmt.Errorf("sanctions list %s: %w", path, err)
+	}
+
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if version == "" {
+		version = digest[:12]
+	}
+	return &List{Version: version, Digest: digest, Source: path, LoadedAt: now, Entries: entries}, nil
+}
+
+func parseJSON(data []byte) (string, []domain.SanctionsEntry, error) {
+	var entries []domain.SanctionsEntry
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(data, &entries)
+		return "", entries, err
+	}
+	var file struct {
+		Version string                  `json:"version"`
+		Entries []domain.SanctionsEntry `json:"entries"`
+	}
+	err := json.Unmarshal(data, &file)
+	return file.Version, file.Entries, err
+}
+
+var csvHeader = []string{"id", "name", "aliases", "documents", "list", "program"}
+
+func parseCSV(data []byte) ([]domain.SanctionsEntry, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = len(csvHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range csvHeader {
+		if strings.ToLower(strings.TrimSpace(header[i])) != column {
+			return nil, fmt.Errorf("header must be %s", strings.Join(csvHeader, ","))
+		}
+	}
+
+	var entries []domain.SanctionsEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, domain.SanctionsEntry{
+			ID:        record[0],
+			Name:      record[1],
+			Aliases:   splitList(record[2]),
+			Documents: splitList(record[3]),
+			List:      record[4],
+			Program:   record[5],
+		})
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func validate(entries []domain.SanctionsEntry) error {
+	var errs []error
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		switch {
+		case entry.ID == "":
+			errs = append(errs, fmt.Errorf("entry %d: id is required", i+1))
+		case seen[entry.ID]:
+			errs = append(errs, fmt.Errorf("entry %s: duplicate id", entry.ID))
+		}
+		seen[entry.ID] = true
+		if entry.Name == "" && len(entry.Documents) == 0 {
+			errs = append(errs, fmt.Errorf("entry %s: needs a name or a document", entry.ID))
+		}
+		if entry.List != domain.ListSanctions && entry.List != domain.ListPEP {
+			errs = append(errs, fmt.Errorf("entry %s: list must be %s or %s, got %q", entry.ID, domain.ListSanctions, domain.ListPEP, entry.List))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package sanctions
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// accents folds the accented letters of Latin alphabets, so "João" and
+// "Joao" compare equal.
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ý", "y", "ÿ", "y", "ß", "ss",
+)
+
+// normalizeName lowercases a name, folds accents and reduces everything
+// but letters and digits to single spaces.
+func normalizeName(name string) string {
+	name = accents.Replace(strings.ToLower(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// normalizeDocument keeps a document number's letters and digits,
+// uppercased, so punctuation doesn't defeat an exact match.
+func normalizeDocument(document string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, document)
+}
+
+// similarity scores two normalized names from 0 to 1: Jaro-Winkler on the
+// names as written and with their words sorted, whichever is higher, so
+// "Silva Maria" matches "Maria Silva".
+func similarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	return max(jaroWinkler(a, b), jaroWinkler(sortWords(a), sortWords(b)))
+}
+
+func sortWords(name string) string {
+	words := strings.Fields(name)
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// jaroWinkler is the Jaro similarity with the Winkler bonus for a common
+// prefix of up to four characters.
+func jaroWinkler(a, b string) float64 {
+	s, t := []rune(a), []rune(b)
+	if slices.Equal(s, t) {
+		return 1
+	}
+
+	window := max(len(s), len(t))/2 - 1
+	sMatched := make([]bool, len(s))
+	tMatched := make([]bool, len(t))
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i], tMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions/2))/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,127 @@
+// Package sanctions screens customers and transfer destinations against a
+// locally loaded sanctions and PEP list: document numbers match exactly,
+// names fuzzily.
+package sanctions
+
+import (
+	"corebanking/internal/domain"
+	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Policy sets how screening runs. The thresholds are the name similarity,
+// from 0 to 1, at which a name matches an entry of the sanctions and the
+// PEP list; documents always match exactly. The zero Policy screens
+// nothing.
+type Policy struct {
+	Enabled       bool
+	NameThreshold float64
+	PEPThreshold  float64
+}
+
+// Subject is who is screened.
+type Subject struct {
+	Name     string
+	Document string
+}
+
+// Result is a screening's hits and the list it ran against.
+type Result struct {
+	Version string
+	Digest  string
+	Hits    []domain.SanctionsHit
+}
+
+// Screener screens subjects against a list and policy that can both be
+// replaced while serving.
+type Screener struct {
+	list   atomic.Pointer[List]
+	policy atomic.Pointer[Policy]
+}
+
+func NewScreener(list *List, policy Policy) *Screener {
+	s := &Screener{}
+	s.SetList(list)
+	s.SetPolicy(policy)
+	return s
+}
+
+func (s *Screener) SetList(list *List) {
+	s.list.Store(list)
+}
+
+func (s *Screener) List() *List {
+	return s.list.Load()
+}
+
+func (s *Screener) SetPolicy(policy Policy) {
+	s.policy.Store(&policy)
+}
+
+func (s *Screener) Policy() Policy {
+	return *s.policy.Load()
+}
+
+// Screen returns the entries the subject matches, best first, one hit per
+// entry. It reports false when screening is off.
+func (s *Screener) Screen(subject Subject) (Result, bool) {
+	policy := s.policy.Load()
+	if !policy.Enabled {
+		return Result{}, false
+	}
+	list := s.list.Load()
+	result := Result{Version: list.Version, Digest: list.Digest, Hits: make([]domain.SanctionsHit, 0)}
+
+	name := normalizeName(subject.Name)
+	document := normalizeDocument(subject.Document)
+	for _, entry := range list.Entries {
+		if hit, ok := matchEntry(entry, name, document, policy); ok {
+			result.Hits = append(result.Hits, hit)
+		}
+	}
+	slices.SortFunc(result.Hits, func(a, b domain.SanctionsHit) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.EntryID, b.EntryID)
+	})
+	return result, true
+}
+
+func matchEntry(entry domain.SanctionsEntry, name, document string, policy *Policy) (domain.SanctionsHit, bool) {
+	hit := domain.SanctionsHit{EntryID: entry.ID, List: entry.List, Program: entry.Program}
+	if document != "" {
+		for _, listed := range entry.Documents {
+			if normalizeDocument(listed) == document {
+				hit.Name, hit.Match, hit.Score = entry.Name, domain.MatchDocument, 1
+				return hit, true
+			}
+		}
+	}
+
+	threshold := policy.NameThreshold
+	if entry.List == domain.ListPEP {
+		threshold = policy.PEPThreshold
+	}
+	if name == "" || threshold <= 0 {
+		return hit, false
+	}
+	for _, listed := range append([]string{entry.Name}, entry.Aliases...) {
+		if score := similarity(name, normalizeName(listed)); score >= threshold && score > hit.Score {
+			hit.Name, hit.Score = listed, score
+		}
+	}
+	if hit.Score == 0 {
+		return hit, false
+	}
+	hit.Match = domain.MatchName
+	// Three decimals are enough to compare with a threshold and keep
+	// records readable.
+	hit.Score = math.Round(hit.Score*1000) / 1000
+	return hit, true
+}
@@ -0,0 +1,88 @@
+// Package server runs the HTTP server and background workers and shuts
+// them down in order when the process is asked to stop.
+package server
+
+import (
+	"context"
+	"corebanking/internal/health"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Options hardens the server against slow or oversized requests and bounds
+// shutdown.
+type Options struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	// DrainDelay keeps serving after readiness starts failing, so load
+	// balancers notice before connections are closed.
+	DrainDelay time.Duration
+	// ShutdownTimeout bounds how long in-flight requests may take to
+	// finish; connections still open after it are closed.
+	ShutdownTimeout time.Duration
+}
+
+func New(addr string, handler http.Handler, options Options, logger *slog.Logger) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       options.ReadTimeout,
+		ReadHeaderTimeout: options.ReadHeaderTimeout,
+		WriteTimeout:      options.WriteTimeout,
+		IdleTimeout:       options.IdleTimeout,
+		MaxHeaderBytes:    options.MaxHeaderBytes,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+}
+
+// Serve accepts connections on listener until ctx is done, then marks the
+// checker as draining, waits DrainDelay, and shuts the server down. It
+// serves HTTPS when srv.TLSConfig is set and returns nil after a clean
+// shutdown.
+func Serve(ctx context.Context, srv *http.Server, listener net.Listener, checker *health.Checker, options Options, logger *slog.Logger) error {
+	served := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			served <- srv.ServeTLS(listener, "", "")
+			return
+		}
+		served <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutdown started, draining", "drain_delay", options.DrainDelay.String())
+	checker.SetDraining()
+	time.Sleep(options.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), options.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return err
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logger.Info("HTTP server stopped")
+	return nil
+}
+
+// ListenAndServe listens on srv.Addr and calls Serve.
+func ListenAndServe(ctx context.Context, srv *http.Server, checker *health.Checker, options Options, logger *slog.Logger) error {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	return Serve(ctx, srv, listener, checker, options, logger)
+}
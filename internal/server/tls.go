@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client certificate modes for TLSOptions.ClientAuth.
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+// TLSOptions configures HTTPS. Without a ClientCAFile no client
+// certificates are requested.
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile holds the PEM CA certificates client certificates must
+	// chain to.
+	ClientCAFile string
+	// ClientAuth is none, optional (verified when sent) or require.
+	ClientAuth string
+	// MinVersion is 1.2 or 1.3.
+	MinVersion string
+	// CipherSuites names the TLS 1.2 suites to offer, e.g.
+	// TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256. Empty keeps Go's defaults;
+	// TLS 1.3 suites are not configurable.
+	CipherSuites []string
+}
+
+// ParseTLSVersion reads "1.2" or "1.3".
+func ParseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q", version)
+	}
+}
+
+// ParseCipherSuites looks names up among the suites Go considers secure.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	var ids []uint16
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, ok := secureCipherSuite(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func secureCipherSuite(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// CertReloader holds the server certificate and client CA pool last read
+// from disk, so they can be replaced without restarting the server. A
+// failed reload keeps serving the previous files.
+type CertReloader struct {
+	options TLSOptions
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+}
+
+// NewCertReloader loads the files once and fails if they are unusable.
+func NewCertReloader(options TLSOptions) (*CertReloader, error) {
+	r := &CertReloader{options: options}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the certificate, key and client CA files again.
+func (r *CertReloader) Reload() error {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.options.CertFile, r.options.KeyFile)
+	if err != nil {
+		return err
+	}
+	var clientCAs *x509.CertPool
+	if r.options.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.options.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates in %s", r.options.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	return nil
+}
+
+// Watch reloads when a file's modification time changes, checking every
+// interval until ctx is done. onReload is called with the result of each
+// reload.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration, onReload func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if r.changed() {
+			onReload(r.Reload())
+		}
+	}
+}
+
+func (r *CertReloader) changed() bool {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		// Mid-rotation a file may be missing; look again next tick.
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for path, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CertReloader) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, path := range []string{r.options.CertFile, r.options.KeyFile, r.options.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[path] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// Certificate returns the certificate currently served.
+func (r *CertReloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// TLSConfig builds a server config that picks up reloaded files on each
+// new handshake.
+func (r *CertReloader) TLSConfig() (*tls.Config, error) {
+	minVersion, err := ParseTLSVersion(r.options.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := ParseCipherSuites(r.options.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth := tls.NoClientCert
+	if r.options.ClientCAFile != "" {
+		switch r.options.ClientAuth {
+		case ClientAuthNone:
+		case ClientAuthOptional, "":
+			clientAuth = tls.VerifyClientCertIfGiven
+		case ClientAuthRequire:
+			clientAuth = tls.RequireAndVerifyClientCert
+		default:
+			return nil, fmt.Errorf("unknown client auth mode %q", r.options.ClientAuth)
+		}
+	} else if r.options.ClientAuth == ClientAuthRequire {
+		return nil, errors.New("client auth require needs a client CA file")
+	}
+
+	base := &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+		ClientAuth:   clientAuth,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	config := base.Clone()
+	config.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return r.Certificate(), nil
+	}
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		current := base.Clone()
+		current.Certificates = []tls.Certificate{*r.cert}
+		current.ClientCAs = r.clientCAs
+		return current, nil
+	}
+	return config, nil
+}
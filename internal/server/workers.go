@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Workers runs background goroutines such as schedulers under one context
+// that Stop cancels.
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	logger *slog.Logger
+}
+
+func NewWorkers(parent context.Context, logger *slog.Logger) *Workers {
+	ctx, cancel := context.WithCancel(parent)
+	return &Workers{ctx: ctx, cancel: cancel, logger: logger}
+}
+
+// Go runs fn until it returns; fn must return once ctx is done. An error
+// other than the context's own is logged.
+func (w *Workers) Go(name string, fn func(ctx context.Context) error) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		if err := fn(w.ctx); err != nil && w.ctx.Err() == nil {
+			w.logger.Error("Background worker failed", "worker", name, "error", err.Error())
+		}
+	}()
+}
+
+// Stop cancels the workers and waits for them, giving up when ctx is done.
+func (w *Workers) Stop(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
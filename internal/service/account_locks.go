@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/repository"
+	"slices"
+)
+
+// accountLocks makes changes to the same account happen one at a time, so
+// none sees another half done. The locks are the account repository's, so
+// every service changing its accounts takes the same ones.
+type accountLocks struct {
+	accounts *repository.AccountRepository
+}
+
+func newAccountLocks(accounts *repository.AccountRepository) *accountLocks {
+	return &accountLocks{accounts: accounts}
+}
+
+// heldAccountsKey marks the accounts a context's caller has locked, so
+// what it calls while holding them doesn't wait on itself.
+type heldAccountsKey struct{}
+
+// lock locks the accounts in ids not already held by ctx, in ID order so
+// that two callers never wait on each other, and returns ctx holding them
+// and the func unlocking them. Empty IDs are ignored.
+func (l *accountLocks) lock(ctx context.Context, ids ...string) (context.Context, func()) {
+	held, _ := ctx.Value(heldAccountsKey{}).(map[string]bool)
+	wanted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !held[id] {
+			wanted = append(wanted, id)
+		}
+	}
+	slices.Sort(wanted)
+	wanted = slices.Compact(wanted)
+	if len(wanted) == 0 {
+		return ctx, func() {}
+	}
+
+	unlocks := make([]func(), 0, len(wanted))
+	for _, id := range wanted {
+		unlocks = append(unlocks, l.accounts.Lock(id))
+	}
+
+	holding := make(map[string]bool, len(held)+len(wanted))
+	for id := range held {
+		holding[id] = true
+	}
+	for _, id := range wanted {
+		holding[id] = true
+	}
+	return context.WithValue(ctx, heldAccountsKey{}, holding), func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
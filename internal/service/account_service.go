@@ -1,10 +1,12 @@
 package service
 
 import (
+	"context"
 	"corebanking/internal/domain"
 	"corebanking/internal/dto"
 	"corebanking/internal/repository"
-	"fmt"
+	"corebanking/internal/tracing"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
@@ -12,67 +14,151 @@ import (
 
 type AccountService struct {
 	accountRepo       *repository.AccountRepository
+	audit             *AuditService
 	documentToAccount map[string]string
+	sanctions         *SanctionsService
+	locks             *accountLocks
 	mu                sync.RWMutex
 }
 
-func NewAccountService(accountRepo *repository.AccountRepository) *AccountService {
+func NewAccountService(accountRepo *repository.AccountRepository, audit *AuditService) *AccountService {
 	return &AccountService{
 		accountRepo:       accountRepo,
+		audit:             audit,
 		documentToAccount: make(map[string]string),
+		locks:             newAccountLocks(accountRepo),
 	}
 }
 
-func (s *AccountService) CreateAccount(documentNumber string) (*dto.AccountResponse, error) {
+// SetSanctionsService screens customers before their account is opened.
+func (s *AccountService) SetSanctionsService(sanctions *SanctionsService) {
+	s.sanctions = sanctions
+}
+
+// CreateAccount opens an account for a document number. The holder name
+// is optional; with sanctions screening, a hit holds the onboarding for
+// review and returns ErrSanctionsReview.
+func (s *AccountService) CreateAccount(ctx context.Context, documentNumber, holderName string) (_ *dto.AccountResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.CreateAccount")
+	defer span.Finish(&err)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if _, exists := s.documentToAccount[documentNumber]; exists {
-		return nil, fmt.Errorf("document already has an account")
+		return nil, ErrDocumentAlreadyExists
 	}
 
 	accountID := uuid.New().String()
+	if s.sanctions != nil {
+		if err := s.sanctions.ScreenOnboarding(ctx, documentNumber, holderName, accountID); err != nil {
+			return nil, err
+		}
+	}
 	account := &domain.Account{
-		ID:      accountID,
-		Balance: 0,
+		ID:         accountID,
+		Balance:    0,
+		HolderName: holderName,
 	}
 
-	s.accountRepo.Save(account)
-	s.documentToAccount[documentNumber] = accountID
-
-	return &dto.AccountResponse{
+	response := &dto.AccountResponse{
 		AccountID:      accountID,
 		DocumentNumber: documentNumber,
-	}, nil
+		HolderName:     holderName,
+	}
+	if err := s.audit.Record(ctx, AuditAccountCreate, "account", accountID, nil, response); err != nil {
+		return nil, err
+	}
+	s.accountRepo.Save(ctx, account)
+	s.documentToAccount[documentNumber] = accountID
+	return response, nil
 }
 
-func (s *AccountService) GetAccount(accountID string) (*dto.AccountResponse, error) {
-	account, exists := s.accountRepo.FindById(accountID)
+func (s *AccountService) GetAccount(ctx context.Context, accountID string) (_ *dto.AccountResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.GetAccount", tracing.String("account.id", accountID))
+	defer span.Finish(&err)
+
+	account, exists := s.accountRepo.FindById(ctx, accountID)
 	if !exists {
-		return nil, fmt.Errorf("account not found")
+		return nil, ErrAccountNotFound
 	}
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	document := "UNKNOWN"
-	for doc, id := range s.documentToAccount {
-		if id == accountID {
-			document = doc
-			break
-		}
+	document := s.documentOf(accountID)
+	if document == "" {
+		document = "UNKNOWN"
 	}
 
 	return &dto.AccountResponse{
 		AccountID:      account.ID,
 		DocumentNumber: document,
+		HolderName:     account.HolderName,
 	}, nil
 }
 
-func (s *AccountService) GetBalance(accountID string) (*dto.BalanceResponse, error) {
-	account, exists := s.accountRepo.FindById(accountID)
+// Verify confirms an account exists before money is sent to it, showing
+// the holder's name masked so a payer can recognise the payee without the
+// name being disclosed. Internal accounts can't be paid into and are not
+// found.
+func (s *AccountService) Verify(ctx context.Context, accountID string) (_ *dto.AccountVerificationResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.Verify", tracing.String("account.id", accountID))
+	defer span.Finish(&err)
+
+	account, exists := s.accountRepo.FindById(ctx, accountID)
+	if !exists || account.Internal {
+		return nil, ErrAccountNotFound
+	}
+
+	return &dto.AccountVerificationResponse{
+		AccountID:  account.ID,
+		HolderName: maskName(account.HolderName),
+	}, nil
+}
+
+// maskName keeps the first letter of each word of a name and stars the
+// rest: "Maria Oliveira" is "M**** O*******".
+func maskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		runes := []rune(word)
+		words[i] = string(runes[0]) + strings.Repeat("*", len(runes)-1)
+	}
+	return strings.Join(words, " ")
+}
+
+// Holder returns the document number and name of an account's holder;
+// accounts opened by a deposit or transfer have neither.
+func (s *AccountService) Holder(ctx context.Context, accountID string) (documentNumber, holderName string, err error) {
+	account, exists := s.accountRepo.FindById(ctx, accountID)
+	if !exists {
+		return "", "", ErrAccountNotFound
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.documentOf(accountID), account.HolderName, nil
+}
+
+// documentOf finds the document number mapped to an account; callers hold
+// s.mu.
+func (s *AccountService) documentOf(accountID string) string {
+	for doc, id := range s.documentToAccount {
+		if id == accountID {
+			return doc
+		}
+	}
+	return ""
+}
+
+func (s *AccountService) GetBalance(ctx context.Context, accountID string) (_ *dto.BalanceResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.GetBalance", tracing.String("account.id", accountID))
+	defer span.Finish(&err)
+
+	account, exists := s.accountRepo.FindById(ctx, accountID)
 	if !exists {
-		return nil, fmt.Errorf("account not found")
+		return nil, ErrAccountNotFound
 	}
 
 	return &dto.BalanceResponse{
@@ -80,21 +166,74 @@ func (s *AccountService) GetBalance(accountID string) (*dto.BalanceResponse, err
 	}, nil
 }
 
-func (s *AccountService) ConfigOverdraft(accountID string, limit int64) error {
-	account, exists := s.accountRepo.FindById(accountID)
+func (s *AccountService) ConfigOverdraft(ctx context.Context, accountID string, limit int64) (err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.ConfigOverdraft", tracing.String("account.id", accountID))
+	defer span.Finish(&err)
+
+	ctx, unlock := s.locks.lock(ctx, accountID)
+	defer unlock()
+
+	account, exists := s.accountRepo.FindById(ctx, accountID)
 	if !exists {
-		return fmt.Errorf("account not found")
+		return ErrAccountNotFound
 	}
 
+	before := *account
 	account.OverdraftLimit = limit
-	s.accountRepo.Save(account)
+	if err := s.audit.Record(ctx, AuditAccountOverdraft, "account", accountID, before, account); err != nil {
+		return err
+	}
+	s.accountRepo.Save(ctx, account)
 	return nil
 }
 
-func (s *AccountService) Reset() {
-	s.accountRepo.Reset()
+// IsOwner reports whether accountID belongs to the holder of the document
+// number, which is the subject of customer principals.
+func (s *AccountService) IsOwner(accountID, documentNumber string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, exists := s.documentToAccount[documentNumber]
+	return exists && id == accountID
+}
 
+// Reset removes every account and returns how many there were. Callers go
+// through ResetService, which gates and audits it and holds every
+// account's lock.
+func (s *AccountService) Reset() int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+
+	count := s.accountRepo.Count()
+	s.accountRepo.Reset()
 	s.documentToAccount = make(map[string]string)
+	return count
+}
+
+// Remove deletes one account and its document mapping, returning the
+// account as it was.
+func (s *AccountService) Remove(ctx context.Context, accountID string) (_ *domain.Account, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.Remove", tracing.String("account.id", accountID))
+	defer span.Finish(&err)
+
+	// The account is locked before mu, as postings lock it before reading
+	// its holder.
+	ctx, unlock := s.locks.lock(ctx, accountID)
+	defer unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, exists := s.accountRepo.FindById(ctx, accountID)
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+
+	s.accountRepo.Delete(ctx, accountID)
+	for doc, id := range s.documentToAccount {
+		if id == accountID {
+			delete(s.documentToAccount, doc)
+		}
+	}
+	return account, nil
 }
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/aml"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/metrics"
+	"corebanking/internal/repository"
+	"corebanking/internal/tracing"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AMLService monitors posted movements for money laundering patterns,
+// keeps the resulting alerts for analysts to disposition and exports the
+// suspicious ones as activity reports. Monitoring never blocks a posting.
+type AMLService struct {
+	monitor  *aml.Monitor
+	history  *repository.AMLHistoryRepository
+	alerts   *repository.AMLAlertRepository
+	accounts *AccountService
+	audit    *AuditService
+	now      func() time.Time
+	// mu makes finding the open alert for a rule and raising a new one
+	// atomic, so concurrent postings don't raise the same alert twice.
+	mu sync.Mutex
+}
+
+func NewAMLService(monitor *aml.Monitor, history *repository.AMLHistoryRepository, alerts *repository.AMLAlertRepository, accounts *AccountService, audit *AuditService) *AMLService {
+	return NewAMLServiceWithClock(monitor, history, alerts, accounts, audit, time.Now)
+}
+
+// NewAMLServiceWithClock lets tests control time.
+func NewAMLServiceWithClock(monitor *aml.Monitor, history *repository.AMLHistoryRepository, alerts *repository.AMLAlertRepository, accounts *AccountService, audit *AuditService, now func() time.Time) *AMLService {
+	return &AMLService{monitor: monitor, history: history, alerts: alerts, accounts: accounts, audit: audit, now: now}
+}
+
+// Observe adds a posted movement to the account's history and raises or
+// updates an alert for every rule the history then matches.
+func (s *AMLService) Observe(ctx context.Context, accountID, kind string, amount int64) (err error) {
+	keep := s.monitor.Rules().Retention()
+	if keep <= 0 {
+		return nil
+	}
+	ctx, span := tracing.Start(ctx, "AMLService.Observe", tracing.String("account.id", accountID), tracing.String("aml.kind", kind))
+	defer span.Finish(&err)
+
+	now := s.now()
+	history := s.history.Add(ctx, accountID, domain.AMLMovement{At: now, Kind: kind, Amount: amount}, keep)
+	findings := s.monitor.Evaluate(history, now)
+	if len(findings) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, finding := range findings {
+		if err := s.raise(ctx, accountID, finding, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// raise updates the account's open alert for the finding's rule, or opens
+// one. Movements an analyst already dispositioned don't raise it again:
+// the finding needs one after the last alert's window.
+func (s *AMLService) raise(ctx context.Context, accountID string, finding aml.Finding, now time.Time) error {
+	if alerts := s.alerts.Find(ctx, "", accountID, finding.Rule); len(alerts) > 0 {
+		last := alerts[len(alerts)-1]
+		if !finding.To.After(last.WindowEnd) {
+			return nil
+		}
+		if last.Status == domain.AlertOpen {
+			before := *last
+			last.Amount = finding.Amount
+			last.Count = finding.Count
+			if finding.From.Before(last.WindowStart) {
+				last.WindowStart = finding.From
+			}
+			last.WindowEnd = finding.To
+			last.Details = finding.Details
+			last.UpdatedAt = now
+			if err := s.audit.Record(ctx, AuditAMLAlert, "aml_alert", last.ID, before, last); err != nil {
+				return err
+			}
+			s.alerts.Save(ctx, last)
+			return nil
+		}
+	}
+
+	alert := &domain.AMLAlert{
+		ID:          uuid.New().String(),
+		AccountID:   accountID,
+		Rule:        finding.Rule,
+		Status:      domain.AlertOpen,
+		Amount:      finding.Amount,
+		Count:       finding.Count,
+		WindowStart: finding.From,
+		WindowEnd:   finding.To,
+		Details:     finding.Details,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.audit.Record(ctx, AuditAMLAlert, "aml_alert", alert.ID, nil, alert); err != nil {
+		return err
+	}
+	s.alerts.Save(ctx, alert)
+	metrics.AMLAlerts.Inc(finding.Rule)
+	return nil
+}
+
+// Alerts lists alerts with status for accountID; empty values match any.
+func (s *AMLService) Alerts(ctx context.Context, status, accountID string) []*domain.AMLAlert {
+	ctx, span := tracing.Start(ctx, "AMLService.Alerts", tracing.String("aml_alert.status", status))
+	defer span.End()
+
+	return s.alerts.Find(ctx, status, accountID, "")
+}
+
+func (s *AMLService) Alert(ctx context.Context, id string) (*domain.AMLAlert, error) {
+	alert, exists := s.alerts.FindByID(ctx, id)
+	if !exists {
+		return nil, ErrAMLAlertNotFound
+	}
+	return alert, nil
+}
+
+// Disposition closes an open alert as false_positive, no_action or
+// suspicious, recording who did it and why.
+func (s *AMLService) Disposition(ctx context.Context, id, disposition, note string) (_ *domain.AMLAlert, err error) {
+	ctx, span := tracing.Start(ctx, "AMLService.Disposition", tracing.String("aml_alert.id", id), tracing.String("aml_alert.status", disposition))
+	defer span.Finish(&err)
+
+	switch disposition {
+	case domain.AlertFalsePositive, domain.AlertNoAction, domain.AlertSuspicious:
+	default:
+		return nil, ErrInvalidDisposition
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, exists := s.alerts.FindByID(ctx, id)
+	if !exists {
+		return nil, ErrAMLAlertNotFound
+	}
+	if alert.Status != domain.AlertOpen {
+		return nil, ErrAMLAlertClosed
+	}
+
+	before := *alert
+	now := s.now()
+	alert.Status = disposition
+	alert.DispositionedAt = &now
+	alert.Note = note
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		alert.DispositionedBy = principal.Subject
+	}
+	if err := s.audit.Record(ctx, AuditAMLDisposition, "aml_alert", id, before, alert); err != nil {
+		return nil, err
+	}
+	s.alerts.Save(ctx, alert)
+	return alert, nil
+}
+
+// Report builds a suspicious activity report from the alerts dispositioned
+// as suspicious between from and to; zero times leave that end open. Each
+// account lists its alerts and the movements still on record in their
+// windows.
+func (s *AMLService) Report(ctx context.Context, from, to time.Time) (_ *dto.SuspiciousActivityReport, err error) {
+	ctx, span := tracing.Start(ctx, "AMLService.Report")
+	defer span.Finish(&err)
+
+	report := &dto.SuspiciousActivityReport{
+		Format:      dto.SARFormat,
+		ReportID:    uuid.New().String(),
+		GeneratedAt: s.now(),
+		Subjects:    make([]dto.SARSubject, 0),
+	}
+	if !from.IsZero() {
+		report.From = &from
+	}
+	if !to.IsZero() {
+		report.To = &to
+	}
+
+	subjects := make(map[string]int)
+	for _, alert := range s.alerts.Find(ctx, domain.AlertSuspicious, "", "") {
+		at := *alert.DispositionedAt
+		if (!from.IsZero() && at.Before(from)) || (!to.IsZero() && !at.Before(to)) {
+			continue
+		}
+		i, seen := subjects[alert.AccountID]
+		if !seen {
+			subject := dto.SARSubject{AccountID: alert.AccountID, Activity: make([]dto.SARMovement, 0)}
+			// The account may have been removed since; the report keeps
+			// the alert without the holder's document.
+			if account, err := s.accounts.GetAccount(ctx, alert.AccountID); err == nil {
+				subject.DocumentNumber = account.DocumentNumber
+			}
+			i = len(report.Subjects)
+			subjects[alert.AccountID] = i
+			report.Subjects = append(report.Subjects, subject)
+		}
+		report.Subjects[i].Alerts = append(report.Subjects[i].Alerts, dto.SARAlert{
+			AlertID:         alert.ID,
+			Rule:            alert.Rule,
+			Amount:          dto.Money(alert.Amount),
+			Count:           alert.Count,
+			WindowStart:     alert.WindowStart,
+			WindowEnd:       alert.WindowEnd,
+			Details:         alert.Details,
+			DispositionedAt: at,
+			DispositionedBy: alert.DispositionedBy,
+			Note:            alert.Note,
+		})
+	}
+
+	for i := range report.Subjects {
+		subject := &report.Subjects[i]
+		for _, movement := range s.history.Find(ctx, subject.AccountID).Movements {
+			if slices.ContainsFunc(subject.Alerts, func(a dto.SARAlert) bool {
+				return !movement.At.Before(a.WindowStart) && !movement.At.After(a.WindowEnd)
+			}) {
+				subject.Activity = append(subject.Activity, dto.SARMovement{At: movement.At, Kind: movement.Kind, Amount: dto.Money(movement.Amount)})
+			}
+		}
+	}
+	return report, nil
+}
+
+// SetRules replaces the monitoring rules while serving.
+func (s *AMLService) SetRules(rules aml.Rules) {
+	s.monitor.SetRules(rules)
+}
+
+// Forget drops an account's history and alerts, for resets.
+func (s *AMLService) Forget(ctx context.Context, accountID string) {
+	s.history.Delete(ctx, accountID)
+	s.alerts.DeleteByAccount(ctx, accountID)
+}
+
+func (s *AMLService) Reset() {
+	s.history.Reset()
+	s.alerts.Reset()
+}
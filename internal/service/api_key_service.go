@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+const apiKeyPrefix = "cbk_"
+
+type APIKeyService struct {
+	repo  *repository.APIKeyRepository
+	audit *AuditService
+	now   func() time.Time
+}
+
+func NewAPIKeyService(repo *repository.APIKeyRepository, audit *AuditService) *APIKeyService {
+	return &APIKeyService{repo: repo, audit: audit, now: time.Now}
+}
+
+// HashAPIKeySecret is the hash stored for a key secret, the part after the
+// "." in cbk_<id>.<secret>.
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue creates a key and returns its plaintext form, which is never stored
+// and cannot be recovered later. tier picks the key's rate limits.
+func (s *APIKeyService) Issue(ctx context.Context, subject string, roles []string, tier string) (string, *domain.APIKey, error) {
+	id, secret, err := newAPIKeyParts()
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := &domain.APIKey{
+		ID:        id,
+		Hash:      HashAPIKeySecret(secret),
+		Subject:   subject,
+		Roles:     roles,
+		Tier:      tier,
+		CreatedAt: s.now(),
+	}
+	if err := s.audit.Record(ctx, AuditAPIKeyIssue, "apikey", id, nil, dto.NewAPIKeyResponse(key, "")); err != nil {
+		return "", nil, err
+	}
+	if _, err := s.repo.Save(ctx, key); err != nil {
+		return "", nil, err
+	}
+
+	return formatAPIKey(id, secret), key, nil
+}
+
+// Rotate replaces the secret of a key. The previous secret keeps working for
+// the grace period.
+func (s *APIKeyService) Rotate(ctx context.Context, id string, grace time.Duration) (string, *domain.APIKey, error) {
+	_, secret, err := newAPIKeyParts()
+	if err != nil {
+		return "", nil, err
+	}
+
+	key, err := s.repo.Update(ctx, id, func(key *domain.APIKey) error {
+		if key == nil || key.IsRevoked() {
+			return ErrAPIKeyNotFound
+		}
+		before := dto.NewAPIKeyResponse(key, "")
+		now := s.now()
+		key.PreviousHash = key.Hash
+		key.PreviousExpiresAt = now.Add(grace)
+		key.Hash = HashAPIKeySecret(secret)
+		key.RotatedAt = now
+		return s.audit.Record(ctx, AuditAPIKeyRotate, "apikey", id, before, dto.NewAPIKeyResponse(key, ""))
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return formatAPIKey(id, secret), key, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id string) error {
+	_, err := s.repo.Update(ctx, id, func(key *domain.APIKey) error {
+		if key == nil {
+			return ErrAPIKeyNotFound
+		}
+		before := dto.NewAPIKeyResponse(key, "")
+		revokedAt := s.now()
+		key.RevokedAt = &revokedAt
+		return s.audit.Record(ctx, AuditAPIKeyRevoke, "apikey", id, before, dto.NewAPIKeyResponse(key, ""))
+	})
+	return err
+}
+
+func (s *APIKeyService) ListKeys(ctx context.Context) []*domain.APIKey {
+	return s.repo.FindAll()
+}
+
+func (s *APIKeyService) VerifyAPIKey(raw string) (*auth.Principal, error) {
+	id, secret, found := strings.Cut(strings.TrimPrefix(raw, apiKeyPrefix), ".")
+	if !found || !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	key, exists := s.repo.FindByID(id)
+	if !exists || key.IsRevoked() {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	hash := HashAPIKeySecret(secret)
+	valid := hashEqual(hash, key.Hash) ||
+		(key.PreviousHash != "" && s.now().Before(key.PreviousExpiresAt) && hashEqual(hash, key.PreviousHash))
+	if !valid {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	return &auth.Principal{
+		ID:      key.ID,
+		Subject: key.Subject,
+		Roles:   key.Roles,
+		Method:  auth.MethodAPIKey,
+		Tier:    key.Tier,
+	}, nil
+}
+
+func hashEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func formatAPIKey(id, secret string) string {
+	return apiKeyPrefix + id + "." + secret
+}
+
+func newAPIKeyParts() (id, secret string, err error) {
+	buf := make([]byte, 40)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(buf[:8]), base64.RawURLEncoding.EncodeToString(buf[8:]), nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/repository"
+	"corebanking/internal/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const systemActor = "system"
+
+const (
+	AuditAccountCreate    = "account.create"
+	AuditAccountOverdraft = "account.overdraft"
+	AuditAccountLimits    = "account.limits"
+	AuditAccountBalance   = "account.balance"
+	AuditFraudReview      = "fraud.review"
+	AuditFraudDecide      = "fraud.decide"
+	AuditAMLAlert         = "aml.alert"
+	AuditAMLDisposition   = "aml.disposition"
+	AuditSanctionsScreen  = "sanctions.screen"
+	AuditSanctionsHold    = "sanctions.hold"
+	AuditSanctionsDecide  = "sanctions.decide"
+	AuditSanctionsList    = "sanctions.list"
+	AuditSuspenseHold     = "suspense.hold"
+	AuditSuspenseClaim    = "suspense.claim"
+	AuditSuspenseReturn   = "suspense.return"
+	AuditScheduleCreate   = "schedule.create"
+	AuditSchedulePause    = "schedule.pause"
+	AuditScheduleResume   = "schedule.resume"
+	AuditScheduleCancel   = "schedule.cancel"
+	AuditBatchRollback    = "batch.rollback"
+	AuditSystemReset      = "system.reset"
+	AuditAccountReset     = "account.reset"
+	AuditAPIKeyIssue      = "apikey.issue"
+	AuditAPIKeyRotate     = "apikey.rotate"
+	AuditAPIKeyRevoke     = "apikey.revoke"
+)
+
+type AuditService struct {
+	repo *repository.AuditRepository
+	now  func() time.Time
+}
+
+func NewAuditService(repo *repository.AuditRepository) *AuditService {
+	return &AuditService{repo: repo, now: time.Now}
+}
+
+// AuditChange is one entity's side of a change recorded with RecordAll.
+type AuditChange struct {
+	EntityID string
+	Before   any
+	After    any
+}
+
+// Record appends an entry for a state change. The actor comes from the
+// principal in ctx ("system" without one) and before/after are stored as
+// JSON snapshots; nil means the entity did not exist on that side.
+func (s *AuditService) Record(ctx context.Context, action, entityType, entityID string, before, after any) error {
+	return s.RecordAll(ctx, action, entityType, AuditChange{EntityID: entityID, Before: before, After: after})
+}
+
+// RecordAll appends an entry per change like Record, all or none, for a
+// change made to several entities at once such as both sides of a transfer.
+func (s *AuditService) RecordAll(ctx context.Context, action, entityType string, changes ...AuditChange) error {
+	timestamp := s.now().UTC()
+	entries := make([]*domain.AuditEntry, len(changes))
+	for i, change := range changes {
+		entry := &domain.AuditEntry{
+			Timestamp:  timestamp,
+			Actor:      systemActor,
+			Action:     action,
+			EntityType: entityType,
+			EntityID:   change.EntityID,
+			RequestID:  utils.RequestIDFrom(ctx),
+		}
+		if principal, ok := auth.PrincipalFrom(ctx); ok {
+			entry.Actor = principal.Subject
+			entry.ActorRoles = principal.Roles
+		}
+
+		var err error
+		if entry.Before, err = snapshot(change.Before); err != nil {
+			return err
+		}
+		if entry.After, err = snapshot(change.After); err != nil {
+			return err
+		}
+		entries[i] = entry
+	}
+
+	return s.repo.AppendAll(entries, HashAuditEntry)
+}
+
+func (s *AuditService) Query(ctx context.Context, actor, entityType, entityID string, begin, end time.Time) []*domain.AuditEntry {
+	return s.repo.FindFiltered(actor, entityType, entityID, begin, end)
+}
+
+// Verify recomputes the hash chain and reports the first entry that does not
+// match, which means the log was edited, reordered or truncated in the middle.
+func (s *AuditService) Verify() error {
+	prevHash := ""
+	for i, entry := range s.repo.FindAll() {
+		if entry.Sequence != int64(i)+1 || entry.PrevHash != prevHash || entry.Hash != HashAuditEntry(entry) {
+			return fmt.Errorf("%w at sequence %d", ErrAuditChainBroken, entry.Sequence)
+		}
+		prevHash = entry.Hash
+	}
+	return nil
+}
+
+// HashAuditEntry hashes the entry's JSON encoding with Hash left empty.
+func HashAuditEntry(entry *domain.AuditEntry) string {
+	unsealed := *entry
+	unsealed.Hash = ""
+	data, _ := json.Marshal(unsealed)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func snapshot(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/metrics"
+	"corebanking/internal/repository"
+	"corebanking/internal/tracing"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchPolicy is how batches are taken and processed.
+type BatchPolicy struct {
+	// MaxItems caps the items in one batch.
+	MaxItems int
+	// AsyncThreshold is the size above which a batch is processed in the
+	// background, after the request is answered. QueueSize background
+	// batches may wait at once.
+	AsyncThreshold int
+	QueueSize      int
+	// Workers is how many best-effort items are posted at once. Items
+	// sharing an account are still posted one at a time, in batch order.
+	Workers int
+	// Retention is how long processed batches can be read back.
+	Retention time.Duration
+}
+
+// queuedBatch is a batch waiting for the background worker and who
+// submitted it, for the audit log.
+type queuedBatch struct {
+	id        string
+	principal *auth.Principal
+}
+
+// suspenseGroup stands for the suspense account when batch items are
+// grouped by account: every item to an unknown destination may credit it.
+const suspenseGroup = "\x00suspense"
+
+// BatchService posts many transactions and events in one request, far
+// cheaper than one request each. Small batches are processed while the
+// request waits, large ones in the background.
+type BatchService struct {
+	batches      *repository.BatchRepository
+	accounts     *repository.AccountRepository
+	transactions *TransactionService
+	policy       BatchPolicy
+	queue        chan queuedBatch
+	now          func() time.Time
+}
+
+func NewBatchService(batches *repository.BatchRepository, accounts *repository.AccountRepository, transactions *TransactionService, policy BatchPolicy) *BatchService {
+	return NewBatchServiceWithClock(batches, accounts, transactions, policy, time.Now)
+}
+
+// NewBatchServiceWithClock lets tests control time.
+func NewBatchServiceWithClock(batches *repository.BatchRepository, accounts *repository.AccountRepository, transactions *TransactionService, policy BatchPolicy, now func() time.Time) *BatchService {
+	return &BatchService{
+		batches:      batches,
+		accounts:     accounts,
+		transactions: transactions,
+		policy:       policy,
+		queue:        make(chan queuedBatch, policy.QueueSize),
+		now:          now,
+	}
+}
+
+// Submit takes a batch of items in mode, all_or_nothing when empty. It is
+// processed before Submit returns unless async is set or it is larger than
+// the policy's AsyncThreshold; then it is returned pending, for Run to
+// process, or refused with ErrBatchQueueFull when too many are waiting.
+func (s *BatchService) Submit(ctx context.Context, mode string, items []domain.BatchItem, async bool) (_ *domain.Batch, err error) {
+	ctx, span := tracing.Start(ctx, "BatchService.Submit", tracing.String("batch.mode", mode), tracing.Int("batch.items", len(items)))
+	defer span.Finish(&err)
+
+	if mode == "" {
+		mode = domain.BatchAllOrNothing
+	}
+	if err := s.validate(mode, items); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	s.batches.DeleteCompletedBefore(ctx, now.Add(-s.policy.Retention))
+	batch := &domain.Batch{
+		ID:        uuid.New().String(),
+		Mode:      mode,
+		Status:    domain.BatchPending,
+		Items:     items,
+		Results:   make([]domain.BatchItemResult, len(items)),
+		CreatedAt: now,
+	}
+	for i := range batch.Results {
+		batch.Results[i].Status = domain.BatchItemPending
+	}
+	principal, _ := auth.PrincipalFrom(ctx)
+	if principal != nil {
+		batch.CreatedBy = principal.Subject
+	}
+	s.batches.Save(ctx, batch)
+
+	if !async && len(items) <= s.policy.AsyncThreshold {
+		s.process(ctx, batch)
+		return batch, nil
+	}
+	select {
+	case s.queue <- queuedBatch{id: batch.ID, principal: principal}:
+		return batch, nil
+	default:
+		s.batches.Delete(ctx, batch.ID)
+		return nil, ErrBatchQueueFull
+	}
+}
+
+func (s *BatchService) validate(mode string, items []domain.BatchItem) error {
+	switch {
+	case mode != domain.BatchAllOrNothing && mode != domain.BatchBestEffort:
+		return fmt.Errorf("%w: mode must be all_or_nothing or best_effort", ErrInvalidBatch)
+	case len(items) == 0:
+		return fmt.Errorf("%w: items must not be empty", ErrInvalidBatch)
+	case len(items) > s.policy.MaxItems:
+		return fmt.Errorf("%w: at most %d items", ErrInvalidBatch, s.policy.MaxItems)
+	}
+	for i, item := range items {
+		if err := validateBatchItem(item); err != nil {
+			return fmt.Errorf("%w: item %d: %s", ErrInvalidBatch, i, err)
+		}
+	}
+	return nil
+}
+
+func validateBatchItem(item domain.BatchItem) error {
+	switch {
+	case item.Amount <= 0:
+		return ErrInvalidAmount
+	case item.Type == domain.BatchItemTransaction && item.AccountID == "":
+		return errors.New("accountId is required")
+	case item.Type == domain.BatchItemTransaction && !validOperationType(item.OperationTypeID):
+		return ErrInvalidOperationType
+	case (item.Type == domain.BatchItemWithdraw || item.Type == domain.BatchItemTransfer) && item.Origin == "":
+		return errors.New("origin is required")
+	case (item.Type == domain.BatchItemDeposit || item.Type == domain.BatchItemTransfer) && item.Destination == "":
+		return errors.New("destination is required")
+	case item.Type == domain.BatchItemTransfer && item.Origin == item.Destination:
+		return errors.New("destination must differ from the origin")
+	}
+	switch item.Type {
+	case domain.BatchItemTransaction, domain.BatchItemDeposit, domain.BatchItemWithdraw, domain.BatchItemTransfer:
+		return nil
+	default:
+		return errors.New("type must be transaction, deposit, withdraw or transfer")
+	}
+}
+
+// Batch returns a batch and the results of the items processed so far.
+func (s *BatchService) Batch(ctx context.Context, id string) (*domain.Batch, error) {
+	batch, exists := s.batches.FindByID(ctx, id)
+	if !exists {
+		return nil, ErrBatchNotFound
+	}
+	return batch, nil
+}
+
+// Run processes the batches Submit left for the background, one at a
+// time, until ctx is done. Batches still waiting then are lost and stay
+// pending.
+func (s *BatchService) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case queued := <-s.queue:
+			batch, exists := s.batches.FindByID(ctx, queued.id)
+			if !exists {
+				continue
+			}
+			// A batch is posted to the end once started, so a shutdown
+			// waits for it rather than leave it half posted.
+			batchCtx := context.WithoutCancel(ctx)
+			if queued.principal != nil {
+				batchCtx = auth.WithPrincipal(batchCtx, queued.principal)
+			}
+			s.process(batchCtx, batch)
+		}
+	}
+}
+
+// process posts a batch's items as its mode says and stores the results,
+// each as soon as it is known.
+func (s *BatchService) process(ctx context.Context, batch *domain.Batch) {
+	ctx, span := tracing.Start(ctx, "BatchService.process", tracing.String("batch.id", batch.ID), tracing.String("batch.mode", batch.Mode))
+	defer span.End()
+
+	batch.Status = domain.BatchProcessing
+	s.batches.Save(ctx, batch)
+	if batch.Mode == domain.BatchAllOrNothing {
+		s.postAll(ctx, batch)
+	} else {
+		s.postEach(ctx, batch)
+	}
+	now := s.now()
+	batch.CompletedAt = &now
+	s.batches.Save(ctx, batch)
+	for _, result := range batch.Results {
+		metrics.BatchItems.Inc(batch.Mode, result.Status)
+	}
+}
+
+// postAll posts the items in order until one fails or is held, then undoes
+// that hold and the items posted before it, latest first, and skips the
+// rest. Items it can't undo stay posted and the batch error says why. The
+// batch's accounts stay locked throughout, so no other posting sees or
+// spends what a reversal takes back, and its transactions reach watchers
+// only once they are known to stay posted.
+func (s *BatchService) postAll(ctx context.Context, batch *domain.Batch) {
+	ctx, unlock := s.transactions.lockBatch(ctx, batch.Items)
+	defer unlock()
+	ctx, held := s.transactions.holdFeed(ctx)
+	defer s.transactions.releaseFeed(held, func(item int) bool {
+		status := batch.Results[item].Status
+		return status == domain.BatchItemPosted || status == domain.BatchItemHeld
+	})
+
+	postings := make([]*batchPosting, len(batch.Items))
+	failed := -1
+	for i, item := range batch.Items {
+		held.posting(i)
+		posting, err := s.transactions.postBatchItem(ctx, item)
+		postings[i] = posting
+		if err == nil {
+			s.settle(ctx, batch, i, domain.BatchItemResult{Status: domain.BatchItemPosted, TransactionID: posting.transactionID})
+			continue
+		}
+		failed = i
+		s.settle(ctx, batch, i, domain.BatchItemResult{Status: domain.BatchItemFailed, Err: err})
+		break
+	}
+	if failed < 0 {
+		batch.Status = domain.BatchCompleted
+		return
+	}
+
+	// Reversals only undo what watchers never saw.
+	held.posting(-1)
+	var errs []error
+	if failure := batch.Results[failed].Err; errors.As(failure, new(*HoldError)) {
+		if err := s.transactions.undoBatchItem(ctx, postings[failed], failure); err != nil {
+			batch.Results[failed].Status = domain.BatchItemHeld
+			errs = append(errs, fmt.Errorf("item %d: %w", failed, err))
+		}
+	}
+	for i := failed + 1; i < len(batch.Items); i++ {
+		batch.Results[i].Status = domain.BatchItemSkipped
+	}
+	for i := failed - 1; i >= 0; i-- {
+		if err := s.transactions.undoBatchItem(ctx, postings[i], nil); err != nil {
+			errs = append(errs, fmt.Errorf("item %d: %w", i, err))
+			continue
+		}
+		s.settle(ctx, batch, i, domain.BatchItemResult{Status: domain.BatchItemRolledBack})
+	}
+	batch.Status = domain.BatchFailed
+	if err := errors.Join(errs...); err != nil {
+		batch.Error = err.Error()
+	}
+}
+
+// postEach posts every item whatever becomes of the others. Items sharing
+// no account are independent, so groups of items linked by their accounts
+// are posted by the policy's workers at once, each group in batch order.
+func (s *BatchService) postEach(ctx context.Context, batch *domain.Batch) {
+	groups := s.group(ctx, batch.Items)
+	work := make(chan []int)
+	var wg sync.WaitGroup
+	for range max(min(s.policy.Workers, len(groups)), 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range work {
+				for _, i := range group {
+					s.settle(ctx, batch, i, s.postOne(ctx, batch.Items[i]))
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		work <- group
+	}
+	close(work)
+	wg.Wait()
+	batch.Status = domain.BatchCompleted
+}
+
+// settle sets the result of item i and stores it, so the batch can be
+// read with its progress while it is processed.
+func (s *BatchService) settle(ctx context.Context, batch *domain.Batch, i int, result domain.BatchItemResult) {
+	batch.Results[i] = result
+	s.batches.SaveResult(ctx, batch.ID, i, result)
+}
+
+func (s *BatchService) postOne(ctx context.Context, item domain.BatchItem) domain.BatchItemResult {
+	posting, err := s.transactions.postBatchItem(ctx, item)
+	switch {
+	case err == nil:
+		return domain.BatchItemResult{Status: domain.BatchItemPosted, TransactionID: posting.transactionID}
+	case errors.As(err, new(*HoldError)):
+		return domain.BatchItemResult{Status: domain.BatchItemHeld, Err: err}
+	default:
+		return domain.BatchItemResult{Status: domain.BatchItemFailed, Err: err}
+	}
+}
+
+// group splits item indexes into groups no two of which touch the same
+// account, keeping batch order within each group.
+func (s *BatchService) group(ctx context.Context, items []domain.BatchItem) [][]int {
+	parent := make([]int, len(items))
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	first := make(map[string]int)
+	for i, item := range items {
+		parent[i] = i
+		for _, accountID := range s.touches(ctx, item) {
+			if j, seen := first[accountID]; seen {
+				parent[find(i)] = find(j)
+				continue
+			}
+			first[accountID] = i
+		}
+	}
+
+	index := make(map[int]int)
+	groups := make([][]int, 0)
+	for i := range items {
+		root := find(i)
+		g, exists := index[root]
+		if !exists {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// touches returns the accounts an item may change, with suspenseGroup for
+// a destination that is not a customer account.
+func (s *BatchService) touches(ctx context.Context, item domain.BatchItem) []string {
+	accounts := item.Accounts()
+	if item.Type == domain.BatchItemDeposit || item.Type == domain.BatchItemTransfer {
+		last := len(accounts) - 1
+		if account, exists := s.accounts.FindById(ctx, accounts[last]); !exists || account.Internal {
+			accounts[last] = suspenseGroup
+		}
+	}
+	return accounts
+}
+
+// Reset drops every batch.
+func (s *BatchService) Reset() {
+	s.batches.Reset()
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by the services. Transports (HTTP, gRPC) map
+// them to their own status codes with errors.Is.
+var (
+	ErrAccountNotFound       = errors.New("account not found")
+	ErrOriginNotFound        = errors.New("origin account not found")
+	ErrDestinationNotFound   = errors.New("destination account not found")
+	ErrTransactionNotFound   = errors.New("transaction not found")
+	ErrDocumentAlreadyExists = errors.New("document already has an account")
+	ErrInsufficientFunds     = errors.New("insufficient funds for transaction")
+	ErrInsufficientOverdraft = errors.New("insufficient funds, including overdraft")
+	ErrInvalidEventType      = errors.New("invalid event type")
+	ErrInvalidOperationType  = errors.New("operation type doesn't exist")
+	ErrInvalidAmount         = errors.New("amount must be positive")
+	ErrAPIKeyNotFound        = errors.New("api key not found")
+	ErrAuditChainBroken      = errors.New("audit chain broken")
+	ErrResetDisabled         = errors.New("reset is disabled outside sandbox mode")
+	ErrResetNotConfirmed     = errors.New("reset confirmation token missing or invalid")
+	ErrWatchLagged           = errors.New("transaction watch fell behind, watch again")
+	ErrUnknownProduct        = errors.New("unknown product")
+	ErrInvalidLimits         = errors.New("limits must not be negative")
+	ErrFraudReview           = errors.New("transaction held for fraud review")
+	ErrFraudDenied           = errors.New("transaction declined by fraud screening")
+	ErrFraudCaseNotFound     = errors.New("fraud case not found")
+	ErrFraudCaseDecided      = errors.New("fraud case already decided")
+	ErrAMLAlertNotFound      = errors.New("aml alert not found")
+	ErrAMLAlertClosed        = errors.New("aml alert already dispositioned")
+	ErrInvalidDisposition    = errors.New("disposition must be false_positive, no_action or suspicious")
+	ErrSanctionsReview       = errors.New("held for sanctions review")
+	ErrSanctionsCaseNotFound = errors.New("sanctions case not found")
+	ErrSanctionsCaseDecided  = errors.New("sanctions case already decided")
+	ErrHeldInSuspense        = errors.New("destination account not found, funds held in suspense")
+	ErrSuspenseItemNotFound  = errors.New("suspense item not found")
+	ErrSuspenseItemResolved  = errors.New("suspense item already claimed or returned")
+	ErrScheduleNotFound      = errors.New("schedule not found")
+	ErrScheduleFinished      = errors.New("schedule already cancelled or completed")
+	ErrInvalidSchedule       = errors.New("invalid schedule")
+	ErrBatchNotFound         = errors.New("batch not found")
+	ErrInvalidBatch          = errors.New("invalid batch")
+	ErrBatchQueueFull        = errors.New("too many batches waiting, try again later")
+)
+
+// ErrLimitExceeded is wrapped by the error for each limit, so callers can
+// match any limit or a specific one.
+var (
+	ErrLimitExceeded          = errors.New("transaction limit exceeded")
+	ErrTransactionAmountLimit = fmt.Errorf("%w: amount above the per-transaction limit", ErrLimitExceeded)
+	ErrDailyDebitLimit        = fmt.Errorf("%w: daily debit limit reached", ErrLimitExceeded)
+	ErrMonthlyDebitLimit      = fmt.Errorf("%w: monthly debit limit reached", ErrLimitExceeded)
+	ErrWithdrawalCountLimit   = fmt.Errorf("%w: daily withdrawal count reached", ErrLimitExceeded)
+	ErrNightTransferLimit     = fmt.Errorf("%w: night transfer limit reached", ErrLimitExceeded)
+)
+
+// HoldError is a posting held rather than made: for review, wrapping
+// ErrFraudReview or ErrSanctionsReview with the case holding it, or in
+// suspense, wrapping ErrHeldInSuspense with the suspense item.
+type HoldError struct {
+	Err error
+	ID  string
+}
+
+func (e *HoldError) Error() string {
+	if e.Err == ErrHeldInSuspense {
+		return fmt.Sprintf("%v as item %s", e.Err, e.ID)
+	}
+	return fmt.Sprintf("%v in case %s", e.Err, e.ID)
+}
+
+func (e *HoldError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/fraud"
+	"corebanking/internal/metrics"
+	"corebanking/internal/repository"
+	"corebanking/internal/tracing"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fraudHistoryRetention is how far back fraud rules see an account's
+// postings.
+const fraudHistoryRetention = 90 * 24 * time.Hour
+
+// Screening is a posting to screen, with what is needed to post it later
+// if it is held.
+type Screening struct {
+	Account         *domain.Account
+	Kind            string
+	OperationTypeID int
+	Destination     string
+	Amount          int64
+}
+
+// FraudService screens debits with the fraud rules and keeps the case
+// queue of debits held for review.
+type FraudService struct {
+	engine  *fraud.Engine
+	history *repository.FraudHistoryRepository
+	cases   *repository.FraudCaseRepository
+	audit   *AuditService
+	now     func() time.Time
+	// post posts an approved case, after lock has locked the accounts it
+	// may change; both set by TransactionService.SetFraudService.
+	post func(context.Context, *domain.FraudCase) error
+	lock func(ctx context.Context, origin, destination string) (context.Context, func())
+}
+
+func NewFraudService(engine *fraud.Engine, history *repository.FraudHistoryRepository, cases *repository.FraudCaseRepository, audit *AuditService) *FraudService {
+	return NewFraudServiceWithClock(engine, history, cases, audit, time.Now)
+}
+
+// NewFraudServiceWithClock lets tests control time.
+func NewFraudServiceWithClock(engine *fraud.Engine, history *repository.FraudHistoryRepository, cases *repository.FraudCaseRepository, audit *AuditService, now func() time.Time) *FraudService {
+	return &FraudService{engine: engine, history: history, cases: cases, audit: audit, now: now}
+}
+
+type fraudApprovedKey struct{}
+
+// Screen evaluates a debit. Allowed debits return nil, held ones
+// ErrFraudReview naming the new case and declined ones ErrFraudDenied with
+// the reasons. Debits posted from an approved case are not screened again.
+func (s *FraudService) Screen(ctx context.Context, posting Screening) (err error) {
+	if ctx.Value(fraudApprovedKey{}) != nil {
+		return nil
+	}
+	ctx, span := tracing.Start(ctx, "FraudService.Screen", tracing.String("account.id", posting.Account.ID), tracing.Int64("amount", posting.Amount))
+	defer span.Finish(&err)
+
+	now := s.now()
+	verdict := s.engine.Evaluate(fraud.Input{
+		AccountID:   posting.Account.ID,
+		Kind:        posting.Kind,
+		Amount:      posting.Amount,
+		Destination: posting.Destination,
+		Balance:     posting.Account.Balance,
+		At:          now,
+	}, s.history.Find(ctx, posting.Account.ID))
+	metrics.FraudDecisions.Inc(string(verdict.Decision))
+
+	switch verdict.Decision {
+	case fraud.Deny:
+		return fmt.Errorf("%w: %s", ErrFraudDenied, strings.Join(verdict.Reasons, "; "))
+	case fraud.Review:
+		fraudCase := &domain.FraudCase{
+			ID:              uuid.New().String(),
+			Status:          domain.FraudCasePending,
+			AccountID:       posting.Account.ID,
+			Kind:            posting.Kind,
+			OperationTypeID: posting.OperationTypeID,
+			Destination:     posting.Destination,
+			Amount:          posting.Amount,
+			Score:           verdict.Score,
+			Reasons:         verdict.Reasons,
+			CreatedAt:       now,
+		}
+		if err := s.audit.Record(ctx, AuditFraudReview, "fraud_case", fraudCase.ID, nil, fraudCase); err != nil {
+			return err
+		}
+		s.cases.Save(ctx, fraudCase)
+		return &HoldError{Err: ErrFraudReview, ID: fraudCase.ID}
+	default:
+		return nil
+	}
+}
+
+// Record adds a posting to the account's history once it is posted.
+func (s *FraudService) Record(ctx context.Context, accountID, kind, destination string, amount int64) {
+	s.history.Add(ctx, accountID, domain.FraudActivity{
+		At:          s.now(),
+		Kind:        kind,
+		Amount:      amount,
+		Destination: destination,
+	}, fraudHistoryRetention)
+}
+
+// Cases lists cases with status, every case when it is empty.
+func (s *FraudService) Cases(ctx context.Context, status string) []*domain.FraudCase {
+	ctx, span := tracing.Start(ctx, "FraudService.Cases", tracing.String("fraud_case.status", status))
+	defer span.End()
+
+	return s.cases.FindByStatus(ctx, status)
+}
+
+func (s *FraudService) Case(ctx context.Context, id string) (*domain.FraudCase, error) {
+	fraudCase, exists := s.cases.FindByID(ctx, id)
+	if !exists {
+		return nil, ErrFraudCaseNotFound
+	}
+	return fraudCase, nil
+}
+
+// Approve posts a pending case's debit, checking funds and limits again,
+// and closes the case. When the debit fails the case stays pending.
+func (s *FraudService) Approve(ctx context.Context, id, note string) (*domain.FraudCase, error) {
+	return s.decide(ctx, id, domain.FraudCaseApproved, note)
+}
+
+// Reject closes a pending case without posting its debit.
+func (s *FraudService) Reject(ctx context.Context, id, note string) (*domain.FraudCase, error) {
+	return s.decide(ctx, id, domain.FraudCaseRejected, note)
+}
+
+func (s *FraudService) decide(ctx context.Context, id, status, note string) (_ *domain.FraudCase, err error) {
+	ctx, span := tracing.Start(ctx, "FraudService.decide", tracing.String("fraud_case.id", id), tracing.String("fraud_case.status", status))
+	defer span.Finish(&err)
+
+	// The accounts are locked before the case, as postings lock them
+	// before opening cases.
+	if pending, exists := s.cases.FindByID(ctx, id); exists && s.lock != nil {
+		var unlock func()
+		ctx, unlock = s.lock(ctx, pending.AccountID, pending.Destination)
+		defer unlock()
+	}
+
+	var before domain.FraudCase
+	decided, exists, err := s.cases.Update(ctx, id, func(fraudCase *domain.FraudCase) error {
+		if fraudCase.Status != domain.FraudCasePending {
+			return ErrFraudCaseDecided
+		}
+		before = *fraudCase
+		if status == domain.FraudCaseApproved {
+			if err := s.post(context.WithValue(ctx, fraudApprovedKey{}, id), fraudCase); err != nil {
+				return err
+			}
+		}
+
+		now := s.now()
+		fraudCase.Status = status
+		fraudCase.DecidedAt = &now
+		fraudCase.Note = note
+		if principal, ok := auth.PrincipalFrom(ctx); ok {
+			fraudCase.DecidedBy = principal.Subject
+		}
+		return nil
+	})
+	if !exists {
+		return nil, ErrFraudCaseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Record(ctx, AuditFraudDecide, "fraud_case", id, before, decided); err != nil {
+		return nil, err
+	}
+	return decided, nil
+}
+
+// SetRules replaces the fraud rules while serving.
+func (s *FraudService) SetRules(rules fraud.Rules) {
+	s.engine.SetRules(rules)
+}
+
+// Forget drops an account's history and cases, for resets.
+func (s *FraudService) Forget(ctx context.Context, accountID string) {
+	s.history.Delete(ctx, accountID)
+	s.cases.DeleteByAccount(ctx, accountID)
+}
+
+func (s *FraudService) Reset() {
+	s.history.Reset()
+	s.cases.Reset()
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/repository"
+	"corebanking/internal/tracing"
+	"sync/atomic"
+	"time"
+)
+
+// Debit kinds count toward different limits: every debit toward the
+// amount, daily and monthly caps, withdrawals toward the withdrawal count
+// and transfers toward the night cap.
+type DebitKind int
+
+const (
+	DebitPurchase DebitKind = iota + 1
+	DebitWithdrawal
+	DebitTransfer
+)
+
+// LimitPolicy is the configured part of the limits.
+type LimitPolicy struct {
+	// Products maps product names to their limits. Accounts without a
+	// product use DefaultProduct.
+	Products       map[string]domain.Limits
+	DefaultProduct string
+	// NightStart and NightEnd are offsets from midnight; the window wraps
+	// past midnight when NightEnd is before NightStart.
+	NightStart time.Duration
+	NightEnd   time.Duration
+	// Location sets where days, months and nights begin.
+	Location *time.Location
+}
+
+// LimitService enforces per-account and per-product limits on debits.
+type LimitService struct {
+	usage    *repository.LimitUsageRepository
+	accounts *repository.AccountRepository
+	audit    *AuditService
+	locks    *accountLocks
+	policy   atomic.Pointer[LimitPolicy]
+	now      func() time.Time
+}
+
+func NewLimitService(usage *repository.LimitUsageRepository, accounts *repository.AccountRepository, audit *AuditService, policy LimitPolicy) *LimitService {
+	return NewLimitServiceWithClock(usage, accounts, audit, policy, time.Now)
+}
+
+// NewLimitServiceWithClock lets tests control time.
+func NewLimitServiceWithClock(usage *repository.LimitUsageRepository, accounts *repository.AccountRepository, audit *AuditService, policy LimitPolicy, now func() time.Time) *LimitService {
+	s := &LimitService{usage: usage, accounts: accounts, audit: audit, locks: newAccountLocks(accounts), now: now}
+	s.SetPolicy(policy)
+	return s
+}
+
+// SetPolicy replaces the product limits and night window while serving.
+// Usage already recorded counts against the new limits.
+func (s *LimitService) SetPolicy(policy LimitPolicy) {
+	if policy.Location == nil {
+		policy.Location = time.Local
+	}
+	s.policy.Store(&policy)
+}
+
+// effective returns the account's product and the limits that apply.
+func (s *LimitService) effective(policy *LimitPolicy, account *domain.Account) (string, domain.Limits) {
+	product := account.Product
+	if product == "" {
+		product = policy.DefaultProduct
+	}
+	if account.Limits != nil {
+		return product, *account.Limits
+	}
+	return product, policy.Products[product]
+}
+
+// periods returns the keys of the day, month and night window containing
+// t; night is empty outside the window.
+func (p *LimitPolicy) periods(t time.Time) (day, month, night string) {
+	t = t.In(p.Location)
+	day, month = t.Format("2006-01-02"), t.Format("2006-01")
+	if p.NightStart == p.NightEnd {
+		return day, month, ""
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, p.Location)
+	offset := t.Sub(midnight)
+	switch {
+	case p.NightStart < p.NightEnd:
+		if offset >= p.NightStart && offset < p.NightEnd {
+			night = day
+		}
+	case offset >= p.NightStart:
+		night = day
+	case offset < p.NightEnd:
+		// The window began the evening before.
+		night = midnight.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	return day, month, night
+}
+
+// Reservation is a debit Reserve recorded and the day, month and night
+// window it counts in, for Release to give back.
+type Reservation struct {
+	AccountID string
+	Kind      DebitKind
+	Amount    int64
+	Day       string
+	Month     string
+	Night     string
+}
+
+// Reserve checks a debit against the account's limits and, when it fits,
+// records it. The caller must post the debit after a nil error. A debit
+// that isn't positive is refused with ErrInvalidAmount: it would give
+// back headroom it never took.
+func (s *LimitService) Reserve(ctx context.Context, account *domain.Account, kind DebitKind, amount int64) (_ *Reservation, err error) {
+	ctx, span := tracing.Start(ctx, "LimitService.Reserve", tracing.String("account.id", account.ID), tracing.Int64("amount", amount))
+	defer span.Finish(&err)
+
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	policy := s.policy.Load()
+	_, limits := s.effective(policy, account)
+	day, month, night := policy.periods(s.now())
+
+	err = s.usage.Update(ctx, account.ID, func(usage *domain.LimitUsage) error {
+		usage.Roll(day, month, night)
+
+		if limits.MaxTransactionAmount > 0 && amount > limits.MaxTransactionAmount {
+			return ErrTransactionAmountLimit
+		}
+		if limits.DailyDebitLimit > 0 && usage.DailyDebits+amount > limits.DailyDebitLimit {
+			return ErrDailyDebitLimit
+		}
+		if limits.MonthlyDebitLimit > 0 && usage.MonthlyDebits+amount > limits.MonthlyDebitLimit {
+			return ErrMonthlyDebitLimit
+		}
+		if kind == DebitWithdrawal && limits.DailyWithdrawalCount > 0 && usage.DailyWithdrawals >= limits.DailyWithdrawalCount {
+			return ErrWithdrawalCountLimit
+		}
+		if kind == DebitTransfer && night != "" && limits.NightTransferLimit > 0 && usage.NightTransfers+amount > limits.NightTransferLimit {
+			return ErrNightTransferLimit
+		}
+
+		usage.DailyDebits += amount
+		usage.MonthlyDebits += amount
+		if kind == DebitWithdrawal {
+			usage.DailyWithdrawals++
+		}
+		if kind == DebitTransfer && night != "" {
+			usage.NightTransfers += amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Reservation{AccountID: account.ID, Kind: kind, Amount: amount, Day: day, Month: month, Night: night}, nil
+}
+
+// Release gives back what Reserve recorded for a debit that was undone,
+// in the periods it was reserved in. Usage of a period that has since
+// ended is not touched, nor the new period's. Like Reserve, it refuses an
+// amount that isn't positive.
+func (s *LimitService) Release(ctx context.Context, reservation *Reservation) (err error) {
+	ctx, span := tracing.Start(ctx, "LimitService.Release", tracing.String("account.id", reservation.AccountID), tracing.Int64("amount", reservation.Amount))
+	defer span.Finish(&err)
+
+	amount := reservation.Amount
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	return s.usage.Update(ctx, reservation.AccountID, func(usage *domain.LimitUsage) error {
+		if usage.Day == reservation.Day {
+			usage.DailyDebits = max(usage.DailyDebits-amount, 0)
+			if reservation.Kind == DebitWithdrawal {
+				usage.DailyWithdrawals = max(usage.DailyWithdrawals-1, 0)
+			}
+		}
+		if usage.Month == reservation.Month {
+			usage.MonthlyDebits = max(usage.MonthlyDebits-amount, 0)
+		}
+		if reservation.Kind == DebitTransfer && reservation.Night != "" && usage.Night == reservation.Night {
+			usage.NightTransfers = max(usage.NightTransfers-amount, 0)
+		}
+		return nil
+	})
+}
+
+// GetLimits returns the account's limits and what is left of them now.
+func (s *LimitService) GetLimits(ctx context.Context, accountID string) (_ *dto.LimitsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "LimitService.GetLimits", tracing.String("account.id", accountID))
+	defer span.Finish(&err)
+
+	account, exists := s.accounts.FindById(ctx, accountID)
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+
+	policy := s.policy.Load()
+	product, limits := s.effective(policy, account)
+	now := s.now()
+	day, month, night := policy.periods(now)
+	usage := s.usage.Find(ctx, accountID)
+	usage.Roll(day, month, night)
+
+	return &dto.LimitsResponse{
+		AccountID:  accountID,
+		Product:    product,
+		Overridden: account.Limits != nil,
+		Limits:     limits,
+		Usage:      usage,
+		Night:      night != "",
+		AsOf:       now,
+	}, nil
+}
+
+// SetLimits moves the account to product, empty meaning the default one,
+// and replaces its own limits; nil limits falls back to the product's.
+func (s *LimitService) SetLimits(ctx context.Context, accountID, product string, limits *domain.Limits) (_ *dto.LimitsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "LimitService.SetLimits", tracing.String("account.id", accountID))
+	defer span.Finish(&err)
+
+	if product != "" {
+		if _, known := s.policy.Load().Products[product]; !known {
+			return nil, ErrUnknownProduct
+		}
+	}
+	if limits != nil {
+		if err := limits.Validate(); err != nil {
+			return nil, ErrInvalidLimits
+		}
+	}
+
+	ctx, unlock := s.locks.lock(ctx, accountID)
+	defer unlock()
+
+	account, exists := s.accounts.FindById(ctx, accountID)
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+	before := *account
+	account.Product = product
+	account.Limits = limits
+	if err := s.audit.Record(ctx, AuditAccountLimits, "account", accountID, before, account); err != nil {
+		return nil, err
+	}
+	s.accounts.Save(ctx, account)
+	return s.GetLimits(ctx, accountID)
+}
+
+// Forget drops an account's usage, for resets.
+func (s *LimitService) Forget(ctx context.Context, accountID string) {
+	s.usage.Delete(ctx, accountID)
+}
+
+func (s *LimitService) Reset() {
+	s.usage.Reset()
+}
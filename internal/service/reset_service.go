@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/repository"
+	"crypto/subtle"
+	"sync"
+)
+
+// ResetService wipes data for test suites. It only works in sandbox mode and
+// every call must carry the configured confirmation token.
+type ResetService struct {
+	accounts        *AccountService
+	transactionRepo *repository.TransactionRepository
+	audit           *AuditService
+	limits          *LimitService
+	fraud           *FraudService
+	aml             *AMLService
+	sanctions       *SanctionsService
+	suspense        *SuspenseService
+	schedules       *ScheduleService
+	batches         *BatchService
+	locks           *accountLocks
+	enabled         bool
+	token           string
+	mu              sync.Mutex
+}
+
+// NewResetService enables resets when sandbox is set and a token is
+// configured; without a token there is nothing to confirm against.
+func NewResetService(accounts *AccountService, transactionRepo *repository.TransactionRepository, audit *AuditService, sandbox bool, token string) *ResetService {
+	return &ResetService{
+		accounts:        accounts,
+		transactionRepo: transactionRepo,
+		audit:           audit,
+		locks:           newAccountLocks(accounts.accountRepo),
+		enabled:         sandbox && token != "",
+		token:           token,
+	}
+}
+
+// SetLimitService makes resets clear limit usage too, so a fixture reusing
+// an account ID starts with fresh daily and monthly totals.
+func (s *ResetService) SetLimitService(limits *LimitService) {
+	s.limits = limits
+}
+
+// SetFraudService makes resets clear fraud history and cases too.
+func (s *ResetService) SetFraudService(fraud *FraudService) {
+	s.fraud = fraud
+}
+
+// SetAMLService makes resets clear AML history and alerts too.
+func (s *ResetService) SetAMLService(aml *AMLService) {
+	s.aml = aml
+}
+
+// SetSanctionsService makes resets clear sanctions screenings, cases and
+// clearances too.
+func (s *ResetService) SetSanctionsService(sanctions *SanctionsService) {
+	s.sanctions = sanctions
+}
+
+// SetSuspenseService makes resets clear suspense items too.
+func (s *ResetService) SetSuspenseService(suspense *SuspenseService) {
+	s.suspense = suspense
+}
+
+// SetScheduleService makes resets drop scheduled transfers too.
+func (s *ResetService) SetScheduleService(schedules *ScheduleService) {
+	s.schedules = schedules
+}
+
+// SetBatchService makes full resets drop batches too. Resetting one
+// account keeps them: a batch records a request, not account state.
+func (s *ResetService) SetBatchService(batches *BatchService) {
+	s.batches = batches
+}
+
+func (s *ResetService) Enabled() bool {
+	return s.enabled
+}
+
+// ResetAll clears accounts and transactions together. API keys and the
+// audit log are kept. Every account is locked meanwhile, so no posting
+// saves an account back after it is cleared.
+func (s *ResetService) ResetAll(ctx context.Context, confirmation string) error {
+	if err := s.check(confirmation); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx, unlock := s.locks.lock(ctx, s.accounts.accountRepo.IDs()...)
+	defer unlock()
+
+	before := map[string]int{
+		"accounts":     s.accounts.Reset(),
+		"transactions": s.transactionRepo.Reset(),
+	}
+	if s.limits != nil {
+		s.limits.Reset()
+	}
+	if s.fraud != nil {
+		s.fraud.Reset()
+	}
+	if s.aml != nil {
+		s.aml.Reset()
+	}
+	if s.sanctions != nil {
+		s.sanctions.Reset()
+	}
+	if s.suspense != nil {
+		s.suspense.Reset()
+	}
+	if s.schedules != nil {
+		if err := s.schedules.Reset(); err != nil {
+			return err
+		}
+	}
+	if s.batches != nil {
+		s.batches.Reset()
+	}
+	after := map[string]int{"accounts": 0, "transactions": 0}
+	return s.audit.Record(ctx, AuditSystemReset, "system", "all", before, after)
+}
+
+// ResetAccount removes a single account fixture and its transactions,
+// with the account locked throughout.
+func (s *ResetService) ResetAccount(ctx context.Context, accountID, confirmation string) error {
+	if err := s.check(confirmation); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx, unlock := s.locks.lock(ctx, accountID)
+	defer unlock()
+
+	account, err := s.accounts.Remove(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	before := map[string]any{
+		"account":      account,
+		"transactions": s.transactionRepo.DeleteByAccount(ctx, accountID),
+	}
+	if s.limits != nil {
+		s.limits.Forget(ctx, accountID)
+	}
+	if s.fraud != nil {
+		s.fraud.Forget(ctx, accountID)
+	}
+	if s.aml != nil {
+		s.aml.Forget(ctx, accountID)
+	}
+	if s.sanctions != nil {
+		s.sanctions.Forget(ctx, accountID)
+	}
+	if s.suspense != nil {
+		s.suspense.Forget(ctx, accountID)
+	}
+	if s.schedules != nil {
+		if err := s.schedules.Forget(ctx, accountID); err != nil {
+			return err
+		}
+	}
+	return s.audit.Record(ctx, AuditAccountReset, "account", accountID, before, nil)
+}
+
+func (s *ResetService) check(confirmation string) error {
+	if !s.enabled {
+		return ErrResetDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(confirmation), []byte(s.token)) != 1 {
+		return ErrResetNotConfirmed
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/metrics"
+	"corebanking/internal/repository"
+	"corebanking/internal/sanctions"
+	"corebanking/internal/tracing"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SanctionsService screens customers at onboarding and the holders of
+// transfer destinations against the sanctions and PEP list, records every
+// screening with the list version it used and keeps the queue of
+// onboardings and transfers held on a hit.
+type SanctionsService struct {
+	screener   *sanctions.Screener
+	screenings *repository.SanctionsScreeningRepository
+	cases      *repository.SanctionsCaseRepository
+	clearances *repository.SanctionsClearanceRepository
+	accounts   *AccountService
+	audit      *AuditService
+	now        func() time.Time
+	// post posts an approved transfer, after lock has locked the accounts
+	// it may change; both set by TransactionService.SetSanctionsService.
+	post func(context.Context, *domain.SanctionsCase) error
+	lock func(ctx context.Context, origin, destination string) (context.Context, func())
+	// mu makes deciding a case happen once.
+	mu sync.Mutex
+	// listMu guards path, where the list was last loaded from.
+	listMu sync.Mutex
+	path   string
+}
+
+func NewSanctionsService(screener *sanctions.Screener, screenings *repository.SanctionsScreeningRepository, cases *repository.SanctionsCaseRepository, clearances *repository.SanctionsClearanceRepository, accounts *AccountService, audit *AuditService) *SanctionsService {
+	return NewSanctionsServiceWithClock(screener, screenings, cases, clearances, accounts, audit, time.Now)
+}
+
+// NewSanctionsServiceWithClock lets tests control time.
+func NewSanctionsServiceWithClock(screener *sanctions.Screener, screenings *repository.SanctionsScreeningRepository, cases *repository.SanctionsCaseRepository, clearances *repository.SanctionsClearanceRepository, accounts *AccountService, audit *AuditService, now func() time.Time) *SanctionsService {
+	return &SanctionsService{
+		screener:   screener,
+		screenings: screenings,
+		cases:      cases,
+		clearances: clearances,
+		accounts:   accounts,
+		audit:      audit,
+		now:        now,
+	}
+}
+
+// ScreenOnboarding screens a customer before accountID is opened for
+// them. A hit that wasn't cleared before holds the onboarding and returns
+// ErrSanctionsReview naming the new case.
+func (s *SanctionsService) ScreenOnboarding(ctx context.Context, documentNumber, holderName, accountID string) (err error) {
+	ctx, span := tracing.Start(ctx, "SanctionsService.ScreenOnboarding")
+	defer span.Finish(&err)
+
+	return s.screen(ctx, &domain.SanctionsScreening{
+		Kind:           domain.ScreeningOnboarding,
+		DocumentNumber: documentNumber,
+		HolderName:     holderName,
+		AccountID:      accountID,
+	})
+}
+
+// ScreenTransfer screens the holder of a transfer's destination. Accounts
+// without a holder, such as those opened by a deposit, have nobody to
+// screen.
+func (s *SanctionsService) ScreenTransfer(ctx context.Context, origin, destination string, amount int64) (err error) {
+	ctx, span := tracing.Start(ctx, "SanctionsService.ScreenTransfer", tracing.String("account.id", destination), tracing.Int64("amount", amount))
+	defer span.Finish(&err)
+
+	documentNumber, holderName, err := s.accounts.Holder(ctx, destination)
+	if errors.Is(err, ErrAccountNotFound) || (documentNumber == "" && holderName == "") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.screen(ctx, &domain.SanctionsScreening{
+		Kind:           domain.ScreeningTransfer,
+		DocumentNumber: documentNumber,
+		HolderName:     holderName,
+		AccountID:      destination,
+		Origin:         origin,
+		Amount:         amount,
+	})
+}
+
+func (s *SanctionsService) screen(ctx context.Context, screening *domain.SanctionsScreening) error {
+	result, enabled := s.screener.Screen(sanctions.Subject{Name: screening.HolderName, Document: screening.DocumentNumber})
+	if !enabled {
+		return nil
+	}
+
+	screening.ID = uuid.New().String()
+	screening.At = s.now()
+	screening.ListVersion = result.Version
+	screening.ListDigest = result.Digest
+	screening.Hits = result.Hits
+	held := false
+	for i, hit := range screening.Hits {
+		screening.Hits[i].Cleared = s.clearances.Cleared(ctx, screening.DocumentNumber, hit.EntryID)
+		held = held || !screening.Hits[i].Cleared
+	}
+
+	var sanctionsCase *domain.SanctionsCase
+	if held {
+		sanctionsCase = &domain.SanctionsCase{
+			ID:             uuid.New().String(),
+			Status:         domain.SanctionsCasePending,
+			Kind:           screening.Kind,
+			ScreeningID:    screening.ID,
+			DocumentNumber: screening.DocumentNumber,
+			HolderName:     screening.HolderName,
+			Origin:         screening.Origin,
+			Amount:         screening.Amount,
+			ListVersion:    screening.ListVersion,
+			Hits:           screening.Hits,
+			CreatedAt:      screening.At,
+		}
+		if screening.Kind == domain.ScreeningTransfer {
+			sanctionsCase.AccountID = screening.AccountID
+		} else {
+			// The account is only opened once the case is approved.
+			screening.AccountID = ""
+		}
+		screening.CaseID = sanctionsCase.ID
+	}
+
+	if err := s.audit.Record(ctx, AuditSanctionsScreen, "sanctions_screening", screening.ID, nil, screening); err != nil {
+		return err
+	}
+	s.screenings.Save(ctx, screening)
+	outcome := "clear"
+	if held {
+		outcome = "review"
+	}
+	metrics.SanctionsScreenings.Inc(screening.Kind, outcome)
+	if !held {
+		return nil
+	}
+
+	if err := s.audit.Record(ctx, AuditSanctionsHold, "sanctions_case", sanctionsCase.ID, nil, sanctionsCase); err != nil {
+		return err
+	}
+	s.cases.Save(ctx, sanctionsCase)
+	return &HoldError{Err: ErrSanctionsReview, ID: sanctionsCase.ID}
+}
+
+// Screenings lists the screenings involving an account or a document
+// number; empty values match any.
+func (s *SanctionsService) Screenings(ctx context.Context, accountID, documentNumber string) []*domain.SanctionsScreening {
+	ctx, span := tracing.Start(ctx, "SanctionsService.Screenings", tracing.String("account.id", accountID))
+	defer span.End()
+
+	return s.screenings.Find(ctx, accountID, documentNumber)
+}
+
+// Cases lists cases with status, every case when it is empty.
+func (s *SanctionsService) Cases(ctx context.Context, status string) []*domain.SanctionsCase {
+	ctx, span := tracing.Start(ctx, "SanctionsService.Cases", tracing.String("sanctions_case.status", status))
+	defer span.End()
+
+	return s.cases.FindByStatus(ctx, status)
+}
+
+func (s *SanctionsService) Case(ctx context.Context, id string) (*domain.SanctionsCase, error) {
+	sanctionsCase, exists := s.cases.FindByID(ctx, id)
+	if !exists {
+		return nil, ErrSanctionsCaseNotFound
+	}
+	return sanctionsCase, nil
+}
+
+// Approve clears the case's document number of the entries it hit, so
+// they don't hold it again, and carries out the onboarding or transfer.
+// When that fails the case stays pending; the clearances stand.
+func (s *SanctionsService) Approve(ctx context.Context, id, note string) (*domain.SanctionsCase, error) {
+	return s.decide(ctx, id, domain.SanctionsCaseApproved, note)
+}
+
+// Reject closes a pending case without opening the account or posting
+// the transfer.
+func (s *SanctionsService) Reject(ctx context.Context, id, note string) (*domain.SanctionsCase, error) {
+	return s.decide(ctx, id, domain.SanctionsCaseRejected, note)
+}
+
+func (s *SanctionsService) decide(ctx context.Context, id, status, note string) (_ *domain.SanctionsCase, err error) {
+	ctx, span := tracing.Start(ctx, "SanctionsService.decide", tracing.String("sanctions_case.id", id), tracing.String("sanctions_case.status", status))
+	defer span.Finish(&err)
+
+	// The accounts are locked before mu, as postings lock them before
+	// opening cases.
+	if pending, exists := s.cases.FindByID(ctx, id); exists && pending.Kind != domain.ScreeningOnboarding && s.lock != nil {
+		var unlock func()
+		ctx, unlock = s.lock(ctx, pending.Origin, pending.AccountID)
+		defer unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sanctionsCase, exists := s.cases.FindByID(ctx, id)
+	if !exists {
+		return nil, ErrSanctionsCaseNotFound
+	}
+	if sanctionsCase.Status != domain.SanctionsCasePending {
+		return nil, ErrSanctionsCaseDecided
+	}
+	before := *sanctionsCase
+
+	if status == domain.SanctionsCaseApproved {
+		for _, hit := range sanctionsCase.Hits {
+			s.clearances.Add(ctx, sanctionsCase.DocumentNumber, hit.EntryID, id)
+		}
+		if sanctionsCase.Kind == domain.ScreeningOnboarding {
+			account, err := s.accounts.CreateAccount(ctx, sanctionsCase.DocumentNumber, sanctionsCase.HolderName)
+			if err != nil {
+				return nil, err
+			}
+			sanctionsCase.AccountID = account.AccountID
+		} else if err := s.post(ctx, sanctionsCase); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.now()
+	sanctionsCase.Status = status
+	sanctionsCase.DecidedAt = &now
+	sanctionsCase.Note = note
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		sanctionsCase.DecidedBy = principal.Subject
+	}
+	// An approval has already opened the account or posted the transfer,
+	// so the case is stored as decided before its own audit entry: it must
+	// not be approved twice.
+	s.cases.Save(ctx, sanctionsCase)
+	if err := s.audit.Record(ctx, AuditSanctionsDecide, "sanctions_case", id, before, sanctionsCase); err != nil {
+		return nil, err
+	}
+	return sanctionsCase, nil
+}
+
+// List returns the list in use.
+func (s *SanctionsService) List() *sanctions.List {
+	return s.screener.List()
+}
+
+// LoadList replaces the list with the one at path, an empty path meaning
+// no list. The change is audited with both versions, so the audit log
+// shows which list was in use when. A list that fails to load leaves the
+// current one in place.
+func (s *SanctionsService) LoadList(ctx context.Context, path string) (_ *sanctions.List, err error) {
+	ctx, span := tracing.Start(ctx, "SanctionsService.LoadList", tracing.String("sanctions_list.path", path))
+	defer span.Finish(&err)
+
+	s.listMu.Lock()
+	defer s.listMu.Unlock()
+
+	list := sanctions.EmptyList()
+	list.LoadedAt = s.now()
+	if path != "" {
+		if list, err = sanctions.Load(path, s.now()); err != nil {
+			return nil, err
+		}
+	}
+	before := s.screener.List()
+	s.screener.SetList(list)
+	s.path = path
+	if err := s.audit.Record(ctx, AuditSanctionsList, "sanctions_list", list.Version, listSummary(before), listSummary(list)); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ReloadList loads the list again from where it was last loaded.
+func (s *SanctionsService) ReloadList(ctx context.Context) (*sanctions.List, error) {
+	s.listMu.Lock()
+	path := s.path
+	s.listMu.Unlock()
+	return s.LoadList(ctx, path)
+}
+
+// listSummary describes a list for the audit log, without its entries.
+func listSummary(list *sanctions.List) map[string]any {
+	return map[string]any{
+		"version": list.Version,
+		"digest":  list.Digest,
+		"source":  list.Source,
+		"entries": len(list.Entries),
+	}
+}
+
+// SetPolicy replaces the screening policy while serving.
+func (s *SanctionsService) SetPolicy(policy sanctions.Policy) {
+	s.screener.SetPolicy(policy)
+}
+
+// Forget drops the screenings and cases involving an account, for resets.
+func (s *SanctionsService) Forget(ctx context.Context, accountID string) {
+	s.screenings.DeleteByAccount(ctx, accountID)
+	s.cases.DeleteByAccount(ctx, accountID)
+}
+
+// Reset drops screenings, cases and clearances; the list stays loaded.
+func (s *SanctionsService) Reset() {
+	s.screenings.Reset()
+	s.cases.Reset()
+	s.clearances.Reset()
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/metrics"
+	"corebanking/internal/repository"
+	"corebanking/internal/tracing"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchedulePolicy is how the scheduler runs due transfers.
+type SchedulePolicy struct {
+	// RetryAttempts is how many times an occurrence declined for lack of
+	// funds is tried again, RetryInterval apart, before it fails; zero
+	// fails it at once.
+	RetryAttempts int
+	RetryInterval time.Duration
+	// Lease is how long an instance has to run an occurrence it claimed.
+	// Past it, other instances record the occurrence as interrupted.
+	Lease time.Duration
+	// ExecutionRetention is how long executions are kept; older ones are
+	// dropped as due schedules are run. Zero keeps them.
+	ExecutionRetention time.Duration
+}
+
+// schedulerPrincipal is who scheduled transfers are posted as, so the
+// audit log tells them apart from transfers requested over the API.
+var schedulerPrincipal = &auth.Principal{ID: "scheduler", Subject: "scheduler", Method: "schedule"}
+
+// errNotDue makes a claim or interruption give up on a schedule another
+// instance, or a pause, got to first.
+var errNotDue = errors.New("schedule not due")
+
+// ScheduleService keeps scheduled transfers, one-off and standing orders,
+// and runs them through the TransactionService when due. An occurrence is
+// claimed in the schedule repository before it runs, so instances sharing
+// the repository's file never both run it.
+type ScheduleService struct {
+	schedules    *repository.ScheduleRepository
+	accounts     *repository.AccountRepository
+	transactions *TransactionService
+	audit        *AuditService
+	// instance names this server instance in claims and executions.
+	instance string
+	policy   atomic.Pointer[SchedulePolicy]
+	now      func() time.Time
+}
+
+func NewScheduleService(schedules *repository.ScheduleRepository, accounts *repository.AccountRepository, transactions *TransactionService, audit *AuditService, instance string, policy SchedulePolicy) *ScheduleService {
+	return NewScheduleServiceWithClock(schedules, accounts, transactions, audit, instance, policy, time.Now)
+}
+
+// NewScheduleServiceWithClock lets tests control time.
+func NewScheduleServiceWithClock(schedules *repository.ScheduleRepository, accounts *repository.AccountRepository, transactions *TransactionService, audit *AuditService, instance string, policy SchedulePolicy, now func() time.Time) *ScheduleService {
+	s := &ScheduleService{
+		schedules:    schedules,
+		accounts:     accounts,
+		transactions: transactions,
+		audit:        audit,
+		instance:     instance,
+		now:          now,
+	}
+	s.SetPolicy(policy)
+	return s
+}
+
+// SetPolicy replaces the policy while serving.
+func (s *ScheduleService) SetPolicy(policy SchedulePolicy) {
+	s.policy.Store(&policy)
+}
+
+func (s *ScheduleService) Policy() SchedulePolicy {
+	return *s.policy.Load()
+}
+
+// Create schedules the transfer described by Origin, Destination, Amount,
+// Frequency, StartAt, EndAt, Count and Description; a zero StartAt is now.
+// Both accounts must exist.
+func (s *ScheduleService) Create(ctx context.Context, req *domain.Schedule) (_ *domain.Schedule, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.Create", tracing.String("account.id", req.Origin), tracing.String("schedule.frequency", req.Frequency))
+	defer span.Finish(&err)
+
+	now := s.now()
+	schedule := &domain.Schedule{
+		ID:          uuid.New().String(),
+		Status:      domain.ScheduleActive,
+		Origin:      req.Origin,
+		Destination: req.Destination,
+		Amount:      req.Amount,
+		Frequency:   req.Frequency,
+		StartAt:     req.StartAt,
+		EndAt:       req.EndAt,
+		Count:       req.Count,
+		Description: req.Description,
+		CreatedAt:   now,
+	}
+	if schedule.StartAt.IsZero() {
+		schedule.StartAt = now
+	}
+	if err := validateSchedule(schedule, now); err != nil {
+		return nil, err
+	}
+	if origin, exists := s.accounts.FindById(ctx, schedule.Origin); !exists || origin.Internal {
+		return nil, ErrAccountNotFound
+	}
+	if destination, exists := s.accounts.FindById(ctx, schedule.Destination); !exists || destination.Internal {
+		return nil, ErrDestinationNotFound
+	}
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		schedule.CreatedBy = principal.Subject
+	}
+	next := schedule.StartAt
+	schedule.NextRunAt = &next
+
+	if err := s.audit.Record(ctx, AuditScheduleCreate, "schedule", schedule.ID, nil, schedule); err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Save(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func validateSchedule(schedule *domain.Schedule, now time.Time) error {
+	switch {
+	case schedule.Amount <= 0:
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidSchedule)
+	case schedule.Destination == "":
+		return fmt.Errorf("%w: destination is required", ErrInvalidSchedule)
+	case schedule.Destination == schedule.Origin:
+		return fmt.Errorf("%w: destination must differ from the origin", ErrInvalidSchedule)
+	case !domain.ValidFrequency(schedule.Frequency):
+		return fmt.Errorf("%w: frequency must be once, daily, weekly or monthly", ErrInvalidSchedule)
+	case schedule.StartAt.Before(now):
+		return fmt.Errorf("%w: startAt must not be in the past", ErrInvalidSchedule)
+	case schedule.EndAt != nil && schedule.EndAt.Before(schedule.StartAt):
+		return fmt.Errorf("%w: endAt must not be before startAt", ErrInvalidSchedule)
+	case schedule.Count < 0:
+		return fmt.Errorf("%w: count must not be negative", ErrInvalidSchedule)
+	case schedule.Frequency == domain.ScheduleOnce && (schedule.EndAt != nil || schedule.Count > 1):
+		return fmt.Errorf("%w: a once schedule takes no endAt or count", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// Schedules lists the schedules from origin, oldest first.
+func (s *ScheduleService) Schedules(ctx context.Context, origin string) (_ []*domain.Schedule, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.Schedules", tracing.String("account.id", origin))
+	defer span.Finish(&err)
+
+	if _, exists := s.accounts.FindById(ctx, origin); !exists {
+		return nil, ErrAccountNotFound
+	}
+	return s.schedules.Find(ctx, origin)
+}
+
+// Schedule returns one of origin's schedules.
+func (s *ScheduleService) Schedule(ctx context.Context, origin, id string) (*domain.Schedule, error) {
+	schedule, exists, err := s.schedules.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exists || schedule.Origin != origin {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// Executions lists the runs of one of origin's schedules, oldest first.
+func (s *ScheduleService) Executions(ctx context.Context, origin, id string) ([]*domain.ScheduleExecution, error) {
+	if _, err := s.Schedule(ctx, origin, id); err != nil {
+		return nil, err
+	}
+	return s.schedules.Executions(ctx, id)
+}
+
+// Pause stops a schedule from running until it is resumed. Pausing a
+// paused schedule changes nothing.
+func (s *ScheduleService) Pause(ctx context.Context, origin, id string) (*domain.Schedule, error) {
+	return s.change(ctx, origin, id, AuditSchedulePause, func(schedule *domain.Schedule) {
+		schedule.Status = domain.SchedulePaused
+	})
+}
+
+// Resume runs a paused schedule again. Standing orders skip the
+// occurrences that fell due while paused; a one-off transfer runs late
+// instead.
+func (s *ScheduleService) Resume(ctx context.Context, origin, id string) (*domain.Schedule, error) {
+	now := s.now()
+	return s.change(ctx, origin, id, AuditScheduleResume, func(schedule *domain.Schedule) {
+		schedule.Status = domain.ScheduleActive
+		if schedule.Frequency == domain.ScheduleOnce {
+			return
+		}
+		for !schedule.Finished() && schedule.DueAt().Before(now) {
+			schedule.Advance(false)
+		}
+	})
+}
+
+// Cancel stops a schedule for good. An occurrence running meanwhile still
+// completes.
+func (s *ScheduleService) Cancel(ctx context.Context, origin, id string) (*domain.Schedule, error) {
+	return s.change(ctx, origin, id, AuditScheduleCancel, func(schedule *domain.Schedule) {
+		schedule.Status = domain.ScheduleCancelled
+		schedule.NextRunAt = nil
+	})
+}
+
+// change applies fn to one of origin's schedules unless it is finished,
+// and audits the change as action.
+func (s *ScheduleService) change(ctx context.Context, origin, id, action string, fn func(schedule *domain.Schedule)) (_ *domain.Schedule, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.change", tracing.String("schedule.id", id), tracing.String("audit.action", action))
+	defer span.Finish(&err)
+
+	schedule, err := s.schedules.Update(ctx, id, func(schedule *domain.Schedule) error {
+		if schedule.Origin != origin {
+			return ErrScheduleNotFound
+		}
+		if schedule.Finished() {
+			return ErrScheduleFinished
+		}
+		before := *schedule
+		fn(schedule)
+		if schedule.Status == before.Status && schedule.Index == before.Index {
+			return nil
+		}
+		return s.audit.Record(ctx, action, "schedule", id, before, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// RunDue runs the occurrence due next of every active schedule whose time
+// has come, and records as interrupted the occurrences whose claim
+// expired, after dropping the executions the policy no longer keeps. It
+// returns the executions it recorded; a schedule that fell
+// behind, after a restart say, catches up one occurrence per call.
+func (s *ScheduleService) RunDue(ctx context.Context) (_ []*domain.ScheduleExecution, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.RunDue")
+	defer span.Finish(&err)
+
+	if retention := s.Policy().ExecutionRetention; retention > 0 {
+		if _, err := s.schedules.DeleteExecutionsBefore(ctx, s.now().Add(-retention)); err != nil {
+			return nil, err
+		}
+	}
+	schedules, err := s.schedules.Find(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	executions := make([]*domain.ScheduleExecution, 0)
+	var errs []error
+	for _, schedule := range schedules {
+		now := s.now()
+		var execution *domain.ScheduleExecution
+		var err error
+		switch {
+		case schedule.ClaimedBy != "":
+			if schedule.ClaimedUntil.Before(now) {
+				execution, err = s.interrupt(ctx, schedule.ID, now)
+			}
+		case schedule.Status == domain.ScheduleActive && schedule.NextRunAt != nil && !schedule.NextRunAt.After(now):
+			execution, err = s.run(ctx, schedule.ID, now)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schedule %s: %w", schedule.ID, err))
+		}
+		if execution != nil {
+			executions = append(executions, execution)
+		}
+	}
+	return executions, errors.Join(errs...)
+}
+
+// run claims a due occurrence, posts its transfer and records the outcome.
+// It does nothing when another instance claimed the occurrence first.
+func (s *ScheduleService) run(ctx context.Context, id string, now time.Time) (*domain.ScheduleExecution, error) {
+	policy := s.Policy()
+	schedule, err := s.schedules.Update(ctx, id, func(schedule *domain.Schedule) error {
+		if schedule.Status != domain.ScheduleActive || schedule.ClaimedBy != "" || schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+			return errNotDue
+		}
+		until := now.Add(policy.Lease)
+		schedule.ClaimedBy = s.instance
+		schedule.ClaimedUntil = &until
+		return nil
+	})
+	if errors.Is(err, errNotDue) || (err == nil && schedule == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req := dto.NewEventRequest("transfer", schedule.Origin, schedule.Destination, schedule.Amount)
+	_, postErr := s.transactions.HandleTransaction(auth.WithPrincipal(ctx, schedulerPrincipal), &req)
+
+	execution := s.execution(schedule)
+	retry := false
+	switch {
+	case postErr == nil:
+		execution.Status = domain.ExecutionPosted
+	case errors.Is(postErr, ErrHeldInSuspense), errors.Is(postErr, ErrFraudReview), errors.Is(postErr, ErrSanctionsReview):
+		execution.Status = domain.ExecutionHeld
+	case (errors.Is(postErr, ErrInsufficientFunds) || errors.Is(postErr, ErrInsufficientOverdraft)) && schedule.Attempts < policy.RetryAttempts:
+		execution.Status = domain.ExecutionRetrying
+		retry = true
+	default:
+		execution.Status = domain.ExecutionFailed
+	}
+	if postErr != nil {
+		execution.Error = postErr.Error()
+	}
+
+	_, err = s.schedules.Update(ctx, id, func(stored *domain.Schedule) error {
+		stored.ClaimedBy = ""
+		stored.ClaimedUntil = nil
+		switch {
+		case stored.Status == domain.ScheduleCancelled:
+		case retry:
+			next := s.now().Add(policy.RetryInterval)
+			stored.Attempts++
+			stored.NextRunAt = &next
+		default:
+			stored.Advance(true)
+		}
+		return nil
+	}, execution)
+	if err != nil {
+		return nil, err
+	}
+	metrics.ScheduleExecutions.Inc(execution.Status)
+	return execution, nil
+}
+
+// interrupt gives up on an occurrence whose claim expired. Whether its
+// transfer was posted is unknown, so it is counted as executed and never
+// run again rather than risk paying twice.
+func (s *ScheduleService) interrupt(ctx context.Context, id string, now time.Time) (*domain.ScheduleExecution, error) {
+	stored, exists, err := s.schedules.FindByID(ctx, id)
+	if err != nil || !exists {
+		return nil, err
+	}
+	execution := s.execution(stored)
+	execution.Status = domain.ExecutionInterrupted
+	execution.Error = fmt.Sprintf("instance %s did not record the outcome before its claim expired", stored.ClaimedBy)
+
+	_, err = s.schedules.Update(ctx, id, func(schedule *domain.Schedule) error {
+		if schedule.ClaimedBy != stored.ClaimedBy || schedule.Index != stored.Index || !schedule.ClaimedUntil.Before(now) {
+			return errNotDue
+		}
+		schedule.ClaimedBy = ""
+		schedule.ClaimedUntil = nil
+		if !schedule.Finished() {
+			schedule.Advance(true)
+		}
+		return nil
+	}, execution)
+	if errors.Is(err, errNotDue) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	metrics.ScheduleExecutions.Inc(execution.Status)
+	return execution, nil
+}
+
+// execution starts the record of running schedule's occurrence due next.
+func (s *ScheduleService) execution(schedule *domain.Schedule) *domain.ScheduleExecution {
+	return &domain.ScheduleExecution{
+		ID:         uuid.New().String(),
+		ScheduleID: schedule.ID,
+		Occurrence: schedule.Index,
+		DueAt:      schedule.DueAt(),
+		Attempt:    schedule.Attempts + 1,
+		ExecutedAt: s.now(),
+		Instance:   s.instance,
+	}
+}
+
+// Watch runs due schedules every interval until ctx is done, reporting
+// each run that recorded executions or failed.
+func (s *ScheduleService) Watch(ctx context.Context, interval time.Duration, onRun func(executions []*domain.ScheduleExecution, err error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if executions, err := s.RunDue(ctx); len(executions) > 0 || err != nil {
+			onRun(executions, err)
+		}
+	}
+}
+
+// Forget drops the schedules from or to an account, for resets.
+func (s *ScheduleService) Forget(ctx context.Context, accountID string) error {
+	_, err := s.schedules.DeleteByAccount(ctx, accountID)
+	return err
+}
+
+// Reset drops every schedule.
+func (s *ScheduleService) Reset() error {
+	return s.schedules.Reset()
+}
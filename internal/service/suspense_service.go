@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/metrics"
+	"corebanking/internal/repository"
+	"corebanking/internal/tracing"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SuspensePolicy is what deposits and transfers to accounts that don't
+// exist do.
+type SuspensePolicy struct {
+	// UnknownDestination is domain.UnknownDestinationReject or
+	// domain.UnknownDestinationSuspense.
+	UnknownDestination string
+	// ReturnAfter is how long a transfer waits in suspense before it goes
+	// back to its origin; zero keeps it until someone handles it. Deposits
+	// have nowhere to go back to and always wait.
+	ReturnAfter time.Duration
+}
+
+// SuspenseService keeps the suspense account, an internal ledger account
+// holding deposits and transfers whose destination does not exist, and
+// the items posted to it until they are claimed by the right account or
+// returned.
+type SuspenseService struct {
+	items     *repository.SuspenseRepository
+	accounts  *repository.AccountRepository
+	audit     *AuditService
+	accountID string
+	policy    atomic.Pointer[SuspensePolicy]
+	now       func() time.Time
+	// release moves a held item's amount from the suspense account to
+	// accountID, or out of the bank when it is empty, after lock has locked
+	// both; set by TransactionService.SetSuspenseService.
+	release func(ctx context.Context, item *domain.SuspenseItem, accountID string) error
+	lock    func(ctx context.Context, origin, destination string) (context.Context, func())
+	// mu makes resolving an item happen once; ledgerMu makes the suspense
+	// account be opened once.
+	mu       sync.Mutex
+	ledgerMu sync.Mutex
+}
+
+func NewSuspenseService(items *repository.SuspenseRepository, accounts *repository.AccountRepository, audit *AuditService, accountID string, policy SuspensePolicy) *SuspenseService {
+	return NewSuspenseServiceWithClock(items, accounts, audit, accountID, policy, time.Now)
+}
+
+// NewSuspenseServiceWithClock lets tests control time.
+func NewSuspenseServiceWithClock(items *repository.SuspenseRepository, accounts *repository.AccountRepository, audit *AuditService, accountID string, policy SuspensePolicy, now func() time.Time) *SuspenseService {
+	s := &SuspenseService{
+		items:     items,
+		accounts:  accounts,
+		audit:     audit,
+		accountID: accountID,
+		now:       now,
+	}
+	s.SetPolicy(policy)
+	return s
+}
+
+// SetPolicy replaces the policy while serving.
+func (s *SuspenseService) SetPolicy(policy SuspensePolicy) {
+	s.policy.Store(&policy)
+}
+
+func (s *SuspenseService) Policy() SuspensePolicy {
+	return *s.policy.Load()
+}
+
+// AccountID is the ID of the suspense account.
+func (s *SuspenseService) AccountID() string {
+	return s.accountID
+}
+
+// Ledger returns the suspense account, opening it the first time, or
+// again after a reset.
+func (s *SuspenseService) Ledger(ctx context.Context) *domain.Account {
+	s.ledgerMu.Lock()
+	defer s.ledgerMu.Unlock()
+
+	if account, exists := s.accounts.FindById(ctx, s.accountID); exists {
+		return account
+	}
+	account := domain.NewAccount(s.accountID, 0)
+	account.Internal = true
+	return s.accounts.Save(ctx, account)
+}
+
+// Hold records a deposit or transfer the caller has posted to the suspense
+// account in place of destination.
+func (s *SuspenseService) Hold(ctx context.Context, kind, origin, destination string, amount int64) (_ *domain.SuspenseItem, err error) {
+	ctx, span := tracing.Start(ctx, "SuspenseService.Hold", tracing.String("event.destination", destination), tracing.Int64("amount", amount))
+	defer span.Finish(&err)
+
+	item := &domain.SuspenseItem{
+		ID:          uuid.New().String(),
+		Status:      domain.SuspenseHeld,
+		Kind:        kind,
+		Origin:      origin,
+		Destination: destination,
+		Amount:      amount,
+		CreatedAt:   s.now(),
+	}
+	if err := s.audit.Record(ctx, AuditSuspenseHold, "suspense_item", item.ID, nil, item); err != nil {
+		return nil, err
+	}
+	s.items.Save(ctx, item)
+	metrics.SuspenseItems.Inc(kind, domain.SuspenseHeld)
+	return item, nil
+}
+
+// Items lists items with status and involving accountID, empty values
+// matching any.
+func (s *SuspenseService) Items(ctx context.Context, status, accountID string) []*domain.SuspenseItem {
+	ctx, span := tracing.Start(ctx, "SuspenseService.Items", tracing.String("suspense_item.status", status))
+	defer span.End()
+
+	return s.items.Find(ctx, status, accountID)
+}
+
+func (s *SuspenseService) Item(ctx context.Context, id string) (*domain.SuspenseItem, error) {
+	item, exists := s.items.FindByID(ctx, id)
+	if !exists {
+		return nil, ErrSuspenseItemNotFound
+	}
+	return item, nil
+}
+
+// Claim moves a held item to the account it was meant for, usually the
+// right spelling of its destination.
+func (s *SuspenseService) Claim(ctx context.Context, id, accountID, note string) (*domain.SuspenseItem, error) {
+	if accountID == "" {
+		return nil, ErrAccountNotFound
+	}
+	return s.resolve(ctx, id, domain.SuspenseClaimed, accountID, note)
+}
+
+// Return sends a held transfer back to its origin. A held deposit has no
+// origin: returning it takes it out of the suspense account, for when the
+// cash is handed back.
+func (s *SuspenseService) Return(ctx context.Context, id, note string) (*domain.SuspenseItem, error) {
+	return s.resolve(ctx, id, domain.SuspenseReturned, "", note)
+}
+
+func (s *SuspenseService) resolve(ctx context.Context, id, status, accountID, note string) (_ *domain.SuspenseItem, err error) {
+	ctx, span := tracing.Start(ctx, "SuspenseService.resolve", tracing.String("suspense_item.id", id), tracing.String("suspense_item.status", status))
+	defer span.Finish(&err)
+
+	// The accounts are locked before mu, as postings lock them before
+	// holding items.
+	if held, exists := s.items.FindByID(ctx, id); exists && s.lock != nil {
+		to := accountID
+		if status == domain.SuspenseReturned {
+			to = held.Origin
+		}
+		var unlock func()
+		ctx, unlock = s.lock(ctx, to, s.accountID)
+		defer unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, exists := s.items.FindByID(ctx, id)
+	if !exists {
+		return nil, ErrSuspenseItemNotFound
+	}
+	if item.Status != domain.SuspenseHeld {
+		return nil, ErrSuspenseItemResolved
+	}
+	if status == domain.SuspenseReturned {
+		accountID = item.Origin
+	}
+	if err := s.release(ctx, item, accountID); err != nil {
+		return nil, err
+	}
+
+	before := *item
+	now := s.now()
+	item.Status = status
+	item.AccountID = accountID
+	item.ResolvedAt = &now
+	item.Note = note
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		item.ResolvedBy = principal.Subject
+	}
+	// The release is posted and audited, so the item is stored as resolved
+	// before its own audit entry: it must not be released twice.
+	s.items.Save(ctx, item)
+	metrics.SuspenseItems.Inc(item.Kind, status)
+	action := AuditSuspenseClaim
+	if status == domain.SuspenseReturned {
+		action = AuditSuspenseReturn
+	}
+	if err := s.audit.Record(ctx, action, "suspense_item", id, before, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ReturnExpired returns the transfers held longer than the policy's
+// ReturnAfter and reports how many it returned. An item that can't be
+// returned, its origin closed say, is left held and its error reported.
+func (s *SuspenseService) ReturnExpired(ctx context.Context) (returned int, err error) {
+	ctx, span := tracing.Start(ctx, "SuspenseService.ReturnExpired")
+	defer span.Finish(&err)
+
+	after := s.Policy().ReturnAfter
+	if after <= 0 {
+		return 0, nil
+	}
+	cutoff := s.now().Add(-after)
+	var errs []error
+	for _, item := range s.items.Find(ctx, domain.SuspenseHeld, "") {
+		if item.Kind != "transfer" || item.CreatedAt.After(cutoff) {
+			continue
+		}
+		note := fmt.Sprintf("unclaimed after %s", after)
+		_, err := s.Return(ctx, item.ID, note)
+		switch {
+		case err == nil:
+			returned++
+		case !errors.Is(err, ErrSuspenseItemResolved):
+			errs = append(errs, fmt.Errorf("suspense item %s: %w", item.ID, err))
+		}
+	}
+	return returned, errors.Join(errs...)
+}
+
+// Watch returns expired transfers every interval until ctx is done,
+// reporting each sweep that returned something or failed.
+func (s *SuspenseService) Watch(ctx context.Context, interval time.Duration, onSweep func(returned int, err error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if returned, err := s.ReturnExpired(ctx); returned > 0 || err != nil {
+			onSweep(returned, err)
+		}
+	}
+}
+
+// Forget drops the items involving an account, for resets.
+func (s *SuspenseService) Forget(ctx context.Context, accountID string) {
+	s.items.DeleteByAccount(ctx, accountID)
+}
+
+// Reset drops every item. The suspense account went with the accounts and
+// is opened again when next needed.
+func (s *SuspenseService) Reset() {
+	s.items.Reset()
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/tracing"
+	"sync"
+)
+
+// watchBuffer is how many transactions a watcher may fall behind by
+// before it is dropped.
+const watchBuffer = 64
+
+// transactionFeed hands the transactions posted to an account to the
+// watchers of that account.
+type transactionFeed struct {
+	mu       sync.Mutex
+	watchers map[string]map[*transactionWatcher]struct{}
+}
+
+func newTransactionFeed() *transactionFeed {
+	return &transactionFeed{watchers: make(map[string]map[*transactionWatcher]struct{})}
+}
+
+// transactionWatcher receives an account's transactions. lagged is closed
+// when it fell behind and was dropped from the feed.
+type transactionWatcher struct {
+	transactions chan *domain.Transaction
+	lagged       chan struct{}
+}
+
+func (f *transactionFeed) subscribe(accountID string) *transactionWatcher {
+	watcher := &transactionWatcher{
+		transactions: make(chan *domain.Transaction, watchBuffer),
+		lagged:       make(chan struct{}),
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.watchers[accountID] == nil {
+		f.watchers[accountID] = make(map[*transactionWatcher]struct{})
+	}
+	f.watchers[accountID][watcher] = struct{}{}
+	return watcher
+}
+
+func (f *transactionFeed) unsubscribe(accountID string, watcher *transactionWatcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.watchers[accountID], watcher)
+	if len(f.watchers[accountID]) == 0 {
+		delete(f.watchers, accountID)
+	}
+}
+
+// publish sends transactions to their accounts' watchers without waiting
+// for any; a watcher whose buffer is full is dropped instead.
+func (f *transactionFeed) publish(transactions ...*domain.Transaction) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, transaction := range transactions {
+		for watcher := range f.watchers[transaction.AccountID] {
+			select {
+			case watcher.transactions <- transaction:
+			default:
+				delete(f.watchers[transaction.AccountID], watcher)
+				close(watcher.lagged)
+			}
+		}
+	}
+}
+
+// heldFeedKey marks a context whose transactions are held back from
+// watchers until released, for postings that may still be undone.
+type heldFeedKey struct{}
+
+// heldTransactions are the transactions posted under a holdFeed context,
+// each with the batch item that posted it.
+type heldTransactions struct {
+	mu           sync.Mutex
+	item         int
+	items        []int
+	transactions []*domain.Transaction
+}
+
+// posting makes the transactions published from now on belong to item; a
+// negative item's are never released.
+func (h *heldTransactions) posting(item int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.item = item
+}
+
+// holdFeed returns ctx holding back the transactions posted under it, and
+// what they are held in for releaseFeed.
+func (s *TransactionService) holdFeed(ctx context.Context) (context.Context, *heldTransactions) {
+	held := &heldTransactions{}
+	return context.WithValue(ctx, heldFeedKey{}, held), held
+}
+
+// releaseFeed publishes the held transactions of the items that kept
+// their posting, those kept reports.
+func (s *TransactionService) releaseFeed(held *heldTransactions, kept func(item int) bool) {
+	held.mu.Lock()
+	defer held.mu.Unlock()
+	for i, transaction := range held.transactions {
+		if held.items[i] >= 0 && kept(held.items[i]) {
+			s.feed.publish(transaction)
+		}
+	}
+	held.items, held.transactions = nil, nil
+}
+
+// publish tells the account's watchers about a posted transaction, or
+// holds it back when ctx says so.
+func (s *TransactionService) publish(ctx context.Context, transaction *domain.Transaction) {
+	if held, ok := ctx.Value(heldFeedKey{}).(*heldTransactions); ok {
+		held.mu.Lock()
+		held.items = append(held.items, held.item)
+		held.transactions = append(held.transactions, transaction)
+		held.mu.Unlock()
+		return
+	}
+	s.feed.publish(transaction)
+}
+
+// TransactionWatch receives the transactions posted to an account after
+// it was opened, until closed.
+type TransactionWatch struct {
+	service   *TransactionService
+	accountID string
+	watcher   *transactionWatcher
+}
+
+// WatchTransactions starts watching the account; every transaction posted
+// to it from now on is handed out by Next, in order.
+func (s *TransactionService) WatchTransactions(ctx context.Context, accountID string) (_ *TransactionWatch, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.WatchTransactions", tracing.String("account.id", accountID))
+	defer span.Finish(&err)
+
+	if _, exists := s.accountRepo.FindById(ctx, accountID); !exists {
+		return nil, ErrAccountNotFound
+	}
+	return &TransactionWatch{service: s, accountID: accountID, watcher: s.feed.subscribe(accountID)}, nil
+}
+
+// Next waits for the next transaction, or for ctx to be done. A watch that
+// fell behind hands out what it buffered before it did, so the watcher
+// knows where to pick up, then fails with ErrWatchLagged.
+func (w *TransactionWatch) Next(ctx context.Context) (*dto.TransactionResponse, error) {
+	select {
+	case transaction := <-w.watcher.transactions:
+		return w.response(transaction), nil
+	case <-w.watcher.lagged:
+		select {
+		case transaction := <-w.watcher.transactions:
+			return w.response(transaction), nil
+		default:
+			return nil, ErrWatchLagged
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the watch.
+func (w *TransactionWatch) Close() {
+	w.service.feed.unsubscribe(w.accountID, w.watcher)
+}
+
+func (w *TransactionWatch) response(transaction *domain.Transaction) *dto.TransactionResponse {
+	return w.service.mapTransactionsToResponse([]*domain.Transaction{transaction})[0]
+}
@@ -1,50 +1,412 @@
 package service
 
 import (
+	"context"
 	"corebanking/internal/domain"
 	"corebanking/internal/dto"
+	"corebanking/internal/logging"
+	"corebanking/internal/metrics"
 	"corebanking/internal/repository"
-	"fmt"
+	"corebanking/internal/tracing"
+	"corebanking/internal/utils"
+	"errors"
 	"time"
 )
 
 type TransactionService struct {
 	transactionRepo *repository.TransactionRepository
 	accountRepo     *repository.AccountRepository
+	audit           *AuditService
+	limits          *LimitService
+	fraud           *FraudService
+	aml             *AMLService
+	sanctions       *SanctionsService
+	suspense        *SuspenseService
+	locks           *accountLocks
+	feed            *transactionFeed
+	errHandler      utils.ErrorHandler
 }
 
-func NewTransactionService(trRepo *repository.TransactionRepository, acRepo *repository.AccountRepository) *TransactionService {
+func NewTransactionService(trRepo *repository.TransactionRepository, acRepo *repository.AccountRepository, audit *AuditService) *TransactionService {
 	return &TransactionService{
 		transactionRepo: trRepo,
 		accountRepo:     acRepo,
+		audit:           audit,
+		locks:           newAccountLocks(acRepo),
+		feed:            newTransactionFeed(),
 	}
 }
 
-func (s *TransactionService) CreateTransaction(req *dto.TransactionRequest) (*dto.TransactionResponse, error) {
-	account, exists := s.accountRepo.FindById(req.AccountID)
+// postingAccounts returns the accounts a posting from origin to
+// destination, either of which may be empty, may change: both, and the
+// suspense account when destination is not a customer account.
+func (s *TransactionService) postingAccounts(ctx context.Context, origin, destination string) []string {
+	ids := []string{origin, destination}
+	if destination != "" && s.suspense != nil {
+		if account, exists := s.accountRepo.FindById(ctx, destination); !exists || account.Internal {
+			ids = append(ids, s.suspense.AccountID())
+		}
+	}
+	return ids
+}
+
+// lockPosting locks the accounts a posting from origin to destination may
+// change until the returned func is called. Postings the returned context
+// is passed to don't lock them again.
+func (s *TransactionService) lockPosting(ctx context.Context, origin, destination string) (context.Context, func()) {
+	return s.locks.lock(ctx, s.postingAccounts(ctx, origin, destination)...)
+}
+
+// lockBatch is lockPosting for every item of a batch at once.
+func (s *TransactionService) lockBatch(ctx context.Context, items []domain.BatchItem) (context.Context, func()) {
+	var ids []string
+	for _, item := range items {
+		switch item.Type {
+		case domain.BatchItemTransaction:
+			ids = append(ids, item.AccountID)
+		case domain.BatchItemDeposit:
+			ids = append(ids, s.postingAccounts(ctx, "", item.Destination)...)
+		case domain.BatchItemWithdraw:
+			ids = append(ids, item.Origin)
+		default:
+			ids = append(ids, s.postingAccounts(ctx, item.Origin, item.Destination)...)
+		}
+	}
+	return s.locks.lock(ctx, ids...)
+}
+
+// SetLimitService enforces account limits on debits; without it only the
+// balance and overdraft are checked.
+func (s *TransactionService) SetLimitService(limits *LimitService) {
+	s.limits = limits
+}
+
+// reservationKey marks a context whose posting's limit reservation is
+// kept where it points, for a batch to give back should it undo the
+// posting.
+type reservationKey struct{}
+
+// reserveLimits checks a debit against the account's limits and records
+// it. The reservation is nil without limits.
+func (s *TransactionService) reserveLimits(ctx context.Context, account *domain.Account, kind DebitKind, amount int64, operation string) (*Reservation, error) {
+	if s.limits == nil {
+		return nil, nil
+	}
+	reservation, err := s.limits.Reserve(ctx, account, kind, amount)
+	if err != nil {
+		if errors.Is(err, ErrLimitExceeded) {
+			metrics.TransactionsDeclined.Inc(operation, "limit_exceeded")
+		}
+		return nil, err
+	}
+	if kept, ok := ctx.Value(reservationKey{}).(**Reservation); ok {
+		*kept = reservation
+	}
+	return reservation, nil
+}
+
+// releaseReserved gives back a debit reserveLimits reserved for a posting
+// that was then not made.
+func (s *TransactionService) releaseReserved(ctx context.Context, reservation *Reservation) {
+	if reservation == nil {
+		return
+	}
+	s.limits.Release(ctx, reservation)
+}
+
+// balanceMove is an amount added to, or taken from, an account's balance.
+// transaction, when set, records the move; it is given its ID and saved
+// once the move is applied.
+type balanceMove struct {
+	account     *domain.Account
+	delta       int64
+	transaction *domain.Transaction
+}
+
+// moveBalances audits the moves with action and only then applies and
+// saves them, so a posting the audit log cannot record changes nothing and
+// one that changed a balance is never reported as failed. Moves of the same
+// account, a transfer to itself say, add up. Every move is then published
+// to the account's watchers: as its transaction, or for moves no
+// transaction records, events say, as a posting with no transaction ID or
+// operation type.
+func (s *TransactionService) moveBalances(ctx context.Context, action string, moves ...balanceMove) error {
+	moved := make(map[string]*domain.Account, len(moves))
+	changes := make([]AuditChange, len(moves))
+	for i, move := range moves {
+		account, seen := moved[move.account.ID]
+		if !seen {
+			copied := *move.account
+			account = &copied
+			moved[account.ID] = account
+		}
+		before := *account
+		account.Balance += move.delta
+		after := *account
+		changes[i] = AuditChange{EntityID: account.ID, Before: before, After: &after}
+	}
+	if err := s.audit.RecordAll(ctx, action, "account", changes...); err != nil {
+		return err
+	}
+	for _, move := range moves {
+		*move.account = *moved[move.account.ID]
+		s.accountRepo.Save(ctx, move.account)
+	}
+	now := time.Now()
+	for _, move := range moves {
+		transaction := move.transaction
+		if transaction != nil {
+			transaction.TransactionID = domain.NextTransactionID()
+			s.transactionRepo.Save(ctx, transaction)
+		} else {
+			transaction = &domain.Transaction{AccountID: move.account.ID, Amount: move.delta, EventDate: now}
+		}
+		s.publish(ctx, transaction)
+	}
+	return nil
+}
+
+// SetFraudService screens debits with fraud before they are posted, and
+// lets it post the debits of approved cases.
+func (s *TransactionService) SetFraudService(fraud *FraudService) {
+	s.fraud = fraud
+	fraud.post = s.postApproved
+	fraud.lock = s.lockPosting
+}
+
+// screen runs a debit through fraud screening.
+func (s *TransactionService) screen(ctx context.Context, posting Screening, operation string) error {
+	if s.fraud == nil {
+		return nil
+	}
+	err := s.fraud.Screen(ctx, posting)
+	switch {
+	case errors.Is(err, ErrFraudReview):
+		metrics.TransactionsDeclined.Inc(operation, "fraud_review")
+	case errors.Is(err, ErrFraudDenied):
+		metrics.TransactionsDeclined.Inc(operation, "fraud_denied")
+	}
+	return err
+}
+
+// recordActivity adds a posted amount to the account's fraud history.
+func (s *TransactionService) recordActivity(ctx context.Context, accountID, kind, destination string, amount int64) {
+	if s.fraud != nil {
+		s.fraud.Record(ctx, accountID, kind, destination, amount)
+	}
+}
+
+// SetAMLService reports posted movements to AML monitoring.
+func (s *TransactionService) SetAMLService(aml *AMLService) {
+	s.aml = aml
+}
+
+// SetErrorHandler receives the failures of work done after a posting,
+// which must not fail the posting itself.
+func (s *TransactionService) SetErrorHandler(errHandler utils.ErrorHandler) {
+	s.errHandler = errHandler
+}
+
+// observe reports a posted movement to AML monitoring. The money has
+// already moved, so a failure is handed to the error handler rather than
+// returned: reporting the posting as failed would invite a retry that
+// posts it twice.
+func (s *TransactionService) observe(ctx context.Context, accountID, kind string, amount int64) {
+	if s.aml == nil {
+		return
+	}
+	if err := s.aml.Observe(ctx, accountID, kind, amount); err != nil && s.errHandler != nil {
+		s.errHandler.Handle(logging.WithAccountID(ctx, accountID), err, "Failed to report a posted movement to AML monitoring.")
+	}
+}
+
+// SetSanctionsService screens the holders of transfer destinations, and
+// lets it post the transfers of approved cases.
+func (s *TransactionService) SetSanctionsService(sanctions *SanctionsService) {
+	s.sanctions = sanctions
+	sanctions.post = s.postCleared
+	sanctions.lock = s.lockPosting
+}
+
+// postCleared posts the transfer held by an approved sanctions case.
+func (s *TransactionService) postCleared(ctx context.Context, sanctionsCase *domain.SanctionsCase) error {
+	_, err := s.HandleTransaction(ctx, &dto.EventRequest{
+		Type:        "transfer",
+		Origin:      sanctionsCase.Origin,
+		Destination: sanctionsCase.AccountID,
+		Amount:      sanctionsCase.Amount,
+	})
+	return posted(err)
+}
+
+// SetSuspenseService lets deposits and transfers to unknown accounts go to
+// the suspense account when its policy says so, and lets it move held
+// funds out again. Without it they are declined.
+func (s *TransactionService) SetSuspenseService(suspense *SuspenseService) {
+	s.suspense = suspense
+	suspense.release = s.releaseSuspense
+	suspense.lock = s.lockPosting
+}
+
+// destination finds the account a deposit or transfer credits. An unknown
+// or internal account is declined with ErrDestinationNotFound or, under
+// the suspense policy, replaced by the suspense account with suspended
+// set.
+func (s *TransactionService) destination(ctx context.Context, id, operation string) (_ *domain.Account, suspended bool, _ error) {
+	if account, exists := s.accountRepo.FindById(ctx, id); exists && !account.Internal {
+		return account, false, nil
+	}
+	if s.suspense == nil || s.suspense.Policy().UnknownDestination != domain.UnknownDestinationSuspense {
+		metrics.TransactionsDeclined.Inc(operation, "unknown_destination")
+		return nil, false, ErrDestinationNotFound
+	}
+	return s.suspense.Ledger(ctx), true, nil
+}
+
+// source finds the account a transaction or a withdrawal or transfer
+// posts to. Internal accounts are not found: their money only moves
+// through the services that own them, as destination declines them too.
+func (s *TransactionService) source(ctx context.Context, id string) (*domain.Account, bool) {
+	account, exists := s.accountRepo.FindById(ctx, id)
+	if !exists || account.Internal {
+		return nil, false
+	}
+	return account, true
+}
+
+// hold records a posting to the suspense account and returns the
+// ErrHeldInSuspense naming the item.
+func (s *TransactionService) hold(ctx context.Context, kind, origin, destination string, amount int64) error {
+	item, err := s.suspense.Hold(ctx, kind, origin, destination, amount)
+	if err != nil {
+		return err
+	}
+	return &HoldError{Err: ErrHeldInSuspense, ID: item.ID}
+}
+
+// releaseSuspense moves a held item's amount out of the suspense account,
+// into accountID or, when it is empty, out of the bank.
+func (s *TransactionService) releaseSuspense(ctx context.Context, item *domain.SuspenseItem, accountID string) error {
+	var account *domain.Account
+	if accountID != "" {
+		found, exists := s.accountRepo.FindById(ctx, accountID)
+		if !exists || found.Internal {
+			return ErrAccountNotFound
+		}
+		account = found
+	}
+
+	moves := []balanceMove{{account: s.suspense.Ledger(ctx), delta: -item.Amount}}
+	if account != nil {
+		moves = append(moves, balanceMove{account: account, delta: item.Amount})
+	}
+	if err := s.moveBalances(ctx, AuditAccountBalance, moves...); err != nil {
+		return err
+	}
+	if account == nil {
+		return nil
+	}
+
+	s.recordActivity(ctx, account.ID, domain.ActivityCredit, "", item.Amount)
+	movement := domain.MovementCredit
+	if item.Kind == "deposit" {
+		movement = domain.MovementCashDeposit
+	}
+	s.observe(ctx, account.ID, movement, item.Amount)
+	return nil
+}
+
+// posted treats a posting held in suspense as posted, for callers that
+// only need to know the money left the origin.
+func posted(err error) error {
+	if errors.Is(err, ErrHeldInSuspense) {
+		return nil
+	}
+	return err
+}
+
+// postApproved posts the debit held by an approved fraud case.
+func (s *TransactionService) postApproved(ctx context.Context, fraudCase *domain.FraudCase) error {
+	var err error
+	if fraudCase.OperationTypeID != 0 {
+		_, err = s.CreateTransaction(ctx, &dto.TransactionRequest{
+			AccountID:       fraudCase.AccountID,
+			OperationTypeID: fraudCase.OperationTypeID,
+			Amount:          fraudCase.Amount,
+		})
+	} else {
+		_, err = s.HandleTransaction(ctx, &dto.EventRequest{
+			Type:        fraudCase.Kind,
+			Origin:      fraudCase.AccountID,
+			Destination: fraudCase.Destination,
+			Amount:      fraudCase.Amount,
+		})
+	}
+	return posted(err)
+}
+
+func (s *TransactionService) CreateTransaction(ctx context.Context, req *dto.TransactionRequest) (_ *dto.TransactionResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.CreateTransaction",
+		tracing.String("account.id", req.AccountID), tracing.Int("transaction.operation_type", req.OperationTypeID))
+	defer span.Finish(&err)
+
+	if !validOperationType(req.OperationTypeID) {
+		return nil, ErrInvalidOperationType
+	}
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	ctx, unlock := s.lockPosting(ctx, req.AccountID, "")
+	defer unlock()
+
+	account, exists := s.source(ctx, req.AccountID)
 	if !exists {
-		return nil, fmt.Errorf("account not found")
+		return nil, ErrAccountNotFound
 	}
 
 	amount := s.normalizeAmount(req.OperationTypeID, req.Amount)
 	available := account.Balance + account.OverdraftLimit
 
 	if amount < 0 && (available+amount) < 0 {
-		return nil, fmt.Errorf("insufficient funds for transaction")
+		metrics.TransactionsDeclined.Inc(metrics.OperationLabel(req.OperationTypeID), "insufficient_funds")
+		return nil, ErrInsufficientFunds
+	}
+	activity := domain.ActivityCredit
+	var reservation *Reservation
+	if amount < 0 {
+		kind := DebitPurchase
+		activity = domain.ActivityPurchase
+		if req.OperationTypeID == 3 {
+			kind, activity = DebitWithdrawal, domain.ActivityWithdraw
+		}
+		posting := Screening{Account: account, Kind: activity, OperationTypeID: req.OperationTypeID, Amount: -amount}
+		if err := s.screen(ctx, posting, metrics.OperationLabel(req.OperationTypeID)); err != nil {
+			return nil, err
+		}
+		var err error
+		if reservation, err = s.reserveLimits(ctx, account, kind, -amount, metrics.OperationLabel(req.OperationTypeID)); err != nil {
+			return nil, err
+		}
 	}
-
-	account.Balance += amount
-	s.accountRepo.Save(account)
 
 	transaction := &domain.Transaction{
-		TransactionID:   domain.NextTransactionID(),
 		AccountID:       req.AccountID,
 		OperationTypeID: req.OperationTypeID,
 		Amount:          amount,
 		EventDate:       time.Now(),
 	}
+	if err := s.moveBalances(ctx, AuditAccountBalance, balanceMove{account, amount, transaction}); err != nil {
+		s.releaseReserved(ctx, reservation)
+		return nil, err
+	}
 
-	s.transactionRepo.Save(transaction)
+	s.recordActivity(ctx, account.ID, activity, "", max(amount, -amount))
+	movement := domain.MovementCredit
+	if amount < 0 {
+		movement = domain.MovementDebit
+	}
+	s.observe(ctx, account.ID, movement, max(amount, -amount))
+	metrics.TransactionsPosted.Inc(metrics.OperationLabel(req.OperationTypeID))
 
 	return &dto.TransactionResponse{
 		TransactionID:   transaction.TransactionID,
@@ -55,10 +417,13 @@ func (s *TransactionService) CreateTransaction(req *dto.TransactionRequest) (*dt
 	}, nil
 }
 
-func (s *TransactionService) GetTransactionByID(transactionID int64) (*dto.TransactionResponse, error) {
-	transaction := s.transactionRepo.FindByID(transactionID)
+func (s *TransactionService) GetTransactionByID(ctx context.Context, transactionID int64) (_ *dto.TransactionResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.GetTransactionByID", tracing.Int64("transaction.id", transactionID))
+	defer span.Finish(&err)
+
+	transaction := s.transactionRepo.FindByID(ctx, transactionID)
 	if transaction == nil {
-		return nil, fmt.Errorf("transaction not found")
+		return nil, ErrTransactionNotFound
 	}
 
 	return &dto.TransactionResponse{
@@ -70,25 +435,38 @@ func (s *TransactionService) GetTransactionByID(transactionID int64) (*dto.Trans
 	}, nil
 }
 
-func (s *TransactionService) GetTransactionsToday() []*dto.TransactionResponse {
+func (s *TransactionService) GetTransactionsToday(ctx context.Context) []*dto.TransactionResponse {
+	ctx, span := tracing.Start(ctx, "TransactionService.GetTransactionsToday")
+	defer span.End()
+
 	today := time.Now()
-	transactions := s.transactionRepo.FindAllTransactionOnDate(today)
+	transactions := s.transactionRepo.FindAllTransactionOnDate(ctx, today)
 	return s.mapTransactionsToResponse(transactions)
 }
 
-func (s *TransactionService) GetTransactionsInRange(begin, end time.Time) []*dto.TransactionResponse {
-	transactions := s.transactionRepo.FindAllTransactionsBetweenDate(begin, end)
+func (s *TransactionService) GetTransactionsInRange(ctx context.Context, begin, end time.Time) []*dto.TransactionResponse {
+	ctx, span := tracing.Start(ctx, "TransactionService.GetTransactionsInRange")
+	defer span.End()
+
+	transactions := s.transactionRepo.FindAllTransactionsBetweenDate(ctx, begin, end)
 	return s.mapTransactionsToResponse(transactions)
 }
 
-func (s *TransactionService) GetTransactionsByType(operationTypeID int) []*dto.TransactionResponse {
-	if operationTypeID < 1 || operationTypeID > 4 {
+func (s *TransactionService) GetTransactionsByType(ctx context.Context, operationTypeID int) []*dto.TransactionResponse {
+	ctx, span := tracing.Start(ctx, "TransactionService.GetTransactionsByType", tracing.Int("transaction.operation_type", operationTypeID))
+	defer span.End()
+
+	if !validOperationType(operationTypeID) {
 		return nil
 	}
-	transactions := s.transactionRepo.FindAllOperationTypeByID(operationTypeID)
+	transactions := s.transactionRepo.FindAllOperationTypeByID(ctx, operationTypeID)
 	return s.mapTransactionsToResponse(transactions)
 }
 
+func validOperationType(operationTypeID int) bool {
+	return operationTypeID >= 1 && operationTypeID <= 4
+}
+
 func (s *TransactionService) normalizeAmount(operationTypeID int, amount int64) int64 {
 	switch operationTypeID {
 	case 1, 2, 3:
@@ -100,74 +478,141 @@ func (s *TransactionService) normalizeAmount(operationTypeID int, amount int64)
 	}
 }
 
-func (s *TransactionService) HandleTransaction(req *dto.EventRequest) (interface{}, error) {
+func (s *TransactionService) HandleTransaction(ctx context.Context, req *dto.EventRequest) (_ interface{}, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.HandleTransaction",
+		tracing.String("event.type", req.Type), tracing.String("event.origin", req.Origin), tracing.String("event.destination", req.Destination))
+	defer span.Finish(&err)
+
+	// The sign comes from the event type: a negative amount would move
+	// money the other way, out of a destination its sender can't debit.
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
 	switch req.Type {
 	case "deposit":
-		return s.handleDeposit(req)
+		return s.handleDeposit(ctx, req)
 	case "withdraw":
-		return s.handleWithdraw(req)
+		return s.handleWithdraw(ctx, req)
 	case "transfer":
-		return s.handleTransfer(req)
+		return s.handleTransfer(ctx, req)
 	default:
-		return nil, fmt.Errorf("invalid event type")
+		return nil, ErrInvalidEventType
 	}
 }
 
-func (s *TransactionService) handleDeposit(req *dto.EventRequest) (map[string]*domain.Account, error) {
+func (s *TransactionService) handleDeposit(ctx context.Context, req *dto.EventRequest) (map[string]*domain.Account, error) {
+	ctx, unlock := s.lockPosting(ctx, "", req.Destination)
+	defer unlock()
+
 	// Recupera a conta do repositório
-	account, exists := s.accountRepo.FindById(req.Destination)
-	if !exists {
-		account = domain.NewAccount(req.Destination, 0)
+	account, suspended, err := s.destination(ctx, req.Destination, "deposit")
+	if err != nil {
+		return nil, err
 	}
 
-	account.Balance += req.Amount
-	s.accountRepo.Save(account)
+	if err := s.moveBalances(ctx, AuditAccountBalance, balanceMove{account: account, delta: req.Amount}); err != nil {
+		return nil, err
+	}
+	if suspended {
+		metrics.TransactionsPosted.Inc("deposit")
+		return nil, s.hold(ctx, "deposit", "", req.Destination, req.Amount)
+	}
+	s.recordActivity(ctx, account.ID, domain.ActivityCredit, "", req.Amount)
+	s.observe(ctx, account.ID, domain.MovementCashDeposit, req.Amount)
+
+	metrics.TransactionsPosted.Inc("deposit")
 
 	return map[string]*domain.Account{
 		"destination": account,
 	}, nil
 }
 
-func (s *TransactionService) handleWithdraw(req *dto.EventRequest) (map[string]*domain.Account, error) {
-	account, exists := s.accountRepo.FindById(req.Origin)
+func (s *TransactionService) handleWithdraw(ctx context.Context, req *dto.EventRequest) (map[string]*domain.Account, error) {
+	ctx, unlock := s.lockPosting(ctx, req.Origin, "")
+	defer unlock()
+
+	account, exists := s.source(ctx, req.Origin)
 	if !exists {
-		return nil, fmt.Errorf("account not found")
+		return nil, ErrAccountNotFound
 	}
 
 	available := account.Balance + account.OverdraftLimit
 	if available < req.Amount {
-		return nil, fmt.Errorf("insufficient funds, including overdraft")
+		metrics.TransactionsDeclined.Inc("withdraw", "insufficient_funds")
+		return nil, ErrInsufficientOverdraft
+	}
+	if err := s.screen(ctx, Screening{Account: account, Kind: domain.ActivityWithdraw, Amount: req.Amount}, "withdraw"); err != nil {
+		return nil, err
+	}
+	reservation, err := s.reserveLimits(ctx, account, DebitWithdrawal, req.Amount, "withdraw")
+	if err != nil {
+		return nil, err
 	}
 
-	account.Balance -= req.Amount
-	s.accountRepo.Save(account)
+	if err := s.moveBalances(ctx, AuditAccountBalance, balanceMove{account: account, delta: -req.Amount}); err != nil {
+		s.releaseReserved(ctx, reservation)
+		return nil, err
+	}
+	s.recordActivity(ctx, account.ID, domain.ActivityWithdraw, "", req.Amount)
+	s.observe(ctx, account.ID, domain.MovementDebit, req.Amount)
+
+	metrics.TransactionsPosted.Inc("withdraw")
 
 	return map[string]*domain.Account{
 		"origin": account,
 	}, nil
 }
 
-func (s *TransactionService) handleTransfer(req *dto.EventRequest) (map[string]*domain.Account, error) {
-	origin, exists := s.accountRepo.FindById(req.Origin)
+func (s *TransactionService) handleTransfer(ctx context.Context, req *dto.EventRequest) (map[string]*domain.Account, error) {
+	ctx, unlock := s.lockPosting(ctx, req.Origin, req.Destination)
+	defer unlock()
+
+	origin, exists := s.source(ctx, req.Origin)
 	if !exists {
-		return nil, fmt.Errorf("origin account not found")
+		return nil, ErrOriginNotFound
 	}
 
-	destination, exists := s.accountRepo.FindById(req.Destination)
-	if !exists {
-		destination = domain.NewAccount(req.Destination, 0)
+	destination, suspended, err := s.destination(ctx, req.Destination, "transfer")
+	if err != nil {
+		return nil, err
 	}
 
 	available := origin.Balance + origin.OverdraftLimit
 	if available < req.Amount {
-		return nil, fmt.Errorf("insufficient funds, including overdraft")
+		metrics.TransactionsDeclined.Inc("transfer", "insufficient_funds")
+		return nil, ErrInsufficientOverdraft
+	}
+	if s.sanctions != nil && !suspended {
+		if err := s.sanctions.ScreenTransfer(ctx, origin.ID, destination.ID, req.Amount); err != nil {
+			if errors.Is(err, ErrSanctionsReview) {
+				metrics.TransactionsDeclined.Inc("transfer", "sanctions_review")
+			}
+			return nil, err
+		}
+	}
+	posting := Screening{Account: origin, Kind: domain.ActivityTransfer, Destination: req.Destination, Amount: req.Amount}
+	if err := s.screen(ctx, posting, "transfer"); err != nil {
+		return nil, err
+	}
+	reservation, err := s.reserveLimits(ctx, origin, DebitTransfer, req.Amount, "transfer")
+	if err != nil {
+		return nil, err
 	}
 
-	origin.Balance -= req.Amount
-	destination.Balance += req.Amount
+	if err := s.moveBalances(ctx, AuditAccountBalance, balanceMove{account: origin, delta: -req.Amount}, balanceMove{account: destination, delta: req.Amount}); err != nil {
+		s.releaseReserved(ctx, reservation)
+		return nil, err
+	}
+	s.recordActivity(ctx, origin.ID, domain.ActivityTransfer, req.Destination, req.Amount)
+	s.observe(ctx, origin.ID, domain.MovementDebit, req.Amount)
+	metrics.TransactionsPosted.Inc("transfer")
+	metrics.TransferVolume.Add(float64(req.Amount))
+	if suspended {
+		return nil, s.hold(ctx, "transfer", origin.ID, req.Destination, req.Amount)
+	}
 
-	s.accountRepo.Save(origin)
-	s.accountRepo.Save(destination)
+	s.recordActivity(ctx, destination.ID, domain.ActivityCredit, "", req.Amount)
+	s.observe(ctx, destination.ID, domain.MovementCredit, req.Amount)
 
 	return map[string]*domain.Account{
 		"origin":      origin,
@@ -175,6 +620,105 @@ func (s *TransactionService) handleTransfer(req *dto.EventRequest) (map[string]*
 	}, nil
 }
 
+// batchPosting is what a batch item changed, so an all-or-nothing batch
+// can undo it: the balances it moved, the transaction it recorded and the
+// debit it reserved against limits, in the periods it was reserved in.
+type batchPosting struct {
+	balances      []balanceChange
+	transactionID int64
+	reservation   *Reservation
+}
+
+type balanceChange struct {
+	accountID string
+	delta     int64
+}
+
+// postBatchItem posts a batch item as a transaction or an event. The
+// posting it returns is what the item changes when it is posted, also
+// returned with a HoldError since held transfers and deposits still
+// reserve and move money.
+func (s *TransactionService) postBatchItem(ctx context.Context, item domain.BatchItem) (*batchPosting, error) {
+	posting := &batchPosting{}
+	ctx = context.WithValue(ctx, reservationKey{}, &posting.reservation)
+	if item.Type == domain.BatchItemTransaction {
+		transaction, err := s.CreateTransaction(ctx, &dto.TransactionRequest{
+			AccountID:       item.AccountID,
+			OperationTypeID: item.OperationTypeID,
+			Amount:          item.Amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+		posting.balances = []balanceChange{{transaction.AccountID, transaction.Amount}}
+		posting.transactionID = transaction.TransactionID
+		return posting, nil
+	}
+
+	switch item.Type {
+	case domain.BatchItemDeposit:
+		posting.balances = []balanceChange{{item.Destination, item.Amount}}
+	case domain.BatchItemWithdraw:
+		posting.balances = []balanceChange{{item.Origin, -item.Amount}}
+	case domain.BatchItemTransfer:
+		posting.balances = []balanceChange{{item.Origin, -item.Amount}, {item.Destination, item.Amount}}
+	}
+	req := dto.NewEventRequest(item.Type, item.Origin, item.Destination, item.Amount)
+	_, err := s.HandleTransaction(ctx, &req)
+	return posting, err
+}
+
+// undoBatchItem reverses a batch item posted by postBatchItem, given the
+// error it returned. A review hold is undone by rejecting its case, which
+// never posted; a suspense hold by returning the item, which moves the
+// money back; a posting by reversing its balances, with the reversal
+// audited, and dropping its transaction. Either way limits reserved are
+// given back. The item stays in fraud and AML history: it was attempted.
+// The caller holds the batch's accounts locked, so reversing a balance
+// gives back exactly what the posting moved and nothing was spent since.
+func (s *TransactionService) undoBatchItem(ctx context.Context, posting *batchPosting, err error) error {
+	const note = "batch rolled back"
+	var hold *HoldError
+	if errors.As(err, &hold) {
+		switch {
+		case errors.Is(hold.Err, ErrFraudReview):
+			_, err := s.fraud.Reject(ctx, hold.ID, note)
+			return err
+		case errors.Is(hold.Err, ErrSanctionsReview):
+			_, err := s.sanctions.Reject(ctx, hold.ID, note)
+			return err
+		}
+		if _, err := s.suspense.Return(ctx, hold.ID, note); err != nil {
+			return err
+		}
+		return s.releaseLimits(ctx, posting)
+	}
+
+	moves := make([]balanceMove, len(posting.balances))
+	for i, change := range posting.balances {
+		account, exists := s.accountRepo.FindById(ctx, change.accountID)
+		if !exists {
+			return ErrAccountNotFound
+		}
+		moves[i] = balanceMove{account: account, delta: -change.delta}
+	}
+	if err := s.moveBalances(ctx, AuditBatchRollback, moves...); err != nil {
+		return err
+	}
+	if posting.transactionID != 0 {
+		s.transactionRepo.Delete(ctx, posting.transactionID)
+	}
+	return s.releaseLimits(ctx, posting)
+}
+
+// releaseLimits gives back the debit a batch posting reserved.
+func (s *TransactionService) releaseLimits(ctx context.Context, posting *batchPosting) error {
+	if posting.reservation == nil {
+		return nil
+	}
+	return s.limits.Release(ctx, posting.reservation)
+}
+
 func (s *TransactionService) mapTransactionsToResponse(transactions []*domain.Transaction) []*dto.TransactionResponse {
 	result := make([]*dto.TransactionResponse, 0, len(transactions))
 	for _, t := range transactions {
@@ -189,6 +733,9 @@ func (s *TransactionService) mapTransactionsToResponse(transactions []*domain.Tr
 	return result
 }
 
-func (s *TransactionService) GetAllTransactions() []*domain.Transaction {
-	return s.transactionRepo.FindAll()
+func (s *TransactionService) GetAllTransactions(ctx context.Context) []*domain.Transaction {
+	ctx, span := tracing.Start(ctx, "TransactionService.GetAllTransactions")
+	defer span.End()
+
+	return s.transactionRepo.FindAll(ctx)
 }
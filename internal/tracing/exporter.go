@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter sends finished spans somewhere.
+type Exporter = sdktrace.SpanExporter
+
+// NewWriterExporter writes one JSON object per span, for stdout or a file
+// while debugging locally. Each span is a single Write, so line-oriented
+// writers such as LogChannel keep spans whole.
+func NewWriterExporter(w io.Writer) (Exporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
+
+// NewOTLPExporter posts spans to an OpenTelemetry collector over OTLP/HTTP,
+// usually at http://collector:4318/v1/traces, adding headers to every
+// request.
+func NewOTLPExporter(endpoint string, headers map[string]string) (Exporter, error) {
+	return otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(endpoint),
+		otlptracehttp.WithHeaders(headers),
+	)
+}
+
+// reportingExporter hands export failures to onError instead of the SDK's
+// global error handler, which would print them to stderr a second time.
+type reportingExporter struct {
+	Exporter
+	onError func(error)
+}
+
+func (e *reportingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := e.Exporter.ExportSpans(ctx, spans); err != nil {
+		e.onError(err)
+	}
+	return nil
+}
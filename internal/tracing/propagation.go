@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceparentHeader carries W3C trace context.
+const TraceparentHeader = "traceparent"
+
+var propagator = propagation.TraceContext{}
+
+// ParseTraceparent reads a W3C traceparent value such as
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func ParseTraceparent(value string) (SpanContext, bool) {
+	header := http.Header{}
+	header.Set(TraceparentHeader, value)
+	sc := trace.SpanContextFromContext(Extract(context.Background(), header))
+	return sc, sc.IsValid()
+}
+
+// Traceparent formats sc as a version 00 traceparent value.
+func Traceparent(sc SpanContext) string {
+	header := http.Header{}
+	Inject(trace.ContextWithSpanContext(context.Background(), sc), header)
+	return header.Get(TraceparentHeader)
+}
+
+// Extract returns ctx with the remote parent from the request headers, if
+// they carry a valid traceparent.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject sets traceparent on outgoing headers from the span in ctx.
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
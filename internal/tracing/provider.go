@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// SetProvider makes p record the spans started from now on; nil turns
+// tracing off.
+func SetProvider(p *Provider) {
+	if p == nil {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return
+	}
+	otel.SetTracerProvider(p.provider)
+}
+
+// Options configures a Provider.
+type Options struct {
+	// ServiceName is reported as the service.name resource attribute; the
+	// SDK's default applies when it is empty.
+	ServiceName string
+	// SampleRatio is the share of new traces recorded, from 0 to 1.
+	// Children follow their parent's decision, remote parents included.
+	SampleRatio float64
+	// QueueSize bounds the spans waiting for export; spans beyond it are
+	// dropped so tracing never blocks a request. It defaults to 2048.
+	QueueSize int
+	// BatchSize exports as soon as this many spans are queued. It defaults
+	// to 512.
+	BatchSize int
+	// BatchInterval exports whatever is queued at this interval. It
+	// defaults to 5s.
+	BatchInterval time.Duration
+	// OnError receives export failures.
+	OnError func(error)
+}
+
+// Provider batches finished spans and hands them to an exporter from a
+// background goroutine.
+type Provider struct {
+	provider *sdktrace.TracerProvider
+}
+
+func NewProvider(exporter Exporter, options Options) *Provider {
+	if options.OnError != nil {
+		exporter = &reportingExporter{Exporter: exporter, onError: options.OnError}
+	}
+	var batch []sdktrace.BatchSpanProcessorOption
+	if options.QueueSize > 0 {
+		batch = append(batch, sdktrace.WithMaxQueueSize(options.QueueSize))
+	}
+	if options.BatchSize > 0 {
+		batch = append(batch, sdktrace.WithMaxExportBatchSize(options.BatchSize))
+	}
+	if options.BatchInterval > 0 {
+		batch = append(batch, sdktrace.WithBatchTimeout(options.BatchInterval))
+	}
+
+	providerOptions := []sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter, batch...),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+	}
+	if options.ServiceName != "" {
+		providerOptions = append(providerOptions, sdktrace.WithResource(
+			resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(options.ServiceName))))
+	}
+	return &Provider{provider: sdktrace.NewTracerProvider(providerOptions...)}
+}
+
+// ForceFlush exports everything queued so far.
+func (p *Provider) ForceFlush() error {
+	return p.provider.ForceFlush(context.Background())
+}
+
+// Shutdown exports the queued spans and closes the exporter. It returns
+// ctx's error if that takes too long.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.provider.Shutdown(ctx)
+}
@@ -0,0 +1,106 @@
+// Package tracing records spans with the OpenTelemetry SDK and propagates
+// W3C trace context. It keeps the call sites short: spans start with a name
+// and attributes and finish with the error they ended on.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer every span comes from.
+const instrumentationName = "corebanking"
+
+// SpanContext identifies a span across process boundaries.
+type SpanContext = trace.SpanContext
+
+// Kind follows the OpenTelemetry span kinds.
+type Kind = trace.SpanKind
+
+const (
+	KindInternal = trace.SpanKindInternal
+	KindServer   = trace.SpanKindServer
+	KindClient   = trace.SpanKindClient
+)
+
+// Attr is a span attribute.
+type Attr = attribute.KeyValue
+
+func String(key, value string) Attr {
+	return attribute.String(key, value)
+}
+
+func Int64(key string, value int64) Attr {
+	return attribute.Int64(key, value)
+}
+
+func Int(key string, value int) Attr {
+	return attribute.Int(key, value)
+}
+
+func Bool(key string, value bool) Attr {
+	return attribute.Bool(key, value)
+}
+
+// Span is an operation in a trace. While no provider is set, or when the
+// trace is not sampled, it records nothing.
+type Span struct {
+	span trace.Span
+}
+
+// Start begins an internal span, child of the span in ctx or of a remote
+// parent extracted from the request headers.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, Span) {
+	return StartKind(ctx, name, KindInternal, attrs...)
+}
+
+func StartKind(ctx context.Context, name string, kind Kind, attrs ...Attr) (context.Context, Span) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	return ctx, Span{span: span}
+}
+
+// SpanContextFromContext returns the current span's context, falling back
+// to a remote parent.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	return trace.SpanContextFromContext(ctx)
+}
+
+func (s Span) SpanContext() SpanContext {
+	return s.span.SpanContext()
+}
+
+func (s Span) SetName(name string) {
+	s.span.SetName(name)
+}
+
+func (s Span) SetAttributes(attrs ...Attr) {
+	s.span.SetAttributes(attrs...)
+}
+
+// RecordError marks the span as failed; a nil err is ignored.
+func (s Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End finishes the span and queues it for export when sampled. Only the
+// first call has an effect.
+func (s Span) End() {
+	s.span.End()
+}
+
+// Finish records *errp and ends the span, for deferring in functions with a
+// named error result.
+func (s Span) Finish(errp *error) {
+	if errp != nil {
+		s.RecordError(*errp)
+	}
+	s.End()
+}
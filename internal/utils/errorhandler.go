@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"corebanking/internal/dto"
 	"encoding/json"
 	"net/http"
 )
@@ -10,20 +11,15 @@ type ErrorHandler interface {
 	Handle(ctx context.Context, err error, message string)
 }
 
-func HandleHTTPError(w http.ResponseWriter, err error, message string, logger ErrorHandler) {
+// HandleHTTPError logs the failure with the request's context, so the log
+// line carries its request ID, and answers 400 with the v1 error body.
+func HandleHTTPError(w http.ResponseWriter, r *http.Request, err error, message string, logger ErrorHandler) {
 	if logger != nil {
-		logger.Handle(context.Background(), err, message)
+		logger.Handle(r.Context(), err, message)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusBadRequest)
 
-	resp := map[string]string{
-		"error":   message,
-		"details": "",
-	}
-	if err != nil {
-		resp["details"] = err.Error()
-	}
-	json.NewEncoder(w).Encode(resp)
+	json.NewEncoder(w).Encode(dto.NewErrorResponse(message, err))
 }
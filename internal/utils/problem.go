@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"corebanking/internal/dto"
+	"encoding/json"
+	"net/http"
+)
+
+func WriteProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(dto.NewProblem(status, http.StatusText(status), detail, r.URL.Path))
+}
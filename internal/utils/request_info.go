@@ -0,0 +1,31 @@
+package utils
+
+import "context"
+
+// RequestInfo collects what inner handlers learn about a request, so outer
+// middleware such as the access log can read it once the request is served.
+type RequestInfo struct {
+	// Pattern is the ServeMux pattern that matched, empty when none did.
+	Pattern string
+	// Principal is the subject of the authenticated caller.
+	Principal string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo attaches a RequestInfo to ctx, reusing one that an outer
+// middleware already attached so every layer reads the same values.
+func WithRequestInfo(ctx context.Context) (context.Context, *RequestInfo) {
+	if info := RequestInfoFrom(ctx); info != nil {
+		return ctx, info
+	}
+	info := &RequestInfo{}
+	return context.WithValue(ctx, requestInfoKey{}, info), info
+}
+
+// RequestInfoFrom returns the request's info, or nil outside of a request
+// wrapped by WithRequestInfo.
+func RequestInfoFrom(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
@@ -2,34 +2,23 @@ package worker
 
 import (
 	"context"
-	"corebanking/internal/event"
-	"fmt"
-	"time"
+	"log/slog"
 )
 
 type ErrorWorker struct {
-	logChannel *event.LogChannel
+	logger *slog.Logger
 }
 
-func NewErrorWorker(logChannel *event.LogChannel) *ErrorWorker {
-	return &ErrorWorker{logChannel: logChannel}
+func NewErrorWorker(logger *slog.Logger) *ErrorWorker {
+	return &ErrorWorker{logger: logger}
 }
 
+// Handle logs with ctx, which carries the request ID of the failing request.
+// It logs even when ctx is already cancelled, e.g. the client went away.
 func (w *ErrorWorker) Handle(ctx context.Context, err error, message string) {
-
-	var logMsg string
 	if err != nil {
-		logMsg = fmt.Sprintf("[ERROR] %s | details: %v | time: %s",
-			message, err, time.Now().Format(time.RFC3339))
-	} else {
-		logMsg = fmt.Sprintf("[ERROR] %s | time: %s",
-			message, time.Now().Format(time.RFC3339))
-	}
-
-	select {
-	case <-ctx.Done():
+		w.logger.ErrorContext(ctx, message, "error", err.Error())
 		return
-	default:
-		w.logChannel.Send(logMsg)
 	}
+	w.logger.ErrorContext(ctx, message)
 }
@@ -1,60 +1,592 @@
 package main
 
 import (
+	"context"
 	"corebanking/config"
+	"corebanking/internal/aml"
+	"corebanking/internal/auth"
 	"corebanking/internal/controller"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
 	"corebanking/internal/event"
+	"corebanking/internal/fraud"
+	"corebanking/internal/health"
+	"corebanking/internal/logging"
+	"corebanking/internal/metrics"
+	"corebanking/internal/middleware"
+	"corebanking/internal/ratelimit"
 	"corebanking/internal/repository"
+	"corebanking/internal/rpc"
+	"corebanking/internal/sanctions"
+	"corebanking/internal/server"
 	"corebanking/internal/service"
+	"corebanking/internal/tracing"
 	"corebanking/internal/worker"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
-	cfg := config.LoadConfig()
-
-	logChannel, err := event.NewLogChannel("log/transactions.log", 100)
+	loader := config.NewLoader(os.Args[1:])
+	cfg, err := loader.Load()
 	if err != nil {
-		panic("Failed to initialize log channel: " + err.Error())
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if loader.PrintConfig {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(cfg.Redacted())
+		return
+	}
+
+	// ctx ends on SIGINT or SIGTERM; a second signal kills the process.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// Validate has checked the level and overflow policy. The level sits
+	// in a LevelVar so a reload can change it.
+	level, _ := logging.ParseLevel(cfg.LogLevel)
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(level)
+	overflow, _ := event.ParseOverflowPolicy(cfg.LogOverflow)
+	var sinks []io.Writer
+	var logFile *event.LogChannel
+	for _, output := range strings.Split(cfg.LogOutputs, ",") {
+		switch strings.TrimSpace(output) {
+		case "stdout":
+			sinks = append(sinks, os.Stdout)
+		case "file":
+			logChannel, err := event.NewLogChannelWithOptions(cfg.LogPath, event.Options{
+				BufferSize:    cfg.LogBufferSize,
+				Overflow:      overflow,
+				SpillSize:     cfg.LogSpillSize,
+				FlushInterval: cfg.LogFlushInterval,
+				OnError: func(err error) {
+					fmt.Fprintln(os.Stderr, "log file write failed:", err)
+				},
+				Rotation: event.RotateOptions{
+					MaxSize:    int64(cfg.LogMaxSizeMB) << 20,
+					Daily:      cfg.LogRotateDaily,
+					Compress:   cfg.LogCompress,
+					MaxBackups: cfg.LogMaxBackups,
+					MaxAge:     cfg.LogMaxAge,
+				},
+			})
+			if err != nil {
+				panic("Failed to initialize log channel: " + err.Error())
+			}
+			defer logChannel.Close()
+			metrics.Default.GaugeFunc("corebanking_log_queue_depth", "Log messages waiting to be written to the log file.", func() float64 {
+				return float64(logChannel.QueueDepth())
+			})
+			metrics.Default.CounterFunc("corebanking_log_dropped_total", "Log messages dropped by the overflow policy.", func() float64 {
+				return float64(logChannel.Stats().Dropped)
+			})
+			sinks = append(sinks, logChannel)
+			logFile = logChannel
+		}
 	}
-	defer logChannel.Close()
+	logger := logging.New(logLevel, sinks...)
+	slog.SetDefault(logger)
 
-	logChannel.Send("[INFO] Apllication has been started.")
+	logger.Info("Application has been started", "app", cfg.AppName, "config_file", loader.File)
 
-	if err := os.MkdirAll("log", os.ModePerm); err != nil {
-		panic("Failed to create log directory: " + err.Error())
+	tracerProvider, err := newTracerProvider(cfg, logger)
+	if err != nil {
+		panic("Failed to initialize tracing: " + err.Error())
+	}
+	if tracerProvider != nil {
+		tracing.SetProvider(tracerProvider)
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			tracerProvider.Shutdown(ctx)
+		}()
+		logger.Info("Tracing enabled", "exporter", cfg.TraceExporter, "sample_ratio", cfg.TraceSampleRatio)
 	}
 
-	errorWorker := worker.NewErrorWorker(logChannel)
-	logChannel.Send("[INFO] Log worker started")
+	workers := server.NewWorkers(ctx, logger)
+	hup := &reloader{loader: loader, cfg: cfg, logLevel: logLevel, logFile: logFile, logger: logger}
+
+	errorWorker := worker.NewErrorWorker(logger)
+	logger.Info("Log worker started")
 
 	accountRepo := repository.NewAccountRepository()
 	transactionRepo := repository.NewTransactionRepository()
-	logChannel.Send("[INFO] Repositories initialized")
+	metrics.Default.GaugeFunc("corebanking_accounts", "Number of open accounts.", func() float64 {
+		return float64(accountRepo.Count())
+	})
+	apiKeyRepo := repository.NewAPIKeyRepository()
+	if cfg.APIKeysFile != "" {
+		if apiKeyRepo, err = repository.NewFileAPIKeyRepository(cfg.APIKeysFile); err != nil {
+			panic("Failed to load API keys: " + err.Error())
+		}
+	}
+	auditRepo, err := repository.NewFileAuditRepository(cfg.AuditLogPath)
+	if err != nil {
+		panic("Failed to open audit log: " + err.Error())
+	}
+	defer auditRepo.Close()
+	if torn := auditRepo.Torn(); torn > 0 {
+		logger.Warn("Dropped an incomplete last line of the audit log", "path", cfg.AuditLogPath, "bytes", torn)
+	}
+	logger.Info("Repositories initialized")
 
 	// Inicializar serviços
-	accountService := service.NewAccountService(accountRepo)
-	transactionService := service.NewTransactionService(transactionRepo, accountRepo)
-	logChannel.Send("[INFO] Services initialized")
+	auditService := service.NewAuditService(auditRepo)
+	auditChainErr := auditService.Verify()
+	if auditChainErr != nil {
+		logger.Error("Audit log integrity check failed", "error", auditChainErr.Error())
+	}
+	accountService := service.NewAccountService(accountRepo, auditService)
+	transactionService := service.NewTransactionService(transactionRepo, accountRepo, auditService)
+	transactionService.SetErrorHandler(errorWorker)
+	limitPolicy, _ := cfg.LimitPolicy()
+	limitService := service.NewLimitService(repository.NewLimitUsageRepository(), accountRepo, auditService, limitPolicy)
+	transactionService.SetLimitService(limitService)
+	hup.limits = limitService
+	fraudService := service.NewFraudService(fraud.NewEngine(cfg.FraudRules()), repository.NewFraudHistoryRepository(), repository.NewFraudCaseRepository(), auditService)
+	transactionService.SetFraudService(fraudService)
+	hup.fraud = fraudService
+	amlService := service.NewAMLService(aml.NewMonitor(cfg.AMLRules()), repository.NewAMLHistoryRepository(), repository.NewAMLAlertRepository(), accountService, auditService)
+	transactionService.SetAMLService(amlService)
+	hup.aml = amlService
+	sanctionsService := service.NewSanctionsService(sanctions.NewScreener(sanctions.EmptyList(), cfg.SanctionsPolicy()), repository.NewSanctionsScreeningRepository(), repository.NewSanctionsCaseRepository(), repository.NewSanctionsClearanceRepository(), accountService, auditService)
+	if _, err := sanctionsService.LoadList(context.Background(), cfg.SanctionsListPath); err != nil {
+		panic("Failed to load sanctions list: " + err.Error())
+	}
+	accountService.SetSanctionsService(sanctionsService)
+	transactionService.SetSanctionsService(sanctionsService)
+	hup.sanctions = sanctionsService
+	suspenseService := service.NewSuspenseService(repository.NewSuspenseRepository(), accountRepo, auditService, cfg.SuspenseAccountID, cfg.SuspensePolicy())
+	transactionService.SetSuspenseService(suspenseService)
+	hup.suspense = suspenseService
+	workers.Go("suspense-sweep", func(ctx context.Context) error {
+		return suspenseService.Watch(ctx, cfg.SuspenseSweepInterval, func(returned int, err error) {
+			if err != nil {
+				logger.Error("Suspense sweep failed", "returned", returned, "error", err.Error())
+				return
+			}
+			logger.Info("Unclaimed transfers returned from suspense", "returned", returned)
+		})
+	})
+	scheduleRepo, err := repository.NewFileScheduleRepository(cfg.SchedulePath)
+	if err != nil {
+		panic("Failed to open schedules: " + err.Error())
+	}
+	scheduleService := service.NewScheduleService(scheduleRepo, accountRepo, transactionService, auditService, scheduleInstance(cfg), cfg.SchedulePolicy())
+	hup.schedules = scheduleService
+	workers.Go("scheduler", func(ctx context.Context) error {
+		return scheduleService.Watch(ctx, cfg.ScheduleInterval, func(executions []*domain.ScheduleExecution, err error) {
+			if err != nil {
+				logger.Error("Scheduled transfers failed to run", "error", err.Error())
+			}
+			for _, execution := range executions {
+				if execution.Status == domain.ExecutionPosted {
+					logger.Info("Scheduled transfer posted", "schedule_id", execution.ScheduleID, "occurrence", execution.Occurrence)
+					continue
+				}
+				logger.Warn("Scheduled transfer not posted", "schedule_id", execution.ScheduleID, "occurrence", execution.Occurrence,
+					"status", execution.Status, "attempt", execution.Attempt, "error", execution.Error)
+			}
+		})
+	})
+	batchService := service.NewBatchService(repository.NewBatchRepository(), accountRepo, transactionService, cfg.BatchPolicy())
+	workers.Go("batches", batchService.Run)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, auditService)
+	resetService := service.NewResetService(accountService, transactionRepo, auditService, cfg.SandboxMode, cfg.ResetToken)
+	resetService.SetLimitService(limitService)
+	resetService.SetFraudService(fraudService)
+	resetService.SetAMLService(amlService)
+	resetService.SetSanctionsService(sanctionsService)
+	resetService.SetSuspenseService(suspenseService)
+	resetService.SetScheduleService(scheduleService)
+	resetService.SetBatchService(batchService)
+	if cfg.SandboxMode && !resetService.Enabled() {
+		logger.Warn("Sandbox mode without RESET_CONFIRMATION_TOKEN, reset stays disabled")
+	}
+	logger.Info("Services initialized")
 
-	// Inicializar controllers
-	accountController := controller.NewAccountController(accountService, errorWorker)
-	transactionController := controller.NewTransactionController(transactionService, errorWorker)
-	logChannel.Send("[INFO] Controllers initialized")
+	policy := auth.NewPolicy(accountService)
+
+	jwtVerifier, err := newJWTVerifier(cfg)
+	if err != nil {
+		panic("Failed to load JWT keys: " + err.Error())
+	}
 
-	apiPrefix := "/api/" + cfg.Version
+	v1Sunset, _ := time.Parse("2006-01-02", cfg.V1Sunset)
+
+	// Inicializar controllers
+	versions := controller.APIVersions(cfg.AppName, v1Sunset, controller.Services{
+		Accounts:     accountService,
+		Transactions: transactionService,
+		APIKeys:      apiKeyService,
+		Limits:       limitService,
+		Fraud:        fraudService,
+		AML:          amlService,
+		Sanctions:    sanctionsService,
+		Suspense:     suspenseService,
+		Schedules:    scheduleService,
+		Batches:      batchService,
+		Audit:        auditService,
+		Reset:        resetService,
+	}, policy, errorWorker)
+	logger.Info("Controllers initialized")
 
 	// Configurar roteador HTTP
+	var handler http.Handler = controller.NewVersionedRouter(cfg.Version, versions...)
+	rateLimitRules, _ := cfg.RateLimitRules()
+	limiter := ratelimit.NewLimiter(rateLimitRules)
+	hup.limiter = limiter
+	handler = middleware.RateLimit(limiter)(handler)
+	var authenticate rpc.Authenticate
+	if cfg.AuthEnabled {
+		authenticator := auth.NewAuthenticator(apiKeyService, jwtVerifier)
+		if cfg.ClientCertsFile != "" {
+			certs, err := auth.LoadClientCertMapperFile(cfg.ClientCertsFile)
+			if err != nil {
+				panic("Failed to load client certificate mappings: " + err.Error())
+			}
+			authenticator.SetClientCertMapper(certs)
+		}
+		handler = middleware.Authenticate(authenticator, errorWorker, middleware.PublicPaths("openapi.json", "docs"))(handler)
+		authenticate = rpc.Authenticator(authenticator)
+	} else {
+		anonymous := &auth.Principal{ID: "anonymous", Subject: "anonymous", Roles: auth.Roles(), Method: "none"}
+		handler = middleware.Anonymous(anonymous)(handler)
+		authenticate = rpc.Anonymous(anonymous)
+		logger.Warn("Authentication is disabled, every endpoint is open")
+	}
+	// The IP bucket is drawn before authenticating, so guessing keys or
+	// tokens is limited too.
+	handler = middleware.RateLimitIP(limiter)(handler)
+	handler = middleware.Metrics(handler)
+
+	// /metrics sits outside the API and its authentication so scrapers
+	// need no credentials; keep it off public networks.
 	mux := http.NewServeMux()
-	accountController.RegisterRoutes(mux, apiPrefix)
-	transactionController.RegisterRoutes(mux, apiPrefix)
+	mux.Handle("/metrics", metrics.Default.Handler())
+	checker := newHealthChecker(auditRepo, logFile, auditChainErr)
+	controller.NewHealthController(checker, versionInfo(cfg, versions...)).RegisterRoutes(mux, "")
+	mux.Handle("/", handler)
+	handler = middleware.AccessLog(logger)(mux)
+	handler = middleware.Trace(handler)
+	handler = middleware.RequestID(handler)
 
 	// Iniciar servidor
-	serverAddr := ":" + cfg.Port
-	logChannel.Send("[INFO] Starting server on " + serverAddr)
+	serverOptions := server.Options{
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		DrainDelay:        cfg.ShutdownDrainDelay,
+		ShutdownTimeout:   cfg.ShutdownTimeout,
+	}
+	srv := server.New(":"+cfg.Port, handler, serverOptions, logger)
+	srv.TLSConfig, hup.certs, err = newTLSConfig(cfg, workers, logger)
+	if err != nil {
+		panic("Failed to load TLS certificates: " + err.Error())
+	}
+	workers.Go("sighup", hup.run)
+	if cfg.GRPCPort != "" {
+		var grpcOptions []grpc.ServerOption
+		if srv.TLSConfig != nil {
+			grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(srv.TLSConfig)))
+		}
+		grpcServer := rpc.NewServer(accountService, transactionService, policy, authenticate, limiter, errorWorker, grpcOptions...)
+		logger.Info("Starting gRPC server", "addr", ":"+cfg.GRPCPort, "tls", srv.TLSConfig != nil)
+		workers.Go("grpc", func(ctx context.Context) error {
+			return grpcServer.ListenAndServe(ctx, ":"+cfg.GRPCPort, cfg.ShutdownTimeout, logger)
+		})
+	}
+	logger.Info("Starting server", "addr", srv.Addr, "tls", srv.TLSConfig != nil)
+
+	if err := server.ListenAndServe(ctx, srv, checker, serverOptions, logger); err != nil {
+		logger.Error("Server stopped with error", "error", err.Error())
+	}
+
+	// Requests are drained; stop background workers, then the deferred
+	// closes flush tracing, the audit log and finally the log file.
+	stopCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := workers.Stop(stopCtx); err != nil {
+		logger.Warn("Background workers did not stop in time", "error", err.Error())
+	}
+	logger.Info("Shutdown complete")
+}
+
+// reloader handles SIGHUP: it reopens the log file, for when an external
+// tool such as logrotate has moved it, re-reads the TLS files and reloads
+// the config. Only settings tagged reload, the log level, rate limits,
+// transaction limits, fraud rules, AML rules, sanctions screening, the
+// suspense policy and scheduler retries, take effect; changes to the others
+// are logged and wait for a restart. An invalid config is logged and the
+// running one kept. The sanctions list is read again too, in case it was
+// updated in place.
+type reloader struct {
+	loader    *config.Loader
+	cfg       *config.Config
+	logLevel  *slog.LevelVar
+	logFile   *event.LogChannel
+	certs     *server.CertReloader
+	limiter   *ratelimit.Limiter
+	limits    *service.LimitService
+	fraud     *service.FraudService
+	aml       *service.AMLService
+	sanctions *service.SanctionsService
+	suspense  *service.SuspenseService
+	schedules *service.ScheduleService
+	logger    *slog.Logger
+}
+
+func (h *reloader) run(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+		}
+
+		if h.logFile != nil {
+			if err := h.logFile.Reopen(); err != nil {
+				fmt.Fprintln(os.Stderr, "log file reopen failed:", err)
+			}
+		}
+		if h.certs != nil {
+			logCertReload(h.logger, h.certs.Reload())
+		}
+
+		next, err := h.loader.Load()
+		if err != nil {
+			h.logger.Error("Config reload failed, keeping the running config", "error", err.Error())
+			continue
+		}
+		var applied, ignored []string
+		h.cfg, applied, ignored = config.Reload(h.cfg, next)
+		level, _ := logging.ParseLevel(h.cfg.LogLevel)
+		h.logLevel.Set(level)
+		if h.limiter != nil {
+			rules, _ := h.cfg.RateLimitRules()
+			h.limiter.SetRules(rules)
+		}
+		if h.limits != nil {
+			limitPolicy, _ := h.cfg.LimitPolicy()
+			h.limits.SetPolicy(limitPolicy)
+		}
+		if h.fraud != nil {
+			h.fraud.SetRules(h.cfg.FraudRules())
+		}
+		if h.aml != nil {
+			h.aml.SetRules(h.cfg.AMLRules())
+		}
+		if h.sanctions != nil {
+			h.sanctions.SetPolicy(h.cfg.SanctionsPolicy())
+			if list, err := h.sanctions.LoadList(ctx, h.cfg.SanctionsListPath); err != nil {
+				h.logger.Error("Sanctions list reload failed, keeping the running list", "error", err.Error())
+			} else {
+				h.logger.Info("Sanctions list loaded", "version", list.Version, "entries", len(list.Entries))
+			}
+		}
+		if h.suspense != nil {
+			h.suspense.SetPolicy(h.cfg.SuspensePolicy())
+		}
+		if h.schedules != nil {
+			h.schedules.SetPolicy(h.cfg.SchedulePolicy())
+		}
+		h.logger.Info("Config reloaded", "applied", applied)
+		if len(ignored) > 0 {
+			h.logger.Warn("Config changes need a restart", "settings", ignored)
+		}
+	}
+}
+
+// scheduleInstance names this instance in schedule claims: the configured
+// name, or the host name and process ID.
+func scheduleInstance(cfg *config.Config) string {
+	if cfg.ScheduleInstance != "" {
+		return cfg.ScheduleInstance
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+func logCertReload(logger *slog.Logger, err error) {
+	if err != nil {
+		logger.Error("TLS certificate reload failed, keeping the previous one", "error", err.Error())
+		return
+	}
+	logger.Info("TLS certificate reloaded")
+}
+
+// newTLSConfig loads the TLS files and watches them for changes. It
+// returns nil when HTTPS is off.
+func newTLSConfig(cfg *config.Config, workers *server.Workers, logger *slog.Logger) (*tls.Config, *server.CertReloader, error) {
+	if cfg.TLSCertFile == "" {
+		return nil, nil, nil
+	}
+	certs, err := server.NewCertReloader(server.TLSOptions{
+		CertFile:     cfg.TLSCertFile,
+		KeyFile:      cfg.TLSKeyFile,
+		ClientCAFile: cfg.TLSClientCAFile,
+		ClientAuth:   cfg.TLSClientAuth,
+		MinVersion:   cfg.TLSMinVersion,
+		CipherSuites: cfg.CipherSuites(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	tlsConfig, err := certs.TLSConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	workers.Go("tls-watch", func(ctx context.Context) error {
+		return certs.Watch(ctx, cfg.TLSReloadInterval, func(err error) {
+			logCertReload(logger, err)
+		})
+	})
+	return tlsConfig, certs, nil
+}
+
+// newHealthChecker registers the readiness checks. Accounts and transactions
+// live in memory, so the audit log is the storage that can fail. There are
+// no schema migrations; the audit log replayed and verified on start is the
+// only startup step readiness waits on.
+func newHealthChecker(auditRepo *repository.AuditRepository, logFile *event.LogChannel, auditChainErr error) *health.Checker {
+	checker := health.NewChecker()
+	checker.Add("storage", func(ctx context.Context) error {
+		return auditRepo.Err()
+	})
+	checker.Add("audit_chain", func(ctx context.Context) error {
+		return auditChainErr
+	})
+	if logFile != nil {
+		checker.Add("log_writer", func(ctx context.Context) error {
+			return logFile.Err()
+		})
+	}
+	return checker
+}
+
+func versionInfo(cfg *config.Config, versions ...controller.APIVersion) dto.VersionResponse {
+	build := health.ReadBuild()
+	names := make([]string, 0, len(versions))
+	for _, version := range versions {
+		names = append(names, version.Name)
+	}
+	return dto.VersionResponse{
+		App:         cfg.AppName,
+		APIVersion:  cfg.Version,
+		APIVersions: names,
+		Commit:      build.Commit,
+		BuildTime:   build.BuildTime,
+		Modified:    build.Modified,
+		GoVersion:   build.GoVersion,
+	}
+}
+
+// newTracerProvider builds the span exporter named by TRACE_EXPORTER. It
+// returns nil when tracing is off.
+func newTracerProvider(cfg *config.Config, logger *slog.Logger) (*tracing.Provider, error) {
+	var exporter tracing.Exporter
+	var err error
+	switch cfg.TraceExporter {
+	case "", "none":
+		return nil, nil
+	case "stdout":
+		exporter, err = tracing.NewWriterExporter(os.Stdout)
+	case "file":
+		// The provider exports from one goroutine and drops spans when its
+		// queue is full, so a blocking channel never stalls requests.
+		traceFile, err := event.NewLogChannel(cfg.TracePath, cfg.LogBufferSize)
+		if err != nil {
+			return nil, err
+		}
+		writer, err := tracing.NewWriterExporter(traceFile)
+		if err != nil {
+			traceFile.Close()
+			return nil, err
+		}
+		exporter = &closingExporter{Exporter: writer, file: traceFile}
+	case "otlp":
+		exporter, err = tracing.NewOTLPExporter(cfg.TraceOTLPEndpoint, parseHeaders(cfg.TraceOTLPHeaders))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.TraceExporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tracing.NewProvider(exporter, tracing.Options{
+		ServiceName:   cfg.AppName,
+		SampleRatio:   cfg.TraceSampleRatio,
+		BatchInterval: cfg.TraceBatchInterval,
+		OnError: func(err error) {
+			logger.Warn("Span export failed", "error", err.Error())
+		},
+	}), nil
+}
+
+// closingExporter closes the trace file once the provider has shut down.
+type closingExporter struct {
+	tracing.Exporter
+	file *event.LogChannel
+}
+
+func (e *closingExporter) Shutdown(ctx context.Context) error {
+	e.Exporter.Shutdown(ctx)
+	return e.file.Close()
+}
 
-	if err := http.ListenAndServe(serverAddr, mux); err != nil {
-		logChannel.Send("[ERROR] Server failed to start: " + err.Error())
+// parseHeaders reads key=value pairs separated by commas.
+func parseHeaders(value string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(key) != "" {
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return headers
+}
+
+func newJWTVerifier(cfg *config.Config) (*auth.JWTVerifier, error) {
+	verifier := auth.NewJWTVerifier(cfg.JWTIssuer, cfg.JWTAudience)
+	if cfg.JWTSecret != "" {
+		verifier.AddHS256Secret("", []byte(cfg.JWTSecret))
+	}
+	if cfg.JWTPublicKeyFile != "" {
+		if err := verifier.LoadRS256PublicKeyFile(cfg.JWTPublicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.JWKSFile != "" {
+		if err := verifier.LoadJWKSFile(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
 	}
+	return verifier, nil
 }
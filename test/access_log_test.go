@@ -0,0 +1,83 @@
+package test
+
+import (
+	"corebanking/internal/dto"
+	"net/http"
+	"testing"
+)
+
+func logRecords(t *testing.T, app *testApp, msg string) []map[string]any {
+	t.Helper()
+
+	var matching []map[string]any
+	for _, record := range decodeLogLines(t, app.logs.String()) {
+		if record["msg"] == msg {
+			matching = append(matching, record)
+		}
+	}
+	return matching
+}
+
+func TestAccessLog_OneLinePerRequest(t *testing.T) {
+	app := newTestApp()
+
+	resp := app.do(t, http.MethodGet, "/api/v2/accounts/missing", nil, map[string]string{
+		"X-Request-ID": "req-7", "X-Test-Subject": "teller-1", "X-Test-Roles": "teller",
+	})
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, resp.Code)
+	}
+
+	access := logRecords(t, app, "request")
+	if len(access) != 1 {
+		t.Fatalf("expected one access line, got %d", len(access))
+	}
+	line := access[0]
+	want := map[string]any{
+		"method":     "GET",
+		"route":      "GET /api/v2/accounts/{accountId}",
+		"path":       "/api/v2/accounts/missing",
+		"status":     float64(http.StatusNotFound),
+		"bytes":      float64(resp.Body.Len()),
+		"principal":  "teller-1",
+		"request_id": "req-7",
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, line[key])
+		}
+	}
+	if _, ok := line["latency_ms"].(float64); !ok {
+		t.Errorf("expected latency_ms, got %v", line["latency_ms"])
+	}
+
+	errors := logRecords(t, app, "Failed to get account.")
+	if len(errors) != 1 {
+		t.Fatalf("expected one error line, got %d", len(errors))
+	}
+	if errors[0]["request_id"] != "req-7" || errors[0]["account_id"] != "missing" || errors[0]["level"] != "ERROR" {
+		t.Errorf("expected error line to carry request and account IDs, got %v", errors[0])
+	}
+}
+
+func TestAccessLog_GeneratedRequestIDAndRoutes(t *testing.T) {
+	app := newTestApp()
+
+	resp := app.do(t, http.MethodPost, "/api/v1/accounts", dto.AccountRequest{DocumentNumber: "1"}, nil)
+	generated := resp.Header().Get("X-Request-ID")
+	if len(generated) != 32 {
+		t.Errorf("expected a generated request ID, got %q", generated)
+	}
+	app.do(t, http.MethodGet, "/api/v9/accounts", nil, nil)
+
+	access := logRecords(t, app, "request")
+	if len(access) != 2 {
+		t.Fatalf("expected two access lines, got %d", len(access))
+	}
+	if access[0]["route"] != "/api/v1/accounts" || access[0]["request_id"] != generated || access[0]["status"] != float64(http.StatusCreated) {
+		t.Errorf("unexpected v1 access line %v", access[0])
+	}
+	if access[1]["route"] != "unmatched" || access[1]["status"] != float64(http.StatusNotFound) {
+		t.Errorf("unexpected unmatched access line %v", access[1])
+	}
+}
@@ -0,0 +1,241 @@
+package test
+
+import (
+	"context"
+	"corebanking/config"
+	"corebanking/internal/aml"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type amlAlertEnvelope struct {
+	Data  dto.AMLAlertV2Response `json:"data"`
+	Error *dto.EnvelopeError     `json:"error"`
+}
+
+func testAMLRules() aml.Rules {
+	return aml.Rules{
+		ReportingThreshold: 1000000,
+		StructuringWindow:  72 * time.Hour,
+		StructuringCount:   3,
+		StructuringMargin:  0.1,
+		RapidWindow:        24 * time.Hour,
+		RapidMinimum:       500000,
+		RapidRatio:         0.8,
+		LargeCashWindow:    24 * time.Hour,
+	}
+}
+
+func cashDeposit(t *testing.T, app *testApp, accountID string, amount int64) {
+	t.Helper()
+	if _, err := app.transactionService.HandleTransaction(context.Background(), &dto.EventRequest{Type: "deposit", Destination: accountID, Amount: amount}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAML_Rules(t *testing.T) {
+	monitor := aml.NewMonitor(testAMLRules())
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	movements := func(kinds []string, amounts []int64, every time.Duration) domain.AMLHistory {
+		var history domain.AMLHistory
+		for i := range kinds {
+			at := now.Add(-time.Duration(len(kinds)-1-i) * every)
+			history.Add(domain.AMLMovement{At: at, Kind: kinds[i], Amount: amounts[i]}, 72*time.Hour)
+		}
+		return history
+	}
+	cash := domain.MovementCashDeposit
+
+	tests := []struct {
+		name    string
+		history domain.AMLHistory
+		rules   []string
+	}{
+		{"deposits just below the threshold", movements([]string{cash, cash, cash}, []int64{950000, 990000, 920000}, 30*time.Hour), []string{aml.RuleStructuring}},
+		{"too few below the threshold", movements([]string{cash, cash}, []int64{950000, 990000}, 30*time.Hour), nil},
+		{"below the margin", movements([]string{cash, cash, cash}, []int64{850000, 850000, 850000}, 30*time.Hour), nil},
+		{"outside the window", movements([]string{cash, cash, cash}, []int64{950000, 950000, 950000}, 40*time.Hour), nil},
+		{"one large deposit", movements([]string{cash}, []int64{1000000}, 0), []string{aml.RuleLargeCash}},
+		{"large cash over a day", movements([]string{cash, cash}, []int64{600000, 400000}, 12*time.Hour), []string{aml.RuleLargeCash}},
+		{"in and straight out", movements([]string{domain.MovementCredit, domain.MovementDebit}, []int64{600000, 500000}, time.Hour), []string{aml.RuleRapidInOut}},
+		{"in and partly out", movements([]string{domain.MovementCredit, domain.MovementDebit}, []int64{600000, 400000}, time.Hour), nil},
+		{"out before in", movements([]string{domain.MovementDebit, domain.MovementCredit}, []int64{600000, 600000}, time.Hour), nil},
+		{"too small to matter", movements([]string{domain.MovementCredit, domain.MovementDebit}, []int64{40000, 40000}, time.Hour), nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := monitor.Evaluate(tc.history, now)
+			rules := make([]string, 0, len(findings))
+			for _, finding := range findings {
+				rules = append(rules, finding.Rule)
+			}
+			if !slices.Equal(rules, tc.rules) && (len(rules) != 0 || len(tc.rules) != 0) {
+				t.Errorf("expected %v, got %+v", tc.rules, findings)
+			}
+		})
+	}
+
+	if findings := aml.NewMonitor(aml.Rules{}).Evaluate(movements([]string{cash}, []int64{5000000}, 0), now); len(findings) != 0 {
+		t.Errorf("expected zero rules to match nothing, got %+v", findings)
+	}
+}
+
+func TestAML_AlertsDispositionAndReport(t *testing.T) {
+	app := newTestApp()
+	app.amlService.SetRules(testAMLRules())
+	account, err := app.accountService.CreateAccount(context.Background(), "12345", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountID := account.AccountID
+
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			app.clock.Advance(30 * time.Hour)
+		}
+		cashDeposit(t, app, accountID, 950000)
+	}
+	var list struct {
+		Data []dto.AMLAlertV2Response `json:"data"`
+	}
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/aml/alerts", nil, nil), &list)
+	if len(list.Data) != 1 || list.Data[0].Rule != aml.RuleStructuring || list.Data[0].Count != 3 || list.Data[0].Status != domain.AlertOpen {
+		t.Fatalf("expected one open structuring alert, got %+v", list.Data)
+	}
+	structuring := list.Data[0]
+
+	// Another deposit updates the open alert instead of raising a second
+	// one, and takes the day's cash over the threshold.
+	app.clock.Advance(time.Hour)
+	cashDeposit(t, app, accountID, 950000)
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/aml/alerts?accountId="+accountID, nil, nil), &list)
+	if len(list.Data) != 2 || list.Data[0].ID != structuring.ID || list.Data[0].Count != 4 || list.Data[1].Rule != aml.RuleLargeCash {
+		t.Fatalf("expected the structuring alert updated and a large cash alert, got %+v", list.Data)
+	}
+	largeCash := list.Data[1]
+
+	resp := app.do(t, http.MethodPost, "/api/v2/aml/alerts/"+structuring.ID+"/disposition", map[string]any{"disposition": "suspicious", "note": "split deposits"}, map[string]string{
+		"X-Test-Subject": "analyst", "X-Test-Roles": "admin",
+	})
+	var body amlAlertEnvelope
+	decodeBody(t, resp, &body)
+	if resp.Code != http.StatusOK || body.Data.Status != domain.AlertSuspicious || body.Data.DispositionedBy != "analyst" || body.Data.Note != "split deposits" {
+		t.Fatalf("unexpected disposition %d %+v", resp.Code, body)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/v2/aml/alerts/"+structuring.ID+"/disposition", map[string]any{"disposition": "no_action"}, nil); resp.Code != http.StatusConflict {
+		t.Errorf("expected a dispositioned alert to stay closed, got %d", resp.Code)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/v2/aml/alerts/"+largeCash.ID+"/disposition", map[string]any{"disposition": "closed"}, nil); resp.Code != http.StatusBadRequest {
+		t.Errorf("expected an unknown disposition to be rejected, got %d", resp.Code)
+	}
+	if resp := app.do(t, http.MethodGet, "/api/v2/aml/alerts/unknown", nil, nil); resp.Code != http.StatusNotFound {
+		t.Errorf("expected an unknown alert to be missing, got %d", resp.Code)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/v2/aml/alerts/"+largeCash.ID+"/disposition", map[string]any{"disposition": "false_positive"}, nil); resp.Code != http.StatusOK {
+		t.Fatalf("expected the large cash alert to close, got %d", resp.Code)
+	}
+
+	// Money moved straight through another account.
+	other := fundedAccount(t, app, "2")
+	if err := withdraw(app, other, 90000); err != nil {
+		t.Fatal(err)
+	}
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/aml/alerts", nil, nil), &list)
+	if len(list.Data) != 0 {
+		t.Fatalf("expected small amounts not to alert, got %+v", list.Data)
+	}
+	app.clock.Advance(time.Hour)
+	if err := transfer(app, accountID, other, 600000); err != nil {
+		t.Fatal(err)
+	}
+	if err := withdraw(app, other, 550000); err != nil {
+		t.Fatal(err)
+	}
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/aml/alerts", nil, nil), &list)
+	if len(list.Data) != 1 || list.Data[0].AccountID != other || list.Data[0].Rule != aml.RuleRapidInOut {
+		t.Fatalf("expected a rapid in and out alert, got %+v", list.Data)
+	}
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/aml/alerts?status=all", nil, nil), &list)
+	if len(list.Data) != 3 {
+		t.Errorf("expected every alert, got %d", len(list.Data))
+	}
+	if resp := app.do(t, http.MethodGet, "/api/v2/aml/alerts?status=closed", nil, nil); resp.Code != http.StatusBadRequest {
+		t.Errorf("expected an unknown status to be rejected, got %d", resp.Code)
+	}
+
+	resp = app.do(t, http.MethodGet, "/api/v2/aml/reports", nil, nil)
+	var report dto.SuspiciousActivityReport
+	decodeBody(t, resp, &report)
+	if resp.Code != http.StatusOK || !strings.HasPrefix(resp.Header().Get("Content-Disposition"), "attachment;") || report.Format != dto.SARFormat {
+		t.Fatalf("expected a report download, got %d %q %+v", resp.Code, resp.Header().Get("Content-Disposition"), report)
+	}
+	if len(report.Subjects) != 1 || report.Subjects[0].DocumentNumber != "12345" || len(report.Subjects[0].Alerts) != 1 || report.Subjects[0].Alerts[0].AlertID != structuring.ID {
+		t.Fatalf("expected the suspicious alert's account only, got %+v", report.Subjects)
+	}
+	if activity := report.Subjects[0].Activity; len(activity) != 4 || activity[0].Kind != domain.MovementCashDeposit || activity[0].Amount != 950000 {
+		t.Errorf("expected the four deposits as activity, got %+v", activity)
+	}
+	later := app.clock.Now().Add(time.Hour).Format(time.RFC3339)
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/aml/reports?from="+later, nil, nil), &report)
+	if len(report.Subjects) != 0 {
+		t.Errorf("expected no alerts dispositioned after from, got %+v", report.Subjects)
+	}
+	if resp := app.do(t, http.MethodGet, "/api/v2/aml/reports?to=yesterday", nil, nil); resp.Code != http.StatusBadRequest {
+		t.Errorf("expected a bad date to be rejected, got %d", resp.Code)
+	}
+
+	entries := app.auditService.Query(context.Background(), "", "aml_alert", structuring.ID, time.Time{}, time.Time{})
+	actions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	if !slices.Equal(actions, []string{service.AuditAMLAlert, service.AuditAMLAlert, service.AuditAMLDisposition}) {
+		t.Errorf("expected the alert, its update and the disposition to be audited, got %v", actions)
+	}
+}
+
+func TestAML_ConfigAndReload(t *testing.T) {
+	env := envMap(map[string]string{"AML_STRUCTURING_MARGIN": "1.5", "AML_RAPID_RATIO": "0"})
+	_, err := config.NewLoaderWithEnv(nil, env).Load()
+	if err == nil || !strings.Contains(err.Error(), "aml.structuring_margin:") || !strings.Contains(err.Error(), "aml.rapid_ratio:") {
+		t.Fatalf("expected both aml settings to be reported, got %v", err)
+	}
+
+	current, err := config.NewLoaderWithEnv(nil, envMap(nil)).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules := current.AMLRules(); rules != testAMLRules() {
+		t.Fatalf("expected the default rules, got %+v", rules)
+	}
+	app := newTestApp()
+	app.amlService.SetRules(current.AMLRules())
+
+	next, _ := config.NewLoaderWithEnv(nil, envMap(map[string]string{"AML_LARGE_CASH_WINDOW": "0s"})).Load()
+	merged, applied, _ := config.Reload(current, next)
+	if !slices.Contains(applied, "aml.large_cash_window") {
+		t.Fatalf("expected aml rules to reload, applied %v", applied)
+	}
+	app.amlService.SetRules(merged.AMLRules())
+	accountID := app.createAccount(t, "1")
+	cashDeposit(t, app, accountID, 2000000)
+	if alerts := app.amlService.Alerts(context.Background(), "", ""); len(alerts) != 0 {
+		t.Fatalf("expected large cash to be off, got %+v", alerts)
+	}
+
+	disabled := *merged
+	disabled.AMLEnabled = false
+	app.amlService.SetRules(disabled.AMLRules())
+	cashDeposit(t, app, accountID, 950000)
+	cashDeposit(t, app, accountID, 950000)
+	cashDeposit(t, app, accountID, 950000)
+	if alerts := app.amlService.Alerts(context.Background(), "", ""); len(alerts) != 0 {
+		t.Errorf("expected disabled monitoring to raise nothing, got %+v", alerts)
+	}
+}
@@ -0,0 +1,335 @@
+package test
+
+import (
+	"context"
+	"corebanking/internal/aml"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/repository"
+	"corebanking/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type auditEnvelope struct {
+	Data []domain.AuditEntry `json:"data"`
+}
+
+func TestAudit_RecordsMutationsWithActorAndRequestID(t *testing.T) {
+	app := newTestApp()
+	headers := map[string]string{"X-Test-Subject": "teller-1", "X-Test-Roles": "teller", "X-Request-ID": "req-42"}
+
+	resp := app.do(t, http.MethodPost, "/api/v2/accounts", dto.AccountRequest{DocumentNumber: "123"}, headers)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+	if resp.Header().Get("X-Request-ID") != "req-42" {
+		t.Errorf("expected request id to be echoed, got %q", resp.Header().Get("X-Request-ID"))
+	}
+	var account accountEnvelope
+	decodeBody(t, resp, &account)
+
+	resp = app.do(t, http.MethodPost, "/api/v2/events", map[string]any{
+		"type": "deposit", "destination": account.Data.AccountID, "amount": "10.00",
+	}, headers)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+
+	resp = app.do(t, http.MethodGet, "/api/v2/audit?entityId="+account.Data.AccountID, nil, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.Code)
+	}
+	var body auditEnvelope
+	decodeBody(t, resp, &body)
+	if len(body.Data) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(body.Data))
+	}
+
+	created, deposited := body.Data[0], body.Data[1]
+	if created.Action != service.AuditAccountCreate || created.Before != nil || created.After == nil {
+		t.Errorf("unexpected create entry %+v", created)
+	}
+	if deposited.Action != service.AuditAccountBalance || deposited.PrevHash != created.Hash {
+		t.Errorf("unexpected balance entry %+v", deposited)
+	}
+	for _, entry := range body.Data {
+		if entry.Actor != "teller-1" || entry.RequestID != "req-42" {
+			t.Errorf("expected actor teller-1 and request req-42, got %q and %q", entry.Actor, entry.RequestID)
+		}
+	}
+
+	var after domain.Account
+	if err := json.Unmarshal(deposited.After, &after); err != nil || after.Balance != 1000 {
+		t.Errorf("expected balance 1000 after deposit, got %+v (%v)", after, err)
+	}
+}
+
+func TestAudit_QueryFilters(t *testing.T) {
+	app := newTestApp()
+	app.do(t, http.MethodPost, "/api/v2/accounts", dto.AccountRequest{DocumentNumber: "1"}, map[string]string{"X-Test-Subject": "a", "X-Test-Roles": "teller"})
+	app.do(t, http.MethodPost, "/api/v2/accounts", dto.AccountRequest{DocumentNumber: "2"}, map[string]string{"X-Test-Subject": "b", "X-Test-Roles": "teller"})
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 2},
+		{"?actor=a", 1},
+		{"?entity=account", 2},
+		{"?entity=apikey", 0},
+		{"?from=2000-01-01T00:00:00Z&to=2999-01-01T00:00:00Z", 2},
+		{"?to=2000-01-01T00:00:00Z", 0},
+	}
+	for _, tc := range tests {
+		resp := app.do(t, http.MethodGet, "/api/v2/audit"+tc.query, nil, nil)
+		var body auditEnvelope
+		decodeBody(t, resp, &body)
+		if len(body.Data) != tc.want {
+			t.Errorf("%q: expected %d entries, got %d", tc.query, tc.want, len(body.Data))
+		}
+	}
+
+	resp := app.do(t, http.MethodGet, "/api/v2/audit?from=yesterday", nil, nil)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, resp.Code)
+	}
+}
+
+func TestAudit_FileReplayAndTamperDetection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	repo, err := repository.NewFileAuditRepository(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	accounts := service.NewAccountService(repository.NewAccountRepository(), service.NewAuditService(repo))
+	for _, document := range []string{"1", "2", "3"} {
+		if _, err := accounts.CreateAccount(context.Background(), document, ""); err != nil {
+			t.Fatalf("failed to create account: %v", err)
+		}
+	}
+	repo.Close()
+
+	replayed, err := repository.NewFileAuditRepository(path)
+	if err != nil {
+		t.Fatalf("failed to replay audit log: %v", err)
+	}
+	audit := service.NewAuditService(replayed)
+	if err := audit.Verify(); err != nil {
+		t.Fatalf("expected intact chain, got %v", err)
+	}
+	if len(replayed.FindAll()) != 3 {
+		t.Fatalf("expected 3 replayed entries, got %d", len(replayed.FindAll()))
+	}
+	if err := audit.Record(context.Background(), service.AuditSystemReset, "system", "accounts", nil, nil); err != nil {
+		t.Fatalf("failed to append after replay: %v", err)
+	}
+	replayed.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var entry domain.AuditEntry
+	json.Unmarshal([]byte(lines[1]), &entry)
+	entry.Actor = "someone-else"
+	tampered, _ := json.Marshal(entry)
+	lines[1] = string(tampered)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := repository.NewFileAuditRepository(path)
+	if err != nil {
+		t.Fatalf("failed to reopen audit log: %v", err)
+	}
+	defer reopened.Close()
+	err = service.NewAuditService(reopened).Verify()
+	if !errors.Is(err, service.ErrAuditChainBroken) || !strings.Contains(err.Error(), "sequence 2") {
+		t.Errorf("expected chain broken at sequence 2, got %v", err)
+	}
+}
+
+func TestAudit_ReplayDropsTornLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	repo, err := repository.NewFileAuditRepository(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	accounts := service.NewAccountService(repository.NewAccountRepository(), service.NewAuditService(repo))
+	for _, document := range []string{"1", "2"} {
+		if _, err := accounts.CreateAccount(context.Background(), document, ""); err != nil {
+			t.Fatalf("failed to create account: %v", err)
+		}
+	}
+	repo.Close()
+
+	// A crash in the middle of a write leaves part of a line behind.
+	torn := `{"sequence":3,"action":"acco`
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(torn)
+	file.Close()
+
+	replayed, err := repository.NewFileAuditRepository(path)
+	if err != nil {
+		t.Fatalf("expected a torn last line to be dropped, got %v", err)
+	}
+	if replayed.Torn() != int64(len(torn)) || len(replayed.FindAll()) != 2 {
+		t.Fatalf("expected 2 entries and %d bytes dropped, got %d and %d", len(torn), len(replayed.FindAll()), replayed.Torn())
+	}
+	audit := service.NewAuditService(replayed)
+	if err := audit.Record(context.Background(), service.AuditSystemReset, "system", "all", nil, nil); err != nil {
+		t.Fatalf("failed to append after replay: %v", err)
+	}
+	replayed.Close()
+
+	reopened, err := repository.NewFileAuditRepository(path)
+	if err != nil {
+		t.Fatalf("failed to reopen audit log: %v", err)
+	}
+	defer reopened.Close()
+	if reopened.Torn() != 0 || len(reopened.FindAll()) != 3 {
+		t.Errorf("expected 3 whole entries, got %d and %d bytes dropped", len(reopened.FindAll()), reopened.Torn())
+	}
+	if err := service.NewAuditService(reopened).Verify(); err != nil {
+		t.Errorf("expected intact chain, got %v", err)
+	}
+}
+
+func TestAudit_FailedRecordLeavesPostingUnmade(t *testing.T) {
+	repo, err := repository.NewFileAuditRepository(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	accountRepo := repository.NewAccountRepository()
+	audit := service.NewAuditService(repo)
+	accounts := service.NewAccountService(accountRepo, audit)
+	transactions := service.NewTransactionService(repository.NewTransactionRepository(), accountRepo, audit)
+	ctx := context.Background()
+
+	origin, err := accounts.CreateAccount(ctx, "1", "")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	destination, err := accounts.CreateAccount(ctx, "2", "")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	deposit := dto.NewEventRequest("deposit", "", origin.AccountID, 1000)
+	if _, err := transactions.HandleTransaction(ctx, &deposit); err != nil {
+		t.Fatalf("failed to deposit: %v", err)
+	}
+	entries := len(repo.FindAll())
+	repo.Close()
+
+	transfer := dto.NewEventRequest("transfer", origin.AccountID, destination.AccountID, 400)
+	if _, err := transactions.HandleTransaction(ctx, &transfer); err == nil {
+		t.Fatal("expected the transfer to fail when the audit log cannot record it")
+	}
+	if _, err := transactions.CreateTransaction(ctx, &dto.TransactionRequest{AccountID: origin.AccountID, OperationTypeID: 3, Amount: 300}); err == nil {
+		t.Fatal("expected the withdrawal to fail when the audit log cannot record it")
+	}
+	for id, want := range map[string]int64{origin.AccountID: 1000, destination.AccountID: 0} {
+		if got, _ := accounts.GetBalance(ctx, id); got.Balance != want {
+			t.Errorf("expected account %s balance %d after failed postings, got %d", id, want, got.Balance)
+		}
+	}
+	if len(transactions.GetAllTransactions(ctx)) != 0 {
+		t.Errorf("expected no transaction for a failed posting")
+	}
+	if len(repo.FindAll()) != entries {
+		t.Errorf("expected no audit entry for failed postings, got %d more", len(repo.FindAll())-entries)
+	}
+}
+
+func TestAudit_FailedRecordLeavesChangeUnmade(t *testing.T) {
+	repo, err := repository.NewFileAuditRepository(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	accountRepo := repository.NewAccountRepository()
+	audit := service.NewAuditService(repo)
+	accounts := service.NewAccountService(accountRepo, audit)
+	limits := service.NewLimitService(repository.NewLimitUsageRepository(), accountRepo, audit, service.LimitPolicy{})
+	keyRepo := repository.NewAPIKeyRepository()
+	keys := service.NewAPIKeyService(keyRepo, audit)
+	ctx := context.Background()
+
+	account, err := accounts.CreateAccount(ctx, "1", "")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	_, key, err := keys.Issue(ctx, "ops", []string{"admin"}, "")
+	if err != nil {
+		t.Fatalf("failed to issue key: %v", err)
+	}
+	repo.Close()
+
+	if _, err := accounts.CreateAccount(ctx, "2", ""); err == nil {
+		t.Error("expected account creation to fail when the audit log cannot record it")
+	}
+	if len(accountRepo.IDs()) != 1 {
+		t.Errorf("expected no account opened by a failed creation, got %d accounts", len(accountRepo.IDs()))
+	}
+	if err := accounts.ConfigOverdraft(ctx, account.AccountID, 500); err == nil {
+		t.Error("expected the overdraft change to fail when the audit log cannot record it")
+	}
+	if _, err := limits.SetLimits(ctx, account.AccountID, "", &domain.Limits{DailyDebitLimit: 100}); err == nil {
+		t.Error("expected the limits change to fail when the audit log cannot record it")
+	}
+	if stored, _ := accountRepo.FindById(ctx, account.AccountID); stored.OverdraftLimit != 0 || stored.Limits != nil {
+		t.Errorf("expected the account unchanged, got overdraft %d and limits %+v", stored.OverdraftLimit, stored.Limits)
+	}
+	if err := keys.Revoke(ctx, key.ID); err == nil {
+		t.Error("expected the revocation to fail when the audit log cannot record it")
+	}
+	if _, _, err := keys.Issue(ctx, "ops", []string{"admin"}, ""); err == nil {
+		t.Error("expected issuing a key to fail when the audit log cannot record it")
+	}
+	if stored := keyRepo.FindAll(); len(stored) != 1 || stored[0].IsRevoked() {
+		t.Errorf("expected only the first key, still active, got %d keys", len(stored))
+	}
+}
+
+func TestAudit_FailedAMLObservationKeepsPosting(t *testing.T) {
+	accountRepo := repository.NewAccountRepository()
+	audit := service.NewAuditService(repository.NewAuditRepository())
+	accounts := service.NewAccountService(accountRepo, audit)
+	transactions := service.NewTransactionService(repository.NewTransactionRepository(), accountRepo, audit)
+	// AML audits alerts to a log that is already closed, so raising one
+	// fails after the posting has moved the money.
+	amlRepo, err := repository.NewFileAuditRepository(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	amlRepo.Close()
+	transactions.SetAMLService(service.NewAMLService(aml.NewMonitor(testAMLRules()), repository.NewAMLHistoryRepository(), repository.NewAMLAlertRepository(), accounts, service.NewAuditService(amlRepo)))
+	errHandler := &MockErrorHandler{}
+	transactions.SetErrorHandler(errHandler)
+	ctx := context.Background()
+
+	account, err := accounts.CreateAccount(ctx, "1", "")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	deposit := dto.NewEventRequest("deposit", "", account.AccountID, 1000000)
+	if _, err := transactions.HandleTransaction(ctx, &deposit); err != nil {
+		t.Fatalf("expected the deposit to succeed once posted, got %v", err)
+	}
+	if !errHandler.Called || errHandler.Err == nil {
+		t.Errorf("expected the failed observation to reach the error handler")
+	}
+	if got, _ := accounts.GetBalance(ctx, account.AccountID); got.Balance != 1000000 {
+		t.Errorf("expected the deposit posted, got balance %d", got.Balance)
+	}
+}
@@ -0,0 +1,361 @@
+package test
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/dto"
+	"corebanking/internal/middleware"
+	"corebanking/internal/repository"
+	"corebanking/internal/service"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func signJWT(t *testing.T, header, claims map[string]any, sign func(signed []byte) []byte) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("failed to encode jwt segment: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(header) + "." + encode(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func hs256(secret []byte) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+func TestAPIKey_IssueRotateRevoke(t *testing.T) {
+	keys := service.NewAPIKeyService(repository.NewAPIKeyRepository(), service.NewAuditService(repository.NewAuditRepository()))
+
+	plaintext, key, err := keys.Issue(context.Background(), "ops", []string{"admin"}, "")
+	if err != nil {
+		t.Fatalf("failed to issue key: %v", err)
+	}
+	if key.Hash == "" || key.Hash == plaintext {
+		t.Fatalf("expected only the hash to be stored")
+	}
+
+	principal, err := keys.VerifyAPIKey(plaintext)
+	if err != nil || principal.Subject != "ops" || !principal.HasRole("admin") {
+		t.Fatalf("expected valid principal, got %+v, %v", principal, err)
+	}
+
+	rotated, _, err := keys.Rotate(context.Background(), key.ID, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to rotate key: %v", err)
+	}
+	if _, err := keys.VerifyAPIKey(plaintext); err != nil {
+		t.Errorf("expected previous secret to be valid during grace period: %v", err)
+	}
+	if _, err := keys.VerifyAPIKey(rotated); err != nil {
+		t.Errorf("expected rotated secret to be valid: %v", err)
+	}
+
+	if _, _, err := keys.Rotate(context.Background(), key.ID, 0); err != nil {
+		t.Fatalf("failed to rotate key: %v", err)
+	}
+	if _, err := keys.VerifyAPIKey(plaintext); err == nil {
+		t.Errorf("expected secret older than the previous one to be rejected")
+	}
+
+	if err := keys.Revoke(context.Background(), key.ID); err != nil {
+		t.Fatalf("failed to revoke key: %v", err)
+	}
+	if _, err := keys.VerifyAPIKey(rotated); err == nil {
+		t.Errorf("expected revoked key to be rejected")
+	}
+}
+
+func TestAPIKey_FileKeepsChangesAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "keys.json")
+	open := func() *service.APIKeyService {
+		t.Helper()
+		repo, err := repository.NewFileAPIKeyRepository(path)
+		if err != nil {
+			t.Fatalf("failed to load keys: %v", err)
+		}
+		return service.NewAPIKeyService(repo, service.NewAuditService(repository.NewAuditRepository()))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	seed := `[{"id": "seed", "hash": "` + service.HashAPIKeySecret("seed-secret") + `", "subject": "ops", "roles": ["admin"]}]`
+	if err := os.WriteFile(path, []byte(seed), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keys := open()
+	if _, err := keys.VerifyAPIKey("cbk_seed.seed-secret"); err != nil {
+		t.Fatalf("expected the seeded key to verify, got %v", err)
+	}
+	issued, issuedKey, err := keys.Issue(context.Background(), "svc", []string{"teller"}, "partner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, _, err := keys.Rotate(context.Background(), issuedKey.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Revoke(context.Background(), "seed"); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := open()
+	if _, err := restarted.VerifyAPIKey("cbk_seed.seed-secret"); err == nil {
+		t.Error("expected the revoked seeded key to stay revoked")
+	}
+	if _, err := restarted.VerifyAPIKey(issued); err == nil {
+		t.Error("expected the rotated out secret to stay invalid")
+	}
+	principal, err := restarted.VerifyAPIKey(rotated)
+	if err != nil || principal.Subject != "svc" || principal.Tier != "partner" {
+		t.Errorf("expected the rotated key to verify, got %+v %v", principal, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plaintext := range []string{issued, rotated} {
+		_, secret, _ := strings.Cut(plaintext, ".")
+		if strings.Contains(string(data), secret) {
+			t.Errorf("expected only hashes written, found the secret of %s", plaintext)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repository.NewFileAPIKeyRepository(path); err == nil {
+		t.Error("expected a malformed file to be refused")
+	}
+}
+
+func TestAPIKey_ConcurrentChangesApplyInTurn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	repo, err := repository.NewFileAPIKeyRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := service.NewAPIKeyService(repo, service.NewAuditService(repository.NewAuditRepository()))
+	_, key, err := keys.Issue(context.Background(), "svc", []string{"teller"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotations racing a revoke must never bring the key back: each one
+	// reads the key as the one before left it.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if _, _, err := keys.Rotate(context.Background(), key.ID, 0); err != nil && !errors.Is(err, service.ErrAPIKeyNotFound) {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	if err := keys.Revoke(context.Background(), key.ID); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	for _, source := range []*repository.APIKeyRepository{repo, reopen(t, path)} {
+		stored, exists := source.FindByID(key.ID)
+		if !exists || !stored.IsRevoked() {
+			t.Errorf("expected the key to stay revoked, got %+v", stored)
+		}
+	}
+}
+
+func TestAPIKey_InstancesSharingAFileSeeEachOthersChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	audit := service.NewAuditService(repository.NewAuditRepository())
+	first := service.NewAPIKeyService(reopen(t, path), audit)
+	second := service.NewAPIKeyService(reopen(t, path), audit)
+
+	issued, key, err := first.Issue(context.Background(), "svc", []string{"teller"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.VerifyAPIKey(issued); err != nil {
+		t.Fatalf("expected a key issued by another instance to verify, got %v", err)
+	}
+	rotated, _, err := second.Rotate(context.Background(), key.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.VerifyAPIKey(issued); err == nil {
+		t.Error("expected the secret rotated out by another instance to be refused")
+	}
+	if _, err := first.VerifyAPIKey(rotated); err != nil {
+		t.Errorf("expected the secret rotated in by another instance to verify, got %v", err)
+	}
+	if err := second.Revoke(context.Background(), key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.VerifyAPIKey(rotated); err == nil {
+		t.Error("expected a key revoked by another instance to be refused")
+	}
+}
+
+func reopen(t *testing.T, path string) *repository.APIKeyRepository {
+	t.Helper()
+	repo, err := repository.NewFileAPIKeyRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestJWT_HS256(t *testing.T) {
+	secret := []byte("test-secret")
+	verifier := auth.NewJWTVerifier("corebanking", "api")
+	verifier.AddHS256Secret("", secret)
+
+	header := map[string]any{"alg": "HS256", "typ": "JWT"}
+	valid := map[string]any{"sub": "123", "iss": "corebanking", "aud": []string{"api"}, "exp": time.Now().Add(time.Hour).Unix(), "roles": []string{"teller"}}
+
+	principal, err := verifier.Verify(signJWT(t, header, valid, hs256(secret)))
+	if err != nil || principal.Subject != "123" || !principal.HasRole("teller") {
+		t.Fatalf("expected valid principal, got %+v, %v", principal, err)
+	}
+
+	expired := map[string]any{"sub": "123", "iss": "corebanking", "aud": "api", "exp": time.Now().Add(-time.Minute).Unix()}
+	if _, err := verifier.Verify(signJWT(t, header, expired, hs256(secret))); err != auth.ErrTokenExpired {
+		t.Errorf("expected expired token error, got %v", err)
+	}
+
+	if _, err := verifier.Verify(signJWT(t, header, valid, hs256([]byte("other")))); err == nil {
+		t.Errorf("expected token signed with another secret to be rejected")
+	}
+
+	none := map[string]any{"alg": "none"}
+	if _, err := verifier.Verify(signJWT(t, none, valid, func([]byte) []byte { return nil })); err == nil {
+		t.Errorf("expected alg none to be rejected")
+	}
+
+	wrongAudience := map[string]any{"sub": "123", "iss": "corebanking", "aud": "other", "exp": time.Now().Add(time.Hour).Unix()}
+	if _, err := verifier.Verify(signJWT(t, header, wrongAudience, hs256(secret))); err == nil {
+		t.Errorf("expected token for another audience to be rejected")
+	}
+}
+
+func TestJWT_RS256FromJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	jwks := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "k1",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, _ := json.Marshal(jwks)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write jwks: %v", err)
+	}
+
+	verifier := auth.NewJWTVerifier("", "")
+	if err := verifier.LoadJWKSFile(path); err != nil {
+		t.Fatalf("failed to load jwks: %v", err)
+	}
+
+	rs256 := func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		return signature
+	}
+	claims := map[string]any{"sub": "admin-1", "exp": time.Now().Add(time.Hour).Unix(), "role": "admin"}
+
+	principal, err := verifier.Verify(signJWT(t, map[string]any{"alg": "RS256", "kid": "k1"}, claims, rs256))
+	if err != nil || !principal.HasRole("admin") {
+		t.Fatalf("expected valid principal, got %+v, %v", principal, err)
+	}
+
+	if _, err := verifier.Verify(signJWT(t, map[string]any{"alg": "RS256", "kid": "unknown"}, claims, rs256)); err == nil {
+		t.Errorf("expected unknown kid to be rejected")
+	}
+}
+
+func TestAuthenticateMiddleware(t *testing.T) {
+	keys := service.NewAPIKeyService(repository.NewAPIKeyRepository(), service.NewAuditService(repository.NewAuditRepository()))
+	plaintext, _, _ := keys.Issue(context.Background(), "ops", []string{"admin"}, "")
+	authenticator := auth.NewAuthenticator(keys, auth.NewJWTVerifier("", ""))
+
+	handler := middleware.Authenticate(authenticator, nil, middleware.PublicPaths("openapi.json"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFrom(r.Context())
+			if ok {
+				w.Write([]byte(principal.Subject))
+			}
+		}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/accounts/reset", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, recorder.Code)
+	}
+	if recorder.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("expected problem content type, got %q", recorder.Header().Get("Content-Type"))
+	}
+	var problem dto.Problem
+	if err := json.NewDecoder(recorder.Body).Decode(&problem); err != nil || problem.Status != http.StatusUnauthorized {
+		t.Errorf("unexpected problem body %+v, %v", problem, err)
+	}
+
+	recorder = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/1", nil)
+	req.Header.Set(auth.APIKeyHeader, plaintext)
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "ops" {
+		t.Errorf("expected principal ops, got %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected public path to be served, got %d", recorder.Code)
+	}
+
+	for _, path := range []string{"/api/v1/accounts/openapi.json", "/api//openapi.json", "/openapi.json", "/api/v1/openapi.json/x"} {
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("expected %s to need credentials, got %d", path, recorder.Code)
+		}
+	}
+}
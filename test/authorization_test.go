@@ -0,0 +1,307 @@
+package test
+
+import (
+	"context"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type authorizationFixture struct {
+	app              *testApp
+	ownAccount       string
+	otherAccount     string
+	ownTransaction   int64
+	otherTransaction int64
+	otherBatch       string
+}
+
+// newAuthorizationFixture creates two funded accounts: one held by the
+// customer "doc-a" used in the tests and one held by "doc-b", who also
+// submitted a batch.
+func newAuthorizationFixture(t *testing.T) *authorizationFixture {
+	t.Helper()
+
+	app := newTestApp()
+	own, err := app.accountService.CreateAccount(context.Background(), "doc-a", "")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	other, err := app.accountService.CreateAccount(context.Background(), "doc-b", "")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	ownTx, err := app.transactionService.CreateTransaction(context.Background(), &dto.TransactionRequest{AccountID: own.AccountID, OperationTypeID: 4, Amount: 1000})
+	if err != nil {
+		t.Fatalf("failed to fund account: %v", err)
+	}
+	otherTx, err := app.transactionService.CreateTransaction(context.Background(), &dto.TransactionRequest{AccountID: other.AccountID, OperationTypeID: 4, Amount: 1000})
+	if err != nil {
+		t.Fatalf("failed to fund account: %v", err)
+	}
+
+	docB := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "doc-b", Subject: "doc-b", Roles: []string{auth.RoleCustomer}})
+	otherBatch, err := app.batchService.Submit(docB, domain.BatchBestEffort, []domain.BatchItem{{Type: domain.BatchItemDeposit, Destination: other.AccountID, Amount: 10}}, false)
+	if err != nil {
+		t.Fatalf("failed to submit batch: %v", err)
+	}
+
+	return &authorizationFixture{
+		app:              app,
+		ownAccount:       own.AccountID,
+		otherAccount:     other.AccountID,
+		ownTransaction:   ownTx.TransactionID,
+		otherTransaction: otherTx.TransactionID,
+		otherBatch:       otherBatch.ID,
+	}
+}
+
+func (f *authorizationFixture) expand(value string) string {
+	return strings.NewReplacer(
+		"{own}", f.ownAccount,
+		"{other}", f.otherAccount,
+		"{ownTx}", strconv.FormatInt(f.ownTransaction, 10),
+		"{otherTx}", strconv.FormatInt(f.otherTransaction, 10),
+		"{otherBatch}", f.otherBatch,
+	).Replace(value)
+}
+
+func (f *authorizationFixture) expandBody(body map[string]any) map[string]any {
+	if body == nil {
+		return nil
+	}
+	expanded := make(map[string]any, len(body))
+	for key, value := range body {
+		expanded[key] = f.expandValue(value)
+	}
+	return expanded
+}
+
+// expandValue expands strings, and the strings in lists of bodies such as
+// batch items.
+func (f *authorizationFixture) expandValue(value any) any {
+	switch value := value.(type) {
+	case string:
+		return f.expand(value)
+	case []map[string]any:
+		expanded := make([]map[string]any, 0, len(value))
+		for _, item := range value {
+			expanded = append(expanded, f.expandBody(item))
+		}
+		return expanded
+	default:
+		return value
+	}
+}
+
+var (
+	staff        = []string{"teller", "admin", "credit-officer"}
+	operators    = []string{"teller", "admin"}
+	ownerOrStaff = []string{"customer", "teller", "admin", "credit-officer"}
+	ownerOrOps   = []string{"customer", "teller", "admin"}
+	overdraft    = []string{"admin", "credit-officer"}
+	adminOnly    = []string{"admin"}
+)
+
+var authorizationCases = []struct {
+	name    string
+	method  string
+	path    string
+	body    map[string]any
+	allowed []string
+}{
+	{"v1 create own account", http.MethodPost, "/api/v1/accounts", map[string]any{"documentNumber": "doc-a"}, ownerOrOps},
+	{"v1 create other account", http.MethodPost, "/api/v1/accounts", map[string]any{"documentNumber": "doc-c"}, operators},
+	{"v1 get own account", http.MethodGet, "/api/v1/accounts/{own}", nil, ownerOrStaff},
+	{"v1 get other account", http.MethodGet, "/api/v1/accounts/{other}", nil, staff},
+	{"v1 own balance", http.MethodGet, "/api/v1/accounts/balance?account_id={own}", nil, ownerOrStaff},
+	{"v1 other balance", http.MethodGet, "/api/v1/accounts/balance?account_id={other}", nil, staff},
+	{"v1 overdraft", http.MethodPost, "/api/v1/accounts/overdraft", map[string]any{"accountId": "{own}", "limit": 100}, overdraft},
+	{"v1 reset", http.MethodPost, "/api/v1/accounts/reset", nil, adminOnly},
+	{"v1 own transaction", http.MethodPost, "/api/v1/transactions", map[string]any{"accountId": "{own}", "operationTypeId": 1, "amount": 10}, ownerOrOps},
+	{"v1 other transaction", http.MethodPost, "/api/v1/transactions", map[string]any{"accountId": "{other}", "operationTypeId": 1, "amount": 10}, operators},
+	{"v1 own credit", http.MethodPost, "/api/v1/transactions", map[string]any{"accountId": "{own}", "operationTypeId": 4, "amount": 10}, operators},
+	{"v1 deposit own", http.MethodPost, "/api/v1/transactions/event", map[string]any{"type": "deposit", "destination": "{own}", "amount": 10}, operators},
+	{"v1 withdraw own", http.MethodPost, "/api/v1/transactions/event", map[string]any{"type": "withdraw", "origin": "{own}", "amount": 10}, ownerOrOps},
+	{"v1 withdraw other", http.MethodPost, "/api/v1/transactions/event", map[string]any{"type": "withdraw", "origin": "{other}", "amount": 10}, operators},
+	{"v1 transfer from own", http.MethodPost, "/api/v1/transactions/event", map[string]any{"type": "transfer", "origin": "{own}", "destination": "{other}", "amount": 10}, ownerOrOps},
+	{"v1 transfer from other", http.MethodPost, "/api/v1/transactions/event", map[string]any{"type": "transfer", "origin": "{other}", "destination": "{own}", "amount": 10}, operators},
+	{"v1 get own transaction", http.MethodGet, "/api/v1/transactions/{ownTx}", nil, ownerOrStaff},
+	{"v1 get other transaction", http.MethodGet, "/api/v1/transactions/{otherTx}", nil, staff},
+	{"v1 today", http.MethodGet, "/api/v1/transactions/today", nil, staff},
+	{"v1 range", http.MethodGet, "/api/v1/transactions/range?begin=2020-01-01T00:00:00Z&end=2030-01-01T00:00:00Z", nil, staff},
+	{"v1 by type", http.MethodGet, "/api/v1/transactions/type/4", nil, staff},
+	{"v1 all", http.MethodGet, "/api/v1/transactions/all", nil, staff},
+
+	{"v2 create own account", http.MethodPost, "/api/v2/accounts", map[string]any{"documentNumber": "doc-a"}, ownerOrOps},
+	{"v2 create other account", http.MethodPost, "/api/v2/accounts", map[string]any{"documentNumber": "doc-c"}, operators},
+	{"v2 get own account", http.MethodGet, "/api/v2/accounts/{own}", nil, ownerOrStaff},
+	{"v2 get other account", http.MethodGet, "/api/v2/accounts/{other}", nil, staff},
+	{"v2 own balance", http.MethodGet, "/api/v2/accounts/{own}/balance", nil, ownerOrStaff},
+	{"v2 other balance", http.MethodGet, "/api/v2/accounts/{other}/balance", nil, staff},
+	{"v2 verify other account", http.MethodGet, "/api/v2/accounts/{other}/verify", nil, ownerOrStaff},
+	{"v2 own limits", http.MethodGet, "/api/v2/accounts/{own}/limits", nil, ownerOrStaff},
+	{"v2 other limits", http.MethodGet, "/api/v2/accounts/{other}/limits", nil, staff},
+	{"v2 set limits", http.MethodPut, "/api/v2/accounts/{own}/limits", map[string]any{"limits": map[string]any{"dailyDebitLimit": "10.00"}}, adminOnly},
+	{"v2 schedule from own", http.MethodPost, "/api/v2/accounts/{own}/schedules", map[string]any{"destination": "{other}", "amount": "0.10", "frequency": "once"}, ownerOrOps},
+	{"v2 schedule from other", http.MethodPost, "/api/v2/accounts/{other}/schedules", map[string]any{"destination": "{own}", "amount": "0.10", "frequency": "once"}, operators},
+	{"v2 own schedules", http.MethodGet, "/api/v2/accounts/{own}/schedules", nil, ownerOrStaff},
+	{"v2 other schedules", http.MethodGet, "/api/v2/accounts/{other}/schedules", nil, staff},
+	{"v2 get own schedule", http.MethodGet, "/api/v2/accounts/{own}/schedules/unknown", nil, ownerOrStaff},
+	{"v2 own schedule executions", http.MethodGet, "/api/v2/accounts/{own}/schedules/unknown/executions", nil, ownerOrStaff},
+	{"v2 pause own schedule", http.MethodPost, "/api/v2/accounts/{own}/schedules/unknown/pause", nil, ownerOrOps},
+	{"v2 resume own schedule", http.MethodPost, "/api/v2/accounts/{own}/schedules/unknown/resume", nil, ownerOrOps},
+	{"v2 cancel own schedule", http.MethodPost, "/api/v2/accounts/{own}/schedules/unknown/cancel", nil, ownerOrOps},
+	{"v2 cancel other schedule", http.MethodPost, "/api/v2/accounts/{other}/schedules/unknown/cancel", nil, operators},
+	{"v2 overdraft", http.MethodPut, "/api/v2/accounts/{own}/overdraft", map[string]any{"limit": "1.00"}, overdraft},
+	{"v2 own transaction", http.MethodPost, "/api/v2/transactions", map[string]any{"accountId": "{own}", "operationTypeId": 1, "amount": "0.10"}, ownerOrOps},
+	{"v2 other transaction", http.MethodPost, "/api/v2/transactions", map[string]any{"accountId": "{other}", "operationTypeId": 1, "amount": "0.10"}, operators},
+	{"v2 own credit", http.MethodPost, "/api/v2/transactions", map[string]any{"accountId": "{own}", "operationTypeId": 4, "amount": "0.10"}, operators},
+	{"v2 list transactions", http.MethodGet, "/api/v2/transactions", nil, staff},
+	{"v2 get own transaction", http.MethodGet, "/api/v2/transactions/{ownTx}", nil, ownerOrStaff},
+	{"v2 get other transaction", http.MethodGet, "/api/v2/transactions/{otherTx}", nil, staff},
+	{"v2 batch from own", http.MethodPost, "/api/v2/transactions/batch", map[string]any{"items": []map[string]any{
+		{"type": "transfer", "origin": "{own}", "destination": "{other}", "amount": "0.10"},
+		{"type": "transaction", "accountId": "{own}", "operationTypeId": 1, "amount": "0.10"},
+	}}, ownerOrOps},
+	{"v2 batch touching other", http.MethodPost, "/api/v2/transactions/batch", map[string]any{"items": []map[string]any{
+		{"type": "transfer", "origin": "{own}", "destination": "{other}", "amount": "0.10"},
+		{"type": "withdraw", "origin": "{other}", "amount": "0.10"},
+	}}, operators},
+	{"v2 batch depositing to own", http.MethodPost, "/api/v2/transactions/batch", map[string]any{"items": []map[string]any{
+		{"type": "deposit", "destination": "{own}", "amount": "0.10"},
+	}}, operators},
+	{"v2 get other batch", http.MethodGet, "/api/v2/transactions/batch/{otherBatch}", nil, staff},
+	{"v2 transfer from own", http.MethodPost, "/api/v2/events", map[string]any{"type": "transfer", "origin": "{own}", "destination": "{other}", "amount": "0.10"}, ownerOrOps},
+	{"v2 transfer from other", http.MethodPost, "/api/v2/events", map[string]any{"type": "transfer", "origin": "{other}", "destination": "{own}", "amount": "0.10"}, operators},
+	{"v2 deposit own", http.MethodPost, "/api/v2/events", map[string]any{"type": "deposit", "destination": "{own}", "amount": "0.10"}, operators},
+	{"v2 deposit other", http.MethodPost, "/api/v2/events", map[string]any{"type": "deposit", "destination": "{other}", "amount": "0.10"}, operators},
+	{"v2 list api keys", http.MethodGet, "/api/v2/auth/keys", nil, adminOnly},
+	{"v2 issue api key", http.MethodPost, "/api/v2/auth/keys", map[string]any{"subject": "svc", "roles": []string{"teller"}}, adminOnly},
+	{"v2 rotate api key", http.MethodPost, "/api/v2/auth/keys/unknown/rotate", map[string]any{"graceSeconds": 0}, adminOnly},
+	{"v2 revoke api key", http.MethodDelete, "/api/v2/auth/keys/unknown", nil, adminOnly},
+	{"v2 reset", http.MethodPost, "/api/v2/system/reset", nil, adminOnly},
+	{"v2 reset account", http.MethodPost, "/api/v2/accounts/{other}/reset", nil, adminOnly},
+	{"v2 query audit", http.MethodGet, "/api/v2/audit", nil, adminOnly},
+	{"v2 verify audit", http.MethodGet, "/api/v2/audit/verify", nil, adminOnly},
+	{"v2 list fraud cases", http.MethodGet, "/api/v2/fraud/cases", nil, adminOnly},
+	{"v2 get fraud case", http.MethodGet, "/api/v2/fraud/cases/unknown", nil, adminOnly},
+	{"v2 approve fraud case", http.MethodPost, "/api/v2/fraud/cases/unknown/approve", map[string]any{}, adminOnly},
+	{"v2 reject fraud case", http.MethodPost, "/api/v2/fraud/cases/unknown/reject", map[string]any{"note": "ok"}, adminOnly},
+	{"v2 list aml alerts", http.MethodGet, "/api/v2/aml/alerts", nil, adminOnly},
+	{"v2 get aml alert", http.MethodGet, "/api/v2/aml/alerts/unknown", nil, adminOnly},
+	{"v2 disposition aml alert", http.MethodPost, "/api/v2/aml/alerts/unknown/disposition", map[string]any{"disposition": "no_action"}, adminOnly},
+	{"v2 export aml report", http.MethodGet, "/api/v2/aml/reports", nil, adminOnly},
+	{"v2 get sanctions list", http.MethodGet, "/api/v2/sanctions/list", nil, adminOnly},
+	{"v2 reload sanctions list", http.MethodPost, "/api/v2/sanctions/list/reload", nil, adminOnly},
+	{"v2 list sanctions screenings", http.MethodGet, "/api/v2/sanctions/screenings", nil, adminOnly},
+	{"v2 list sanctions cases", http.MethodGet, "/api/v2/sanctions/cases", nil, adminOnly},
+	{"v2 get sanctions case", http.MethodGet, "/api/v2/sanctions/cases/unknown", nil, adminOnly},
+	{"v2 approve sanctions case", http.MethodPost, "/api/v2/sanctions/cases/unknown/approve", map[string]any{}, adminOnly},
+	{"v2 reject sanctions case", http.MethodPost, "/api/v2/sanctions/cases/unknown/reject", map[string]any{}, adminOnly},
+	{"v2 list suspense items", http.MethodGet, "/api/v2/suspense/items", nil, adminOnly},
+	{"v2 get suspense item", http.MethodGet, "/api/v2/suspense/items/unknown", nil, adminOnly},
+	{"v2 claim suspense item", http.MethodPost, "/api/v2/suspense/items/unknown/claim", map[string]any{"accountId": "{own}"}, adminOnly},
+	{"v2 return suspense item", http.MethodPost, "/api/v2/suspense/items/unknown/return", map[string]any{}, adminOnly},
+}
+
+// deniedAsNotFound lists the cases answered as if the resource did not
+// exist when the caller may not read it, with the status that means.
+var deniedAsNotFound = map[string]int{
+	"v1 get other transaction": http.StatusBadRequest,
+	"v2 get other transaction": http.StatusNotFound,
+}
+
+func TestAuthorization_EveryRouteAndRole(t *testing.T) {
+	for _, tc := range authorizationCases {
+		for _, role := range []string{"customer", "teller", "admin", "credit-officer"} {
+			t.Run(tc.name+"/"+role, func(t *testing.T) {
+				fixture := newAuthorizationFixture(t)
+				resp := fixture.app.do(t, tc.method, fixture.expand(tc.path), fixture.expandBody(tc.body), map[string]string{
+					"X-Test-Subject": "doc-a",
+					"X-Test-Roles":   role,
+				})
+
+				denied := http.StatusForbidden
+				if status, hidden := deniedAsNotFound[tc.name]; hidden {
+					denied = status
+				}
+				allowed := slices.Contains(tc.allowed, role)
+				if allowed && resp.Code == denied {
+					t.Errorf("expected %s to be allowed, got %d: %s", role, resp.Code, resp.Body.String())
+				}
+				if !allowed && resp.Code != denied {
+					t.Errorf("expected %s to be denied with %d, got %d", role, denied, resp.Code)
+				}
+			})
+		}
+	}
+}
+
+func TestAuthorization_OtherTransactionsLookUnknown(t *testing.T) {
+	fixture := newAuthorizationFixture(t)
+	customer := map[string]string{"X-Test-Subject": "doc-a", "X-Test-Roles": "customer"}
+
+	for _, version := range []string{"v1", "v2"} {
+		prefix := "/api/" + version + "/transactions/"
+		other := fixture.app.do(t, http.MethodGet, fixture.expand(prefix+"{otherTx}"), nil, customer)
+		unknown := fixture.app.do(t, http.MethodGet, prefix+"999999999", nil, customer)
+		if other.Code != unknown.Code || other.Body.String() != unknown.Body.String() {
+			t.Errorf("%s: expected another account's transaction to look unknown, got %d %s and %d %s",
+				version, other.Code, other.Body.String(), unknown.Code, unknown.Body.String())
+		}
+		if other.Code == http.StatusForbidden {
+			t.Errorf("%s: expected no 403 to give the transaction away", version)
+		}
+	}
+}
+
+func TestAuthorization_UnknownRoleIsForbidden(t *testing.T) {
+	fixture := newAuthorizationFixture(t)
+
+	resp := fixture.app.do(t, http.MethodGet, fixture.expand("/api/v1/accounts/{own}"), nil, map[string]string{
+		"X-Test-Subject": "doc-a",
+		"X-Test-Roles":   "auditor",
+	})
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, resp.Code)
+	}
+}
+
+func TestAuthorization_NonPositiveAmountsAreRefused(t *testing.T) {
+	customer := map[string]string{"X-Test-Subject": "doc-a", "X-Test-Roles": "customer"}
+	for _, tc := range []struct {
+		name string
+		path string
+		body map[string]any
+	}{
+		{"v1 negative transfer", "/api/v1/transactions/event", map[string]any{"type": "transfer", "origin": "{own}", "destination": "{other}", "amount": -500}},
+		{"v1 negative withdraw", "/api/v1/transactions/event", map[string]any{"type": "withdraw", "origin": "{own}", "amount": -500}},
+		{"v1 negative purchase", "/api/v1/transactions", map[string]any{"accountId": "{own}", "operationTypeId": 1, "amount": -500}},
+		{"v1 zero transfer", "/api/v1/transactions/event", map[string]any{"type": "transfer", "origin": "{own}", "destination": "{other}", "amount": 0}},
+		{"v2 negative transfer", "/api/v2/events", map[string]any{"type": "transfer", "origin": "{own}", "destination": "{other}", "amount": "-5.00"}},
+		{"v2 negative withdraw", "/api/v2/events", map[string]any{"type": "withdraw", "origin": "{own}", "amount": "-5.00"}},
+		{"v2 negative purchase", "/api/v2/transactions", map[string]any{"accountId": "{own}", "operationTypeId": 1, "amount": "-5.00"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fixture := newAuthorizationFixture(t)
+			balances := func() []int64 {
+				return []int64{balance(t, fixture.app, fixture.ownAccount), balance(t, fixture.app, fixture.otherAccount)}
+			}
+			before := balances()
+			resp := fixture.app.do(t, http.MethodPost, tc.path, fixture.expandBody(tc.body), customer)
+			if resp.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, resp.Code, resp.Body.String())
+			}
+			if after := balances(); !slices.Equal(after, before) {
+				t.Errorf("expected balances %v untouched, got %v", before, after)
+			}
+		})
+	}
+}
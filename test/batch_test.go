@@ -0,0 +1,417 @@
+package test
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/repository"
+	"corebanking/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type batchEnvelope struct {
+	Data  dto.BatchV2Response `json:"data"`
+	Error *dto.EnvelopeError  `json:"error"`
+}
+
+var testBatchPolicy = service.BatchPolicy{MaxItems: 100, AsyncThreshold: 5, QueueSize: 1, Workers: 4, Retention: time.Hour}
+
+func postBatch(t *testing.T, app *testApp, body map[string]any) (int, dto.BatchV2Response) {
+	t.Helper()
+	resp := app.do(t, http.MethodPost, "/api/v2/transactions/batch", body, nil)
+	var posted batchEnvelope
+	decodeBody(t, resp, &posted)
+	if posted.Error != nil {
+		t.Fatalf("expected the batch taken, got %d %+v", resp.Code, posted.Error)
+	}
+	return resp.Code, posted.Data
+}
+
+func itemStatuses(batch dto.BatchV2Response) []string {
+	statuses := make([]string, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		statuses = append(statuses, item.Status)
+	}
+	return statuses
+}
+
+func TestBatch_BestEffortPostsWhatItCan(t *testing.T) {
+	app := newTestApp()
+	a, b := fundedAccount(t, app, "1"), fundedAccount(t, app, "2")
+	c := app.createAccount(t, "3")
+
+	code, batch := postBatch(t, app, map[string]any{"mode": "best_effort", "items": []map[string]any{
+		{"type": "transfer", "origin": a, "destination": b, "amount": "100.00", "reference": "pay-1"},
+		{"type": "withdraw", "origin": c, "amount": "1.00", "reference": "pay-2"},
+		{"type": "deposit", "destination": c, "amount": "5.00"},
+		{"type": "transaction", "accountId": a, "operationTypeId": 1, "amount": "10.00"},
+	}})
+	if code != http.StatusCreated || batch.Status != domain.BatchCompleted {
+		t.Fatalf("expected a completed batch, got %d %s", code, batch.Status)
+	}
+	want := []string{domain.BatchItemPosted, domain.BatchItemFailed, domain.BatchItemPosted, domain.BatchItemPosted}
+	if got := itemStatuses(batch); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if batch.Total != 4 || batch.Posted != 3 || batch.Failed != 1 {
+		t.Errorf("expected 3 of 4 posted, got %+v", batch)
+	}
+	if item := batch.Items[1]; item.Code != "insufficient_funds" || item.Reference != "pay-2" {
+		t.Errorf("expected the withdrawal refused for lack of funds, got %+v", item)
+	}
+	if batch.Items[3].TransactionID == 0 {
+		t.Error("expected the purchase's transaction ID")
+	}
+	if got := []int64{balance(t, app, a), balance(t, app, b), balance(t, app, c)}; !slices.Equal(got, []int64{89000, 110000, 500}) {
+		t.Errorf("unexpected balances %v", got)
+	}
+
+	resp := app.do(t, http.MethodGet, "/api/v2/transactions/batch/"+batch.ID, nil, nil)
+	var read batchEnvelope
+	decodeBody(t, resp, &read)
+	if resp.Code != http.StatusOK || !slices.Equal(itemStatuses(read.Data), want) {
+		t.Errorf("expected the batch read back, got %d %+v", resp.Code, read)
+	}
+	if resp := app.do(t, http.MethodGet, "/api/v2/transactions/batch/unknown", nil, nil); resp.Code != http.StatusNotFound {
+		t.Errorf("expected an unknown batch to be 404, got %d", resp.Code)
+	}
+}
+
+func TestBatch_AllOrNothingRollsBack(t *testing.T) {
+	app := newLimitsApp(domain.Limits{DailyDebitLimit: 20000})
+	a, b := fundedAccount(t, app, "1"), fundedAccount(t, app, "2")
+	c := app.createAccount(t, "3")
+	transactions := len(app.transactionService.GetAllTransactions(context.Background()))
+
+	code, batch := postBatch(t, app, map[string]any{"items": []map[string]any{
+		{"type": "transfer", "origin": a, "destination": b, "amount": "100.00"},
+		{"type": "transaction", "accountId": a, "operationTypeId": 1, "amount": "50.00"},
+		{"type": "withdraw", "origin": c, "amount": "1.00"},
+		{"type": "deposit", "destination": c, "amount": "1.00"},
+	}})
+	if code != http.StatusCreated || batch.Mode != domain.BatchAllOrNothing || batch.Status != domain.BatchFailed || batch.Error != "" {
+		t.Fatalf("expected a rolled back all-or-nothing batch, got %d %+v", code, batch)
+	}
+	want := []string{domain.BatchItemRolledBack, domain.BatchItemRolledBack, domain.BatchItemFailed, domain.BatchItemSkipped}
+	if got := itemStatuses(batch); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := []int64{balance(t, app, a), balance(t, app, b), balance(t, app, c)}; !slices.Equal(got, []int64{100000, 100000, 0}) {
+		t.Errorf("expected balances restored, got %v", got)
+	}
+	if got := len(app.transactionService.GetAllTransactions(context.Background())); got != transactions {
+		t.Errorf("expected the purchase's transaction dropped, got %d transactions from %d", got, transactions)
+	}
+	if rollbacks := app.auditService.Query(context.Background(), "", "account", a, time.Time{}, time.Time{}); !slices.ContainsFunc(rollbacks, func(entry *domain.AuditEntry) bool {
+		return entry.Action == service.AuditBatchRollback
+	}) {
+		t.Error("expected the reversal audited")
+	}
+	// The daily limit was given back along with the money.
+	if err := purchase(app, a, 20000); err != nil {
+		t.Errorf("expected the whole daily limit left, got %v", err)
+	}
+}
+
+func TestBatch_AllOrNothingHidesCreditsItRollsBack(t *testing.T) {
+	app := newTestApp()
+	a := fundedAccount(t, app, "1")
+	b, c := app.createAccount(t, "2"), app.createAccount(t, "3")
+
+	// b is credited first and the last item fails, so the credit is taken
+	// back. The batch is long enough for a withdrawal from b to race it,
+	// which must never spend the credit.
+	policy := testBatchPolicy
+	policy.MaxItems, policy.AsyncThreshold = 5000, 5000
+	batches := service.NewBatchServiceWithClock(repository.NewBatchRepository(), app.accountRepo, app.transactionService, policy, app.clock.Now)
+	items := []domain.BatchItem{{Type: domain.BatchItemTransfer, Origin: a, Destination: b, Amount: 50000}}
+	for len(items) < policy.MaxItems-1 {
+		items = append(items, domain.BatchItem{Type: domain.BatchItemTransaction, AccountID: a, OperationTypeID: 1, Amount: 1})
+	}
+	items = append(items, domain.BatchItem{Type: domain.BatchItemWithdraw, Origin: c, Amount: 100})
+
+	done := make(chan struct{})
+	spent := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case <-done:
+				spent <- nil
+				return
+			default:
+			}
+			if err := withdraw(app, b, 50000); err == nil {
+				spent <- errors.New("withdrew the batch's credit before it was rolled back")
+				return
+			}
+		}
+	}()
+	batch, err := batches.Submit(context.Background(), domain.BatchAllOrNothing, items, false)
+	close(done)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-spent; err != nil {
+		t.Error(err)
+	}
+	if batch.Status != domain.BatchFailed || batch.Error != "" || batch.Results[0].Status != domain.BatchItemRolledBack {
+		t.Fatalf("expected the batch rolled back, got %s %q %+v", batch.Status, batch.Error, batch.Results[0])
+	}
+	if got := []int64{balance(t, app, a), balance(t, app, b)}; !slices.Equal(got, []int64{100000, 0}) {
+		t.Errorf("expected balances restored, got %v", got)
+	}
+}
+
+func TestBatch_AllOrNothingUndoesHolds(t *testing.T) {
+	app := newTestApp()
+	app.suspenseService.SetPolicy(service.SuspensePolicy{UnknownDestination: domain.UnknownDestinationSuspense})
+	a, b := fundedAccount(t, app, "1"), fundedAccount(t, app, "2")
+
+	_, batch := postBatch(t, app, map[string]any{"items": []map[string]any{
+		{"type": "transfer", "origin": a, "destination": b, "amount": "10.00"},
+		{"type": "transfer", "origin": a, "destination": "missing", "amount": "20.00"},
+	}})
+	if batch.Status != domain.BatchFailed || batch.Items[1].Code != "held_in_suspense" {
+		t.Fatalf("expected the held transfer to fail the batch, got %+v", batch)
+	}
+	if got := []int64{balance(t, app, a), balance(t, app, b), balance(t, app, app.suspenseService.AccountID())}; !slices.Equal(got, []int64{100000, 100000, 0}) {
+		t.Errorf("expected balances restored, got %v", got)
+	}
+	items := app.suspenseService.Items(context.Background(), "", "")
+	if len(items) != 1 || items[0].Status != domain.SuspenseReturned {
+		t.Errorf("expected the suspense item returned, got %+v", items)
+	}
+
+	_, batch = postBatch(t, app, map[string]any{"mode": "best_effort", "items": []map[string]any{
+		{"type": "transfer", "origin": a, "destination": "missing", "amount": "20.00"},
+	}})
+	if batch.Items[0].Status != domain.BatchItemHeld || batch.Held != 1 {
+		t.Errorf("expected a best-effort hold left held, got %+v", batch)
+	}
+}
+
+func TestBatch_BestEffortKeepsAccountOrder(t *testing.T) {
+	app := newTestApp()
+	accounts := make([]string, 0, 6)
+	for _, document := range []string{"1", "2", "3", "4", "5", "6"} {
+		accounts = append(accounts, app.createAccount(t, document))
+	}
+
+	// Each account only ever holds what its last deposit brought, so every
+	// withdrawal fails unless the items of an account run in order; the
+	// accounts are independent and run in parallel.
+	items := make([]map[string]any, 0, 60)
+	for round := 0; round < 5; round++ {
+		for _, accountID := range accounts {
+			items = append(items,
+				map[string]any{"type": "deposit", "destination": accountID, "amount": "1.00"},
+				map[string]any{"type": "withdraw", "origin": accountID, "amount": "1.00"})
+		}
+	}
+	_, batch := postBatch(t, app, map[string]any{"mode": "best_effort", "async": false, "items": items[:4]})
+	if batch.Posted != 4 {
+		t.Fatalf("expected a small batch posted, got %+v", batch)
+	}
+
+	code, batch := postBatch(t, app, map[string]any{"mode": "best_effort", "items": items})
+	if code != http.StatusAccepted {
+		t.Fatalf("expected a large batch left for the background, got %d", code)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go app.batchService.Run(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for batch.Status != domain.BatchCompleted {
+		if time.Now().After(deadline) {
+			t.Fatalf("batch not processed, status %s", batch.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+		resp := app.do(t, http.MethodGet, "/api/v2/transactions/batch/"+batch.ID, nil, nil)
+		var read batchEnvelope
+		decodeBody(t, resp, &read)
+		batch = read.Data
+	}
+	if batch.Posted != len(items) {
+		t.Errorf("expected every item posted in order, got %d of %d: %v", batch.Posted, len(items), itemStatuses(batch))
+	}
+	for _, accountID := range accounts {
+		if got := balance(t, app, accountID); got != 0 {
+			t.Errorf("expected %s back to 0, got %d", accountID, got)
+		}
+	}
+}
+
+func TestBatch_ReadsProgressWhileProcessing(t *testing.T) {
+	for _, mode := range []string{domain.BatchAllOrNothing, domain.BatchBestEffort} {
+		t.Run(mode, func(t *testing.T) {
+			app := newTestApp()
+			a := fundedAccount(t, app, "1")
+
+			// The second withdrawal stops while its limits are reserved,
+			// with the first already posted.
+			reached, resume := make(chan struct{}), make(chan struct{})
+			var calls atomic.Int32
+			gate := func() time.Time {
+				if calls.Add(1) == 2 {
+					close(reached)
+					<-resume
+				}
+				return app.clock.Now()
+			}
+			app.transactionService.SetLimitService(service.NewLimitServiceWithClock(repository.NewLimitUsageRepository(), app.accountRepo, app.auditService, service.LimitPolicy{}, gate))
+
+			withdrawal := map[string]any{"type": "withdraw", "origin": a, "amount": "1.00"}
+			_, batch := postBatch(t, app, map[string]any{"mode": mode, "async": true, "items": []map[string]any{withdrawal, withdrawal, withdrawal}})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go app.batchService.Run(ctx)
+
+			select {
+			case <-reached:
+			case <-time.After(5 * time.Second):
+				t.Fatal("batch not processed")
+			}
+			read := readBatch(t, app, batch.ID)
+			close(resume)
+			want := []string{domain.BatchItemPosted, domain.BatchItemPending, domain.BatchItemPending}
+			if read.Status != domain.BatchProcessing || read.Posted != 1 || !slices.Equal(itemStatuses(read), want) {
+				t.Errorf("expected the first item posted mid-run, got %s %d %v", read.Status, read.Posted, itemStatuses(read))
+			}
+
+			deadline := time.Now().Add(5 * time.Second)
+			for read.Status == domain.BatchProcessing {
+				if time.Now().After(deadline) {
+					t.Fatalf("batch not completed, status %s", read.Status)
+				}
+				time.Sleep(10 * time.Millisecond)
+				read = readBatch(t, app, batch.ID)
+			}
+			if read.Status != domain.BatchCompleted || read.Posted != 3 {
+				t.Errorf("expected every item posted, got %s %v", read.Status, itemStatuses(read))
+			}
+		})
+	}
+}
+
+func readBatch(t *testing.T, app *testApp, id string) dto.BatchV2Response {
+	t.Helper()
+	resp := app.do(t, http.MethodGet, "/api/v2/transactions/batch/"+id, nil, nil)
+	var read batchEnvelope
+	decodeBody(t, resp, &read)
+	return read.Data
+}
+
+func TestBatch_OtherWritersWaitForTheAccount(t *testing.T) {
+	app := newLimitsApp(domain.Limits{})
+	a, b := app.createAccount(t, "1"), app.createAccount(t, "2")
+
+	// Limits, overdraft and a reset change accounts the batch is posting
+	// to while it runs, and balances are read meanwhile. No posting may be
+	// undone by a save of the account read before it, nor the reset account
+	// saved back.
+	policy := testBatchPolicy
+	policy.MaxItems, policy.AsyncThreshold = 2000, 2000
+	batches := service.NewBatchServiceWithClock(repository.NewBatchRepository(), app.accountRepo, app.transactionService, policy, app.clock.Now)
+	items := make([]domain.BatchItem, 0, policy.MaxItems)
+	for len(items) < policy.MaxItems {
+		items = append(items,
+			domain.BatchItem{Type: domain.BatchItemDeposit, Destination: a, Amount: 1},
+			domain.BatchItem{Type: domain.BatchItemDeposit, Destination: b, Amount: 1})
+	}
+
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() {
+		batch, err := batches.Submit(ctx, domain.BatchBestEffort, items, false)
+		if err == nil && batch.Status != domain.BatchCompleted {
+			err = fmt.Errorf("batch %s", batch.Status)
+		}
+		done <- err
+	}()
+	for i := int64(0); ; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := balance(t, app, a); got != int64(len(items)/2) {
+				t.Errorf("expected every deposit kept, got %d of %d", got, len(items)/2)
+			}
+			if _, err := app.accountService.GetAccount(ctx, b); !errors.Is(err, service.ErrAccountNotFound) {
+				t.Errorf("expected the reset account to stay removed, got %v", err)
+			}
+			if limits, err := app.limitService.GetLimits(ctx, a); err != nil || limits.Product != "premium" {
+				t.Errorf("expected the last product kept, got %+v %v", limits, err)
+			}
+			return
+		default:
+		}
+		if _, err := app.limitService.SetLimits(ctx, a, "premium", &domain.Limits{DailyDebitLimit: i}); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.accountService.ConfigOverdraft(ctx, a, i); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := app.accountService.GetBalance(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := app.accountService.GetAccount(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 {
+			resp := app.do(t, http.MethodPost, "/api/v2/accounts/"+b+"/reset", nil, map[string]string{"X-Reset-Confirmation": testResetToken})
+			if resp.Code != http.StatusOK {
+				t.Fatalf("expected the reset to pass, got %d", resp.Code)
+			}
+		}
+	}
+}
+
+func TestBatch_RefusesInvalidAndOverflowingBatches(t *testing.T) {
+	app := newTestApp()
+	a := fundedAccount(t, app, "1")
+
+	for _, body := range []map[string]any{
+		{"items": []map[string]any{}},
+		{"mode": "sometimes", "items": []map[string]any{{"type": "deposit", "destination": a, "amount": "1.00"}}},
+		{"items": []map[string]any{{"type": "deposit", "destination": a, "amount": "1.00"}, {"type": "refund", "origin": a, "amount": "1.00"}}},
+		{"items": []map[string]any{{"type": "transaction", "accountId": a, "operationTypeId": 9, "amount": "1.00"}}},
+		{"items": []map[string]any{{"type": "transfer", "origin": a, "destination": a, "amount": "1.00"}}},
+	} {
+		if resp := app.do(t, http.MethodPost, "/api/v2/transactions/batch", body, nil); resp.Code != http.StatusBadRequest {
+			t.Errorf("expected %v refused, got %d", body, resp.Code)
+		}
+	}
+
+	// Nothing runs the queue, which holds one batch.
+	async := map[string]any{"async": true, "items": []map[string]any{{"type": "deposit", "destination": a, "amount": "1.00"}}}
+	if code, batch := postBatch(t, app, async); code != http.StatusAccepted || batch.Status != domain.BatchPending {
+		t.Fatalf("expected the batch queued, got %d %s", code, batch.Status)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/v2/transactions/batch", async, nil); resp.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected a full queue to be 503, got %d", resp.Code)
+	}
+	if got := balance(t, app, a); got != 100000 {
+		t.Errorf("expected queued batches not posted yet, got %d", got)
+	}
+}
+
+func TestLocks_UnknownAccountsLeaveNoLocks(t *testing.T) {
+	app := newTestApp()
+	for i := 0; i < 50; i++ {
+		unknown := fmt.Sprintf("unknown-%d", i)
+		app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "withdraw", "origin": unknown, "amount": "1.00"}, nil)
+		app.do(t, http.MethodPost, "/api/v1/transactions", map[string]any{"accountId": unknown, "operationTypeId": 1, "amount": 100}, nil)
+	}
+	if locked := app.accountRepo.Locked(); locked != 0 {
+		t.Errorf("expected no account locks left after the requests, got %d", locked)
+	}
+}
@@ -0,0 +1,134 @@
+//go:build unix
+
+package test
+
+import (
+	"corebanking/internal/event"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// stalledLogChannel opens a channel on a FIFO nobody reads yet and sends a
+// message larger than the pipe buffer, so the worker is stuck writing it and
+// the next sends hit the overflow policy. drain starts reading, closes the
+// channel and returns the lines written after the stalling message.
+func stalledLogChannel(t *testing.T, options event.Options) (lc *event.LogChannel, drain func() []string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "log.fifo")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Skipf("fifo not supported: %v", err)
+	}
+	reader, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Fatalf("failed to open fifo: %v", err)
+	}
+
+	lc, err = event.NewLogChannelWithOptions(path, options)
+	if err != nil {
+		t.Fatalf("failed to create LogChannel: %v", err)
+	}
+	if err := lc.Send(strings.Repeat("x", 256*1024)); err != nil {
+		t.Fatalf("failed to send stalling message: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	return lc, func() []string {
+		done := make(chan []byte)
+		go func() {
+			data, _ := io.ReadAll(reader)
+			done <- data
+		}()
+		if err := lc.Close(); err != nil {
+			t.Fatalf("failed to close LogChannel: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(<-done)), "\n")
+		reader.Close()
+		return lines[1:]
+	}
+}
+
+func sendAll(t *testing.T, lc *event.LogChannel, messages ...string) []error {
+	t.Helper()
+	errs := make([]error, len(messages))
+	for i, message := range messages {
+		errs[i] = lc.Send(message)
+	}
+	return errs
+}
+
+func TestLogChannel_DropNewest(t *testing.T) {
+	lc, drain := stalledLogChannel(t, event.Options{BufferSize: 2, Overflow: event.OverflowDropNewest})
+
+	errs := sendAll(t, lc, "a", "b", "c")
+	if errs[0] != nil || errs[1] != nil || !errors.Is(errs[2], event.ErrLogDropped) {
+		t.Errorf("unexpected send errors %v", errs)
+	}
+
+	if lines := drain(); strings.Join(lines, ",") != "a,b" {
+		t.Errorf("expected a,b, got %v", lines)
+	}
+	if stats := lc.Stats(); stats.Dropped != 1 || stats.Written != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLogChannel_DropOldest(t *testing.T) {
+	lc, drain := stalledLogChannel(t, event.Options{BufferSize: 2, Overflow: event.OverflowDropOldest})
+
+	for _, err := range sendAll(t, lc, "a", "b", "c") {
+		if err != nil {
+			t.Errorf("unexpected send error %v", err)
+		}
+	}
+
+	if lines := drain(); strings.Join(lines, ",") != "b,c" {
+		t.Errorf("expected b,c, got %v", lines)
+	}
+	if stats := lc.Stats(); stats.Dropped != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLogChannel_Spill(t *testing.T) {
+	lc, drain := stalledLogChannel(t, event.Options{BufferSize: 2, Overflow: event.OverflowSpill, SpillSize: 2})
+
+	errs := sendAll(t, lc, "a", "b", "c", "d", "e")
+	if errs[2] != nil || errs[3] != nil || !errors.Is(errs[4], event.ErrLogDropped) {
+		t.Errorf("unexpected send errors %v", errs)
+	}
+
+	if lines := drain(); strings.Join(lines, ",") != "a,b,c,d" {
+		t.Errorf("expected a,b,c,d in order, got %v", lines)
+	}
+	if stats := lc.Stats(); stats.Spilled != 2 || stats.Dropped != 1 || stats.Written != 5 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLogChannel_BlockWaitsForRoom(t *testing.T) {
+	lc, drain := stalledLogChannel(t, event.Options{BufferSize: 1, Overflow: event.OverflowBlock})
+	sendAll(t, lc, "a")
+
+	sent := make(chan error)
+	go func() { sent <- lc.Send("b") }()
+	select {
+	case <-sent:
+		t.Fatalf("expected send to block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	lines := drain()
+	if err := <-sent; err != nil {
+		t.Errorf("unexpected send error %v", err)
+	}
+	if strings.Join(lines, ",") != "a,b" {
+		t.Errorf("expected a,b, got %v", lines)
+	}
+}
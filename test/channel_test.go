@@ -3,10 +3,12 @@ package test
 import (
 	"bufio"
 	"corebanking/internal/event"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLogChannel(t *testing.T) {
@@ -72,3 +74,63 @@ func TestLogChannel_BufferOverflow(t *testing.T) {
 		t.Errorf("log file not found: %v", err)
 	}
 }
+
+func TestLogChannel_SendAfterClose(t *testing.T) {
+	lc, err := event.NewLogChannel(filepath.Join(t.TempDir(), "closed.log"), 1)
+	if err != nil {
+		t.Fatalf("failed to create LogChannel: %v", err)
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatalf("failed to close LogChannel: %v", err)
+	}
+
+	if err := lc.Send("late"); !errors.Is(err, event.ErrLogChannelClosed) {
+		t.Errorf("expected ErrLogChannelClosed, got %v", err)
+	}
+	if err := lc.Close(); err != nil {
+		t.Errorf("expected second Close to be a no-op, got %v", err)
+	}
+	if stats := lc.Stats(); stats.Dropped != 1 {
+		t.Errorf("expected the late message to be counted as dropped, got %+v", stats)
+	}
+}
+
+func TestLogChannel_FlushIntervalAndWriteErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batched.log")
+	lc, err := event.NewLogChannelWithOptions(path, event.Options{BufferSize: 10, FlushInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("failed to create LogChannel: %v", err)
+	}
+	lc.Send("batched")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		data, _ := os.ReadFile(path)
+		if string(data) == "batched\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the interval flush to write the message, got %q", data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	lc.Close()
+
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full not available")
+	}
+	var reported []error
+	full, err := event.NewLogChannelWithOptions("/dev/full", event.Options{
+		BufferSize: 10,
+		OnError:    func(err error) { reported = append(reported, err) },
+	})
+	if err != nil {
+		t.Skipf("cannot open /dev/full: %v", err)
+	}
+	full.Send("lost")
+	full.Close()
+
+	if stats := full.Stats(); stats.WriteErrors != 1 || len(reported) != 1 {
+		t.Errorf("expected one reported write error, got %+v and %v", stats, reported)
+	}
+}
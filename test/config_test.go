@@ -0,0 +1,180 @@
+package test
+
+import (
+	"corebanking/config"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func envMap(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfig_Defaults(t *testing.T) {
+	cfg, err := config.NewLoaderWithEnv(nil, envMap(nil)).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "8080" || cfg.Version != "v1" || cfg.LogLevel != "DEBUG" || !cfg.AuthEnabled {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+	if cfg.ReadTimeout != 15*time.Second || cfg.TraceSampleRatio != 1 || cfg.LogBufferSize != 100 {
+		t.Errorf("unexpected typed defaults: %+v", cfg)
+	}
+}
+
+func TestConfig_LayerPrecedence(t *testing.T) {
+	file := writeConfig(t, "config.yaml", `
+# file layer
+server:
+  port: 9000
+  read_timeout: 20s
+log:
+  level: INFO
+  outputs: [stdout]
+api:
+  default_version: "v2"
+`)
+	env := envMap(map[string]string{"LOG_LEVEL": "WARN", "SERVER_READ_TIMEOUT": "25s"})
+	args := []string{"--config", file, "--log.level", "ERROR"}
+
+	cfg, err := config.NewLoaderWithEnv(args, env).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "9000" || cfg.Version != "v2" || cfg.LogOutputs != "stdout" {
+		t.Errorf("expected file values, got port %s version %s outputs %s", cfg.Port, cfg.Version, cfg.LogOutputs)
+	}
+	if cfg.ReadTimeout != 25*time.Second {
+		t.Errorf("expected env to override the file, got %s", cfg.ReadTimeout)
+	}
+	if cfg.LogLevel != "ERROR" {
+		t.Errorf("expected the flag to override env, got %s", cfg.LogLevel)
+	}
+}
+
+func TestConfig_JSONFileFromEnv(t *testing.T) {
+	file := writeConfig(t, "config.json", `{"server": {"port": 7000}, "tracing": {"sample_ratio": 0.25}, "auth": {"enabled": false}}`)
+
+	loader := config.NewLoaderWithEnv(nil, envMap(map[string]string{"CONFIG_FILE": file}))
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loader.File != file || cfg.Port != "7000" || cfg.TraceSampleRatio != 0.25 || cfg.AuthEnabled {
+		t.Errorf("unexpected config from %s: %+v", loader.File, cfg)
+	}
+}
+
+func TestConfig_Rejects(t *testing.T) {
+	cases := map[string]struct {
+		file string
+		args []string
+		want string
+	}{
+		"unknown key":      {file: "server:\n  prot: 80\n", want: `unknown setting "server.prot"`},
+		"bad indentation":  {file: "server:\n    port: 80\n  read_timeout: 1s\n", want: "inconsistent indentation"},
+		"block list":       {file: "log:\n  outputs:\n    - stdout\n", want: "block lists are not supported"},
+		"bad type":         {args: []string{"--server.max_header_bytes", "lots"}, want: "invalid integer"},
+		"unknown flag":     {args: []string{"--nope"}, want: "flag provided but not defined"},
+		"stray argument":   {args: []string{"serve"}, want: `unexpected argument "serve"`},
+		"malformed JSON":   {file: `{"server": `, want: "config file"},
+		"invalid duration": {args: []string{"--server.idle_timeout", "soon"}, want: "invalid duration"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			args := tc.args
+			if tc.file != "" {
+				ext := "config.yaml"
+				if strings.HasPrefix(tc.file, "{") {
+					ext = "config.json"
+				}
+				args = append([]string{"--config", writeConfig(t, ext, tc.file)}, args...)
+			}
+			_, err := config.NewLoaderWithEnv(args, envMap(nil)).Load()
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidationReportsEverySetting(t *testing.T) {
+	env := envMap(map[string]string{
+		"PORT":               "70000",
+		"LOG_LEVEL":          "LOUD",
+		"TRACE_SAMPLE_RATIO": "2",
+		"VERSION":            "v3",
+	})
+	_, err := config.NewLoaderWithEnv(nil, env).Load()
+	if err == nil {
+		t.Fatal("expected validation to fail")
+	}
+	for _, key := range []string{"server.port", "log.level", "tracing.sample_ratio", "api.default_version"} {
+		if !strings.Contains(err.Error(), key+":") {
+			t.Errorf("expected an error for %s, got %v", key, err)
+		}
+	}
+}
+
+func TestConfig_RedactsSecrets(t *testing.T) {
+	env := envMap(map[string]string{"JWT_HS256_SECRET": "s3cret", "RESET_CONFIRMATION_TOKEN": "tok"})
+	loader := config.NewLoaderWithEnv([]string{"--print-config"}, env)
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loader.PrintConfig {
+		t.Error("expected --print-config to be recorded")
+	}
+
+	redacted := cfg.Redacted()
+	if redacted["auth"]["jwt_hs256_secret"] != "[REDACTED]" || redacted["sandbox"]["reset_token"] != "[REDACTED]" {
+		t.Errorf("expected secrets to be redacted, got %v %v", redacted["auth"], redacted["sandbox"])
+	}
+	if redacted["tracing"]["otlp_headers"] != "" {
+		t.Errorf("expected unset secrets to stay empty, got %v", redacted["tracing"]["otlp_headers"])
+	}
+	if redacted["server"]["port"] != "8080" || redacted["server"]["read_timeout"] != "15s" {
+		t.Errorf("expected plain settings to be shown, got %v", redacted["server"])
+	}
+}
+
+func TestConfig_ReloadAppliesOnlySafeSettings(t *testing.T) {
+	current, err := config.NewLoaderWithEnv(nil, envMap(nil)).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := config.NewLoaderWithEnv(nil, envMap(map[string]string{"LOG_LEVEL": "WARN", "PORT": "9090"})).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, applied, ignored := config.Reload(current, next)
+	if merged.LogLevel != "WARN" || merged.Port != "8080" {
+		t.Errorf("expected only the log level to change, got level %s port %s", merged.LogLevel, merged.Port)
+	}
+	if !reflect.DeepEqual(applied, []string{"log.level"}) || !reflect.DeepEqual(ignored, []string{"server.port"}) {
+		t.Errorf("unexpected applied %v and ignored %v", applied, ignored)
+	}
+	if current.LogLevel != "DEBUG" {
+		t.Errorf("expected the current config to be left alone, got %s", current.LogLevel)
+	}
+}
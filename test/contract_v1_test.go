@@ -0,0 +1,80 @@
+package test
+
+import (
+	"corebanking/internal/dto"
+	"net/http"
+	"testing"
+)
+
+func TestContractV1_AccountAndEventFlow(t *testing.T) {
+	app := newTestApp()
+
+	resp := app.do(t, http.MethodPost, "/api/v1/accounts", dto.AccountRequest{DocumentNumber: "12345678900"}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+	var account dto.AccountResponse
+	decodeBody(t, resp, &account)
+	if account.AccountID == "" || account.DocumentNumber != "12345678900" {
+		t.Fatalf("unexpected account response %+v", account)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v1/transactions/event", dto.EventRequest{Type: "deposit", Destination: account.AccountID, Amount: 1500}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+	var event map[string]map[string]any
+	decodeBody(t, resp, &event)
+	if event["destination"]["balance"] != float64(1500) {
+		t.Errorf("expected numeric balance 1500, got %v", event["destination"]["balance"])
+	}
+
+	resp = app.do(t, http.MethodGet, "/api/v1/accounts/balance?account_id="+account.AccountID, nil, nil)
+	var balance dto.BalanceResponse
+	decodeBody(t, resp, &balance)
+	if balance.Balance != 1500 {
+		t.Errorf("expected balance 1500, got %d", balance.Balance)
+	}
+}
+
+func TestContractV1_ErrorsAreBadRequest(t *testing.T) {
+	app := newTestApp()
+
+	resp := app.do(t, http.MethodGet, "/api/v1/accounts/unknown", nil, nil)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, resp.Code)
+	}
+
+	var body map[string]string
+	decodeBody(t, resp, &body)
+	if body["error"] != "Failed to get account." || body["details"] != "account not found" {
+		t.Errorf("unexpected error body %v", body)
+	}
+}
+
+func TestContractV1_DeprecationHeaders(t *testing.T) {
+	app := newTestApp()
+
+	resp := app.do(t, http.MethodGet, "/api/v1/transactions/today", nil, nil)
+	if resp.Header().Get("Deprecation") != "true" {
+		t.Errorf("expected Deprecation header, got %q", resp.Header().Get("Deprecation"))
+	}
+	if resp.Header().Get("Sunset") != "Wed, 30 Jun 2027 00:00:00 GMT" {
+		t.Errorf("unexpected Sunset header %q", resp.Header().Get("Sunset"))
+	}
+	if resp.Header().Get("Link") != `</api/v2>; rel="successor-version"` {
+		t.Errorf("unexpected Link header %q", resp.Header().Get("Link"))
+	}
+}
+
+func TestContractV1_DefaultNegotiation(t *testing.T) {
+	app := newTestApp()
+
+	resp := app.do(t, http.MethodPost, "/api/accounts", dto.AccountRequest{DocumentNumber: "1"}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+	if resp.Header().Get("Deprecation") != "true" {
+		t.Errorf("expected unversioned request to be served by v1")
+	}
+}
@@ -0,0 +1,170 @@
+package test
+
+import (
+	"corebanking/internal/dto"
+	"net/http"
+	"testing"
+)
+
+type accountEnvelope struct {
+	Data  dto.AccountV2Response `json:"data"`
+	Error *dto.EnvelopeError    `json:"error"`
+	Meta  dto.EnvelopeMeta      `json:"meta"`
+}
+
+func createAccountV2(t *testing.T, app *testApp, document string) string {
+	t.Helper()
+
+	resp := app.do(t, http.MethodPost, "/api/v2/accounts", dto.AccountRequest{DocumentNumber: document}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+	var body accountEnvelope
+	decodeBody(t, resp, &body)
+	if body.Error != nil || body.Meta.APIVersion != "v2" {
+		t.Fatalf("unexpected envelope %+v", body)
+	}
+	return body.Data.AccountID
+}
+
+func TestContractV2_MoneyAsString(t *testing.T) {
+	app := newTestApp()
+	accountID := createAccountV2(t, app, "12345678900")
+
+	resp := app.do(t, http.MethodPost, "/api/v2/events", map[string]any{
+		"type": "deposit", "destination": accountID, "amount": "15.05",
+	}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+	var event struct {
+		Data map[string]map[string]any `json:"data"`
+	}
+	decodeBody(t, resp, &event)
+	if event.Data["destination"]["balance"] != "15.05" {
+		t.Errorf("expected balance \"15.05\", got %v", event.Data["destination"]["balance"])
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v2/events", map[string]any{
+		"type": "deposit", "destination": accountID, "amount": 1505,
+	}, nil)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("expected numeric amount to be rejected, got %d", resp.Code)
+	}
+}
+
+func TestContractV2_ParseMoney(t *testing.T) {
+	for _, tc := range []struct {
+		raw  string
+		want dto.Money
+		ok   bool
+	}{
+		{"15.05", 1505, true},
+		{"15.5", 1550, true},
+		{"15", 1500, true},
+		{" 0.01 ", 1, true},
+		{"-3.20", -320, true},
+		{"92233720368547758.07", 9223372036854775807, true},
+		{"92233720368547758.08", 0, false},
+		{"99999999999999999999", 0, false},
+		{"--5", 0, false},
+		{"-+5", 0, false},
+		{"+3", 0, false},
+		{"1.-5", 0, false},
+		{"1.+5", 0, false},
+		{"1.234", 0, false},
+		{"1.", 0, false},
+		{".50", 0, false},
+		{"-", 0, false},
+		{"", 0, false},
+		{"1e3", 0, false},
+		{"1_000", 0, false},
+	} {
+		got, err := dto.ParseMoney(tc.raw)
+		if tc.ok && (err != nil || got != tc.want) {
+			t.Errorf("ParseMoney(%q) = %d, %v; expected %d", tc.raw, got, err, tc.want)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("ParseMoney(%q) = %d; expected an error", tc.raw, got)
+		}
+	}
+}
+
+func TestContractV2_TransactionList(t *testing.T) {
+	app := newTestApp()
+	accountID := createAccountV2(t, app, "1")
+
+	resp := app.do(t, http.MethodPost, "/api/v2/transactions", map[string]any{
+		"accountId": accountID, "operationTypeId": 4, "amount": "10.00",
+	}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+
+	resp = app.do(t, http.MethodGet, "/api/v2/transactions?operationTypeId=4", nil, nil)
+	var list struct {
+		Data []dto.TransactionV2Response `json:"data"`
+		Meta dto.EnvelopeMeta            `json:"meta"`
+	}
+	decodeBody(t, resp, &list)
+	if list.Meta.Count == nil || *list.Meta.Count != 1 || list.Data[0].Amount != 1000 {
+		t.Errorf("unexpected transaction list %+v", list)
+	}
+}
+
+func TestContractV2_ErrorEnvelopes(t *testing.T) {
+	app := newTestApp()
+	accountID := createAccountV2(t, app, "1")
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+		code   string
+	}{
+		{"unknown account", http.MethodGet, "/api/v2/accounts/unknown", nil, http.StatusNotFound, "not_found"},
+		{"duplicate document", http.MethodPost, "/api/v2/accounts", dto.AccountRequest{DocumentNumber: "1"}, http.StatusConflict, "conflict"},
+		{"insufficient funds", http.MethodPost, "/api/v2/events", map[string]any{"type": "withdraw", "origin": accountID, "amount": "1.00"}, http.StatusUnprocessableEntity, "insufficient_funds"},
+		{"invalid operation type", http.MethodPost, "/api/v2/transactions", map[string]any{"accountId": accountID, "operationTypeId": 9, "amount": "1.00"}, http.StatusBadRequest, "invalid_request"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := app.do(t, tc.method, tc.path, tc.body, nil)
+			if resp.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, resp.Code)
+			}
+			var body accountEnvelope
+			decodeBody(t, resp, &body)
+			if body.Error == nil || body.Error.Code != tc.code {
+				t.Errorf("expected error code %s, got %+v", tc.code, body.Error)
+			}
+		})
+	}
+}
+
+func TestContractV2_AcceptHeaderNegotiation(t *testing.T) {
+	app := newTestApp()
+
+	resp := app.do(t, http.MethodPost, "/api/accounts", dto.AccountRequest{DocumentNumber: "1"}, map[string]string{
+		"Accept": "application/vnd.corebanking.v2+json",
+	})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+	if resp.Header().Get("Deprecation") != "" {
+		t.Errorf("v2 responses must not be deprecated")
+	}
+	var body accountEnvelope
+	decodeBody(t, resp, &body)
+	if body.Meta.APIVersion != "v2" {
+		t.Errorf("expected v2 envelope, got %+v", body)
+	}
+
+	resp = app.do(t, http.MethodGet, "/api/v9/accounts", nil, nil)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("expected unknown version to return %d, got %d", http.StatusNotFound, resp.Code)
+	}
+}
@@ -29,8 +29,10 @@ func TestHandleHTTPError(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	testErr := errors.New("some error")
 	testMsg := "Test message"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/1", nil)
+	req = req.WithContext(utils.WithRequestID(req.Context(), "req-1"))
 
-	utils.HandleHTTPError(recorder, testErr, testMsg, mockHandler)
+	utils.HandleHTTPError(recorder, req, testErr, testMsg, mockHandler)
 
 	if !mockHandler.Called {
 		t.Errorf("expected ErrorHandler to be called")
@@ -41,6 +43,9 @@ func TestHandleHTTPError(t *testing.T) {
 	if mockHandler.Message != testMsg {
 		t.Errorf("expected message %s, got %s", testMsg, mockHandler.Message)
 	}
+	if utils.RequestIDFrom(mockHandler.Ctx) != "req-1" {
+		t.Errorf("expected the request context to reach the error handler")
+	}
 
 	if recorder.Code != http.StatusBadRequest {
 		t.Errorf("expected status %d, got %d", http.StatusBadRequest, recorder.Code)
@@ -65,7 +70,7 @@ func TestHandleHTTPError_NoErr(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	testMsg := "Another test"
 
-	utils.HandleHTTPError(recorder, nil, testMsg, mockHandler)
+	utils.HandleHTTPError(recorder, httptest.NewRequest(http.MethodGet, "/", nil), nil, testMsg, mockHandler)
 
 	if !mockHandler.Called {
 		t.Errorf("expected ErrorHandler to be called")
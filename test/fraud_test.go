@@ -0,0 +1,280 @@
+package test
+
+import (
+	"context"
+	"corebanking/config"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/fraud"
+	"corebanking/internal/service"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fraudCaseEnvelope struct {
+	Data  dto.FraudCaseV2Response `json:"data"`
+	Error *dto.EnvelopeError      `json:"error"`
+}
+
+func testFraudRules() fraud.Rules {
+	return fraud.Rules{
+		Weights: map[string]int{
+			fraud.RuleAmountHistory:   40,
+			fraud.RuleNewDestinations: 50,
+			fraud.RuleRoundAmount:     20,
+			fraud.RuleNewAccountDrain: 60,
+		},
+		ReviewScore:          50,
+		DenyScore:            100,
+		HistoryMultiplier:    5,
+		HistoryMinimum:       3,
+		NewDestinationWindow: time.Hour,
+		NewDestinationCount:  3,
+		RoundUnit:            10000,
+		RoundMinimum:         100000,
+		NewAccountAge:        72 * time.Hour,
+		DrainRatio:           0.9,
+	}
+}
+
+func TestFraud_RulesScoreAndDecide(t *testing.T) {
+	engine := fraud.NewEngine(testFraudRules())
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	old := domain.FraudHistory{FirstSeen: now.AddDate(0, -1, 0)}
+	for i := 0; i < 3; i++ {
+		old.Add(domain.FraudActivity{At: now.Add(-time.Duration(3-i) * 24 * time.Hour), Kind: domain.ActivityPurchase, Amount: 1000}, 90*24*time.Hour)
+	}
+
+	tests := []struct {
+		name     string
+		in       fraud.Input
+		history  domain.FraudHistory
+		decision fraud.Decision
+		rules    []string
+	}{
+		{"ordinary purchase", fraud.Input{Kind: domain.ActivityPurchase, Amount: 1200, Balance: 50000}, old, fraud.Allow, nil},
+		{"round amount alone", fraud.Input{Kind: domain.ActivityWithdraw, Amount: 100000, Balance: 500000}, domain.FraudHistory{FirstSeen: now.AddDate(-1, 0, 0)}, fraud.Allow, []string{fraud.RuleRoundAmount}},
+		{"far above history", fraud.Input{Kind: domain.ActivityPurchase, Amount: 6000, Balance: 50000}, old, fraud.Allow, []string{fraud.RuleAmountHistory}},
+		{"far above history and round", fraud.Input{Kind: domain.ActivityPurchase, Amount: 100000, Balance: 500000}, old, fraud.Review, []string{fraud.RuleAmountHistory, fraud.RuleRoundAmount}},
+		{"new account drained", fraud.Input{Kind: domain.ActivityWithdraw, Amount: 9500, Balance: 10000}, domain.FraudHistory{FirstSeen: now.Add(-time.Hour)}, fraud.Review, []string{fraud.RuleNewAccountDrain}},
+		{"old account drained", fraud.Input{Kind: domain.ActivityWithdraw, Amount: 9500, Balance: 10000}, domain.FraudHistory{FirstSeen: now.Add(-96 * time.Hour)}, fraud.Allow, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.in.At = now
+			verdict := engine.Evaluate(tc.in, tc.history)
+			if verdict.Decision != tc.decision || len(verdict.Reasons) != len(tc.rules) {
+				t.Fatalf("expected %s with %v, got %+v", tc.decision, tc.rules, verdict)
+			}
+			for i, rule := range tc.rules {
+				if !strings.HasPrefix(verdict.Reasons[i], rule+": ") {
+					t.Errorf("expected reason %d to come from %s, got %q", i, rule, verdict.Reasons[i])
+				}
+			}
+		})
+	}
+
+	transfers := domain.FraudHistory{FirstSeen: now.AddDate(-1, 0, 0)}
+	for i, destination := range []string{"acc-1", "acc-2", "acc-3"} {
+		transfers.Add(domain.FraudActivity{At: now.Add(-time.Duration(90-i*30) * time.Minute), Kind: domain.ActivityTransfer, Amount: 500, Destination: destination}, 90*24*time.Hour)
+	}
+	newDestination := fraud.Input{Kind: domain.ActivityTransfer, Amount: 500, Destination: "acc-4", Balance: 50000, At: now}
+	if verdict := engine.Evaluate(newDestination, transfers); verdict.Decision != fraud.Review || verdict.Score != 50 {
+		t.Errorf("expected the third new destination within the hour to be held, got %+v", verdict)
+	}
+	known := newDestination
+	known.Destination = "acc-1"
+	if verdict := engine.Evaluate(known, transfers); verdict.Score != 0 {
+		t.Errorf("expected a known destination to pass, got %+v", verdict)
+	}
+
+	drainToNew := fraud.Input{Kind: domain.ActivityTransfer, Amount: 100000, Destination: "acc-4", Balance: 100000, At: now}
+	transfers.FirstSeen = now.Add(-time.Hour)
+	if verdict := engine.Evaluate(drainToNew, transfers); verdict.Decision != fraud.Deny || verdict.Score != 170 {
+		t.Errorf("expected a deny, got %+v", verdict)
+	}
+	if verdict := fraud.NewEngine(fraud.Rules{}).Evaluate(drainToNew, transfers); verdict.Decision != fraud.Allow || verdict.Score != 0 {
+		t.Errorf("expected zero rules to screen nothing, got %+v", verdict)
+	}
+}
+
+func TestFraud_CaseQueue(t *testing.T) {
+	app := newTestApp()
+	app.fraudService.SetRules(testFraudRules())
+	accountID := createAccountV2(t, app, "1")
+	deposit := app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "deposit", "destination": accountID, "amount": "1000.00"}, nil)
+	if deposit.Code != http.StatusCreated {
+		t.Fatalf("expected the deposit to pass, got %d", deposit.Code)
+	}
+
+	held := app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "withdraw", "origin": accountID, "amount": "950.00"}, nil)
+	var body fraudCaseEnvelope
+	decodeBody(t, held, &body)
+	if held.Code != http.StatusAccepted || body.Error == nil || body.Error.Code != "under_review" {
+		t.Fatalf("expected a draining withdrawal to be held, got %d %+v", held.Code, body.Error)
+	}
+
+	var list struct {
+		Data []dto.FraudCaseV2Response `json:"data"`
+	}
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/fraud/cases", nil, nil), &list)
+	if len(list.Data) != 1 || list.Data[0].AccountID != accountID || list.Data[0].Amount != 95000 || list.Data[0].Status != domain.FraudCasePending {
+		t.Fatalf("expected one pending case, got %+v", list.Data)
+	}
+	pending := list.Data[0]
+	if !strings.Contains(body.Error.Details, pending.ID) || !strings.HasPrefix(pending.Reasons[0], fraud.RuleNewAccountDrain) {
+		t.Errorf("expected the error to name case %s for a drain, got %q %v", pending.ID, body.Error.Details, pending.Reasons)
+	}
+	balance, _ := app.accountService.GetBalance(context.Background(), accountID)
+	if balance.Balance != 100000 {
+		t.Fatalf("expected a held debit to leave the balance alone, got %d", balance.Balance)
+	}
+
+	// Funds spent after the hold make the approval fail, and the case waits.
+	if err := withdraw(app, accountID, 10000); err != nil {
+		t.Fatal(err)
+	}
+	resp := app.do(t, http.MethodPost, "/api/v2/fraud/cases/"+pending.ID+"/approve", map[string]any{}, nil)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected the approval to fail on funds, got %d", resp.Code)
+	}
+	if fraudCase, _ := app.fraudService.Case(context.Background(), pending.ID); fraudCase.Status != domain.FraudCasePending {
+		t.Fatalf("expected the case to stay pending, got %s", fraudCase.Status)
+	}
+
+	if _, err := app.transactionService.HandleTransaction(context.Background(), &dto.EventRequest{Type: "deposit", Destination: accountID, Amount: 10000}); err != nil {
+		t.Fatal(err)
+	}
+	resp = app.do(t, http.MethodPost, "/api/v2/fraud/cases/"+pending.ID+"/approve", map[string]any{"note": "customer confirmed"}, map[string]string{
+		"X-Test-Subject": "analyst", "X-Test-Roles": "admin",
+	})
+	body = fraudCaseEnvelope{}
+	decodeBody(t, resp, &body)
+	if resp.Code != http.StatusOK || body.Data.Status != domain.FraudCaseApproved || body.Data.DecidedBy != "analyst" || body.Data.Note != "customer confirmed" {
+		t.Fatalf("unexpected approval %d %+v", resp.Code, body)
+	}
+	if balance, _ := app.accountService.GetBalance(context.Background(), accountID); balance.Balance != 5000 {
+		t.Errorf("expected the approved debit to be posted, got %d", balance.Balance)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/v2/fraud/cases/"+pending.ID+"/reject", map[string]any{}, nil); resp.Code != http.StatusConflict {
+		t.Errorf("expected a decided case to stay decided, got %d", resp.Code)
+	}
+
+	other := fundedAccount(t, app, "2")
+	if err := purchase(app, other, 95000); !errors.Is(err, service.ErrFraudReview) {
+		t.Fatalf("expected the purchase to be held, got %v", err)
+	}
+	cases := app.fraudService.Cases(context.Background(), domain.FraudCasePending)
+	if len(cases) != 1 || cases[0].OperationTypeID != 1 {
+		t.Fatalf("expected a pending transaction case, got %+v", cases)
+	}
+	resp = app.do(t, http.MethodPost, "/api/v2/fraud/cases/"+cases[0].ID+"/reject", map[string]any{}, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected the rejection to pass, got %d", resp.Code)
+	}
+	if balance, _ := app.accountService.GetBalance(context.Background(), other); balance.Balance != 100000 {
+		t.Errorf("expected a rejected debit not to be posted, got %d", balance.Balance)
+	}
+
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/fraud/cases?status=all", nil, nil), &list)
+	if len(list.Data) != 2 {
+		t.Errorf("expected both cases, got %d", len(list.Data))
+	}
+	if resp := app.do(t, http.MethodGet, "/api/v2/fraud/cases?status=open", nil, nil); resp.Code != http.StatusBadRequest {
+		t.Errorf("expected an unknown status to be rejected, got %d", resp.Code)
+	}
+	entries := app.auditService.Query(context.Background(), "", "fraud_case", pending.ID, time.Time{}, time.Time{})
+	actions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	if !slices.Equal(actions, []string{service.AuditFraudReview, service.AuditFraudDecide}) {
+		t.Errorf("expected the hold and decision to be audited, got %v", actions)
+	}
+}
+
+func TestFraud_V1HoldIsAcceptedWithTheCase(t *testing.T) {
+	app := newTestApp()
+	app.fraudService.SetRules(testFraudRules())
+	accountID := fundedAccount(t, app, "1")
+
+	for _, request := range []struct {
+		path string
+		body map[string]any
+	}{
+		{"/api/v1/transactions/event", map[string]any{"type": "withdraw", "origin": accountID, "amount": 95000}},
+		{"/api/v1/transactions", map[string]any{"accountId": accountID, "operationTypeId": 1, "amount": 95000}},
+	} {
+		resp := app.do(t, http.MethodPost, request.path, request.body, nil)
+		var held dto.HoldResponse
+		decodeBody(t, resp, &held)
+		if resp.Code != http.StatusAccepted || held.Status != "under_review" {
+			t.Fatalf("expected %s to be held for review, got %d %+v", request.path, resp.Code, held)
+		}
+		if fraudCase, err := app.fraudService.Case(context.Background(), held.HoldID); err != nil || fraudCase.Status != domain.FraudCasePending {
+			t.Errorf("expected the response to name the pending case, got %+v %v", fraudCase, err)
+		}
+	}
+	if cases := app.fraudService.Cases(context.Background(), domain.FraudCasePending); len(cases) != 2 {
+		t.Errorf("expected a case per held request, got %d", len(cases))
+	}
+	if got := balance(t, app, accountID); got != 100000 {
+		t.Errorf("expected held debits to leave the balance alone, got %d", got)
+	}
+}
+
+func TestFraud_DenyConfigAndReload(t *testing.T) {
+	env := envMap(map[string]string{"FRAUD_WEIGHTS": "velocity=10", "FRAUD_REVIEW_SCORE": "80", "FRAUD_DENY_SCORE": "60"})
+	_, err := config.NewLoaderWithEnv(nil, env).Load()
+	if err == nil || !strings.Contains(err.Error(), "fraud.weights:") || !strings.Contains(err.Error(), "fraud.deny_score:") {
+		t.Fatalf("expected both fraud settings to be reported, got %v", err)
+	}
+
+	current, err := config.NewLoaderWithEnv(nil, envMap(nil)).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp()
+	app.fraudService.SetRules(current.FraudRules())
+	accountID := fundedAccount(t, app, "1")
+	var destinations []string
+	for _, document := range []string{"2", "3", "4", "5"} {
+		destinations = append(destinations, app.createAccount(t, document))
+	}
+	for _, destination := range destinations[:2] {
+		if err := transfer(app, accountID, destination, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp := app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "transfer", "origin": accountID, "destination": destinations[2], "amount": "900.00"}, nil)
+	var body fraudCaseEnvelope
+	decodeBody(t, resp, &body)
+	if resp.Code != http.StatusUnprocessableEntity || body.Error == nil || body.Error.Code != "fraud_declined" {
+		t.Fatalf("expected a drain to a third new destination to be declined, got %d %+v", resp.Code, body.Error)
+	}
+	if !strings.Contains(body.Error.Details, fraud.RuleNewDestinations) || !strings.Contains(body.Error.Details, fraud.RuleNewAccountDrain) {
+		t.Errorf("expected the reasons in the details, got %q", body.Error.Details)
+	}
+
+	next, _ := config.NewLoaderWithEnv(nil, envMap(map[string]string{"FRAUD_DENY_SCORE": "0"})).Load()
+	merged, applied, _ := config.Reload(current, next)
+	if !slices.Contains(applied, "fraud.deny_score") {
+		t.Fatalf("expected fraud rules to reload, applied %v", applied)
+	}
+	app.fraudService.SetRules(merged.FraudRules())
+	if err := transfer(app, accountID, destinations[2], 90000); !errors.Is(err, service.ErrFraudReview) {
+		t.Errorf("expected a hold once denying is off, got %v", err)
+	}
+
+	disabled := *merged
+	disabled.FraudEnabled = false
+	app.fraudService.SetRules(disabled.FraudRules())
+	if err := transfer(app, accountID, destinations[3], 90000); err != nil {
+		t.Errorf("expected disabled screening to pass every debit, got %v", err)
+	}
+}
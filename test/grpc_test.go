@@ -0,0 +1,357 @@
+package test
+
+import (
+	"context"
+	corebankingv1 "corebanking/api/gen/corebankingv1"
+	"corebanking/internal/auth"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/ratelimit"
+	"corebanking/internal/repository"
+	"corebanking/internal/rpc"
+	"corebanking/internal/service"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// serveGRPC serves the app's services over an in-memory connection until
+// the returned stop is called or the test ends.
+func serveGRPC(t *testing.T, app *testApp, authenticate rpc.Authenticate, limiter *ratelimit.Limiter) (*grpc.ClientConn, func()) {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := rpc.NewServer(app.accountService, app.transactionService, auth.NewPolicy(app.accountService), authenticate, limiter, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(ctx, listener, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	}()
+	stop := func() {
+		cancel()
+		if err := <-served; err != nil {
+			t.Errorf("expected the server to stop cleanly, got %v", err)
+		}
+	}
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		if ctx.Err() == nil {
+			stop()
+		}
+	})
+	return conn, stop
+}
+
+// errorReason is the ErrorInfo reason of a status error.
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func expectStatus(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	if status.Code(err) != code || errorReason(err) != reason {
+		t.Errorf("expected %s %s, got %v (reason %q)", code, reason, err, errorReason(err))
+	}
+}
+
+func fundedGRPCAccount(t *testing.T, app *testApp, document string) string {
+	t.Helper()
+
+	account, err := app.accountService.CreateAccount(context.Background(), document, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.transactionService.CreateTransaction(context.Background(), &dto.TransactionRequest{AccountID: account.AccountID, OperationTypeID: 4, Amount: 100000}); err != nil {
+		t.Fatal(err)
+	}
+	return account.AccountID
+}
+
+func purchaseOf(app *testApp, accountID string, amount int64) error {
+	_, err := app.transactionService.CreateTransaction(context.Background(), &dto.TransactionRequest{AccountID: accountID, OperationTypeID: 1, Amount: amount})
+	return err
+}
+
+func TestGRPC_ServesTheServices(t *testing.T) {
+	app := newTestApp()
+	conn, _ := serveGRPC(t, app, rpc.Anonymous(&auth.Principal{ID: "test", Subject: "test", Roles: auth.Roles()}), nil)
+	accounts := corebankingv1.NewAccountServiceClient(conn)
+	transactions := corebankingv1.NewTransactionServiceClient(conn)
+	events := corebankingv1.NewEventServiceClient(conn)
+	ctx := context.Background()
+
+	account, err := accounts.CreateAccount(ctx, &corebankingv1.CreateAccountRequest{DocumentNumber: "1"})
+	if err != nil || account.GetAccountId() == "" || account.GetDocumentNumber() != "1" {
+		t.Fatalf("expected the account created, got %+v %v", account, err)
+	}
+	id := account.GetAccountId()
+	deposit, err := events.Deposit(ctx, &corebankingv1.DepositRequest{Destination: id, Amount: 5000})
+	if err != nil || deposit.GetDestination().GetBalance() != 5000 || deposit.GetOrigin() != nil {
+		t.Fatalf("expected the deposit posted, got %+v %v", deposit, err)
+	}
+	purchase, err := transactions.CreateTransaction(ctx, &corebankingv1.CreateTransactionRequest{AccountId: id, OperationTypeId: 1, Amount: 2000})
+	if err != nil || purchase.GetAmount() != -2000 {
+		t.Fatalf("expected the purchase posted, got %+v %v", purchase, err)
+	}
+	if read, err := transactions.GetTransaction(ctx, &corebankingv1.GetTransactionRequest{TransactionId: purchase.GetTransactionId()}); err != nil || read.GetAccountId() != id {
+		t.Errorf("expected the purchase read back, got %+v %v", read, err)
+	}
+	if got, err := accounts.GetBalance(ctx, &corebankingv1.GetBalanceRequest{AccountId: id}); err != nil || got.GetBalance() != 3000 {
+		t.Errorf("expected a balance of 3000, got %+v %v", got, err)
+	}
+
+	// Errors carry the HTTP API's error code as their reason.
+	_, err = events.Withdraw(ctx, &corebankingv1.WithdrawRequest{Origin: id, Amount: 100000})
+	expectStatus(t, err, codes.FailedPrecondition, "insufficient_funds")
+	_, err = accounts.CreateAccount(ctx, &corebankingv1.CreateAccountRequest{DocumentNumber: "1"})
+	expectStatus(t, err, codes.AlreadyExists, "conflict")
+	_, err = accounts.GetAccount(ctx, &corebankingv1.GetAccountRequest{AccountId: "unknown"})
+	expectStatus(t, err, codes.NotFound, "not_found")
+	_, err = transactions.CreateTransaction(ctx, &corebankingv1.CreateTransactionRequest{AccountId: id, OperationTypeId: 9, Amount: 1})
+	expectStatus(t, err, codes.InvalidArgument, "invalid_request")
+	_, err = transactions.ListTransactionsByType(ctx, &corebankingv1.ListTransactionsByTypeRequest{OperationTypeId: 9})
+	expectStatus(t, err, codes.InvalidArgument, "invalid_request")
+	_, err = transactions.ListTransactionsInRange(ctx, &corebankingv1.ListTransactionsInRangeRequest{})
+	expectStatus(t, err, codes.InvalidArgument, "invalid_request")
+}
+
+func TestGRPC_AuthenticatesAuthorizesAndRateLimits(t *testing.T) {
+	app := newTestApp()
+	a := fundedGRPCAccount(t, app, "1")
+	keys := service.NewAPIKeyService(repository.NewAPIKeyRepository(), app.auditService)
+	teller, _, _ := keys.Issue(context.Background(), "teller", []string{auth.RoleTeller}, "")
+	customer, _, _ := keys.Issue(context.Background(), "2", []string{auth.RoleCustomer}, "")
+	limiter := ratelimit.NewLimiter(ratelimit.Rules{Money: ratelimit.Limits{Client: mustLimit(t, "1/m")}})
+	conn, _ := serveGRPC(t, app, rpc.Authenticator(auth.NewAuthenticator(keys, auth.NewJWTVerifier("", ""))), limiter)
+	accounts := corebankingv1.NewAccountServiceClient(conn)
+	transactions := corebankingv1.NewTransactionServiceClient(conn)
+	events := corebankingv1.NewEventServiceClient(conn)
+	as := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+	}
+
+	_, err := accounts.GetAccount(context.Background(), &corebankingv1.GetAccountRequest{AccountId: a})
+	expectStatus(t, err, codes.Unauthenticated, "unauthorized")
+	_, err = accounts.GetAccount(as("not-a-key"), &corebankingv1.GetAccountRequest{AccountId: a})
+	expectStatus(t, err, codes.Unauthenticated, "unauthorized")
+	_, err = accounts.GetAccount(as(customer), &corebankingv1.GetAccountRequest{AccountId: a})
+	expectStatus(t, err, codes.PermissionDenied, "forbidden")
+	if _, err := accounts.GetAccount(as(teller), &corebankingv1.GetAccountRequest{AccountId: a}); err != nil {
+		t.Errorf("expected the teller to read the account, got %v", err)
+	}
+
+	// Another account's transaction reads as missing, not forbidden, so
+	// its ID can't be told from one never used.
+	posted := app.transactionService.GetAllTransactions(context.Background())[0].TransactionID
+	_, forbidden := transactions.GetTransaction(as(customer), &corebankingv1.GetTransactionRequest{TransactionId: posted})
+	expectStatus(t, forbidden, codes.NotFound, "not_found")
+	_, missing := transactions.GetTransaction(as(customer), &corebankingv1.GetTransactionRequest{TransactionId: posted + 1000})
+	expectStatus(t, missing, codes.NotFound, "not_found")
+	if status.Convert(forbidden).Message() != status.Convert(missing).Message() {
+		t.Errorf("expected the same answer for both, got %q and %q", status.Convert(forbidden).Message(), status.Convert(missing).Message())
+	}
+	if _, err := transactions.GetTransaction(as(teller), &corebankingv1.GetTransactionRequest{TransactionId: posted}); err != nil {
+		t.Errorf("expected the teller to read the transaction, got %v", err)
+	}
+
+	// Streams are authenticated and authorized before they start.
+	stream, err := transactions.WatchTransactions(context.Background(), &corebankingv1.WatchTransactionsRequest{AccountId: a})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	expectStatus(t, err, codes.Unauthenticated, "unauthorized")
+	stream, err = transactions.WatchTransactions(as(customer), &corebankingv1.WatchTransactionsRequest{AccountId: a})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	expectStatus(t, err, codes.PermissionDenied, "forbidden")
+
+	// Events draw from the money bucket, reads don't.
+	if _, err := events.Withdraw(as(teller), &corebankingv1.WithdrawRequest{Origin: a, Amount: 100}); err != nil {
+		t.Fatalf("expected the first withdrawal posted, got %v", err)
+	}
+	_, err = events.Withdraw(as(teller), &corebankingv1.WithdrawRequest{Origin: a, Amount: 100})
+	expectStatus(t, err, codes.ResourceExhausted, "rate_limited")
+	var retry *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() <= 0 {
+		t.Errorf("expected when to retry, got %v", retry)
+	}
+	if _, err := accounts.GetBalance(as(teller), &corebankingv1.GetBalanceRequest{AccountId: a}); err != nil {
+		t.Errorf("expected reads left alone, got %v", err)
+	}
+}
+
+func TestGRPC_FailedAuthenticationIsLimitedByIP(t *testing.T) {
+	app := newTestApp()
+	keys := service.NewAPIKeyService(repository.NewAPIKeyRepository(), app.auditService)
+	limiter := ratelimit.NewLimiter(ratelimit.Rules{Standard: ratelimit.Limits{IP: mustLimit(t, "3/m")}})
+	conn, _ := serveGRPC(t, app, rpc.Authenticator(auth.NewAuthenticator(keys, auth.NewJWTVerifier("", ""))), limiter)
+	accounts := corebankingv1.NewAccountServiceClient(conn)
+	guess := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "cbk_guess.secret")
+
+	for range 3 {
+		_, err := accounts.GetAccount(guess, &corebankingv1.GetAccountRequest{AccountId: "acc-1"})
+		expectStatus(t, err, codes.Unauthenticated, "unauthorized")
+	}
+	_, err := accounts.GetAccount(guess, &corebankingv1.GetAccountRequest{AccountId: "acc-1"})
+	expectStatus(t, err, codes.ResourceExhausted, "rate_limited")
+}
+
+func TestGRPC_WatchTransactions(t *testing.T) {
+	app := newTestApp()
+	a := fundedGRPCAccount(t, app, "1")
+	c := app.createAccount(t, "2")
+	conn, stop := serveGRPC(t, app, rpc.Anonymous(&auth.Principal{ID: "test", Subject: "test", Roles: auth.Roles()}), nil)
+	transactions := corebankingv1.NewTransactionServiceClient(conn)
+
+	unknown, err := transactions.WatchTransactions(context.Background(), &corebankingv1.WatchTransactionsRequest{AccountId: "unknown"})
+	if err == nil {
+		_, err = unknown.Recv()
+	}
+	expectStatus(t, err, codes.NotFound, "not_found")
+
+	stream, err := transactions.WatchTransactions(context.Background(), &corebankingv1.WatchTransactionsRequest{AccountId: a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("expected the watch to start, got %v", err)
+	}
+
+	if err := purchaseOf(app, a, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := stream.Recv(); err != nil || got.GetAccountId() != a || got.GetAmount() != -1000 {
+		t.Fatalf("expected the purchase streamed, got %+v %v", got, err)
+	}
+
+	// The batch's purchase and deposit are rolled back when the withdrawal
+	// from the empty account fails, so neither is streamed.
+	batch, err := app.batchService.Submit(context.Background(), domain.BatchAllOrNothing, []domain.BatchItem{
+		{Type: domain.BatchItemTransaction, AccountID: a, OperationTypeID: 1, Amount: 500},
+		{Type: domain.BatchItemDeposit, Destination: a, Amount: 300},
+		{Type: domain.BatchItemWithdraw, Origin: c, Amount: 100},
+	}, false)
+	if err != nil || batch.Status != domain.BatchFailed {
+		t.Fatalf("expected the batch rolled back, got %+v %v", batch, err)
+	}
+	if err := purchaseOf(app, a, 700); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := stream.Recv(); err != nil || got.GetAmount() != -700 {
+		t.Fatalf("expected the next purchase streamed and not the rolled back one, got %+v %v", got, err)
+	}
+
+	stop()
+	_, err = stream.Recv()
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("expected the watch ended as unavailable on shutdown, got %v", err)
+	}
+}
+
+func TestGRPC_WatchStreamsEveryPosting(t *testing.T) {
+	app := newTestApp()
+	app.suspenseService.SetPolicy(testSuspensePolicy)
+	a := fundedGRPCAccount(t, app, "1")
+	c := app.createAccount(t, "2")
+	conn, _ := serveGRPC(t, app, rpc.Anonymous(&auth.Principal{ID: "test", Subject: "test", Roles: auth.Roles()}), nil)
+	transactions := corebankingv1.NewTransactionServiceClient(conn)
+	ctx := context.Background()
+
+	stream, err := transactions.WatchTransactions(ctx, &corebankingv1.WatchTransactionsRequest{AccountId: a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("expected the watch to start, got %v", err)
+	}
+
+	for _, event := range []dto.EventRequest{
+		dto.NewEventRequest("deposit", "", a, 300),
+		dto.NewEventRequest("withdraw", a, "", 200),
+		dto.NewEventRequest("transfer", a, c, 100),
+		dto.NewEventRequest("transfer", c, "unknown", 50),
+	} {
+		if _, err := app.transactionService.HandleTransaction(ctx, &event); err != nil && !errors.Is(err, service.ErrHeldInSuspense) {
+			t.Fatal(err)
+		}
+	}
+	items := app.suspenseService.Items(ctx, domain.SuspenseHeld, "")
+	if len(items) != 1 {
+		t.Fatalf("expected one held transfer, got %d", len(items))
+	}
+	if _, err := app.suspenseService.Claim(ctx, items[0].ID, a, "meant for a"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []int64{300, -200, -100, 50} {
+		got, err := stream.Recv()
+		if err != nil || got.GetAccountId() != a || got.GetAmount() != want {
+			t.Fatalf("expected a posting of %d streamed, got %+v %v", want, got, err)
+		}
+		if got.GetTransactionId() != 0 || got.GetOperationTypeId() != 0 {
+			t.Errorf("expected no transaction for an event posting, got %+v", got)
+		}
+	}
+}
+
+func TestGRPC_WatchThatFallsBehindIsDropped(t *testing.T) {
+	app := newTestApp()
+	a := fundedGRPCAccount(t, app, "1")
+	watch, err := app.transactionService.WatchTransactions(context.Background(), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watch.Close()
+
+	const posted = 100
+	for i := 0; i < posted; i++ {
+		if err := purchaseOf(app, a, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// What was buffered is handed out before the watch ends, and posting
+	// never waited for it.
+	received := 0
+	for {
+		_, err := watch.Next(context.Background())
+		if errors.Is(err, service.ErrWatchLagged) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		received++
+	}
+	if received == 0 || received >= posted {
+		t.Errorf("expected part of the %d transactions before the watch lagged, got %d", posted, received)
+	}
+}
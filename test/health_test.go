@@ -0,0 +1,134 @@
+package test
+
+import (
+	"context"
+	"corebanking/internal/controller"
+	"corebanking/internal/dto"
+	"corebanking/internal/event"
+	"corebanking/internal/health"
+	"corebanking/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func healthHandler(checker *health.Checker) http.Handler {
+	mux := http.NewServeMux()
+	controller.NewHealthController(checker, dto.VersionResponse{
+		App: "coreBanking", APIVersion: "v1", APIVersions: []string{"v1", "v2"}, Commit: "abc123", GoVersion: "go1.23",
+	}).RegisterRoutes(mux, "")
+	return mux
+}
+
+func probe(t *testing.T, handler http.Handler, path string, out any) int {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+		t.Fatalf("failed to decode %s: %v", path, err)
+	}
+	return recorder.Code
+}
+
+func TestHealth_LiveAndVersion(t *testing.T) {
+	checker := health.NewChecker()
+	checker.Add("broken", func(ctx context.Context) error { return errors.New("down") })
+	handler := healthHandler(checker)
+
+	var live dto.HealthResponse
+	if code := probe(t, handler, "/healthz", &live); code != http.StatusOK || live.Status != "ok" {
+		t.Errorf("expected liveness to ignore failing checks, got %d %+v", code, live)
+	}
+
+	var version dto.VersionResponse
+	if code := probe(t, handler, "/version", &version); code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+	}
+	if version.APIVersion != "v1" || version.Commit != "abc123" || len(version.APIVersions) != 2 {
+		t.Errorf("unexpected version %+v", version)
+	}
+}
+
+func TestHealth_ReadinessChecksAndDraining(t *testing.T) {
+	var failing error
+	checker := health.NewChecker()
+	checker.Add("storage", func(ctx context.Context) error { return failing })
+	handler := healthHandler(checker)
+
+	var ready dto.ReadinessResponse
+	if code := probe(t, handler, "/readyz", &ready); code != http.StatusOK || ready.Status != "ready" {
+		t.Fatalf("expected ready, got %d %+v", code, ready)
+	}
+	if len(ready.Checks) != 2 || ready.Checks[0].Name != "draining" || ready.Checks[1].Name != "storage" {
+		t.Errorf("unexpected checks %+v", ready.Checks)
+	}
+
+	failing = errors.New("disk gone")
+	ready = dto.ReadinessResponse{}
+	if code := probe(t, handler, "/readyz", &ready); code != http.StatusServiceUnavailable || ready.Status != "not_ready" {
+		t.Fatalf("expected not ready, got %d %+v", code, ready)
+	}
+	if ready.Checks[1].Status != "failing" || ready.Checks[1].Error != "disk gone" {
+		t.Errorf("unexpected storage check %+v", ready.Checks[1])
+	}
+
+	failing = nil
+	checker.SetDraining()
+	ready = dto.ReadinessResponse{}
+	if code := probe(t, handler, "/readyz", &ready); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected draining to fail readiness, got %d", code)
+	}
+	if ready.Checks[0].Status != "failing" || ready.Checks[0].Error != health.ErrDraining.Error() {
+		t.Errorf("unexpected draining check %+v", ready.Checks[0])
+	}
+}
+
+func TestHealth_StorageAndLogWriterErrors(t *testing.T) {
+	dir := t.TempDir()
+	auditRepo, err := repository.NewFileAuditRepository(filepath.Join(dir, "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := auditRepo.Err(); err != nil {
+		t.Errorf("expected open audit repository to be healthy, got %v", err)
+	}
+	auditRepo.Close()
+	if err := auditRepo.Err(); err == nil {
+		t.Errorf("expected closed audit repository to report an error")
+	}
+
+	lc, err := event.NewLogChannel(filepath.Join(dir, "app.log"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Err(); err != nil {
+		t.Errorf("expected healthy log channel, got %v", err)
+	}
+	lc.Close()
+	if err := lc.Err(); !errors.Is(err, event.ErrLogChannelClosed) {
+		t.Errorf("expected closed log channel error, got %v", err)
+	}
+
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full not available")
+	}
+	full, err := event.NewLogChannel("/dev/full", 10)
+	if err != nil {
+		t.Skipf("cannot open /dev/full: %v", err)
+	}
+	defer full.Close()
+	full.Send("lost")
+	deadline := time.Now().Add(time.Second)
+	for full.Err() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a failed write to make the log channel unhealthy")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,173 @@
+package test
+
+import (
+	"bytes"
+	"corebanking/internal/aml"
+	"corebanking/internal/auth"
+	"corebanking/internal/controller"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/fraud"
+	"corebanking/internal/logging"
+	"corebanking/internal/middleware"
+	"corebanking/internal/repository"
+	"corebanking/internal/sanctions"
+	"corebanking/internal/service"
+	"corebanking/internal/worker"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testResetToken confirms resets in the sandboxed test app.
+const testResetToken = "test-reset-token"
+
+type testApp struct {
+	accountRepo        *repository.AccountRepository
+	accountService     *service.AccountService
+	transactionService *service.TransactionService
+	auditService       *service.AuditService
+	// limitService starts with no limits; clock is its time.
+	limitService *service.LimitService
+	fraudService *service.FraudService
+	// amlService starts with every rule off.
+	amlService *service.AMLService
+	// sanctionsService starts off, with an empty list.
+	sanctionsService *service.SanctionsService
+	// suspenseService rejects unknown destinations, like the default
+	// config.
+	suspenseService *service.SuspenseService
+	// scheduleService keeps schedules in memory and retries twice, an hour
+	// apart.
+	scheduleService *service.ScheduleService
+	// batchService processes batches above five items in the background,
+	// once runBatches starts it.
+	batchService *service.BatchService
+	clock        *fakeClock
+	handler      http.Handler
+	// logs holds the JSON log lines of the access log and error worker.
+	logs *bytes.Buffer
+}
+
+func newTestApp() *testApp {
+	accountRepo := repository.NewAccountRepository()
+	transactionRepo := repository.NewTransactionRepository()
+	auditService := service.NewAuditService(repository.NewAuditRepository())
+	accountService := service.NewAccountService(accountRepo, auditService)
+	transactionService := service.NewTransactionService(transactionRepo, accountRepo, auditService)
+	clock := &fakeClock{now: time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)}
+	limitService := service.NewLimitServiceWithClock(repository.NewLimitUsageRepository(), accountRepo, auditService, service.LimitPolicy{}, clock.Now)
+	transactionService.SetLimitService(limitService)
+	resetService := service.NewResetService(accountService, transactionRepo, auditService, true, testResetToken)
+	fraudService := service.NewFraudServiceWithClock(fraud.NewEngine(fraud.Rules{}), repository.NewFraudHistoryRepository(), repository.NewFraudCaseRepository(), auditService, clock.Now)
+	transactionService.SetFraudService(fraudService)
+	resetService.SetLimitService(limitService)
+	resetService.SetFraudService(fraudService)
+	amlService := service.NewAMLServiceWithClock(aml.NewMonitor(aml.Rules{}), repository.NewAMLHistoryRepository(), repository.NewAMLAlertRepository(), accountService, auditService, clock.Now)
+	transactionService.SetAMLService(amlService)
+	resetService.SetAMLService(amlService)
+	sanctionsService := service.NewSanctionsServiceWithClock(sanctions.NewScreener(sanctions.EmptyList(), sanctions.Policy{}), repository.NewSanctionsScreeningRepository(), repository.NewSanctionsCaseRepository(), repository.NewSanctionsClearanceRepository(), accountService, auditService, clock.Now)
+	accountService.SetSanctionsService(sanctionsService)
+	transactionService.SetSanctionsService(sanctionsService)
+	resetService.SetSanctionsService(sanctionsService)
+	suspenseService := service.NewSuspenseServiceWithClock(repository.NewSuspenseRepository(), accountRepo, auditService, "suspense", service.SuspensePolicy{UnknownDestination: domain.UnknownDestinationReject}, clock.Now)
+	transactionService.SetSuspenseService(suspenseService)
+	resetService.SetSuspenseService(suspenseService)
+	scheduleService := service.NewScheduleServiceWithClock(repository.NewScheduleRepository(), accountRepo, transactionService, auditService, "test", testSchedulePolicy, clock.Now)
+	resetService.SetScheduleService(scheduleService)
+	batchService := service.NewBatchServiceWithClock(repository.NewBatchRepository(), accountRepo, transactionService, testBatchPolicy, clock.Now)
+	resetService.SetBatchService(batchService)
+	policy := auth.NewPolicy(accountService)
+	logs := &bytes.Buffer{}
+	logger := logging.New(slog.LevelDebug, logs)
+	errorWorker := worker.NewErrorWorker(logger)
+	transactionService.SetErrorHandler(errorWorker)
+
+	versions := controller.APIVersions("coreBanking", time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC), controller.Services{
+		Accounts:     accountService,
+		Transactions: transactionService,
+		APIKeys:      service.NewAPIKeyService(repository.NewAPIKeyRepository(), auditService),
+		Limits:       limitService,
+		Fraud:        fraudService,
+		AML:          amlService,
+		Sanctions:    sanctionsService,
+		Suspense:     suspenseService,
+		Schedules:    scheduleService,
+		Batches:      batchService,
+		Audit:        auditService,
+		Reset:        resetService,
+	}, policy, errorWorker)
+
+	return &testApp{
+		accountRepo:        accountRepo,
+		accountService:     accountService,
+		transactionService: transactionService,
+		auditService:       auditService,
+		limitService:       limitService,
+		fraudService:       fraudService,
+		amlService:         amlService,
+		sanctionsService:   sanctionsService,
+		suspenseService:    suspenseService,
+		scheduleService:    scheduleService,
+		batchService:       batchService,
+		clock:              clock,
+		handler:            middleware.RequestID(middleware.Trace(middleware.AccessLog(logger)(middleware.Metrics(testPrincipal(controller.NewVersionedRouter("v1", versions...)))))),
+		logs:               logs,
+	}
+}
+
+// testPrincipal stands in for authentication: requests act as the subject
+// and comma separated roles of the X-Test-Subject and X-Test-Roles headers,
+// or as a caller holding every role when they are absent.
+func testPrincipal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := &auth.Principal{ID: "test", Subject: "test", Roles: auth.Roles()}
+		if roles := r.Header.Get("X-Test-Roles"); roles != "" {
+			principal = &auth.Principal{
+				ID:      r.Header.Get("X-Test-Subject"),
+				Subject: r.Header.Get("X-Test-Subject"),
+				Roles:   strings.Split(roles, ","),
+			}
+		}
+		middleware.Anonymous(principal)(next).ServeHTTP(w, r)
+	})
+}
+
+func (a *testApp) do(t *testing.T, method, path string, body any, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("failed to encode body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, &payload)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	a.handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func decodeBody(t *testing.T, recorder *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.NewDecoder(recorder.Body).Decode(v); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+}
+
+func (a *testApp) createAccount(t *testing.T, document string) string {
+	t.Helper()
+
+	resp := a.do(t, http.MethodPost, "/api/v1/accounts", dto.AccountRequest{DocumentNumber: document}, nil)
+	var account dto.AccountResponse
+	decodeBody(t, resp, &account)
+	return account.AccountID
+}
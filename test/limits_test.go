@@ -0,0 +1,281 @@
+package test
+
+import (
+	"context"
+	"corebanking/config"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type limitsEnvelope struct {
+	Data  dto.LimitsV2Response `json:"data"`
+	Error *dto.EnvelopeError   `json:"error"`
+}
+
+// newLimitsApp is a test app whose accounts fall under the standard
+// product with the given limits, nights being 22:00 to 06:00 UTC.
+func newLimitsApp(limits domain.Limits) *testApp {
+	app := newTestApp()
+	app.limitService.SetPolicy(service.LimitPolicy{
+		Products:       map[string]domain.Limits{"standard": limits, "premium": {}},
+		DefaultProduct: "standard",
+		NightStart:     22 * time.Hour,
+		NightEnd:       6 * time.Hour,
+		Location:       time.UTC,
+	})
+	return app
+}
+
+// fundedAccount opens an account holding 1000.00.
+func fundedAccount(t *testing.T, app *testApp, document string) string {
+	t.Helper()
+
+	account, err := app.accountService.CreateAccount(context.Background(), document, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.transactionService.CreateTransaction(context.Background(), &dto.TransactionRequest{AccountID: account.AccountID, OperationTypeID: 4, Amount: 100000}); err != nil {
+		t.Fatal(err)
+	}
+	return account.AccountID
+}
+
+func withdraw(app *testApp, accountID string, amount int64) error {
+	_, err := app.transactionService.HandleTransaction(context.Background(), &dto.EventRequest{Type: "withdraw", Origin: accountID, Amount: amount})
+	return err
+}
+
+func purchase(app *testApp, accountID string, amount int64) error {
+	_, err := app.transactionService.CreateTransaction(context.Background(), &dto.TransactionRequest{AccountID: accountID, OperationTypeID: 1, Amount: amount})
+	return err
+}
+
+func transfer(app *testApp, origin, destination string, amount int64) error {
+	_, err := app.transactionService.HandleTransaction(context.Background(), &dto.EventRequest{Type: "transfer", Origin: origin, Destination: destination, Amount: amount})
+	return err
+}
+
+func TestLimits_AmountDailyMonthlyAndWithdrawalCaps(t *testing.T) {
+	app := newLimitsApp(domain.Limits{MaxTransactionAmount: 500, DailyDebitLimit: 1000, MonthlyDebitLimit: 1500, DailyWithdrawalCount: 2})
+	accountID := fundedAccount(t, app, "1")
+
+	if err := withdraw(app, accountID, 600); !errors.Is(err, service.ErrTransactionAmountLimit) {
+		t.Fatalf("expected the amount cap, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := withdraw(app, accountID, 400); err != nil {
+			t.Fatalf("expected withdrawal %d to pass, got %v", i+1, err)
+		}
+	}
+	if err := withdraw(app, accountID, 100); !errors.Is(err, service.ErrWithdrawalCountLimit) {
+		t.Fatalf("expected the withdrawal count cap, got %v", err)
+	}
+	err := purchase(app, accountID, 300)
+	if !errors.Is(err, service.ErrDailyDebitLimit) || !errors.Is(err, service.ErrLimitExceeded) {
+		t.Fatalf("expected the daily cap, got %v", err)
+	}
+	if balance, _ := app.accountService.GetBalance(context.Background(), accountID); balance.Balance != 100000-800 {
+		t.Errorf("expected refused debits to leave the balance alone, got %d", balance.Balance)
+	}
+
+	app.clock.Advance(24 * time.Hour)
+	if err := purchase(app, accountID, 500); err != nil {
+		t.Fatalf("expected a new day to reset the daily cap, got %v", err)
+	}
+	if err := purchase(app, accountID, 300); !errors.Is(err, service.ErrMonthlyDebitLimit) {
+		t.Fatalf("expected the monthly cap, got %v", err)
+	}
+
+	app.clock.Advance(31 * 24 * time.Hour)
+	if err := purchase(app, accountID, 300); err != nil {
+		t.Errorf("expected a new month to reset the monthly cap, got %v", err)
+	}
+}
+
+func TestLimits_RefuseNonPositiveAmounts(t *testing.T) {
+	app := newLimitsApp(domain.Limits{DailyDebitLimit: 1000})
+	accountID := fundedAccount(t, app, "1")
+	account, _ := app.accountRepo.FindById(context.Background(), accountID)
+
+	for _, amount := range []int64{0, -5000} {
+		if _, err := app.limitService.Reserve(context.Background(), account, service.DebitPurchase, amount); !errors.Is(err, service.ErrInvalidAmount) {
+			t.Errorf("expected reserving %d refused, got %v", amount, err)
+		}
+		reservation := &service.Reservation{AccountID: accountID, Kind: service.DebitPurchase, Amount: amount}
+		if err := app.limitService.Release(context.Background(), reservation); !errors.Is(err, service.ErrInvalidAmount) {
+			t.Errorf("expected releasing %d refused, got %v", amount, err)
+		}
+	}
+	if err := purchase(app, accountID, 1000); err != nil {
+		t.Errorf("expected the daily cap untouched, got %v", err)
+	}
+	if err := purchase(app, accountID, 1); !errors.Is(err, service.ErrDailyDebitLimit) {
+		t.Errorf("expected no headroom given back, got %v", err)
+	}
+}
+
+func TestLimits_ReleaseOnlyTouchesTheReservedPeriods(t *testing.T) {
+	app := newLimitsApp(domain.Limits{})
+	accountID := fundedAccount(t, app, "1")
+	account, _ := app.accountRepo.FindById(context.Background(), accountID)
+
+	yesterday, err := app.limitService.Reserve(context.Background(), account, service.DebitWithdrawal, 400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.clock.Advance(24 * time.Hour)
+	if _, err := app.limitService.Reserve(context.Background(), account, service.DebitWithdrawal, 300); err != nil {
+		t.Fatal(err)
+	}
+
+	// Giving back yesterday's withdrawal leaves today's day untouched; the
+	// month is the same, so it gives that back.
+	if err := app.limitService.Release(context.Background(), yesterday); err != nil {
+		t.Fatal(err)
+	}
+	limits, err := app.limitService.GetLimits(context.Background(), accountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage := limits.Usage; usage.DailyDebits != 300 || usage.DailyWithdrawals != 1 || usage.MonthlyDebits != 300 {
+		t.Errorf("expected only the month released, got %+v", usage)
+	}
+}
+
+func TestLimits_NightTransferCapSpansMidnight(t *testing.T) {
+	app := newLimitsApp(domain.Limits{NightTransferLimit: 300})
+	origin, destination := fundedAccount(t, app, "1"), fundedAccount(t, app, "2")
+
+	if err := transfer(app, origin, destination, 1000); err != nil {
+		t.Fatalf("expected no night cap at noon, got %v", err)
+	}
+	app.clock.Advance(11 * time.Hour) // 23:00
+	if err := transfer(app, origin, destination, 200); err != nil {
+		t.Fatal(err)
+	}
+	app.clock.Advance(2 * time.Hour) // 01:00 the next day
+	if err := transfer(app, origin, destination, 200); !errors.Is(err, service.ErrNightTransferLimit) {
+		t.Fatalf("expected the night to carry over midnight, got %v", err)
+	}
+	if err := withdraw(app, origin, 200); err != nil {
+		t.Errorf("expected withdrawals to be outside the night cap, got %v", err)
+	}
+	app.clock.Advance(5 * time.Hour) // 06:00
+	if err := transfer(app, origin, destination, 200); err != nil {
+		t.Errorf("expected the cap to end with the night, got %v", err)
+	}
+}
+
+func TestLimits_Endpoints(t *testing.T) {
+	app := newLimitsApp(domain.Limits{DailyDebitLimit: 1000, DailyWithdrawalCount: 3})
+	accountID := fundedAccount(t, app, "1")
+	if err := withdraw(app, accountID, 250); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := app.do(t, http.MethodGet, "/api/v2/accounts/"+accountID+"/limits", nil, nil)
+	var body limitsEnvelope
+	decodeBody(t, resp, &body)
+	if resp.Code != http.StatusOK || body.Data.Product != "standard" || body.Data.Overridden {
+		t.Fatalf("unexpected limits %d %+v", resp.Code, body)
+	}
+	remaining := body.Data.Remaining
+	if remaining.DailyDebit == nil || *remaining.DailyDebit != 750 || remaining.DailyWithdrawals == nil || *remaining.DailyWithdrawals != 2 {
+		t.Errorf("unexpected remaining %+v", remaining)
+	}
+	if remaining.MonthlyDebit != nil || body.Data.Limits.MaxTransactionAmount != nil {
+		t.Errorf("expected uncapped limits to be null, got %+v", body.Data)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "withdraw", "origin": accountID, "amount": "8.00"}, nil)
+	body = limitsEnvelope{}
+	decodeBody(t, resp, &body)
+	if resp.Code != http.StatusUnprocessableEntity || body.Error == nil || body.Error.Code != "limit_exceeded" {
+		t.Fatalf("expected 422 limit_exceeded, got %d %+v", resp.Code, body.Error)
+	}
+
+	resp = app.do(t, http.MethodPut, "/api/v2/accounts/"+accountID+"/limits", map[string]any{"product": "gold"}, nil)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("expected an unknown product to be rejected, got %d", resp.Code)
+	}
+	resp = app.do(t, http.MethodPut, "/api/v2/accounts/"+accountID+"/limits", map[string]any{
+		"product": "premium", "limits": map[string]any{"dailyDebitLimit": "20.00"},
+	}, nil)
+	body = limitsEnvelope{}
+	decodeBody(t, resp, &body)
+	if resp.Code != http.StatusOK || body.Data.Product != "premium" || !body.Data.Overridden || *body.Data.Remaining.DailyDebit != 1750 {
+		t.Fatalf("unexpected limits after update %d %+v", resp.Code, body.Data)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "withdraw", "origin": accountID, "amount": "8.00"}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Errorf("expected the raised limit to apply, got %d", resp.Code)
+	}
+	entries := app.auditService.Query(context.Background(), "", "account", accountID, time.Time{}, time.Time{})
+	if !slices.ContainsFunc(entries, func(e *domain.AuditEntry) bool { return e.Action == service.AuditAccountLimits }) {
+		t.Error("expected the change to be audited")
+	}
+
+	resp = app.do(t, http.MethodPut, "/api/v2/accounts/"+accountID+"/limits", map[string]any{}, nil)
+	body = limitsEnvelope{}
+	decodeBody(t, resp, &body)
+	if body.Data.Product != "standard" || body.Data.Overridden {
+		t.Errorf("expected an empty request to restore the default product, got %+v", body.Data)
+	}
+}
+
+func TestLimits_ConfigAndReload(t *testing.T) {
+	env := envMap(map[string]string{
+		"LIMITS_PRODUCTS":        "basic.daily_debit=100,basic.overnight=5",
+		"LIMITS_DEFAULT_PRODUCT": "basic",
+		"LIMITS_NIGHT_START":     "10pm",
+		"LIMITS_TIMEZONE":        "Mars/Olympus",
+	})
+	_, err := config.NewLoaderWithEnv(nil, env).Load()
+	for _, key := range []string{"limits.products:", "limits.night_start:", "limits.timezone:"} {
+		if err == nil || !strings.Contains(err.Error(), key) {
+			t.Errorf("expected %s to be reported, got %v", key, err)
+		}
+	}
+	_, err = config.NewLoaderWithEnv(nil, envMap(map[string]string{"LIMITS_DEFAULT_PRODUCT": "gold"})).Load()
+	if err == nil || !strings.Contains(err.Error(), "limits.default_product:") {
+		t.Errorf("expected an unknown default product to be rejected, got %v", err)
+	}
+
+	current, err := config.NewLoaderWithEnv(nil, envMap(nil)).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, _ := current.LimitPolicy()
+	if policy.Products["standard"].DailyWithdrawalCount != 10 || policy.NightStart != 22*time.Hour || policy.NightEnd != 6*time.Hour {
+		t.Fatalf("unexpected default policy %+v", policy)
+	}
+
+	app := newTestApp()
+	app.limitService.SetPolicy(policy)
+	accountID := fundedAccount(t, app, "1")
+	if err := withdraw(app, accountID, 2000); err != nil {
+		t.Fatalf("expected the default limits to allow a withdrawal, got %v", err)
+	}
+
+	next, _ := config.NewLoaderWithEnv(nil, envMap(map[string]string{"LIMITS_PRODUCTS": "standard.max_transaction=1000,standard.daily_withdrawals=1"})).Load()
+	merged, applied, _ := config.Reload(current, next)
+	if !slices.Contains(applied, "limits.products") {
+		t.Fatalf("expected limits to reload, applied %v", applied)
+	}
+	policy, _ = merged.LimitPolicy()
+	app.limitService.SetPolicy(policy)
+	if err := withdraw(app, accountID, 2000); !errors.Is(err, service.ErrTransactionAmountLimit) {
+		t.Fatalf("expected the reloaded amount cap, got %v", err)
+	}
+	if err := withdraw(app, accountID, 100); !errors.Is(err, service.ErrWithdrawalCountLimit) {
+		t.Errorf("expected usage before the reload to count, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"corebanking/internal/event"
+	"corebanking/internal/logging"
+	"corebanking/internal/utils"
+	"corebanking/internal/worker"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func decodeLogLines(t *testing.T, data string) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("log line is not JSON: %q", scanner.Text())
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogging_LevelAndContextFields(t *testing.T) {
+	var stdout, file bytes.Buffer
+	level, err := logging.ParseLevel("warn")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger := logging.New(level, &stdout, &file)
+
+	ctx := utils.WithRequestID(context.Background(), "req-1")
+	ctx = logging.WithAccountID(ctx, "acc-1")
+	ctx = logging.WithTransactionID(ctx, 7)
+
+	logger.InfoContext(ctx, "dropped")
+	logger.WarnContext(ctx, "kept", "amount", 10)
+
+	for name, sink := range map[string]*bytes.Buffer{"stdout": &stdout, "file": &file} {
+		records := decodeLogLines(t, sink.String())
+		if len(records) != 1 {
+			t.Fatalf("%s: expected 1 record, got %d", name, len(records))
+		}
+		record := records[0]
+		if record["level"] != "WARN" || record["msg"] != "kept" || record["amount"] != float64(10) {
+			t.Errorf("%s: unexpected record %v", name, record)
+		}
+		if record[logging.FieldRequestID] != "req-1" || record[logging.FieldAccountID] != "acc-1" || record[logging.FieldTransactionID] != float64(7) {
+			t.Errorf("%s: missing context fields in %v", name, record)
+		}
+	}
+}
+
+func TestLogging_ParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"DEBUG": slog.LevelDebug,
+		"info":  slog.LevelInfo,
+		"WARN":  slog.LevelWarn,
+		"Error": slog.LevelError,
+	}
+	for value, want := range tests {
+		got, err := logging.ParseLevel(value)
+		if err != nil || got != want {
+			t.Errorf("%s: expected %v, got %v (%v)", value, want, got, err)
+		}
+	}
+
+	if _, err := logging.ParseLevel("verbose"); err == nil {
+		t.Errorf("expected unknown level to fail")
+	}
+}
+
+func TestLogging_AsyncFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	lc, err := event.NewLogChannel(path, 10)
+	if err != nil {
+		t.Fatalf("failed to create LogChannel: %v", err)
+	}
+
+	errorWorker := worker.NewErrorWorker(logging.New(slog.LevelDebug, lc))
+	errorWorker.Handle(utils.WithRequestID(context.Background(), "req-2"), errors.New("boom"), "Failed to create account.")
+	errorWorker.Handle(context.Background(), nil, "Failed to instance method RESTful.")
+
+	if err := lc.Close(); err != nil {
+		t.Fatalf("failed to close LogChannel: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	records := decodeLogLines(t, string(data))
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0]["level"] != "ERROR" || records[0]["error"] != "boom" || records[0][logging.FieldRequestID] != "req-2" {
+		t.Errorf("unexpected first record %v", records[0])
+	}
+	if _, ok := records[1]["error"]; ok {
+		t.Errorf("expected no error field, got %v", records[1])
+	}
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"bufio"
+	"corebanking/internal/dto"
+	"corebanking/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// scrape reads the default registry through its handler and returns each
+// sample keyed by its name and labels.
+func scrape(t *testing.T) map[string]float64 {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	metrics.Default.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+
+	samples := map[string]float64{}
+	scanner := bufio.NewScanner(recorder.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			t.Fatalf("malformed sample %q: %v", line, err)
+		}
+		samples[line[:i]] = value
+	}
+	return samples
+}
+
+func TestMetrics_TextFormat(t *testing.T) {
+	registry := metrics.NewRegistry()
+	requests := registry.NewCounterVec("test_requests_total", "Requests\nserved.", "path")
+	latency := registry.NewHistogramVec("test_latency_seconds", "Latency.", []float64{0.1, 1}, "path")
+	registry.GaugeFunc("test_depth", "Depth.", func() float64 { return 3 })
+
+	requests.Inc(`/a"b`)
+	requests.Add(2, `/a"b`)
+	requests.Add(-5, `/a"b`)
+	latency.Observe(0.05, "/")
+	latency.Observe(0.5, "/")
+	latency.Observe(5, "/")
+
+	var out strings.Builder
+	registry.WriteText(&out)
+	want := `# HELP test_depth Depth.
+# TYPE test_depth gauge
+test_depth 3
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{path="/",le="0.1"} 1
+test_latency_seconds_bucket{path="/",le="1"} 2
+test_latency_seconds_bucket{path="/",le="+Inf"} 3
+test_latency_seconds_sum{path="/"} 5.55
+test_latency_seconds_count{path="/"} 3
+# HELP test_requests_total Requests\nserved.
+# TYPE test_requests_total counter
+test_requests_total{path="/a\"b"} 3
+`
+	if out.String() != want {
+		t.Errorf("unexpected exposition:\n%s", out.String())
+	}
+}
+
+func TestMetrics_HTTPAndBusinessCounters(t *testing.T) {
+	app := newTestApp()
+	before := scrape(t)
+
+	resp := app.do(t, http.MethodPost, "/api/v1/accounts", dto.AccountRequest{DocumentNumber: "1"}, nil)
+	var account dto.AccountResponse
+	decodeBody(t, resp, &account)
+	other := createAccountV2(t, app, "2")
+
+	app.do(t, http.MethodPost, "/api/v1/transactions", map[string]any{"accountId": account.AccountID, "operationTypeId": 4, "amount": 100}, nil)
+	resp = app.do(t, http.MethodPost, "/api/v1/transactions", map[string]any{"accountId": account.AccountID, "operationTypeId": 1, "amount": 500}, nil)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected declined purchase, got %d", resp.Code)
+	}
+	resp = app.do(t, http.MethodPost, "/api/v2/events", map[string]any{
+		"type": "transfer", "origin": account.AccountID, "destination": other, "amount": "0.40",
+	}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected transfer to succeed, got %d", resp.Code)
+	}
+	app.do(t, http.MethodGet, "/api/v9/accounts", nil, nil)
+
+	after := scrape(t)
+	delta := func(sample string) float64 { return after[sample] - before[sample] }
+
+	wantDeltas := map[string]float64{
+		`corebanking_http_requests_total{method="POST",route="/api/v1/accounts",status="201"}`:                 1,
+		`corebanking_http_requests_total{method="POST",route="POST /api/v2/accounts",status="201"}`:            1,
+		`corebanking_http_requests_total{method="POST",route="/api/v1/transactions",status="400"}`:             1,
+		`corebanking_http_requests_total{method="GET",route="unmatched",status="404"}`:                         1,
+		`corebanking_http_request_duration_seconds_count{method="POST",route="/api/v1/accounts",status="201"}`: 1,
+		`corebanking_transactions_posted_total{operation="credit_voucher"}`:                                    1,
+		`corebanking_transactions_posted_total{operation="transfer"}`:                                          1,
+		`corebanking_transactions_declined_total{operation="normal_purchase",reason="insufficient_funds"}`:     1,
+		`corebanking_transfer_volume_total`:                                                                    40,
+	}
+	for sample, want := range wantDeltas {
+		if got := delta(sample); got != want {
+			t.Errorf("expected %s to grow by %v, got %v", sample, want, got)
+		}
+	}
+	if _, ok := after[`go_goroutines`]; !ok {
+		t.Errorf("expected Go runtime metrics")
+	}
+	if after[`go_memstats_alloc_bytes`] <= 0 {
+		t.Errorf("expected heap allocation to be reported")
+	}
+}
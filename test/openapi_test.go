@@ -0,0 +1,198 @@
+package test
+
+import (
+	"bytes"
+	"corebanking/internal/auth"
+	"corebanking/internal/controller"
+	"corebanking/internal/middleware"
+	"corebanking/internal/openapi"
+	"corebanking/internal/repository"
+	"corebanking/internal/service"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// updateGolden rewrites the golden specs in testdata from the current
+// build: go test ./test -run OpenAPI -update.
+var updateGolden = flag.Bool("update", false, "rewrite the golden OpenAPI specs")
+
+// specRoutes lists the routes a version serves, built the way main builds
+// them. The docs controller serves the spec itself and is left out.
+func specRoutes(version string) []controller.Route {
+	var routes []controller.Route
+	for _, apiVersion := range controller.APIVersions("coreBanking", time.Time{}, controller.Services{}, nil, nil) {
+		if apiVersion.Name != version {
+			continue
+		}
+		for _, registrar := range apiVersion.Registrars {
+			if _, docs := registrar.(*controller.DocsController); !docs {
+				routes = append(routes, registrar.Routes()...)
+			}
+		}
+	}
+	return routes
+}
+
+// specPathFor finds the documented path served by a mux pattern. Patterns
+// ending in "/" are subtree routes and match a single templated segment.
+func specPathFor(doc *openapi.Document, pattern string) (openapi.PathItem, bool) {
+	if item, exists := doc.Paths[pattern]; exists {
+		return item, true
+	}
+	if !strings.HasSuffix(pattern, "/") {
+		return nil, false
+	}
+	for path, item := range doc.Paths {
+		rest, found := strings.CutPrefix(path, pattern)
+		if found && strings.HasPrefix(rest, "{") && strings.HasSuffix(rest, "}") && !strings.Contains(rest, "/") {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+func TestOpenAPI_CoversRegisteredRoutes(t *testing.T) {
+	for _, version := range []string{"v1", "v2"} {
+		doc := openapi.Build("coreBanking", version)
+
+		for _, route := range specRoutes(version) {
+			item, exists := specPathFor(doc, route.Pattern)
+			if !exists {
+				t.Errorf("%s route %s %s missing from spec", version, route.Method, route.Pattern)
+				continue
+			}
+			if _, exists := item[strings.ToLower(route.Method)]; !exists {
+				t.Errorf("%s route %s %s has no %s operation in spec", version, route.Method, route.Pattern, route.Method)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_ServesEveryVersion(t *testing.T) {
+	var names []string
+	for _, version := range controller.APIVersions("coreBanking", time.Time{}, controller.Services{}, nil, nil) {
+		names = append(names, version.Name)
+		if len(specRoutes(version.Name)) == 0 {
+			t.Errorf("expected %s to serve routes", version.Name)
+		}
+	}
+	if strings.Join(names, ",") != "v1,v2" {
+		t.Errorf("expected versions v1 and v2, got %v", names)
+	}
+}
+
+// TestOpenAPI_MatchesGolden pins the published specs: any change to a
+// path, parameter or schema shows up as a diff against testdata.
+func TestOpenAPI_MatchesGolden(t *testing.T) {
+	for _, version := range []string{"v1", "v2"} {
+		got, err := json.MarshalIndent(openapi.Build("coreBanking", version), "", "  ")
+		if err != nil {
+			t.Fatalf("failed to encode %s spec: %v", version, err)
+		}
+		got = append(got, '\n')
+
+		path := filepath.Join("testdata", "openapi-"+version+".json")
+		if *updateGolden {
+			if err := os.WriteFile(path, got, 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", path, err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s spec differs from %s; rerun with -update if the change is intended", version, path)
+		}
+	}
+}
+
+func TestOpenAPI_DocumentsWireTypes(t *testing.T) {
+	cases := []struct {
+		version, schema, field string
+		typ, format            string
+	}{
+		{"v1", "EventRequest", "amount", "integer", "int64"},
+		{"v1", "EventRequest", "type", "string", ""},
+		{"v1", "TransactionResponse", "transactionId", "integer", "int64"},
+		{"v1", "TransactionResponse", "eventDate", "string", "date-time"},
+		{"v2", "EventV2Request", "amount", "string", "decimal"},
+		{"v2", "TransactionV2Request", "accountId", "string", ""},
+		{"v2", "TransactionV2Request", "operationTypeId", "integer", "int32"},
+		{"v2", "TransactionV2Request", "amount", "string", "decimal"},
+		{"v2", "TransactionV2Response", "amount", "string", "decimal"},
+		{"v2", "TransactionV2Response", "eventDate", "string", "date-time"},
+		{"v2", "BalanceV2Response", "balance", "string", "decimal"},
+		{"v2", "OverdraftV2Request", "limit", "string", "decimal"},
+		{"v2", "BatchV2Response", "total", "integer", "int32"},
+		{"v2", "BatchV2Response", "createdAt", "string", "date-time"},
+		{"v2", "EnvelopeMeta", "apiVersion", "string", ""},
+	}
+	specs := map[string]*openapi.Document{
+		"v1": openapi.Build("coreBanking", "v1"),
+		"v2": openapi.Build("coreBanking", "v2"),
+	}
+	for _, c := range cases {
+		schema, exists := specs[c.version].Components.Schemas[c.schema]
+		if !exists {
+			t.Errorf("%s schema %s missing", c.version, c.schema)
+			continue
+		}
+		field, exists := schema.Properties[c.field]
+		if !exists {
+			t.Errorf("%s field %s.%s missing", c.version, c.schema, c.field)
+			continue
+		}
+		if field.Type != c.typ || field.Format != c.format {
+			t.Errorf("%s field %s.%s documented as %s/%s, expected %s/%s", c.version, c.schema, c.field, field.Type, field.Format, c.typ, c.format)
+		}
+	}
+}
+
+func TestOpenAPI_SpecsArePublic(t *testing.T) {
+	keys := service.NewAPIKeyService(repository.NewAPIKeyRepository(), service.NewAuditService(repository.NewAuditRepository()))
+	authenticator := auth.NewAuthenticator(keys, auth.NewJWTVerifier("", ""))
+	versions := controller.APIVersions("coreBanking", time.Time{}, controller.Services{}, nil, nil)
+	handler := middleware.Authenticate(authenticator, nil, middleware.PublicPaths("openapi.json", "docs"))(controller.NewVersionedRouter("v1", versions...))
+
+	for path, want := range map[string]int{
+		"/api/openapi.json":    http.StatusOK,
+		"/api/v1/openapi.json": http.StatusOK,
+		"/api/v2/openapi.json": http.StatusOK,
+		"/api/docs":            http.StatusOK,
+		"/api/v2/docs":         http.StatusOK,
+		"/api/v2/accounts/1":   http.StatusUnauthorized,
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != want {
+			t.Errorf("%s: expected status %d without credentials, got %d", path, want, recorder.Code)
+		}
+	}
+}
+
+func TestOpenAPI_DocsLoadNothingFromElsewhere(t *testing.T) {
+	app := newTestApp()
+	resp := app.do(t, http.MethodGet, "/api/v2/docs", nil, nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected the docs page, got %d", resp.Code)
+	}
+	page := resp.Body.String()
+	for _, external := range []string{"http://", "https://", "//unpkg", "src="} {
+		if strings.Contains(page, external) {
+			t.Errorf("expected a self-contained page, found %q", external)
+		}
+	}
+	policy := resp.Header().Get("Content-Security-Policy")
+	if !strings.Contains(policy, "default-src 'none'") || !strings.Contains(policy, "script-src 'sha256-") || strings.Contains(policy, "script-src 'unsafe-inline'") {
+		t.Errorf("expected a policy allowing only the page's own script, got %q", policy)
+	}
+}
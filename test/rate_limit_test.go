@@ -0,0 +1,302 @@
+package test
+
+import (
+	"corebanking/config"
+	"corebanking/internal/auth"
+	"corebanking/internal/middleware"
+	"corebanking/internal/ratelimit"
+	"corebanking/internal/repository"
+	"corebanking/internal/service"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func mustLimit(t *testing.T, value string) ratelimit.Limit {
+	t.Helper()
+
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return limit
+}
+
+// rateLimitedHandler echoes the request body behind RateLimitIP and
+// RateLimit, acting as the principal named by X-Client and X-Tier.
+func rateLimitedHandler(limiter *ratelimit.Limiter) http.Handler {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	})
+	limited := middleware.RateLimit(limiter)(echo)
+	return middleware.RateLimitIP(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := &auth.Principal{ID: r.Header.Get("X-Client"), Tier: r.Header.Get("X-Tier")}
+		limited.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	}))
+}
+
+func limitedRequest(handler http.Handler, method, path, client, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-Client", client)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestRateLimit_MoneyMovingRequestsHaveTheirOwnBucket(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	limiter := ratelimit.NewLimiterWithClock(ratelimit.Rules{
+		Standard: ratelimit.Limits{Client: mustLimit(t, "5/s")},
+		Money:    ratelimit.Limits{Client: mustLimit(t, "2/m")},
+	}, clock.Now)
+	handler := rateLimitedHandler(limiter)
+
+	for i := 0; i < 2; i++ {
+		if code := limitedRequest(handler, http.MethodPost, "/api/v2/transactions", "alice", "{}").Code; code != http.StatusOK {
+			t.Fatalf("expected request %d to pass, got %d", i+1, code)
+		}
+	}
+	denied := limitedRequest(handler, http.MethodPost, "/api/v2/transactions", "alice", "{}")
+	if denied.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", denied.Code)
+	}
+	if got := denied.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("expected Retry-After 30, got %q", got)
+	}
+	if denied.Header().Get("RateLimit-Limit") != "2" || denied.Header().Get("RateLimit-Remaining") != "0" ||
+		denied.Header().Get("RateLimit-Policy") != "2;w=60" {
+		t.Errorf("unexpected RateLimit headers %v", denied.Header())
+	}
+	if !strings.Contains(denied.Body.String(), "rate limit exceeded for client") {
+		t.Errorf("expected a problem body naming the scope, got %s", denied.Body.String())
+	}
+
+	standard := limitedRequest(handler, http.MethodGet, "/api/v2/transactions", "alice", "")
+	if standard.Code != http.StatusOK || standard.Header().Get("RateLimit-Remaining") != "4" {
+		t.Errorf("expected reads to use their own bucket, got %d remaining %q", standard.Code, standard.Header().Get("RateLimit-Remaining"))
+	}
+	if code := limitedRequest(handler, http.MethodPost, "/api/v2/transactions", "bob", "{}").Code; code != http.StatusOK {
+		t.Errorf("expected another client to be unaffected, got %d", code)
+	}
+
+	clock.Advance(30 * time.Second)
+	if code := limitedRequest(handler, http.MethodPost, "/api/v1/transactions/event", "alice", "{}").Code; code != http.StatusOK {
+		t.Errorf("expected a token after Retry-After, got %d", code)
+	}
+}
+
+func TestRateLimit_BatchesTakeATokenPerItem(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	limiter := ratelimit.NewLimiterWithClock(ratelimit.Rules{
+		Standard: ratelimit.Limits{Client: mustLimit(t, "100/m")},
+		Money:    ratelimit.Limits{Client: mustLimit(t, "10/m")},
+	}, clock.Now)
+	handler := rateLimitedHandler(limiter)
+	batch := func(items int) string {
+		return `{"items": [` + strings.Repeat(`{"type": "deposit", "destination": "acc-1", "amount": "1.00"},`, items-1) + `{"type": "deposit", "destination": "acc-1", "amount": "1.00"}]}`
+	}
+
+	body := batch(3)
+	recorder := limitedRequest(handler, http.MethodPost, "/api/v2/transactions/batch", "alice", body)
+	if recorder.Code != http.StatusOK || recorder.Body.String() != body {
+		t.Fatalf("expected the handler to read the original batch, got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("RateLimit-Remaining"); got != "7" {
+		t.Errorf("expected 3 money tokens taken, got %q left", got)
+	}
+	denied := limitedRequest(handler, http.MethodPost, "/api/v2/transactions/batch", "alice", batch(8))
+	if denied.Code != http.StatusTooManyRequests || denied.Header().Get("Retry-After") != "6" {
+		t.Fatalf("expected 8 items refused with 7 tokens left, got %d Retry-After %q", denied.Code, denied.Header().Get("Retry-After"))
+	}
+	if code := limitedRequest(handler, http.MethodPost, "/api/v2/transactions", "alice", "{}").Code; code != http.StatusOK {
+		t.Errorf("expected the refused batch to take nothing, got %d", code)
+	}
+
+	// A batch larger than the burst waits for a full bucket and empties it.
+	clock.Advance(time.Minute)
+	recorder = limitedRequest(handler, http.MethodPost, "/api/v2/transactions/batch", "alice", batch(25))
+	if recorder.Code != http.StatusOK || recorder.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("expected a full bucket taken, got %d remaining %q", recorder.Code, recorder.Header().Get("RateLimit-Remaining"))
+	}
+	if code := limitedRequest(handler, http.MethodGet, "/api/v2/transactions/batch/some-id", "alice", "").Code; code != http.StatusOK {
+		t.Errorf("expected reading a batch to be a standard request, got %d", code)
+	}
+}
+
+func TestRateLimit_AccountBucketIsSharedAcrossClients(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	limiter := ratelimit.NewLimiterWithClock(ratelimit.Rules{
+		Money: ratelimit.Limits{Client: mustLimit(t, "10/m"), Account: mustLimit(t, "2/m")},
+	}, clock.Now)
+	handler := rateLimitedHandler(limiter)
+
+	body := `{"type": "transfer", "origin": "acc-1", "destination": "acc-2", "amount": 10}`
+	for _, client := range []string{"alice", "bob"} {
+		recorder := limitedRequest(handler, http.MethodPost, "/api/v2/events", client, body)
+		if recorder.Code != http.StatusOK || recorder.Body.String() != body {
+			t.Fatalf("expected the handler to read the original body, got %d %q", recorder.Code, recorder.Body.String())
+		}
+	}
+	denied := limitedRequest(handler, http.MethodPost, "/api/v2/events", "carol", body)
+	if denied.Code != http.StatusTooManyRequests || !strings.Contains(denied.Body.String(), "account") {
+		t.Fatalf("expected the account limit to apply across clients, got %d %s", denied.Code, denied.Body.String())
+	}
+
+	// The denied request took no token from carol's own bucket.
+	other := `{"accountId": "acc-9", "operationTypeId": 1, "amount": 10}`
+	recorder := limitedRequest(handler, http.MethodPost, "/api/v2/transactions", "carol", other)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected another account to pass, got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("RateLimit-Remaining"); got != "1" {
+		t.Errorf("expected the account bucket to be the most constrained with 1 left, got %q", got)
+	}
+}
+
+func TestRateLimit_IPAndTiers(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.Rules{
+		Standard: ratelimit.Limits{Client: mustLimit(t, "1/m"), IP: mustLimit(t, "4/m")},
+		Tiers:    map[string]ratelimit.TierLimits{"partner": {Standard: mustLimit(t, "10/m")}},
+	})
+	handler := rateLimitedHandler(limiter)
+
+	get := func(client, tier string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/accounts/acc-1", nil)
+		req.Header.Set("X-Client", client)
+		req.Header.Set("X-Tier", tier)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if get("alice", "") != http.StatusOK || get("alice", "") != http.StatusTooManyRequests {
+		t.Error("expected the default tier to allow one request a minute")
+	}
+	if get("partner-1", "partner") != http.StatusOK || get("partner-1", "partner") != http.StatusOK {
+		t.Error("expected the partner tier to allow more")
+	}
+	// The IP bucket is drawn before the client's, so alice's denied
+	// request took an IP token too and none is left after four requests
+	// from the same address.
+	if code := get("partner-1", "partner"); code != http.StatusTooManyRequests {
+		t.Errorf("expected the IP limit to apply across clients, got %d", code)
+	}
+}
+
+func TestRateLimit_FailedAuthenticationIsLimitedByIP(t *testing.T) {
+	keys := service.NewAPIKeyService(repository.NewAPIKeyRepository(), service.NewAuditService(repository.NewAuditRepository()))
+	authenticator := auth.NewAuthenticator(keys, auth.NewJWTVerifier("", ""))
+	limiter := ratelimit.NewLimiter(ratelimit.Rules{
+		Standard: ratelimit.Limits{Client: mustLimit(t, "100/m"), IP: mustLimit(t, "5/m")},
+	})
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := middleware.RateLimitIP(limiter)(middleware.Authenticate(authenticator, nil, middleware.PublicPaths())(middleware.RateLimit(limiter)(echo)))
+
+	guess := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/accounts/acc-1", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(auth.APIKeyHeader, "cbk_guess.secret")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	for i := range 5 {
+		if code := guess("192.0.2.1:1234"); code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: expected status %d, got %d", i+1, http.StatusUnauthorized, code)
+		}
+	}
+	if code := guess("192.0.2.1:1234"); code != http.StatusTooManyRequests {
+		t.Errorf("expected repeated failed authentications limited, got %d", code)
+	}
+	if code := guess("192.0.2.2:1234"); code != http.StatusUnauthorized {
+		t.Errorf("expected another address left alone, got %d", code)
+	}
+}
+
+func TestRateLimit_Parsing(t *testing.T) {
+	limit := mustLimit(t, "600/m")
+	if limit.Burst != 600 || limit.Rate != 10 || limit.Window() != time.Minute {
+		t.Errorf("unexpected limit %+v", limit)
+	}
+	if !mustLimit(t, "off").Unlimited() {
+		t.Error("expected off to be unlimited")
+	}
+	for _, bad := range []string{"10", "ten/s", "10/d", "-1/s"} {
+		if _, err := ratelimit.ParseLimit(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+
+	tiers, err := ratelimit.ParseTiers("partner.standard=200/s, partner.money=20/s,internal.money=off")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tiers["partner"].Money.Burst != 20 || !tiers["internal"].Money.Unlimited() {
+		t.Errorf("unexpected tiers %+v", tiers)
+	}
+	for _, bad := range []string{"partner=20/s", "partner.writes=20/s", "partner.money=fast"} {
+		if _, err := ratelimit.ParseTiers(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestRateLimit_ConfigAndReload(t *testing.T) {
+	env := envMap(map[string]string{"RATE_LIMIT_CLIENT_MONEY": "often", "RATE_LIMIT_TIERS": "gold=1/s"})
+	_, err := config.NewLoaderWithEnv(nil, env).Load()
+	if err == nil || !strings.Contains(err.Error(), "ratelimit.client_money:") || !strings.Contains(err.Error(), "ratelimit.tiers:") {
+		t.Fatalf("expected both rate limit settings to be reported, got %v", err)
+	}
+
+	current, _ := config.NewLoaderWithEnv(nil, envMap(nil)).Load()
+	next, err := config.NewLoaderWithEnv(nil, envMap(map[string]string{"RATE_LIMIT_CLIENT_MONEY": "1/m"})).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, applied, _ := config.Reload(current, next)
+	if !slices.Contains(applied, "ratelimit.client_money") {
+		t.Fatalf("expected rate limits to reload, applied %v", applied)
+	}
+
+	rules, _ := current.RateLimitRules()
+	limiter := ratelimit.NewLimiter(rules)
+	handler := rateLimitedHandler(limiter)
+	if code := limitedRequest(handler, http.MethodPost, "/api/v2/transactions", "alice", "{}").Code; code != http.StatusOK {
+		t.Fatalf("expected the first request to pass, got %d", code)
+	}
+	rules, _ = merged.RateLimitRules()
+	limiter.SetRules(rules)
+	limitedRequest(handler, http.MethodPost, "/api/v2/transactions", "alice", "{}")
+	if code := limitedRequest(handler, http.MethodPost, "/api/v2/transactions", "alice", "{}").Code; code != http.StatusTooManyRequests {
+		t.Errorf("expected the lowered limit to apply to existing buckets, got %d", code)
+	}
+
+	disabled := *merged
+	disabled.RateLimitEnabled = false
+	if rules, _ := disabled.RateLimitRules(); len(rules.Keys(ratelimit.ClassMoney, "alice", "", "10.0.0.1", "acc-1")) != 3 || !rules.Money.Client.Unlimited() {
+		t.Errorf("expected disabled limiting to leave every bucket unlimited, got %+v", rules)
+	}
+}
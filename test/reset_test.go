@@ -0,0 +1,117 @@
+package test
+
+import (
+	"context"
+	"corebanking/internal/repository"
+	"corebanking/internal/service"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestReset_RequiresConfirmation(t *testing.T) {
+	app := newTestApp()
+	createAccountV2(t, app, "1")
+
+	resp := app.do(t, http.MethodPost, "/api/v2/system/reset", nil, nil)
+	if resp.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected status %d, got %d", http.StatusPreconditionRequired, resp.Code)
+	}
+	resp = app.do(t, http.MethodPost, "/api/v2/system/reset", nil, map[string]string{"X-Reset-Confirmation": "wrong"})
+	if resp.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected status %d, got %d", http.StatusPreconditionRequired, resp.Code)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v1/accounts/reset", nil, nil)
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected v1 status %d, got %d", http.StatusBadRequest, resp.Code)
+	}
+}
+
+func TestReset_ClearsAccountsAndTransactions(t *testing.T) {
+	app := newTestApp()
+	accountID := createAccountV2(t, app, "1")
+	resp := app.do(t, http.MethodPost, "/api/v2/transactions", map[string]any{
+		"accountId": accountID, "operationTypeId": 4, "amount": "1.00",
+	}, nil)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v1/accounts/reset", nil, map[string]string{"X-Reset-Confirmation": testResetToken})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.Code)
+	}
+
+	if _, err := app.accountService.GetAccount(context.Background(), accountID); !errors.Is(err, service.ErrAccountNotFound) {
+		t.Errorf("expected account to be removed, got %v", err)
+	}
+	if n := len(app.transactionService.GetAllTransactions(context.Background())); n != 0 {
+		t.Errorf("expected no transactions, got %d", n)
+	}
+
+	entries := app.auditService.Query(context.Background(), "", "system", "", time.Time{}, time.Time{})
+	if len(entries) != 1 || entries[0].Action != service.AuditSystemReset {
+		t.Fatalf("expected one system.reset entry, got %+v", entries)
+	}
+	if string(entries[0].Before) != `{"accounts":1,"transactions":1}` {
+		t.Errorf("unexpected before snapshot %s", entries[0].Before)
+	}
+}
+
+func TestReset_SingleAccount(t *testing.T) {
+	app := newTestApp()
+	fixture := createAccountV2(t, app, "fixture")
+	kept := createAccountV2(t, app, "kept")
+	for _, id := range []string{fixture, kept} {
+		app.do(t, http.MethodPost, "/api/v2/transactions", map[string]any{
+			"accountId": id, "operationTypeId": 4, "amount": "1.00",
+		}, nil)
+	}
+
+	headers := map[string]string{"X-Reset-Confirmation": testResetToken}
+	resp := app.do(t, http.MethodPost, "/api/v2/accounts/"+fixture+"/reset", nil, headers)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.Code)
+	}
+
+	if _, err := app.accountService.GetAccount(context.Background(), kept); err != nil {
+		t.Errorf("expected other account to survive, got %v", err)
+	}
+	transactions := app.transactionService.GetAllTransactions(context.Background())
+	if len(transactions) != 1 || transactions[0].AccountID != kept {
+		t.Errorf("expected only the kept account's transaction, got %+v", transactions)
+	}
+
+	// The document number is free again, so the fixture can be recreated.
+	createAccountV2(t, app, "fixture")
+
+	resp = app.do(t, http.MethodPost, "/api/v2/accounts/"+fixture+"/reset", nil, headers)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for removed account, got %d", http.StatusNotFound, resp.Code)
+	}
+}
+
+func TestReset_DisabledOutsideSandbox(t *testing.T) {
+	audit := service.NewAuditService(repository.NewAuditRepository())
+	accounts := service.NewAccountService(repository.NewAccountRepository(), audit)
+	if _, err := accounts.CreateAccount(context.Background(), "1", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		sandbox bool
+		token   string
+	}{
+		{"sandbox off", false, "token"},
+		{"no token", true, ""},
+	}
+	for _, tc := range tests {
+		resetter := service.NewResetService(accounts, repository.NewTransactionRepository(), audit, tc.sandbox, tc.token)
+		if err := resetter.ResetAll(context.Background(), tc.token); !errors.Is(err, service.ErrResetDisabled) {
+			t.Errorf("%s: expected ErrResetDisabled, got %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package test
+
+import (
+	"compress/gzip"
+	"corebanking/internal/event"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock is a settable clock shared with the log worker goroutine.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func readLogFile(t *testing.T, path string) string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("failed to read gzip %s: %v", path, err)
+		}
+		r = gz
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestRotation_BySizeKeepsEveryLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "app.log")
+	clock := &testClock{now: time.Date(2026, 1, 1, 10, 0, 0, 0, time.Local)}
+
+	lc, err := event.NewLogChannelWithOptions(path, event.Options{
+		BufferSize: 100,
+		Rotation:   event.RotateOptions{MaxSize: 64, Now: clock.Now},
+	})
+	if err != nil {
+		t.Fatalf("expected the log directory to be created, got %v", err)
+	}
+	var want []string
+	for i := 0; i < 10; i++ {
+		line := fmt.Sprintf("line %02d xxxxxxxxxx", i)
+		want = append(want, line)
+		lc.Send(line)
+		clock.Advance(time.Millisecond)
+	}
+	lc.Close()
+
+	backups, err := event.Backups(path)
+	if err != nil || len(backups) == 0 {
+		t.Fatalf("expected rotated files, got %v (%v)", backups, err)
+	}
+	var got string
+	for _, file := range append(backups, path) {
+		content := readLogFile(t, file)
+		if len(content) > 64 {
+			t.Errorf("%s is %d bytes, over the limit", file, len(content))
+		}
+		got += content
+	}
+	if got != strings.Join(want, "\n")+"\n" {
+		t.Errorf("lines lost or reordered across rotations:\n%s", got)
+	}
+}
+
+func TestRotation_CompressAndRetention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	clock := &testClock{now: time.Date(2026, 1, 1, 10, 0, 0, 0, time.Local)}
+
+	stale := filepath.Join(dir, "app-2025-01-01T00-00-00.000.log.gz")
+	os.WriteFile(stale, nil, 0644)
+	old := clock.Now().Add(-48 * time.Hour)
+	os.Chtimes(stale, old, old)
+
+	lc, err := event.NewLogChannelWithOptions(path, event.Options{
+		BufferSize: 100,
+		Rotation: event.RotateOptions{
+			MaxSize: 16, Compress: true, MaxBackups: 2, MaxAge: 24 * time.Hour, Now: clock.Now,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LogChannel: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		lc.Send(fmt.Sprintf("message %d xxx", i))
+		clock.Advance(time.Second)
+	}
+	lc.Close()
+
+	backups, _ := event.Backups(path)
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups to be kept, got %v", backups)
+	}
+	for _, backup := range backups {
+		if !strings.HasSuffix(backup, ".gz") {
+			t.Errorf("expected %s to be compressed", backup)
+		}
+	}
+	if got := readLogFile(t, backups[1]); got != "message 3 xxx\n" {
+		t.Errorf("unexpected newest backup content %q", got)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected backup older than max age to be removed")
+	}
+}
+
+func TestRotation_Daily(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	clock := &testClock{now: time.Date(2026, 1, 1, 23, 59, 0, 0, time.Local)}
+
+	lc, err := event.NewLogChannelWithOptions(path, event.Options{
+		BufferSize: 100,
+		Rotation:   event.RotateOptions{Daily: true, Now: clock.Now},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LogChannel: %v", err)
+	}
+	lc.Send("yesterday")
+	lc.Send("still yesterday")
+	waitForContent(t, path, "yesterday\nstill yesterday\n")
+	clock.Advance(2 * time.Minute)
+	lc.Send("today")
+	lc.Close()
+
+	backups, _ := event.Backups(path)
+	if len(backups) != 1 {
+		t.Fatalf("expected one daily backup, got %v", backups)
+	}
+	if want := filepath.Join(filepath.Dir(path), "app-2026-01-01.log"); backups[0] != want {
+		t.Errorf("expected the backup named after the day it holds, got %s", backups[0])
+	}
+	if got := readLogFile(t, backups[0]); got != "yesterday\nstill yesterday\n" {
+		t.Errorf("unexpected backup content %q", got)
+	}
+	if got := readLogFile(t, path); got != "today\n" {
+		t.Errorf("unexpected current content %q", got)
+	}
+}
+
+func TestRotation_ReopenAfterExternalMove(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	lc, err := event.NewLogChannel(path, 10)
+	if err != nil {
+		t.Fatalf("failed to create LogChannel: %v", err)
+	}
+	lc.Send("before")
+	waitForContent(t, path, "before\n")
+
+	moved := filepath.Join(dir, "app.log.1")
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Reopen(); err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	lc.Send("after")
+	lc.Close()
+
+	if got := readLogFile(t, moved); got != "before\n" {
+		t.Errorf("unexpected moved content %q", got)
+	}
+	if got := readLogFile(t, path); got != "after\n" {
+		t.Errorf("unexpected reopened content %q", got)
+	}
+	if err := lc.Reopen(); err == nil {
+		t.Errorf("expected reopen after close to fail")
+	}
+}
+
+func TestRotation_BackupsMatchOnlyRotatedNames(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	for _, name := range []string{
+		"app-2026-01-02T08-00-00.000.log",
+		"app-2026-01-01.log.gz",
+		"app-2026-01-01T08-00-00.000.log.gz",
+		"app-2026-01-02.log",
+		"app-foo.log",
+		"app-server.log",
+		"app-2026-01-01.log.bak",
+		"app-2026-01-01T08-00-00.log",
+		"app.log",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := event.Backups(path)
+	if err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	var names []string
+	for _, backup := range backups {
+		names = append(names, filepath.Base(backup))
+	}
+	want := []string{
+		"app-2026-01-01T08-00-00.000.log.gz",
+		"app-2026-01-01.log.gz",
+		"app-2026-01-02T08-00-00.000.log",
+		"app-2026-01-02.log",
+	}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("expected backups %v, got %v", want, names)
+	}
+}
+
+func TestRotation_FailedReopenKeepsLogging(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	lc, err := event.NewLogChannel(path, 10)
+	if err != nil {
+		t.Fatalf("failed to create LogChannel: %v", err)
+	}
+	lc.Send("before")
+	waitForContent(t, path, "before\n")
+
+	moved := filepath.Join(dir, "app.log.1")
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Reopen(); err == nil {
+		t.Fatalf("expected reopen over a directory to fail")
+	}
+	lc.Send("after")
+	lc.Close()
+
+	if got := readLogFile(t, moved); got != "before\nafter\n" {
+		t.Errorf("expected logging to carry on in the old file, got %q", got)
+	}
+}
+
+func waitForContent(t *testing.T, path, want string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		data, _ := os.ReadFile(path)
+		if string(data) == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %q in %s, got %q", want, path, data)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
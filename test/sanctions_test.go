@@ -0,0 +1,335 @@
+package test
+
+import (
+	"context"
+	"corebanking/config"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/sanctions"
+	"corebanking/internal/service"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sanctionsCaseEnvelope struct {
+	Data  dto.SanctionsCaseV2Response `json:"data"`
+	Error *dto.EnvelopeError          `json:"error"`
+}
+
+var testSanctionsPolicy = sanctions.Policy{Enabled: true, NameThreshold: 0.9, PEPThreshold: 0.95}
+
+const testSanctionsList = `{"version": "2026-03-10", "entries": [
+	{"id": "SAN-1", "name": "José da Silva Santos", "aliases": ["Zé Santos"], "list": "sanctions", "program": "OFAC-SDN"},
+	{"id": "SAN-2", "name": "Northwind Trading", "documents": ["12.345.678/0001-90"], "list": "sanctions"},
+	{"id": "PEP-1", "name": "Ana Maria Costa", "list": "pep", "program": "Minister"}
+]}`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSanctions_ListsAndMatching(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	jsonPath := filepath.Join(dir, "list.json")
+	writeFile(t, jsonPath, testSanctionsList)
+	list, err := sanctions.Load(jsonPath, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Version != "2026-03-10" || len(list.Digest) != 64 || len(list.Entries) != 3 || list.Entries[0].Aliases[0] != "Zé Santos" {
+		t.Fatalf("unexpected json list %+v", list)
+	}
+
+	csvPath := filepath.Join(dir, "list.csv")
+	writeFile(t, csvPath, "id,name,aliases,documents,list,program\nSAN-1,José da Silva Santos,Zé Santos;J. Santos,,sanctions,OFAC-SDN\nPEP-1,Ana Maria Costa,,987.654.321-00,pep,\n")
+	csvList, err := sanctions.Load(csvPath, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if csvList.Version != csvList.Digest[:12] || len(csvList.Entries) != 2 || len(csvList.Entries[0].Aliases) != 2 || csvList.Entries[1].Documents[0] != "987.654.321-00" {
+		t.Fatalf("unexpected csv list %+v", csvList)
+	}
+
+	broken := filepath.Join(dir, "broken.json")
+	writeFile(t, broken, `[{"id": "X-1", "name": "A", "list": "watch"}, {"id": "X-1", "name": "B", "list": "pep"}, {"id": "X-2", "list": "pep"}]`)
+	if _, err := sanctions.Load(broken, now); err == nil || !strings.Contains(err.Error(), "list must be") || !strings.Contains(err.Error(), "duplicate id") || !strings.Contains(err.Error(), "needs a name or a document") {
+		t.Errorf("expected every bad entry to be reported, got %v", err)
+	}
+	if _, err := sanctions.Load(filepath.Join(dir, "list.txt"), now); err == nil {
+		t.Error("expected an unknown file type to be rejected")
+	}
+
+	screener := sanctions.NewScreener(list, testSanctionsPolicy)
+	tests := []struct {
+		name    string
+		subject sanctions.Subject
+		entry   string
+		match   string
+	}{
+		{"exact name without accents", sanctions.Subject{Name: "JOSE DA SILVA SANTOS"}, "SAN-1", domain.MatchName},
+		{"words reordered", sanctions.Subject{Name: "Santos, José da Silva"}, "SAN-1", domain.MatchName},
+		{"misspelt", sanctions.Subject{Name: "Jose da Silva Santoz"}, "SAN-1", domain.MatchName},
+		{"alias", sanctions.Subject{Name: "Ze Santos"}, "SAN-1", domain.MatchName},
+		{"document with other punctuation", sanctions.Subject{Name: "NW Trading Ltd", Document: "12345678000190"}, "SAN-2", domain.MatchDocument},
+		{"PEP spelt alike", sanctions.Subject{Name: "Ana Maria Kosta"}, "PEP-1", domain.MatchName},
+		{"below the PEP threshold", sanctions.Subject{Name: "Ana Costa"}, "", ""},
+		{"unrelated", sanctions.Subject{Name: "Maria Oliveira", Document: "111"}, "", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, enabled := screener.Screen(tc.subject)
+			if !enabled || result.Version != "2026-03-10" || result.Digest != list.Digest {
+				t.Fatalf("expected a screening against the loaded list, got %+v", result)
+			}
+			if tc.entry == "" {
+				if len(result.Hits) != 0 {
+					t.Errorf("expected no hits, got %+v", result.Hits)
+				}
+				return
+			}
+			if len(result.Hits) != 1 || result.Hits[0].EntryID != tc.entry || result.Hits[0].Match != tc.match {
+				t.Errorf("expected a %s hit on %s, got %+v", tc.match, tc.entry, result.Hits)
+			}
+		})
+	}
+
+	screener.SetPolicy(sanctions.Policy{Enabled: true, NameThreshold: 0.9, PEPThreshold: 0.9})
+	if result, _ := screener.Screen(sanctions.Subject{Name: "Ana Costa"}); len(result.Hits) != 1 {
+		t.Errorf("expected a lower PEP threshold to match, got %+v", result.Hits)
+	}
+	screener.SetPolicy(sanctions.Policy{})
+	if _, enabled := screener.Screen(sanctions.Subject{Name: "José da Silva Santos"}); enabled {
+		t.Error("expected the zero policy to screen nothing")
+	}
+}
+
+func TestSanctions_OnboardingAndTransferHolds(t *testing.T) {
+	app := newTestApp()
+	path := filepath.Join(t.TempDir(), "list.json")
+	writeFile(t, path, testSanctionsList)
+	app.sanctionsService.SetPolicy(testSanctionsPolicy)
+	if _, err := app.sanctionsService.LoadList(context.Background(), path); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := app.do(t, http.MethodPost, "/api/v2/accounts", map[string]any{"documentNumber": "111", "holderName": "Maria Oliveira"}, nil)
+	var account struct {
+		Data dto.AccountV2Response `json:"data"`
+	}
+	decodeBody(t, resp, &account)
+	if resp.Code != http.StatusCreated || account.Data.HolderName != "Maria Oliveira" {
+		t.Fatalf("expected a clear onboarding to open the account, got %d %+v", resp.Code, account.Data)
+	}
+	origin := account.Data.AccountID
+	if _, err := app.transactionService.CreateTransaction(context.Background(), &dto.TransactionRequest{AccountID: origin, OperationTypeID: 4, Amount: 100000}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v2/accounts", map[string]any{"documentNumber": "222", "holderName": "Jose da Silva Santos"}, nil)
+	var held sanctionsCaseEnvelope
+	decodeBody(t, resp, &held)
+	if resp.Code != http.StatusAccepted || held.Error == nil || held.Error.Code != "sanctions_review" {
+		t.Fatalf("expected a listed name to hold the onboarding, got %d %+v", resp.Code, held.Error)
+	}
+	var list struct {
+		Data []dto.SanctionsCaseV2Response `json:"data"`
+	}
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/sanctions/cases", nil, nil), &list)
+	if len(list.Data) != 1 || list.Data[0].Type != domain.ScreeningOnboarding || list.Data[0].AccountID != "" || list.Data[0].Hits[0].EntryID != "SAN-1" || list.Data[0].ListVersion != "2026-03-10" {
+		t.Fatalf("expected one pending onboarding case, got %+v", list.Data)
+	}
+	onboarding := list.Data[0]
+	if !strings.Contains(held.Error.Details, onboarding.ID) {
+		t.Errorf("expected the error to name case %s, got %q", onboarding.ID, held.Error.Details)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v2/sanctions/cases/"+onboarding.ID+"/approve", map[string]any{"note": "different date of birth"}, map[string]string{
+		"X-Test-Subject": "compliance", "X-Test-Roles": "admin",
+	})
+	decodeBody(t, resp, &held)
+	if resp.Code != http.StatusOK || held.Data.Status != domain.SanctionsCaseApproved || held.Data.AccountID == "" || held.Data.DecidedBy != "compliance" {
+		t.Fatalf("unexpected approval %d %+v", resp.Code, held)
+	}
+	destination := held.Data.AccountID
+	if opened, err := app.accountService.GetAccount(context.Background(), destination); err != nil || opened.DocumentNumber != "222" || opened.HolderName != "Jose da Silva Santos" {
+		t.Fatalf("expected the approval to open the account, got %+v %v", opened, err)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/v2/sanctions/cases/"+onboarding.ID+"/reject", map[string]any{}, nil); resp.Code != http.StatusConflict {
+		t.Errorf("expected a decided case to stay decided, got %d", resp.Code)
+	}
+
+	// The cleared holder receives transfers; a new list version adds a
+	// name that matches them under another entry.
+	if err := transfer(app, origin, destination, 1000); err != nil {
+		t.Fatalf("expected a cleared holder to pass, got %v", err)
+	}
+	writeFile(t, path, strings.Replace(testSanctionsList, `"2026-03-10", "entries": [`, `"2026-03-11", "entries": [
+	{"id": "SAN-3", "name": "José Santos da Silva", "list": "sanctions"},`, 1))
+	resp = app.do(t, http.MethodPost, "/api/v2/sanctions/list/reload", nil, nil)
+	var info struct {
+		Data dto.SanctionsListV2Response `json:"data"`
+	}
+	decodeBody(t, resp, &info)
+	if resp.Code != http.StatusOK || info.Data.Version != "2026-03-11" || info.Data.Entries != 4 {
+		t.Fatalf("expected the new list version, got %d %+v", resp.Code, info.Data)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "transfer", "origin": origin, "destination": destination, "amount": "50.00"}, nil)
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("expected the new entry to hold the transfer, got %d", resp.Code)
+	}
+	cases := app.sanctionsService.Cases(context.Background(), domain.SanctionsCasePending)
+	if len(cases) != 1 || cases[0].Kind != domain.ScreeningTransfer || cases[0].AccountID != destination || cases[0].Origin != origin || cases[0].Amount != 5000 || cases[0].ListVersion != "2026-03-11" {
+		t.Fatalf("expected a pending transfer case, got %+v", cases)
+	}
+	if hits := cases[0].Hits; len(hits) != 2 || hits[0].EntryID != "SAN-1" || !hits[0].Cleared || hits[1].EntryID != "SAN-3" || hits[1].Cleared {
+		t.Errorf("expected the cleared and the new hit, got %+v", hits)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/v2/sanctions/cases/"+cases[0].ID+"/reject", map[string]any{}, nil); resp.Code != http.StatusOK {
+		t.Fatalf("expected the rejection to pass, got %d", resp.Code)
+	}
+	if balance, _ := app.accountService.GetBalance(context.Background(), destination); balance.Balance != 1000 {
+		t.Fatalf("expected a rejected transfer not to be posted, got %d", balance.Balance)
+	}
+
+	if err := transfer(app, origin, destination, 5000); !errors.Is(err, service.ErrSanctionsReview) {
+		t.Fatalf("expected the transfer to be held again, got %v", err)
+	}
+	cases = app.sanctionsService.Cases(context.Background(), domain.SanctionsCasePending)
+	if _, err := app.sanctionsService.Approve(context.Background(), cases[0].ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := transfer(app, origin, destination, 4000); err != nil {
+		t.Fatalf("expected both entries to be cleared, got %v", err)
+	}
+	if balance, _ := app.accountService.GetBalance(context.Background(), destination); balance.Balance != 10000 {
+		t.Errorf("expected the approved and the later transfer to be posted, got %d", balance.Balance)
+	}
+
+	var screenings struct {
+		Data []dto.SanctionsScreeningV2Response `json:"data"`
+	}
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/sanctions/screenings?accountId="+destination, nil, nil), &screenings)
+	versions := make([]string, 0, len(screenings.Data))
+	holds := 0
+	for _, screening := range screenings.Data {
+		versions = append(versions, screening.ListVersion)
+		if screening.CaseID != "" {
+			holds++
+		}
+	}
+	if !slices.Equal(versions, []string{"2026-03-10", "2026-03-10", "2026-03-11", "2026-03-11", "2026-03-11", "2026-03-11"}) || holds != 2 {
+		t.Errorf("expected every screening with its list version, got %v with %d held", versions, holds)
+	}
+	decodeBody(t, app.do(t, http.MethodGet, "/api/v2/sanctions/screenings?documentNumber=222", nil, nil), &screenings)
+	if len(screenings.Data) != 7 || screenings.Data[0].CaseID != onboarding.ID {
+		t.Errorf("expected the held onboarding under the document too, got %d", len(screenings.Data))
+	}
+
+	if resp := app.do(t, http.MethodGet, "/api/v2/sanctions/cases/unknown", nil, nil); resp.Code != http.StatusNotFound {
+		t.Errorf("expected an unknown case to be missing, got %d", resp.Code)
+	}
+	if resp := app.do(t, http.MethodGet, "/api/v2/sanctions/cases?status=open", nil, nil); resp.Code != http.StatusBadRequest {
+		t.Errorf("expected an unknown status to be rejected, got %d", resp.Code)
+	}
+	entries := app.auditService.Query(context.Background(), "", "sanctions_list", "", time.Time{}, time.Time{})
+	if len(entries) != 2 || entries[1].EntityID != "2026-03-11" {
+		t.Errorf("expected both list loads to be audited, got %+v", entries)
+	}
+}
+
+func TestSanctions_V1HoldIsAcceptedWithTheCase(t *testing.T) {
+	app := newTestApp()
+	origin := fundedAccount(t, app, "1")
+	listed, err := app.accountService.CreateAccount(context.Background(), "2", "José da Silva Santos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "list.json")
+	writeFile(t, path, testSanctionsList)
+	app.sanctionsService.SetPolicy(testSanctionsPolicy)
+	if _, err := app.sanctionsService.LoadList(context.Background(), path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, request := range []struct {
+		path string
+		body map[string]any
+	}{
+		{"/api/v1/transactions/event", map[string]any{"type": "transfer", "origin": origin, "destination": listed.AccountID, "amount": 500}},
+		{"/api/v1/accounts", map[string]any{"documentNumber": "3", "holderName": "Jose da Silva Santos"}},
+	} {
+		resp := app.do(t, http.MethodPost, request.path, request.body, nil)
+		var held dto.HoldResponse
+		decodeBody(t, resp, &held)
+		if resp.Code != http.StatusAccepted || held.Status != "sanctions_review" {
+			t.Fatalf("expected %s to be held for review, got %d %+v", request.path, resp.Code, held)
+		}
+		if sanctionsCase, err := app.sanctionsService.Case(context.Background(), held.HoldID); err != nil || sanctionsCase.Status != domain.SanctionsCasePending {
+			t.Errorf("expected the response to name the pending case, got %+v %v", sanctionsCase, err)
+		}
+	}
+	if got := []int64{balance(t, app, origin), balance(t, app, listed.AccountID)}; !slices.Equal(got, []int64{100000, 0}) {
+		t.Errorf("expected a held transfer not to be posted, got %v", got)
+	}
+}
+
+func TestSanctions_ConfigAndReload(t *testing.T) {
+	env := envMap(map[string]string{"SANCTIONS_NAME_THRESHOLD": "0", "SANCTIONS_PEP_THRESHOLD": "1.5"})
+	_, err := config.NewLoaderWithEnv(nil, env).Load()
+	if err == nil || !strings.Contains(err.Error(), "sanctions.name_threshold:") || !strings.Contains(err.Error(), "sanctions.pep_threshold:") {
+		t.Fatalf("expected both thresholds to be reported, got %v", err)
+	}
+
+	current, err := config.NewLoaderWithEnv(nil, envMap(nil)).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy := current.SanctionsPolicy(); policy != testSanctionsPolicy {
+		t.Fatalf("expected the default policy, got %+v", policy)
+	}
+	app := newTestApp()
+	app.sanctionsService.SetPolicy(current.SanctionsPolicy())
+	if list, err := app.sanctionsService.LoadList(context.Background(), current.SanctionsListPath); err != nil || list.Version != "none" {
+		t.Fatalf("expected no list by default, got %+v %v", list, err)
+	}
+	if _, err := app.accountService.CreateAccount(context.Background(), "1", "José da Silva Santos"); err != nil {
+		t.Fatalf("expected everybody to screen clear without a list, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "list.json")
+	writeFile(t, path, testSanctionsList)
+	next, _ := config.NewLoaderWithEnv(nil, envMap(map[string]string{"SANCTIONS_LIST_PATH": path, "SANCTIONS_NAME_THRESHOLD": "0.99"})).Load()
+	merged, applied, _ := config.Reload(current, next)
+	if !slices.Contains(applied, "sanctions.list_path") || !slices.Contains(applied, "sanctions.name_threshold") {
+		t.Fatalf("expected sanctions settings to reload, applied %v", applied)
+	}
+	app.sanctionsService.SetPolicy(merged.SanctionsPolicy())
+	if _, err := app.sanctionsService.LoadList(context.Background(), merged.SanctionsListPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.accountService.CreateAccount(context.Background(), "2", "Jose da Silva Santoz"); err != nil {
+		t.Errorf("expected a misspelling to pass the stricter threshold, got %v", err)
+	}
+	if _, err := app.accountService.CreateAccount(context.Background(), "3", "Jose da Silva Santos"); !errors.Is(err, service.ErrSanctionsReview) {
+		t.Errorf("expected the exact name to be held, got %v", err)
+	}
+
+	writeFile(t, path, `{"entries": [{"id": "X", "list": "watch"}]}`)
+	if _, err := app.sanctionsService.ReloadList(context.Background()); err == nil {
+		t.Fatal("expected a broken list to fail to load")
+	}
+	if list := app.sanctionsService.List(); list.Version != "2026-03-10" {
+		t.Errorf("expected the running list to be kept, got %s", list.Version)
+	}
+}
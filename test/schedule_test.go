@@ -0,0 +1,402 @@
+package test
+
+import (
+	"context"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/repository"
+	"corebanking/internal/service"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type scheduleEnvelope struct {
+	Data  dto.ScheduleV2Response `json:"data"`
+	Error *dto.EnvelopeError     `json:"error"`
+}
+
+var testSchedulePolicy = service.SchedulePolicy{RetryAttempts: 2, RetryInterval: time.Hour, Lease: 5 * time.Minute}
+
+func createSchedule(t *testing.T, app *testApp, origin string, body map[string]any) dto.ScheduleV2Response {
+	t.Helper()
+	resp := app.do(t, http.MethodPost, "/api/v2/accounts/"+origin+"/schedules", body, nil)
+	var created scheduleEnvelope
+	decodeBody(t, resp, &created)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected the schedule created, got %d %+v", resp.Code, created.Error)
+	}
+	return created.Data
+}
+
+// runDue runs the due schedules and returns the statuses of the executions
+// recorded.
+func runDue(t *testing.T, app *testApp) []string {
+	t.Helper()
+	executions, err := app.scheduleService.RunDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make([]string, 0, len(executions))
+	for _, execution := range executions {
+		statuses = append(statuses, execution.Status)
+	}
+	return statuses
+}
+
+func TestSchedule_Occurrences(t *testing.T) {
+	start := time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		frequency string
+		want      []time.Time
+	}{
+		{domain.ScheduleDaily, []time.Time{start, start.AddDate(0, 0, 1), start.AddDate(0, 0, 2)}},
+		{domain.ScheduleWeekly, []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)}},
+		{domain.ScheduleMonthly, []time.Time{
+			start,
+			time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC),
+			time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC),
+			time.Date(2026, time.April, 30, 9, 0, 0, 0, time.UTC),
+		}},
+	} {
+		schedule := &domain.Schedule{Frequency: tc.frequency, StartAt: start}
+		for n, want := range tc.want {
+			if got := schedule.Occurrence(n); !got.Equal(want) {
+				t.Errorf("%s occurrence %d: expected %s, got %s", tc.frequency, n, want, got)
+			}
+		}
+	}
+
+	end := start.AddDate(0, 0, 1)
+	schedule := &domain.Schedule{Status: domain.ScheduleActive, Frequency: domain.ScheduleDaily, StartAt: start, EndAt: &end}
+	schedule.Advance(true)
+	if schedule.Status != domain.ScheduleActive || !schedule.NextRunAt.Equal(end) {
+		t.Fatalf("expected a run left on the end date, got %+v", schedule)
+	}
+	schedule.Advance(true)
+	if schedule.Status != domain.ScheduleCompleted || schedule.NextRunAt != nil || schedule.Executed != 2 {
+		t.Errorf("expected the schedule completed after its end date, got %+v", schedule)
+	}
+}
+
+func TestSchedule_StandingOrderRunsUntilCount(t *testing.T) {
+	app := newTestApp()
+	origin := fundedAccount(t, app, "1")
+	destination := app.createAccount(t, "2")
+
+	for _, tc := range []struct {
+		body map[string]any
+		want int
+	}{
+		{map[string]any{"destination": destination, "amount": "100.00", "frequency": "yearly"}, http.StatusBadRequest},
+		{map[string]any{"destination": destination, "amount": "100.00", "frequency": "once", "startAt": "2026-03-01T00:00:00Z"}, http.StatusBadRequest},
+		{map[string]any{"destination": destination, "amount": "100.00", "frequency": "once", "count": 2}, http.StatusBadRequest},
+		{map[string]any{"destination": "typo", "amount": "100.00", "frequency": "daily"}, http.StatusNotFound},
+	} {
+		if resp := app.do(t, http.MethodPost, "/api/v2/accounts/"+origin+"/schedules", tc.body, nil); resp.Code != tc.want {
+			t.Errorf("expected %v to get %d, got %d", tc.body, tc.want, resp.Code)
+		}
+	}
+
+	created := createSchedule(t, app, origin, map[string]any{
+		"destination": destination, "amount": "100.00", "frequency": "monthly", "startAt": "2026-03-31T09:00:00Z", "count": 2,
+	})
+	if created.Status != domain.ScheduleActive || created.NextRunAt == nil || !created.NextRunAt.Equal(time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the first run on the start date, got %+v", created)
+	}
+	if got := runDue(t, app); len(got) != 0 {
+		t.Fatalf("expected nothing due yet, got %v", got)
+	}
+
+	app.clock.Advance(21*24*time.Hour - 3*time.Hour)
+	if got := runDue(t, app); len(got) != 1 || got[0] != domain.ExecutionPosted {
+		t.Fatalf("expected the first transfer posted, got %v", got)
+	}
+	if got := runDue(t, app); len(got) != 0 {
+		t.Fatalf("expected the occurrence to run once, got %v", got)
+	}
+	app.clock.Advance(30 * 24 * time.Hour)
+	if got := runDue(t, app); len(got) != 1 || got[0] != domain.ExecutionPosted {
+		t.Fatalf("expected the second transfer posted, got %v", got)
+	}
+	if got := balance(t, app, destination); got != 20000 {
+		t.Errorf("expected two transfers, got %d", got)
+	}
+
+	resp := app.do(t, http.MethodGet, "/api/v2/accounts/"+origin+"/schedules/"+created.ID, nil, nil)
+	var finished scheduleEnvelope
+	decodeBody(t, resp, &finished)
+	if finished.Data.Status != domain.ScheduleCompleted || finished.Data.Executed != 2 || finished.Data.NextRunAt != nil {
+		t.Errorf("expected the schedule completed, got %+v", finished.Data)
+	}
+	resp = app.do(t, http.MethodGet, "/api/v2/accounts/"+origin+"/schedules/"+created.ID+"/executions", nil, nil)
+	var executions struct {
+		Data []dto.ScheduleExecutionV2Response `json:"data"`
+	}
+	decodeBody(t, resp, &executions)
+	if len(executions.Data) != 2 || executions.Data[1].Occurrence != 1 || !executions.Data[1].DueAt.Equal(time.Date(2026, time.April, 30, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected both executions recorded, got %+v", executions.Data)
+	}
+	if entries := app.auditService.Query(context.Background(), "scheduler", "account", destination, time.Time{}, time.Time{}); len(entries) != 2 {
+		t.Errorf("expected the transfers audited as the scheduler, got %d entries", len(entries))
+	}
+}
+
+func TestSchedule_RetriesInsufficientFunds(t *testing.T) {
+	app := newTestApp()
+	origin := app.createAccount(t, "1")
+	destination := app.createAccount(t, "2")
+
+	once := createSchedule(t, app, origin, map[string]any{"destination": destination, "amount": "50.00", "frequency": "once"})
+	for _, want := range []string{domain.ExecutionRetrying, domain.ExecutionRetrying, domain.ExecutionFailed} {
+		if got := runDue(t, app); len(got) != 1 || got[0] != want {
+			t.Fatalf("expected %s, got %v", want, got)
+		}
+		if got := runDue(t, app); len(got) != 0 {
+			t.Fatalf("expected the retry to wait, got %v", got)
+		}
+		app.clock.Advance(time.Hour)
+	}
+	failed, err := app.scheduleService.Schedule(context.Background(), origin, once.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed.Status != domain.ScheduleCompleted || failed.Executed != 1 {
+		t.Errorf("expected the one-off transfer given up on, got %+v", failed)
+	}
+
+	daily := createSchedule(t, app, origin, map[string]any{"destination": destination, "amount": "50.00", "frequency": "daily"})
+	if got := runDue(t, app); len(got) != 1 || got[0] != domain.ExecutionRetrying {
+		t.Fatalf("expected a retry, got %v", got)
+	}
+	cashDeposit(t, app, origin, 5000)
+	app.clock.Advance(time.Hour)
+	if got := runDue(t, app); len(got) != 1 || got[0] != domain.ExecutionPosted {
+		t.Fatalf("expected the retry posted, got %v", got)
+	}
+	posted, _ := app.scheduleService.Schedule(context.Background(), origin, daily.ID)
+	if !posted.NextRunAt.Equal(daily.StartAt.AddDate(0, 0, 1)) || posted.Attempts != 0 {
+		t.Errorf("expected the next run a day after the due time, got %+v", posted)
+	}
+	executions, _ := app.scheduleService.Executions(context.Background(), origin, daily.ID)
+	if len(executions) != 2 || executions[1].Attempt != 2 || executions[0].Error != service.ErrInsufficientOverdraft.Error() {
+		t.Errorf("expected both attempts recorded, got %+v", executions)
+	}
+}
+
+func TestSchedule_PauseResumeAndCancel(t *testing.T) {
+	app := newTestApp()
+	origin := fundedAccount(t, app, "1")
+	destination := app.createAccount(t, "2")
+	customer := map[string]string{"X-Test-Subject": "1", "X-Test-Roles": "customer"}
+	path := "/api/v2/accounts/" + origin + "/schedules/"
+
+	resp := app.do(t, http.MethodPost, "/api/v2/accounts/"+origin+"/schedules", map[string]any{"destination": destination, "amount": "10.00", "frequency": "daily"}, customer)
+	var created scheduleEnvelope
+	decodeBody(t, resp, &created)
+	if resp.Code != http.StatusCreated || created.Data.CreatedBy != "1" {
+		t.Fatalf("expected the customer to schedule from their account, got %d %+v", resp.Code, created)
+	}
+
+	resp = app.do(t, http.MethodPost, path+created.Data.ID+"/pause", nil, customer)
+	var paused scheduleEnvelope
+	decodeBody(t, resp, &paused)
+	if resp.Code != http.StatusOK || paused.Data.Status != domain.SchedulePaused {
+		t.Fatalf("expected the schedule paused, got %d %+v", resp.Code, paused)
+	}
+	app.clock.Advance(49 * time.Hour)
+	if got := runDue(t, app); len(got) != 0 {
+		t.Fatalf("expected a paused schedule not to run, got %v", got)
+	}
+
+	resp = app.do(t, http.MethodPost, path+created.Data.ID+"/resume", nil, customer)
+	var resumed scheduleEnvelope
+	decodeBody(t, resp, &resumed)
+	if resumed.Data.Status != domain.ScheduleActive || resumed.Data.Executed != 0 || !resumed.Data.NextRunAt.Equal(created.Data.StartAt.AddDate(0, 0, 3)) {
+		t.Fatalf("expected the missed occurrences skipped, got %+v", resumed.Data)
+	}
+
+	resp = app.do(t, http.MethodPost, path+created.Data.ID+"/cancel", nil, customer)
+	var cancelled scheduleEnvelope
+	decodeBody(t, resp, &cancelled)
+	if cancelled.Data.Status != domain.ScheduleCancelled || cancelled.Data.NextRunAt != nil {
+		t.Fatalf("expected the schedule cancelled, got %+v", cancelled.Data)
+	}
+	if resp := app.do(t, http.MethodPost, path+created.Data.ID+"/resume", nil, customer); resp.Code != http.StatusConflict {
+		t.Errorf("expected a cancelled schedule not to resume, got %d", resp.Code)
+	}
+	if resp := app.do(t, http.MethodGet, "/api/v2/accounts/"+destination+"/schedules/"+created.Data.ID, nil, nil); resp.Code != http.StatusNotFound {
+		t.Errorf("expected the schedule only under its origin, got %d", resp.Code)
+	}
+	app.clock.Advance(24 * time.Hour)
+	if got := runDue(t, app); len(got) != 0 || balance(t, app, destination) != 0 {
+		t.Errorf("expected a cancelled schedule not to run, got %v", got)
+	}
+
+	var actions []string
+	for _, entry := range app.auditService.Query(context.Background(), "", "schedule", created.Data.ID, time.Time{}, time.Time{}) {
+		actions = append(actions, entry.Action)
+	}
+	want := []string{service.AuditScheduleCreate, service.AuditSchedulePause, service.AuditScheduleResume, service.AuditScheduleCancel}
+	if !slices.Equal(actions, want) {
+		t.Errorf("expected audit %v, got %v", want, actions)
+	}
+}
+
+func TestSchedule_SharedFileRunsEachOccurrenceOnce(t *testing.T) {
+	app := newTestApp()
+	origin := fundedAccount(t, app, "1")
+	destination := app.createAccount(t, "2")
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	instance := func(name string) (*service.ScheduleService, *repository.ScheduleRepository) {
+		repo, err := repository.NewFileScheduleRepository(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return service.NewScheduleServiceWithClock(repo, app.accountRepo, app.transactionService, app.auditService, name, testSchedulePolicy, app.clock.Now), repo
+	}
+	a, _ := instance("a")
+	b, repo := instance("b")
+	ctx := context.Background()
+
+	once, err := a.Create(ctx, &domain.Schedule{Origin: origin, Destination: destination, Amount: 1000, Frequency: domain.ScheduleOnce})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for _, scheduler := range []*service.ScheduleService{a, b, a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := scheduler.RunDue(ctx); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := balance(t, app, destination); got != 1000 {
+		t.Fatalf("expected one transfer, got %d", got)
+	}
+
+	restarted, _ := instance("a")
+	executions, err := restarted.Executions(ctx, origin, once.ID)
+	if err != nil || len(executions) != 1 || executions[0].Status != domain.ExecutionPosted {
+		t.Fatalf("expected the execution kept across restarts, got %+v %v", executions, err)
+	}
+
+	daily, err := a.Create(ctx, &domain.Schedule{Origin: origin, Destination: destination, Amount: 1000, Frequency: domain.ScheduleDaily})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimedUntil := app.clock.Now().Add(testSchedulePolicy.Lease)
+	repo.Update(ctx, daily.ID, func(schedule *domain.Schedule) error {
+		schedule.ClaimedBy = "gone"
+		schedule.ClaimedUntil = &claimedUntil
+		return nil
+	})
+	if executions, _ := b.RunDue(ctx); len(executions) != 0 {
+		t.Fatalf("expected a claimed occurrence left to its instance, got %+v", executions)
+	}
+	app.clock.Advance(testSchedulePolicy.Lease + time.Second)
+	executions, _ = b.RunDue(ctx)
+	if len(executions) != 1 || executions[0].Status != domain.ExecutionInterrupted {
+		t.Fatalf("expected the occurrence interrupted, got %+v", executions)
+	}
+	interrupted, _ := restarted.Schedule(ctx, origin, daily.ID)
+	if interrupted.ClaimedBy != "" || interrupted.Executed != 1 || !interrupted.NextRunAt.Equal(daily.StartAt.AddDate(0, 0, 1)) {
+		t.Errorf("expected the interrupted occurrence not to run again, got %+v", interrupted)
+	}
+	if got := balance(t, app, destination); got != 1000 {
+		t.Errorf("expected no second transfer, got %d", got)
+	}
+}
+
+func TestSchedule_ExecutionsExpire(t *testing.T) {
+	app := newTestApp()
+	origin := fundedAccount(t, app, "1")
+	destination := app.createAccount(t, "2")
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	repo, err := repository.NewFileScheduleRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := testSchedulePolicy
+	policy.ExecutionRetention = 48 * time.Hour
+	scheduler := service.NewScheduleServiceWithClock(repo, app.accountRepo, app.transactionService, app.auditService, "a", policy, app.clock.Now)
+	ctx := context.Background()
+
+	daily, err := scheduler.Create(ctx, &domain.Schedule{Origin: origin, Destination: destination, Amount: 1000, Frequency: domain.ScheduleDaily})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := app.clock.Now()
+	for day := range 4 {
+		if day > 0 {
+			app.clock.Advance(24 * time.Hour)
+		}
+		if executions, err := scheduler.RunDue(ctx); err != nil || len(executions) != 1 {
+			t.Fatalf("day %d: expected one execution, got %+v %v", day, executions, err)
+		}
+	}
+
+	restarted, err := repository.NewFileScheduleRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executions, err := restarted.Executions(ctx, daily.ID)
+	if err != nil || len(executions) != 3 {
+		t.Fatalf("expected the executions of the last 48h kept, got %+v %v", executions, err)
+	}
+	if !executions[0].ExecutedAt.Equal(first.Add(24 * time.Hour)) {
+		t.Errorf("expected the oldest execution dropped, got %+v", executions[0])
+	}
+	if got := balance(t, app, destination); got != 4000 {
+		t.Errorf("expected every occurrence paid, got %d", got)
+	}
+}
+
+func TestSchedule_FileLockWaitsForItsHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	holder, err := repository.NewFileScheduleRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiter, err := repository.NewFileScheduleRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := holder.Save(ctx, &domain.Schedule{ID: "s1", Amount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+
+	held, release, done := make(chan struct{}), make(chan struct{}), make(chan error)
+	go func() {
+		_, err := holder.Update(ctx, "s1", func(schedule *domain.Schedule) error {
+			close(held)
+			<-release
+			schedule.Amount = 2000
+			return nil
+		})
+		done <- err
+	}()
+	<-held
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := waiter.FindByID(timeout, "s1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait given up with the context, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	schedule, exists, err := waiter.FindByID(ctx, "s1")
+	if err != nil || !exists || schedule.Amount != 2000 {
+		t.Fatalf("expected the holder's change seen once it let go, got %+v %v", schedule, err)
+	}
+}
@@ -0,0 +1,155 @@
+package test
+
+import (
+	"context"
+	"corebanking/internal/health"
+	"corebanking/internal/server"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, handler http.Handler, options server.Options) (string, *health.Checker, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	checker := health.NewChecker()
+	srv := server.New(listener.Addr().String(), handler, options, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(ctx, srv, listener, checker, options, logger)
+	}()
+	t.Cleanup(cancel)
+	return "http://" + listener.Addr().String(), checker, cancel, done
+}
+
+func TestServer_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "finished")
+	})
+	url, checker, cancel, done := startServer(t, handler, server.Options{
+		DrainDelay: 50 * time.Millisecond, ShutdownTimeout: 5 * time.Second,
+	})
+
+	type result struct {
+		body string
+		err  error
+	}
+	inFlight := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url + "/slow")
+		if err != nil {
+			inFlight <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		inFlight <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for !checker.Draining() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected readiness to flip to draining")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+
+	if got := <-inFlight; got.err != nil || got.body != "finished" {
+		t.Fatalf("expected in-flight request to finish, got %q %v", got.body, got.err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+	if _, err := http.Get(url + "/after"); err == nil {
+		t.Errorf("expected new connections to be refused after shutdown")
+	}
+}
+
+func TestServer_ShutdownDeadline(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+	url, _, cancel, done := startServer(t, handler, server.Options{ShutdownTimeout: 50 * time.Millisecond})
+
+	go http.Get(url + "/stuck")
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the drain deadline to be reported, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected shutdown to give up after its deadline")
+	}
+}
+
+func TestServer_MaxHeaderBytes(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	url, _, _, _ := startServer(t, handler, server.Options{MaxHeaderBytes: 1 << 10, ReadHeaderTimeout: time.Second})
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("X-Large", strings.Repeat("a", 8<<10))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("expected status %d, got %d", http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+	}
+}
+
+func TestServer_WorkersStopWithContext(t *testing.T) {
+	workers := server.NewWorkers(context.Background(), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	var stopped atomic.Int32
+	for i := 0; i < 3; i++ {
+		workers.Go("ticker", func(ctx context.Context) error {
+			<-ctx.Done()
+			stopped.Add(1)
+			return ctx.Err()
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := workers.Stop(ctx); err != nil || stopped.Load() != 3 {
+		t.Fatalf("expected all workers to stop, got %d (%v)", stopped.Load(), err)
+	}
+
+	stuck := server.NewWorkers(context.Background(), slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	block := make(chan struct{})
+	defer close(block)
+	stuck.Go("stuck", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := stuck.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected stop to give up on a stuck worker, got %v", err)
+	}
+}
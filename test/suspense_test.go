@@ -0,0 +1,272 @@
+package test
+
+import (
+	"context"
+	"corebanking/config"
+	"corebanking/internal/domain"
+	"corebanking/internal/dto"
+	"corebanking/internal/service"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type suspenseItemEnvelope struct {
+	Data  dto.SuspenseItemV2Response `json:"data"`
+	Error *dto.EnvelopeError         `json:"error"`
+}
+
+var testSuspensePolicy = service.SuspensePolicy{UnknownDestination: domain.UnknownDestinationSuspense, ReturnAfter: 72 * time.Hour}
+
+func balance(t *testing.T, app *testApp, accountID string) int64 {
+	t.Helper()
+	response, err := app.accountService.GetBalance(context.Background(), accountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.Balance
+}
+
+// heldItem reads the suspense item ID from a held_in_suspense response.
+func heldItem(t *testing.T, resp *httptest.ResponseRecorder) string {
+	t.Helper()
+	if resp.Code != http.StatusAccepted {
+		t.Fatalf("expected the posting to be held in suspense, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var body struct {
+		Error *dto.EnvelopeError `json:"error"`
+	}
+	decodeBody(t, resp, &body)
+	if body.Error == nil || body.Error.Code != "held_in_suspense" {
+		t.Fatalf("expected held_in_suspense, got %+v", body.Error)
+	}
+	_, id, ok := strings.Cut(body.Error.Details, " as item ")
+	if !ok {
+		t.Fatalf("expected the item in the details, got %q", body.Error.Details)
+	}
+	return id
+}
+
+func TestSuspense_RejectsUnknownDestinationsAndVerifies(t *testing.T) {
+	app := newTestApp()
+	origin := fundedAccount(t, app, "1")
+	named, err := app.accountService.CreateAccount(context.Background(), "2", "Maria da Silva Oliveira")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range []map[string]any{
+		{"type": "deposit", "destination": "typo", "amount": "10.00"},
+		{"type": "transfer", "origin": origin, "destination": "typo", "amount": "10.00"},
+		{"type": "transfer", "origin": origin, "destination": "suspense", "amount": "10.00"},
+	} {
+		resp := app.do(t, http.MethodPost, "/api/v2/events", event, nil)
+		var body accountEnvelope
+		decodeBody(t, resp, &body)
+		if resp.Code != http.StatusNotFound || body.Error == nil || body.Error.Details != service.ErrDestinationNotFound.Error() {
+			t.Errorf("expected %v to be declined, got %d %+v", event, resp.Code, body.Error)
+		}
+	}
+	if _, err := app.accountService.GetAccount(context.Background(), "typo"); !errors.Is(err, service.ErrAccountNotFound) {
+		t.Errorf("expected no account to be opened, got %v", err)
+	}
+	if got := balance(t, app, origin); got != 100000 {
+		t.Errorf("expected the origin untouched, got %d", got)
+	}
+
+	resp := app.do(t, http.MethodGet, "/api/v2/accounts/"+named.AccountID+"/verify", nil, map[string]string{
+		"X-Test-Subject": "1",
+		"X-Test-Roles":   "customer",
+	})
+	var verified struct {
+		Data dto.AccountVerificationV2Response `json:"data"`
+	}
+	decodeBody(t, resp, &verified)
+	if resp.Code != http.StatusOK || verified.Data.AccountID != named.AccountID || verified.Data.HolderName != "M**** d* S**** O*******" {
+		t.Errorf("expected the masked holder, got %d %+v", resp.Code, verified.Data)
+	}
+	app.suspenseService.Ledger(context.Background())
+	for _, id := range []string{"typo", "suspense"} {
+		if resp := app.do(t, http.MethodGet, "/api/v2/accounts/"+id+"/verify", nil, nil); resp.Code != http.StatusNotFound {
+			t.Errorf("expected %s not to verify, got %d", id, resp.Code)
+		}
+	}
+}
+
+func TestSuspense_HoldClaimAndReturn(t *testing.T) {
+	app := newTestApp()
+	app.suspenseService.SetPolicy(testSuspensePolicy)
+	origin := fundedAccount(t, app, "1")
+	intended := app.createAccount(t, "2")
+
+	resp := app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "transfer", "origin": origin, "destination": "typo", "amount": "100.00"}, nil)
+	transferItem := heldItem(t, resp)
+	app.clock.Advance(time.Minute)
+	resp = app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "deposit", "destination": "typo", "amount": "30.00"}, nil)
+	depositItem := heldItem(t, resp)
+	if got := balance(t, app, origin); got != 90000 {
+		t.Errorf("expected the origin debited, got %d", got)
+	}
+	if got := balance(t, app, "suspense"); got != 13000 {
+		t.Errorf("expected both postings in suspense, got %d", got)
+	}
+	if _, err := app.accountService.GetAccount(context.Background(), "typo"); !errors.Is(err, service.ErrAccountNotFound) {
+		t.Errorf("expected no account to be opened, got %v", err)
+	}
+
+	resp = app.do(t, http.MethodGet, "/api/v2/suspense/items?accountId=typo", nil, nil)
+	var list struct {
+		Data []dto.SuspenseItemV2Response `json:"data"`
+	}
+	decodeBody(t, resp, &list)
+	if len(list.Data) != 2 || list.Data[0].ID != transferItem || list.Data[0].Origin != origin || list.Data[0].Amount != 10000 || list.Data[1].Type != "deposit" {
+		t.Fatalf("expected both items held, got %+v", list.Data)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v2/suspense/items/"+transferItem+"/claim", map[string]any{"accountId": "typo-2"}, nil)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("expected a claim to an unknown account to fail, got %d", resp.Code)
+	}
+	resp = app.do(t, http.MethodPost, "/api/v2/suspense/items/"+transferItem+"/claim", map[string]any{"accountId": intended, "note": "customer called"}, map[string]string{
+		"X-Test-Subject": "ops",
+		"X-Test-Roles":   "admin",
+	})
+	var claimed suspenseItemEnvelope
+	decodeBody(t, resp, &claimed)
+	if resp.Code != http.StatusOK || claimed.Data.Status != domain.SuspenseClaimed || claimed.Data.AccountID != intended || claimed.Data.ResolvedBy != "ops" {
+		t.Fatalf("expected the item claimed, got %d %+v", resp.Code, claimed)
+	}
+	if got := balance(t, app, intended); got != 10000 {
+		t.Errorf("expected the intended account credited, got %d", got)
+	}
+	resp = app.do(t, http.MethodPost, "/api/v2/suspense/items/"+transferItem+"/return", map[string]any{}, nil)
+	if resp.Code != http.StatusConflict {
+		t.Errorf("expected a claimed item not to be returned, got %d", resp.Code)
+	}
+
+	resp = app.do(t, http.MethodPost, "/api/v2/suspense/items/"+depositItem+"/return", map[string]any{"note": "cash handed back"}, nil)
+	var returned suspenseItemEnvelope
+	decodeBody(t, resp, &returned)
+	if resp.Code != http.StatusOK || returned.Data.Status != domain.SuspenseReturned || returned.Data.AccountID != "" {
+		t.Fatalf("expected the deposit returned, got %d %+v", resp.Code, returned)
+	}
+	if got := balance(t, app, "suspense"); got != 0 {
+		t.Errorf("expected suspense to be empty, got %d", got)
+	}
+
+	var actions []string
+	for _, entry := range app.auditService.Query(context.Background(), "", "suspense_item", "", time.Time{}, time.Time{}) {
+		actions = append(actions, entry.Action)
+	}
+	want := []string{service.AuditSuspenseHold, service.AuditSuspenseHold, service.AuditSuspenseClaim, service.AuditSuspenseReturn}
+	if !slices.Equal(actions, want) {
+		t.Errorf("expected audit %v, got %v", want, actions)
+	}
+}
+
+func TestSuspense_V1TransferIsAcceptedNotRefused(t *testing.T) {
+	app := newTestApp()
+	app.suspenseService.SetPolicy(testSuspensePolicy)
+	origin := fundedAccount(t, app, "1")
+
+	resp := app.do(t, http.MethodPost, "/api/v1/transactions/event", map[string]any{"type": "transfer", "origin": origin, "destination": "typo", "amount": 500}, nil)
+	var held dto.HoldResponse
+	decodeBody(t, resp, &held)
+	if resp.Code != http.StatusAccepted || held.Status != "held_in_suspense" || held.HoldID == "" {
+		t.Fatalf("expected the transfer accepted into suspense, got %d %+v", resp.Code, held)
+	}
+	if item, err := app.suspenseService.Item(context.Background(), held.HoldID); err != nil || item.Origin != origin {
+		t.Errorf("expected the response to name the suspense item, got %+v %v", item, err)
+	}
+	if got := balance(t, app, origin); got != 99500 {
+		t.Errorf("expected the origin debited once, got %d", got)
+	}
+
+	// Rejecting unknown destinations still refuses without posting.
+	app.suspenseService.SetPolicy(service.SuspensePolicy{UnknownDestination: domain.UnknownDestinationReject})
+	resp = app.do(t, http.MethodPost, "/api/v1/transactions/event", map[string]any{"type": "transfer", "origin": origin, "destination": "typo", "amount": 500}, nil)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("expected the transfer refused, got %d", resp.Code)
+	}
+	if got := balance(t, app, origin); got != 99500 {
+		t.Errorf("expected a refused transfer not to be posted, got %d", got)
+	}
+}
+
+func TestSuspense_AccountIsNotADebitSource(t *testing.T) {
+	app := newTestApp()
+	app.suspenseService.SetPolicy(testSuspensePolicy)
+	origin := fundedAccount(t, app, "1")
+	heldItem(t, app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "deposit", "destination": "typo", "amount": "30.00"}, nil))
+
+	for _, request := range []struct {
+		path string
+		body map[string]any
+	}{
+		{"/api/v2/events", map[string]any{"type": "withdraw", "origin": "suspense", "amount": "10.00"}},
+		{"/api/v2/events", map[string]any{"type": "transfer", "origin": "suspense", "destination": origin, "amount": "10.00"}},
+		{"/api/v2/transactions", map[string]any{"accountId": "suspense", "operationTypeId": 1, "amount": "10.00"}},
+		{"/api/v2/transactions", map[string]any{"accountId": "suspense", "operationTypeId": 4, "amount": "10.00"}},
+		{"/api/v2/accounts/suspense/schedules", map[string]any{"destination": origin, "amount": "10.00", "frequency": "once"}},
+	} {
+		if resp := app.do(t, http.MethodPost, request.path, request.body, nil); resp.Code != http.StatusNotFound {
+			t.Errorf("expected %s %v to find no account, got %d", request.path, request.body, resp.Code)
+		}
+	}
+	if got := []int64{balance(t, app, "suspense"), balance(t, app, origin)}; !slices.Equal(got, []int64{3000, 100000}) {
+		t.Errorf("expected balances untouched, got %v", got)
+	}
+}
+
+func TestSuspense_ReturnsExpiredTransfersAndReloads(t *testing.T) {
+	current, err := config.NewLoaderWithEnv(nil, envMap(nil)).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy := current.SuspensePolicy(); policy.UnknownDestination != domain.UnknownDestinationReject || policy.ReturnAfter != 168*time.Hour {
+		t.Fatalf("expected the default policy, got %+v", policy)
+	}
+	if _, err := config.NewLoaderWithEnv(nil, envMap(map[string]string{"SUSPENSE_UNKNOWN_DESTINATION": "create"})).Load(); err == nil || !strings.Contains(err.Error(), "suspense.unknown_destination") {
+		t.Errorf("expected an unknown policy to be refused, got %v", err)
+	}
+
+	next, _ := config.NewLoaderWithEnv(nil, envMap(map[string]string{"SUSPENSE_UNKNOWN_DESTINATION": "suspense", "SUSPENSE_RETURN_AFTER": "72h"})).Load()
+	merged, applied, _ := config.Reload(current, next)
+	if !slices.Contains(applied, "suspense.unknown_destination") || !slices.Contains(applied, "suspense.return_after") {
+		t.Fatalf("expected the suspense policy to reload, applied %v", applied)
+	}
+	app := newTestApp()
+	app.suspenseService.SetPolicy(merged.SuspensePolicy())
+	origin := fundedAccount(t, app, "1")
+
+	if err := transfer(app, origin, "typo", 25000); !errors.Is(err, service.ErrHeldInSuspense) {
+		t.Fatalf("expected the transfer held, got %v", err)
+	}
+	cashDeposit := &dto.EventRequest{Type: "deposit", Destination: "typo", Amount: 5000}
+	if _, err := app.transactionService.HandleTransaction(context.Background(), cashDeposit); !errors.Is(err, service.ErrHeldInSuspense) {
+		t.Fatalf("expected the deposit held, got %v", err)
+	}
+
+	app.clock.Advance(71 * time.Hour)
+	if returned, err := app.suspenseService.ReturnExpired(context.Background()); returned != 0 || err != nil {
+		t.Fatalf("expected nothing due yet, got %d %v", returned, err)
+	}
+	app.clock.Advance(time.Hour)
+	if returned, err := app.suspenseService.ReturnExpired(context.Background()); returned != 1 || err != nil {
+		t.Fatalf("expected the transfer returned, got %d %v", returned, err)
+	}
+	if got := balance(t, app, origin); got != 100000 {
+		t.Errorf("expected the origin refunded, got %d", got)
+	}
+	held := app.suspenseService.Items(context.Background(), domain.SuspenseHeld, "")
+	if len(held) != 1 || held[0].Kind != "deposit" {
+		t.Errorf("expected the deposit still held, got %+v", held)
+	}
+	if returned := app.suspenseService.Items(context.Background(), domain.SuspenseReturned, origin); len(returned) != 1 || returned[0].Note != "unclaimed after 72h0m0s" || returned[0].ResolvedBy != "" {
+		t.Errorf("expected a system return, got %+v", returned)
+	}
+}
@@ -0,0 +1,350 @@
+package test
+
+import (
+	"context"
+	"corebanking/config"
+	"corebanking/internal/auth"
+	"corebanking/internal/health"
+	"corebanking/internal/server"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+var serialNumber int64
+
+// issueCert signs a certificate for commonName with parent, or self-signs
+// a CA when parent is nil.
+func issueCert(t *testing.T, commonName string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serialNumber++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serialNumber),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		if usage == x509.ExtKeyUsageServerAuth {
+			template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		}
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// writePEM writes the certificate and key and returns their paths.
+func (c *testCert) writePEM(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+
+	certPath := filepath.Join(dir, name+".crt")
+	keyPath := filepath.Join(dir, name+".key")
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(certPath, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key, Leaf: c.cert}
+}
+
+type tlsFixture struct {
+	dir      string
+	ca       *testCert
+	caPath   string
+	certPath string
+	keyPath  string
+}
+
+func newTLSFixture(t *testing.T) *tlsFixture {
+	t.Helper()
+
+	dir := t.TempDir()
+	ca := issueCert(t, "test-ca", nil, 0)
+	caPath, _ := ca.writePEM(t, dir, "ca")
+	certPath, keyPath := issueCert(t, "server-1", ca, x509.ExtKeyUsageServerAuth).writePEM(t, dir, "server")
+	return &tlsFixture{dir: dir, ca: ca, caPath: caPath, certPath: certPath, keyPath: keyPath}
+}
+
+func (f *tlsFixture) client(clientCert *testCert, maxVersion uint16) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AddCert(f.ca.cert)
+	config := &tls.Config{RootCAs: roots, MaxVersion: maxVersion}
+	if clientCert != nil {
+		config.Certificates = []tls.Certificate{clientCert.tlsCertificate()}
+	}
+	// A fresh transport per client so every request does a handshake.
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: config}, Timeout: 5 * time.Second}
+}
+
+func startTLSServer(t *testing.T, handler http.Handler, options server.TLSOptions) (string, *server.CertReloader) {
+	t.Helper()
+
+	certs, err := server.NewCertReloader(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig, err := certs.TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	srv := server.New(listener.Addr().String(), handler, server.Options{ShutdownTimeout: time.Second}, logger)
+	srv.TLSConfig = tlsConfig
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(ctx, srv, listener, health.NewChecker(), server.Options{ShutdownTimeout: time.Second}, logger)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return "https://" + listener.Addr().String(), certs
+}
+
+// whoami answers with the subject of the authenticated principal.
+func whoami(authenticator *auth.Authenticator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, principal.Subject+" "+principal.Method)
+	})
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, string, error) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, strings.TrimSpace(string(body)), nil
+}
+
+func TestTLS_ServesHTTPSWithMinimumVersion(t *testing.T) {
+	fixture := newTLSFixture(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, tls.VersionName(r.TLS.Version))
+	})
+	url, _ := startTLSServer(t, handler, server.TLSOptions{
+		CertFile: fixture.certPath, KeyFile: fixture.keyPath, MinVersion: "1.3",
+	})
+
+	status, body, err := get(t, fixture.client(nil, 0), url)
+	if err != nil || status != http.StatusOK || body != "TLS 1.3" {
+		t.Fatalf("expected a TLS 1.3 response, got %d %q %v", status, body, err)
+	}
+	if _, _, err := get(t, fixture.client(nil, tls.VersionTLS12), url); err == nil {
+		t.Error("expected a TLS 1.2 client to be refused")
+	}
+}
+
+func TestTLS_MutualTLSMapsClientCertificates(t *testing.T) {
+	fixture := newTLSFixture(t)
+	teller := issueCert(t, "branch-42", fixture.ca, x509.ExtKeyUsageClientAuth)
+	pinned := issueCert(t, "batch-job", fixture.ca, x509.ExtKeyUsageClientAuth)
+	unknown := issueCert(t, "stranger", fixture.ca, x509.ExtKeyUsageClientAuth)
+	rogueCA := issueCert(t, "rogue-ca", nil, 0)
+	rogue := issueCert(t, "branch-42", rogueCA, x509.ExtKeyUsageClientAuth)
+
+	mapper, err := auth.NewClientCertMapper([]auth.ClientCertificate{
+		{CommonName: "branch-42", Subject: "teller-42", Roles: []string{auth.RoleTeller}},
+		{Fingerprint: strings.ToUpper(auth.CertificateFingerprint(pinned.cert)), Subject: "batch", Roles: []string{auth.RoleAdmin}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := auth.NewAuthenticator(nil, nil)
+	authenticator.SetClientCertMapper(mapper)
+
+	url, _ := startTLSServer(t, whoami(authenticator), server.TLSOptions{
+		CertFile: fixture.certPath, KeyFile: fixture.keyPath, ClientCAFile: fixture.caPath,
+		ClientAuth: server.ClientAuthRequire, MinVersion: "1.2",
+	})
+
+	cases := map[string]struct {
+		cert   *testCert
+		status int
+		body   string
+	}{
+		"common name": {cert: teller, status: http.StatusOK, body: "teller-42 client_cert"},
+		"fingerprint": {cert: pinned, status: http.StatusOK, body: "batch client_cert"},
+		"unmapped":    {cert: unknown, status: http.StatusUnauthorized, body: auth.ErrInvalidCredentials.Error()},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			status, body, err := get(t, fixture.client(tc.cert, 0), url)
+			if err != nil || status != tc.status || body != tc.body {
+				t.Errorf("expected %d %q, got %d %q %v", tc.status, tc.body, status, body, err)
+			}
+		})
+	}
+
+	if _, _, err := get(t, fixture.client(nil, 0), url); err == nil {
+		t.Error("expected a client without a certificate to be refused")
+	}
+	if _, _, err := get(t, fixture.client(rogue, 0), url); err == nil {
+		t.Error("expected a certificate from another CA to be refused")
+	}
+}
+
+func TestTLS_OptionalClientCertFallsBackToHeaders(t *testing.T) {
+	fixture := newTLSFixture(t)
+	mapper, _ := auth.NewClientCertMapper([]auth.ClientCertificate{{CommonName: "branch-42", Subject: "teller-42"}})
+	authenticator := auth.NewAuthenticator(nil, nil)
+	authenticator.SetClientCertMapper(mapper)
+
+	url, _ := startTLSServer(t, whoami(authenticator), server.TLSOptions{
+		CertFile: fixture.certPath, KeyFile: fixture.keyPath, ClientCAFile: fixture.caPath,
+		ClientAuth: server.ClientAuthOptional, MinVersion: "1.2",
+	})
+
+	status, body, err := get(t, fixture.client(nil, 0), url)
+	if err != nil || status != http.StatusUnauthorized || body != auth.ErrMissingCredentials.Error() {
+		t.Errorf("expected missing credentials without a certificate, got %d %q %v", status, body, err)
+	}
+}
+
+func TestTLS_ReloadsCertificateWithoutRestart(t *testing.T) {
+	fixture := newTLSFixture(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	url, certs := startTLSServer(t, handler, server.TLSOptions{
+		CertFile: fixture.certPath, KeyFile: fixture.keyPath, MinVersion: "1.2",
+	})
+
+	servedName := func() string {
+		resp, err := fixture.client(nil, 0).Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	if name := servedName(); name != "server-1" {
+		t.Fatalf("expected server-1, got %s", name)
+	}
+
+	// A broken file is rejected and the old certificate kept.
+	os.WriteFile(fixture.keyPath, []byte("not a key"), 0o600)
+	if err := certs.Reload(); err == nil {
+		t.Error("expected reloading a broken key to fail")
+	}
+	if name := servedName(); name != "server-1" {
+		t.Fatalf("expected the previous certificate after a failed reload, got %s", name)
+	}
+
+	issueCert(t, "server-2", fixture.ca, x509.ExtKeyUsageServerAuth).writePEM(t, fixture.dir, "server")
+	// Make the change visible to the watcher even on coarse file systems.
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(fixture.certPath, later, later)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan error, 1)
+	go certs.Watch(ctx, 10*time.Millisecond, func(err error) { reloaded <- err })
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("expected the watcher to reload, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the watcher to notice the new certificate")
+	}
+	if name := servedName(); name != "server-2" {
+		t.Errorf("expected server-2 after reload, got %s", name)
+	}
+}
+
+func TestTLS_ConfigValidation(t *testing.T) {
+	cases := map[string]struct {
+		env  map[string]string
+		want string
+	}{
+		"key without cert": {env: map[string]string{"TLS_KEY_FILE": "a.key"}, want: "tls.key_file"},
+		"require without CA": {
+			env:  map[string]string{"TLS_CERT_FILE": "a.crt", "TLS_KEY_FILE": "a.key", "TLS_CLIENT_AUTH": "require"},
+			want: "tls.client_auth",
+		},
+		"old version":     {env: map[string]string{"TLS_MIN_VERSION": "1.0"}, want: "tls.min_version"},
+		"insecure cipher": {env: map[string]string{"TLS_CIPHER_SUITES": "TLS_RSA_WITH_RC4_128_SHA"}, want: "tls.cipher_suites"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.NewLoaderWithEnv(nil, envMap(tc.env)).Load()
+			if err == nil || !strings.Contains(err.Error(), tc.want+":") {
+				t.Errorf("expected an error for %s, got %v", tc.want, err)
+			}
+		})
+	}
+
+	suites, err := server.ParseCipherSuites([]string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"})
+	if err != nil || len(suites) != 1 || suites[0] != tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("expected the suite to parse, got %v %v", suites, err)
+	}
+	if _, err := server.NewCertReloader(server.TLSOptions{CertFile: "missing.crt", KeyFile: "missing.key"}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected missing files to fail, got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"corebanking/internal/tracing"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// spansNamed returns the exported spans called name.
+func spansNamed(exporter *tracetest.InMemoryExporter, name string) tracetest.SpanStubs {
+	var result tracetest.SpanStubs
+	for _, span := range exporter.GetSpans() {
+		if span.Name == name {
+			result = append(result, span)
+		}
+	}
+	return result
+}
+
+func spanAttribute(span tracetest.SpanStub, key string) any {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.AsInterface()
+		}
+	}
+	return nil
+}
+
+func installProvider(t *testing.T, exporter tracing.Exporter, ratio float64) *tracing.Provider {
+	t.Helper()
+
+	provider := tracing.NewProvider(exporter, tracing.Options{SampleRatio: ratio, BatchInterval: time.Hour})
+	tracing.SetProvider(provider)
+	t.Cleanup(func() {
+		tracing.SetProvider(nil)
+		provider.Shutdown(context.Background())
+	})
+	return provider
+}
+
+func TestTracing_ParseTraceparent(t *testing.T) {
+	tests := []struct {
+		value   string
+		valid   bool
+		sampled bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false, false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6-00f067aa0ba902b7-01", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		sc, ok := tracing.ParseTraceparent(tt.value)
+		if ok != tt.valid || sc.IsSampled() != tt.sampled {
+			t.Errorf("%q: expected valid=%v sampled=%v, got %v %v", tt.value, tt.valid, tt.sampled, ok, sc.IsSampled())
+		}
+		if ok && tt.value[:2] == "00" && tracing.Traceparent(sc) != tt.value {
+			t.Errorf("%q: expected round trip, got %q", tt.value, tracing.Traceparent(sc))
+		}
+	}
+}
+
+func TestTracing_TransferSpansFollowRemoteParent(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := installProvider(t, exporter, 0)
+	app := newTestApp()
+
+	account := app.createAccount(t, "1")
+	other := app.createAccount(t, "2")
+	app.do(t, http.MethodPost, "/api/v2/events", map[string]any{"type": "deposit", "destination": account, "amount": "5.00"}, nil)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	resp := app.do(t, http.MethodPost, "/api/v2/events", map[string]any{
+		"type": "transfer", "origin": account, "destination": other, "amount": "1.00",
+	}, map[string]string{"traceparent": "00-" + traceID + "-00f067aa0ba902b7-01"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.Code)
+	}
+	provider.ForceFlush()
+
+	servers := spansNamed(exporter, "POST /api/v2/events")
+	if len(servers) != 1 {
+		t.Fatalf("expected only the sampled request to be exported, got %d server spans", len(servers))
+	}
+	server := servers[0]
+	if server.SpanContext.TraceID().String() != traceID || server.Parent.SpanID().String() != "00f067aa0ba902b7" || server.SpanKind != tracing.KindServer {
+		t.Errorf("expected server span to continue the remote trace, got %+v", server)
+	}
+	if spanAttribute(server, "http.response.status_code") != int64(http.StatusCreated) || spanAttribute(server, "http.route") != "POST /api/v2/events" {
+		t.Errorf("unexpected server attributes %v", server.Attributes)
+	}
+
+	handled := spansNamed(exporter, "TransactionService.HandleTransaction")
+	if len(handled) != 1 || handled[0].Parent.SpanID() != server.SpanContext.SpanID() || spanAttribute(handled[0], "event.type") != "transfer" {
+		t.Fatalf("expected service span under the server span, got %+v", handled)
+	}
+	saves := spansNamed(exporter, "AccountRepository.Save")
+	if len(saves) != 2 {
+		t.Fatalf("expected origin and destination saves, got %d", len(saves))
+	}
+	for _, save := range saves {
+		if save.SpanContext.TraceID().String() != traceID || save.Parent.SpanID() != handled[0].SpanContext.SpanID() {
+			t.Errorf("expected repository span under the service span, got %+v", save)
+		}
+	}
+
+	for _, record := range logRecords(t, app, "request") {
+		if record["path"] == "/api/v2/events" && record["trace_id"] == traceID {
+			return
+		}
+	}
+	t.Errorf("expected the access line to carry the trace ID")
+}
+
+func TestTracing_RecordsErrors(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := installProvider(t, exporter, 1)
+	app := newTestApp()
+
+	app.do(t, http.MethodGet, "/api/v2/accounts/missing", nil, nil)
+	provider.ForceFlush()
+
+	spans := spansNamed(exporter, "AccountService.GetAccount")
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error || spans[0].Status.Description == "" {
+		t.Fatalf("expected a failed service span, got %+v", spans)
+	}
+	if servers := spansNamed(exporter, "GET /api/v2/accounts/{accountId}"); len(servers) != 1 || servers[0].Parent.IsValid() {
+		t.Errorf("expected a root server span, got %+v", servers)
+	}
+}
+
+func TestTracing_WriterExporter(t *testing.T) {
+	var out bytes.Buffer
+	exporter, err := tracing.NewWriterExporter(&out)
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+	provider := installProvider(t, exporter, 1)
+
+	ctx, parent := tracing.Start(context.Background(), "parent", tracing.String("k", "v"))
+	_, child := tracing.Start(ctx, "child")
+	child.End()
+	parent.End()
+	provider.ForceFlush()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two span lines, got %q", out.String())
+	}
+	type line struct {
+		Name        string
+		SpanContext struct{ SpanID string }
+		Parent      struct{ SpanID string }
+		Attributes  []struct {
+			Key   string
+			Value struct{ Value any }
+		}
+	}
+	var first, second line
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+	if first.Name != "child" || second.Name != "parent" || first.Parent.SpanID != second.SpanContext.SpanID {
+		t.Errorf("unexpected spans %+v %+v", first, second)
+	}
+	if len(second.Attributes) != 1 || second.Attributes[0].Key != "k" || second.Attributes[0].Value.Value != "v" {
+		t.Errorf("unexpected attributes %+v", second.Attributes)
+	}
+}
+
+func TestTracing_OTLPExporter(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		request coltracepb.ExportTraceServiceRequest
+		headers http.Header
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		data, _ := io.ReadAll(r.Body)
+		proto.Unmarshal(data, &request)
+		headers = r.Header.Clone()
+	}))
+	defer collector.Close()
+
+	exporter, err := tracing.NewOTLPExporter(collector.URL+"/v1/traces", map[string]string{"Authorization": "Bearer t"})
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+	provider := tracing.NewProvider(exporter, tracing.Options{ServiceName: "corebanking-test", SampleRatio: 1, BatchInterval: time.Hour})
+	tracing.SetProvider(provider)
+	t.Cleanup(func() {
+		tracing.SetProvider(nil)
+		provider.Shutdown(context.Background())
+	})
+
+	_, span := tracing.Start(context.Background(), "work", tracing.Int64("n", 7))
+	span.RecordError(io.EOF)
+	span.End()
+	if err := provider.ForceFlush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if headers.Get("Content-Type") != "application/x-protobuf" || headers.Get("Authorization") != "Bearer t" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+	if len(request.ResourceSpans) != 1 {
+		t.Fatalf("expected one resource, got %d", len(request.ResourceSpans))
+	}
+	resource := request.ResourceSpans[0]
+	serviceName := ""
+	for _, attr := range resource.Resource.Attributes {
+		if attr.Key == "service.name" {
+			serviceName = attr.Value.GetStringValue()
+		}
+	}
+	if serviceName != "corebanking-test" {
+		t.Errorf("unexpected resource %v", resource.Resource)
+	}
+	exported := resource.ScopeSpans[0].Spans[0]
+	if exported.Name != "work" || len(exported.TraceId) != 16 || len(exported.SpanId) != 8 || exported.StartTimeUnixNano == 0 {
+		t.Errorf("unexpected span %v", exported)
+	}
+	if exported.Status.Code != tracepb.Status_STATUS_CODE_ERROR || exported.Status.Message != "EOF" {
+		t.Errorf("expected error status, got %v", exported.Status)
+	}
+	if len(exported.Attributes) != 1 || exported.Attributes[0].Key != "n" || exported.Attributes[0].Value.GetIntValue() != 7 {
+		t.Errorf("unexpected attributes %v", exported.Attributes)
+	}
+}